@@ -44,7 +44,20 @@ func (h *Harbor) GetComponentSpec(ctx context.Context, component harbormetav1.Co
 	var spec harbormetav1.ComponentSpec
 
 	h.deepCopyComponentSpecInto(ctx, component, &spec)
-	h.deepCopyImageSpecInto(ctx, component, &spec)
+	h.deepCopyImageSpecInto(ctx, component.String(), &spec)
+
+	return spec
+}
+
+// GetExposeProxySpec returns the spec of the front proxy of the core exposed with a service.
+func (h *Harbor) GetExposeProxySpec(ctx context.Context) harbormetav1.ComponentSpec {
+	var spec harbormetav1.ComponentSpec
+
+	if h.Spec.Expose.Core.Service != nil && h.Spec.Expose.Core.Service.Proxy != nil {
+		h.Spec.Expose.Core.Service.Proxy.DeepCopyInto(&spec)
+	}
+
+	h.deepCopyImageSpecInto(ctx, ExposeProxyImageComponent, &spec)
 
 	return spec
 }
@@ -124,7 +137,7 @@ func (h *Harbor) GetComponentProxySpec(component harbormetav1.Component) *harbor
 	return nil
 }
 
-func (h *Harbor) deepCopyImageSpecInto(ctx context.Context, component harbormetav1.Component, spec *harbormetav1.ComponentSpec) {
+func (h *Harbor) deepCopyImageSpecInto(ctx context.Context, component string, spec *harbormetav1.ComponentSpec) {
 	imageSource := h.Spec.ImageSource
 	if imageSource == nil {
 		return
@@ -136,7 +149,7 @@ func (h *Harbor) deepCopyImageSpecInto(ctx context.Context, component harbormeta
 			image.WithTagSuffix(imageSource.TagSuffix),
 			image.WithHarborVersion(h.Spec.Version),
 		}
-		spec.Image, _ = image.GetImage(ctx, component.String(), getImageOptions...)
+		spec.Image, _ = image.GetImage(ctx, component, getImageOptions...)
 	}
 
 	if spec.ImagePullPolicy == nil && imageSource.ImagePullPolicy != nil {
@@ -209,6 +222,47 @@ type HarborSpec struct {
 	Trace *harbormetav1.TraceSpec `json:"trace,omitempty"`
 }
 
+// ValidateComponents checks the spec of each component.
+func (r *HarborComponentsSpec) ValidateComponents() field.ErrorList {
+	p := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+
+	validate := func(spec *harbormetav1.ComponentSpec, path *field.Path) {
+		if err := spec.Validate(path); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+
+	if r.Portal != nil {
+		validate(&r.Portal.ComponentSpec, p.Child("portal"))
+	}
+
+	validate(&r.Core.ComponentSpec, p.Child("core"))
+	validate(&r.JobService.ComponentSpec, p.Child("jobservice"))
+	validate(&r.Registry.ComponentSpec, p.Child("registry"))
+	validate(r.RegistryController, p.Child("registryctl"))
+
+	if r.ChartMuseum != nil {
+		validate(&r.ChartMuseum.ComponentSpec, p.Child("chartmuseum"))
+	}
+
+	if r.Exporter != nil {
+		validate(&r.Exporter.ComponentSpec, p.Child("exporter"))
+	}
+
+	if r.Trivy != nil {
+		validate(&r.Trivy.ComponentSpec, p.Child("trivy"))
+	}
+
+	if r.Notary != nil {
+		validate(&r.Notary.Server, p.Child("notary", "server"))
+		validate(&r.Notary.Signer, p.Child("notary", "signer"))
+	}
+
+	return allErrs
+}
+
 func (spec *HarborSpec) ValidateNotary() *field.Error {
 	return nil
 }
@@ -807,6 +861,159 @@ type HarborExposeComponentSpec struct {
 
 	// +kubebuilder:validation:Optional
 	Ingress *HarborExposeIngressSpec `json:"ingress,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Gateway exposes the component with Gateway API routes attached to existing gateways.
+	// Only supported by the core and the notary, exclusive with the ingress.
+	// The HTTPRoutes cannot reach the HTTPS ports of the internal TLS, only the TLS passthrough of the notary can.
+	Gateway *HarborExposeGatewaySpec `json:"gateway,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Service exposes the component with a LoadBalancer or NodePort service, without ingress controller.
+	// The requests are routed by a front proxy deployed by the operator, which terminates the TLS.
+	// Only supported by the core, exclusive with the ingress and the gateway.
+	Service *HarborExposeServiceSpec `json:"service,omitempty"`
+}
+
+// Validate checks that each component is exposed by either an ingress, a gateway or a service,
+// and that the core, which routes the requests by path, is not exposed with TLS passthrough.
+func (spec *HarborExposeSpec) Validate() *field.Error {
+	p := field.NewPath("spec").Child("expose")
+
+	if spec.Core.Ingress != nil && spec.Core.Gateway != nil {
+		return field.Forbidden(p.Child("core", "gateway"), "cannot be specified with spec.expose.core.ingress")
+	}
+
+	if spec.Core.Gateway != nil && spec.Core.Gateway.Passthrough {
+		return field.Forbidden(p.Child("core", "gateway", "passthrough"), "the core requests are routed by path, TLS passthrough is only supported by the notary")
+	}
+
+	if spec.Notary != nil && spec.Notary.Ingress != nil && spec.Notary.Gateway != nil {
+		return field.Forbidden(p.Child("notary", "gateway"), "cannot be specified with spec.expose.notary.ingress")
+	}
+
+	if spec.Core.Service != nil && (spec.Core.Ingress != nil || spec.Core.Gateway != nil) {
+		return field.Forbidden(p.Child("core", "service"), "cannot be specified with spec.expose.core.ingress or spec.expose.core.gateway")
+	}
+
+	if spec.Notary != nil && spec.Notary.Service != nil {
+		return field.Forbidden(p.Child("notary", "service"), "the notary cannot be exposed with a service")
+	}
+
+	if spec.Core.Service != nil {
+		return spec.Core.Service.Proxy.Validate(p.Child("core", "service", "proxy"))
+	}
+
+	return nil
+}
+
+type HarborExposeGatewaySpec struct {
+	// +kubebuilder:validation:Required
+	// The hostname matched by the routes.
+	Host string `json:"host"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// The gateways, or gateway listeners, the routes are attached to.
+	ParentRefs []HarborExposeGatewayParentReference `json:"parentRefs"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// Create a TLSRoute forwarding the TLS connections to the component instead of an HTTPRoute.
+	// The gateway listener must be in Passthrough TLS mode and the internal TLS must be enabled.
+	Passthrough bool `json:"passthrough,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExposeProxyImageComponent is the image of the front proxy of the core exposed with a service.
+const ExposeProxyImageComponent = "nginx"
+
+type HarborExposeServiceSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"LoadBalancer","NodePort"}
+	// +kubebuilder:default="LoadBalancer"
+	// The type of the service.
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// The class of the load balancer implementation, only for the LoadBalancer services.
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// The client IP ranges allowed by the load balancer, only for the LoadBalancer services.
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// The node port of the HTTP port, allocated by Kubernetes if not specified.
+	HTTPNodePort *int32 `json:"httpNodePort,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// The node port of the HTTPS port, allocated by Kubernetes if not specified.
+	HTTPSNodePort *int32 `json:"httpsNodePort,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={"Cluster","Local"}
+	// Whether the external traffic is routed to the proxies of the node only (Local) or of the whole cluster (Cluster).
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// The deployment of the front proxy, an nginx of the Harbor version.
+	Proxy *harbormetav1.ComponentSpec `json:"proxy,omitempty"`
+}
+
+// ValidateInternalTLS checks that the gateway routes can reach the components with the internal TLS settings:
+// the TLS passthrough requires the internal TLS, and the HTTPRoutes cannot reach the HTTPS ports of the internal TLS
+// without a BackendTLSPolicy, which is not managed by the operator.
+func (spec *HarborExposeSpec) ValidateInternalTLS(internalTLS *HarborInternalTLSSpec) *field.Error {
+	p := field.NewPath("spec").Child("expose")
+
+	if spec.Notary != nil && spec.Notary.Gateway != nil && spec.Notary.Gateway.Passthrough && !internalTLS.IsEnabled() {
+		return field.Forbidden(p.Child("notary", "gateway", "passthrough"), "the TLS passthrough requires spec.internalTLS.enabled")
+	}
+
+	if !internalTLS.IsEnabled() {
+		return nil
+	}
+
+	if spec.Core.Gateway != nil {
+		return field.Forbidden(p.Child("core", "gateway"), "the HTTPRoute cannot reach the HTTPS port of the core with spec.internalTLS.enabled")
+	}
+
+	if spec.Notary != nil && spec.Notary.Gateway != nil && !spec.Notary.Gateway.Passthrough {
+		return field.Forbidden(p.Child("notary", "gateway"), "the HTTPRoute cannot reach the HTTPS port of the notary with spec.internalTLS.enabled, use passthrough")
+	}
+
+	return nil
+}
+
+// HarborExposeGatewayParentReference references a Gateway, see the ParentReference of the Gateway API.
+type HarborExposeGatewayParentReference struct {
+	// +kubebuilder:validation:Required
+	// The name of the gateway.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// The namespace of the gateway, defaults to the namespace of the harbor.
+	Namespace *string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// The name of the gateway listener the routes are attached to.
+	SectionName *string `json:"sectionName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// The port of the gateway listeners the routes are attached to.
+	Port *int32 `json:"port,omitempty"`
 }
 
 type HarborExposeIngressSpec struct {
@@ -83,6 +83,14 @@ func (h *Harbor) Validate(old *Harbor) error {
 		allErrs = append(allErrs, required(field.NewPath("spec").Child("redis")))
 	}
 
+	if err := h.Spec.Expose.Validate(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := h.Spec.Expose.ValidateInternalTLS(&h.Spec.InternalTLS); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if err := h.Spec.ValidateNotary(); err != nil {
 		allErrs = append(allErrs, err)
 	}
@@ -91,6 +99,8 @@ func (h *Harbor) Validate(old *Harbor) error {
 		allErrs = append(allErrs, err)
 	}
 
+	allErrs = append(allErrs, h.Spec.ValidateComponents()...)
+
 	if old == nil { // create harbor resource
 		if err := version.Validate(h.Spec.Version); err != nil {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("version"), h.Spec.Version, err.Error()))
@@ -0,0 +1,101 @@
+package v1beta1_test
+
+import (
+	"github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("Harbor webhook", func() {
+	one := intstr.FromInt(1)
+	bothBounds := &harbormetav1.PodDisruptionBudgetSpec{
+		MinAvailable:   &one,
+		MaxUnavailable: &one,
+	}
+
+	It("Should accept a single bound of the pod disruption budgets", func() {
+		components := v1beta1.HarborComponentsSpec{}
+		components.Core.PodDisruptionBudget = &harbormetav1.PodDisruptionBudgetSpec{MinAvailable: &one}
+		components.Registry.PodDisruptionBudget = &harbormetav1.PodDisruptionBudgetSpec{MaxUnavailable: &one}
+
+		Expect(components.ValidateComponents()).To(BeEmpty())
+	})
+
+	It("Should refuse both bounds of the pod disruption budgets", func() {
+		components := v1beta1.HarborComponentsSpec{
+			Trivy: &v1beta1.TrivyComponentSpec{},
+		}
+		components.Core.PodDisruptionBudget = bothBounds
+		components.Trivy.PodDisruptionBudget = bothBounds
+
+		errs := components.ValidateComponents()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.core.podDisruptionBudget.maxUnavailable"))
+		Expect(errs[1].Field).To(Equal("spec.trivy.podDisruptionBudget.maxUnavailable"))
+	})
+
+	It("Should refuse both bounds of the pod disruption budgets in a harbor cluster", func() {
+		components := v1beta1.EmbeddedHarborComponentsSpec{
+			RegistryController: &harbormetav1.ComponentSpec{PodDisruptionBudget: bothBounds},
+		}
+
+		errs := components.ValidateComponents()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.registryctl.podDisruptionBudget.maxUnavailable"))
+	})
+
+	It("Should refuse both bounds of the pod disruption budget of the proxy", func() {
+		expose := v1beta1.HarborExposeSpec{
+			Core: v1beta1.HarborExposeComponentSpec{
+				Service: &v1beta1.HarborExposeServiceSpec{
+					Proxy: &harbormetav1.ComponentSpec{PodDisruptionBudget: bothBounds},
+				},
+			},
+		}
+
+		err := expose.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Field).To(Equal("spec.expose.core.service.proxy.podDisruptionBudget.maxUnavailable"))
+	})
+
+	DescribeTable("Gateway routes with the internal TLS",
+		func(coreGateway, notaryGateway, passthrough, internalTLS bool, field string) {
+			gateway := &v1beta1.HarborExposeGatewaySpec{
+				Host:       "registry.example.com",
+				ParentRefs: []v1beta1.HarborExposeGatewayParentReference{{Name: "gateway"}},
+			}
+
+			expose := v1beta1.HarborExposeSpec{
+				Core: v1beta1.HarborExposeComponentSpec{
+					Ingress: &v1beta1.HarborExposeIngressSpec{Host: "registry.example.com"},
+				},
+			}
+
+			if coreGateway {
+				expose.Core = v1beta1.HarborExposeComponentSpec{Gateway: gateway}
+			}
+
+			if notaryGateway {
+				notary := *gateway
+				notary.Passthrough = passthrough
+				expose.Notary = &v1beta1.HarborExposeComponentSpec{Gateway: &notary}
+			}
+
+			err := expose.ValidateInternalTLS(&v1beta1.HarborInternalTLSSpec{Enabled: internalTLS})
+			if field == "" {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).NotTo(BeNil())
+				Expect(err.Field).To(Equal(field))
+			}
+		},
+		Entry("core route without internal TLS", true, true, false, false, ""),
+		Entry("core route with internal TLS", true, false, false, true, "spec.expose.core.gateway"),
+		Entry("notary route with internal TLS", false, true, false, true, "spec.expose.notary.gateway"),
+		Entry("notary passthrough with internal TLS", false, true, true, true, ""),
+		Entry("notary passthrough without internal TLS", false, true, true, false, "spec.expose.notary.gateway.passthrough"),
+		Entry("ingresses with internal TLS", false, false, false, true, ""),
+	)
+})
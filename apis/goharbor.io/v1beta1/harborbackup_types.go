@@ -0,0 +1,165 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborBackupComponent is an in-cluster service of a HarborCluster holding data.
+// +kubebuilder:validation:Enum={Database,Cache,Storage}
+type HarborBackupComponent string
+
+const (
+	HarborBackupComponentDatabase HarborBackupComponent = "Database"
+	HarborBackupComponentCache    HarborBackupComponent = "Cache"
+	HarborBackupComponentStorage  HarborBackupComponent = "Storage"
+)
+
+// HarborBackupPhase is the phase of a backup or a restore.
+type HarborBackupPhase string
+
+const (
+	HarborBackupPhasePending   HarborBackupPhase = "Pending"
+	HarborBackupPhaseRunning   HarborBackupPhase = "Running"
+	HarborBackupPhaseCompleted HarborBackupPhase = "Completed"
+	HarborBackupPhaseFailed    HarborBackupPhase = "Failed"
+)
+
+// HarborBackupSpec defines the desired state of HarborBackup.
+type HarborBackupSpec struct {
+	// +kubebuilder:validation:Required
+	// HarborClusterRef is the name of the HarborCluster to backup, in the same namespace.
+	HarborClusterRef string `json:"harborClusterRef"`
+
+	// +kubebuilder:validation:Optional
+	// Components to backup. All the in-cluster components of the HarborCluster are backed up if empty.
+	Components []HarborBackupComponent `json:"components,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Target is where the data is written.
+	Target HarborBackupTarget `json:"target"`
+}
+
+// HarborBackupTarget defines where the data is stored.
+// Exactly one of the targets must be set.
+type HarborBackupTarget struct {
+	// +kubebuilder:validation:Optional
+	// PersistentVolumeClaim stores the data in an existing PVC of the namespace.
+	PersistentVolumeClaim *HarborBackupPVCTarget `json:"persistentVolumeClaim,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Bucket stores the data in a S3 compatible bucket.
+	Bucket *HarborBackupBucketTarget `json:"bucket,omitempty"`
+}
+
+type HarborBackupPVCTarget struct {
+	// +kubebuilder:validation:Required
+	// ClaimName is the name of the PVC.
+	ClaimName string `json:"claimName"`
+
+	// +kubebuilder:validation:Optional
+	// Path is the directory of the volume where the backups are written.
+	Path string `json:"path,omitempty"`
+}
+
+type HarborBackupBucketTarget struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="https?://.*"
+	// Endpoint of the S3 compatible service.
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:Required
+	// Bucket is the name of the bucket, it must exist.
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:validation:Optional
+	// Path is the prefix of the objects in the bucket.
+	Path string `json:"path,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// CredentialsRef is the name of a secret of the namespace with the `accesskey` and `secretkey` keys.
+	CredentialsRef string `json:"credentialsRef"`
+
+	// +kubebuilder:validation:Optional
+	// Insecure skips the verification of the TLS certificate of the endpoint.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// HarborBackupComponentStatus is the status of the job of a component.
+type HarborBackupComponentStatus struct {
+	Component HarborBackupComponent `json:"component"`
+
+	Phase HarborBackupPhase `json:"phase"`
+
+	// +kubebuilder:validation:Optional
+	// JobName is the name of the job backing up or restoring the component.
+	JobName string `json:"jobName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Location is the path of the data of the component in the target.
+	Location string `json:"location,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// HarborBackupStatus defines the observed state of HarborBackup.
+type HarborBackupStatus struct {
+	// +kubebuilder:validation:Optional
+	Phase HarborBackupPhase `json:"phase,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Components is the status of each backed up component.
+	Components []HarborBackupComponentStatus `json:"components,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetComponent returns the status of the component, nil if it is not tracked.
+func (status *HarborBackupStatus) GetComponent(component HarborBackupComponent) *HarborBackupComponentStatus {
+	for i := range status.Components {
+		if status.Components[i].Component == component {
+			return &status.Components[i]
+		}
+	}
+
+	return nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hbk"
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.harborClusterRef`,description="The HarborCluster backed up",priority=0
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The phase of the backup",priority=0
+// +kubebuilder:printcolumn:name="Completion",type=date,JSONPath=`.status.completionTime`,description="The completion time of the backup",priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// HarborBackup is the Schema for the backups of the in-cluster services of a HarborCluster.
+type HarborBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborBackupSpec   `json:"spec,omitempty"`
+	Status HarborBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HarborBackupList contains a list of HarborBackup.
+type HarborBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborBackup `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborBackup{}, &HarborBackupList{})
+}
@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hbklog = logf.Log.WithName("harborbackup-resource")
+
+func (hb *HarborBackup) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hb).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-harborbackup,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborbackups,versions=v1beta1,name=vharborbackup.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborBackup{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hb *HarborBackup) ValidateCreate() error {
+	hbklog.Info("validate create", "name", hb.Name)
+
+	return hb.Validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hb *HarborBackup) ValidateUpdate(old runtime.Object) error {
+	hbklog.Info("validate update", "name", hb.Name)
+
+	obj, ok := old.(*HarborBackup)
+	if !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return hb.Validate(obj)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hb *HarborBackup) ValidateDelete() error {
+	hbklog.Info("validate delete", "name", hb.Name)
+
+	return nil
+}
+
+func (hb *HarborBackup) Validate(old *HarborBackup) error {
+	var allErrs field.ErrorList
+
+	if old != nil && !equality.Semantic.DeepEqual(hb.Spec, old.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "the spec of a backup cannot be changed"))
+	}
+
+	allErrs = append(allErrs, hb.Spec.Target.validate(field.NewPath("spec").Child("target"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborBackup"}, hb.Name, allErrs)
+}
+
+func (target *HarborBackupTarget) validate(path *field.Path) field.ErrorList {
+	switch {
+	case target.PersistentVolumeClaim == nil && target.Bucket == nil:
+		return field.ErrorList{field.Required(path, "one of persistentVolumeClaim or bucket must be set")}
+	case target.PersistentVolumeClaim != nil && target.Bucket != nil:
+		return field.ErrorList{field.Forbidden(path, "only one of persistentVolumeClaim or bucket can be set")}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborBackupScheduleSpec defines the desired state of HarborBackupSchedule.
+type HarborBackupScheduleSpec struct {
+	// +kubebuilder:validation:Required
+	// Schedule in Cron format, see https://en.wikipedia.org/wiki/Cron,
+	// optionally with a seconds field first. Times are in UTC.
+	Schedule string `json:"schedule"`
+
+	// +kubebuilder:validation:Optional
+	// Suspend stops the creation of new backups.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=3
+	// SuccessfulBackupsHistoryLimit is the number of completed backups to keep.
+	SuccessfulBackupsHistoryLimit *int32 `json:"successfulBackupsHistoryLimit,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// FailedBackupsHistoryLimit is the number of failed backups to keep.
+	FailedBackupsHistoryLimit *int32 `json:"failedBackupsHistoryLimit,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// Template is the spec of the created backups.
+	Template HarborBackupSpec `json:"template"`
+}
+
+// HarborBackupScheduleStatus defines the observed state of HarborBackupSchedule.
+type HarborBackupScheduleStatus struct {
+	// +kubebuilder:validation:Optional
+	// LastScheduleTime is the last time a backup was created.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LastBackup is the name of the last created backup.
+	LastBackup string `json:"lastBackup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// LastSuccessfulBackup is the name of the last completed backup.
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hbks"
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="The schedule of the backups",priority=0
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,description="Whether the schedule is suspended",priority=0
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`,description="The last time a backup was created",priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// HarborBackupSchedule is the Schema for the periodic backups of a HarborCluster.
+type HarborBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborBackupScheduleSpec   `json:"spec,omitempty"`
+	Status HarborBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HarborBackupScheduleList contains a list of HarborBackupSchedule.
+type HarborBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborBackupSchedule `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborBackupSchedule{}, &HarborBackupScheduleList{})
+}
@@ -0,0 +1,69 @@
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor-operator/pkg/utils/cron"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hbkslog = logf.Log.WithName("harborbackupschedule-resource")
+
+func (hbs *HarborBackupSchedule) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hbs).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-harborbackupschedule,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborbackupschedules,versions=v1beta1,name=vharborbackupschedule.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborBackupSchedule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hbs *HarborBackupSchedule) ValidateCreate() error {
+	hbkslog.Info("validate create", "name", hbs.Name)
+
+	return hbs.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hbs *HarborBackupSchedule) ValidateUpdate(old runtime.Object) error {
+	hbkslog.Info("validate update", "name", hbs.Name)
+
+	if _, ok := old.(*HarborBackupSchedule); !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return hbs.Validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hbs *HarborBackupSchedule) ValidateDelete() error {
+	hbkslog.Info("validate delete", "name", hbs.Name)
+
+	return nil
+}
+
+func (hbs *HarborBackupSchedule) Validate() error {
+	var allErrs field.ErrorList
+
+	if _, err := cron.Parse(hbs.Spec.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("schedule"), hbs.Spec.Schedule, err.Error()))
+	}
+
+	allErrs = append(allErrs, hbs.Spec.Template.Target.validate(field.NewPath("spec").Child("template", "target"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborBackupSchedule"}, hbs.Name, allErrs)
+}
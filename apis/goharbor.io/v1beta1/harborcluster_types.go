@@ -6,11 +6,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 const (
 	KindDatabaseZlandoPostgreSQL = "Zlando/PostgreSQL"
 	KindDatabasePostgreSQL       = "PostgreSQL"
+	KindDatabaseCloudNativePG    = "CloudNativePG"
 	KindStorageOss               = "Oss"
 	KindStorageGcs               = "Gcs"
 	KindStorageAzure             = "Azure"
@@ -19,6 +21,7 @@ const (
 	KindStorageS3                = "S3"
 	KindStorageFileSystem        = "FileSystem"
 	KindCacheRedisFailover       = "RedisFailover"
+	KindCacheRedisReplication    = "RedisReplication"
 	KindCacheRedis               = "Redis"
 )
 
@@ -124,14 +127,59 @@ type EmbeddedHarborComponentsSpec struct {
 	Notary *NotaryComponentSpec `json:"notary,omitempty"`
 }
 
+// ValidateComponents checks the spec of each component.
+func (r *EmbeddedHarborComponentsSpec) ValidateComponents() field.ErrorList {
+	components := HarborComponentsSpec{
+		Portal:             r.Portal,
+		Core:               r.Core,
+		JobService:         r.JobService,
+		Registry:           r.Registry,
+		RegistryController: r.RegistryController,
+		ChartMuseum:        r.ChartMuseum,
+		Exporter:           r.Exporter,
+		Trivy:              r.Trivy,
+		Notary:             r.Notary,
+	}
+
+	return components.ValidateComponents()
+}
+
+// DeletionPolicy defines what happens to an in-cluster service when the harbor cluster is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the service and its data, they are no longer owned by the harbor cluster.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete deletes the service, its volumes and its secrets.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicySnapshotThenDelete takes a VolumeSnapshot of each volume before deleting the service.
+	DeletionPolicySnapshotThenDelete DeletionPolicy = "SnapshotThenDelete"
+)
+
 type Cache struct {
 	// Set the kind of cache service to be used. Only support Redis now.
-	// +kubebuilder:validation:Enum={Redis,RedisFailover}
+	// +kubebuilder:validation:Enum={Redis,RedisFailover,RedisReplication}
 	Kind string `json:"kind"`
 
 	// RedisSpec is the specification of redis.
 	// +kubebuilder:validation:Required
 	Spec *CacheSpec `json:"spec"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={Retain,Delete,SnapshotThenDelete}
+	// +kubebuilder:default="Retain"
+	// DeletionPolicy defines what happens to the in-cluster cache when the harbor cluster is deleted.
+	// Default to Retain, the data of the clusters created before the policy existed is kept.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// GetDeletionPolicy returns the deletion policy of the in-cluster cache.
+func (cache *Cache) GetDeletionPolicy() DeletionPolicy {
+	if cache.DeletionPolicy == "" {
+		return DeletionPolicyRetain
+	}
+
+	return cache.DeletionPolicy
 }
 
 type CacheSpec struct {
@@ -140,6 +188,23 @@ type CacheSpec struct {
 
 	// +kubebuilder:validation:Optional
 	RedisFailover *RedisFailoverSpec `json:"redisFailover,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RedisReplication deploys a primary and its replicas without sentinel, with the OT-Container-Kit redis operator.
+	RedisReplication *RedisReplicationSpec `json:"redisReplication,omitempty"`
+}
+
+// IsInCluster returns true if the cache is deployed by the operator.
+func (spec *CacheSpec) IsInCluster() bool {
+	return spec != nil && (spec.RedisFailover != nil || spec.RedisReplication != nil)
+}
+
+type RedisReplicationSpec struct {
+	harbormetav1.ImageSpec `json:",inline"`
+
+	// +kubebuilder:validation:Optional
+	// Server is the configuration of the redis servers, the replicas include the primary.
+	Server *RedisServer `json:"server,omitempty"`
 }
 
 type RedisFailoverSpec struct {
@@ -187,11 +252,27 @@ type RedisServer struct {
 
 type Database struct {
 	// Set the kind of which database service to be used, Only support PostgreSQL now.
-	// +kubebuilder:validation:Enum={PostgreSQL,Zlando/PostgreSQL}
+	// +kubebuilder:validation:Enum={PostgreSQL,Zlando/PostgreSQL,CloudNativePG}
 	Kind string `json:"kind"`
 
 	// +kubebuilder:validation:Required
 	Spec DatabaseSpec `json:"spec"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={Retain,Delete,SnapshotThenDelete}
+	// +kubebuilder:default="Retain"
+	// DeletionPolicy defines what happens to the in-cluster database when the harbor cluster is deleted.
+	// Default to Retain, the data of the clusters created before the policy existed is kept.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// GetDeletionPolicy returns the deletion policy of the in-cluster database.
+func (database *Database) GetDeletionPolicy() DeletionPolicy {
+	if database.DeletionPolicy == "" {
+		return DeletionPolicyRetain
+	}
+
+	return database.DeletionPolicy
 }
 
 type DatabaseSpec struct {
@@ -200,6 +281,15 @@ type DatabaseSpec struct {
 
 	// ZlandoPostgreSQL
 	ZlandoPostgreSQL *ZlandoPostgreSQLSpec `json:"zlandoPostgreSql,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// CloudNativePG deploys the in-cluster PostgreSQL with the CloudNativePG operator.
+	CloudNativePG *CloudNativePGSpec `json:"cloudNativePG,omitempty"`
+}
+
+// IsInCluster returns whether the database is deployed in-cluster by the operator.
+func (spec *DatabaseSpec) IsInCluster() bool {
+	return spec.ZlandoPostgreSQL != nil || spec.CloudNativePG != nil
 }
 
 type PostgreSQLSpec struct {
@@ -217,6 +307,86 @@ type ZlandoPostgreSQLSpec struct {
 	Resources        corev1.ResourceRequirements `json:"resources,omitempty"`
 	SslConfig        string                      `json:"sslConfig,omitempty"`
 	ConnectTimeout   int                         `json:"connectTimeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^[0-9]+$"
+	// Version is the major version of the PostgreSQL server, eg 13.
+	// Default to the version associated with the Harbor version.
+	// The default spilo-13 image cannot run a version above 13, raising it requires an image able to run it.
+	Version string `json:"version,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Upgrade configures how the in-cluster PostgreSQL is upgraded
+	// when the operator version or the PostgreSQL major version changes.
+	Upgrade *PostgreSQLUpgradeSpec `json:"upgrade,omitempty"`
+}
+
+type CloudNativePGSpec struct {
+	harbormetav1.ImageSpec `json:",inline"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Instances is the number of PostgreSQL instances, one primary and the standbys.
+	// Default to 3.
+	Instances int `json:"instances,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Storage is the size of the volume of each instance.
+	Storage string `json:"storage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^[0-9]+$"
+	// Version is the major version of the PostgreSQL server, eg 15.
+	// It selects the tag of the default image and cannot be changed once the database is deployed.
+	Version string `json:"version,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Parameters are the PostgreSQL configuration parameters, they take precedence over the operator defaults.
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+const (
+	// PostgreSQLBackupNone skips the backup before upgrading.
+	PostgreSQLBackupNone = "None"
+	// PostgreSQLBackupDump runs a pg_dumpall into a dedicated volume before upgrading.
+	PostgreSQLBackupDump = "Dump"
+	// PostgreSQLBackupSnapshot takes a VolumeSnapshot of the master volume before upgrading.
+	PostgreSQLBackupSnapshot = "Snapshot"
+)
+
+type PostgreSQLUpgradeSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum={None,Dump,Snapshot}
+	// +kubebuilder:default="Dump"
+	// PreUpgradeBackup is the kind of backup taken before upgrading.
+	PreUpgradeBackup string `json:"preUpgradeBackup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// StorageClassName is the storage class name of the dump volume.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Storage is the size of the dump volume.
+	// Default to the size of the database storage.
+	Storage string `json:"storage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// VolumeSnapshotClassName is the class of the snapshot taken when preUpgradeBackup is Snapshot.
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+}
+
+func (spec *ZlandoPostgreSQLSpec) GetPreUpgradeBackup() string {
+	if spec.Upgrade == nil || spec.Upgrade.PreUpgradeBackup == "" {
+		return PostgreSQLBackupDump
+	}
+
+	return spec.Upgrade.PreUpgradeBackup
 }
 
 type Storage struct {
@@ -3,6 +3,7 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/goharbor/harbor-operator/pkg/version"
 	"github.com/pkg/errors"
@@ -39,15 +40,25 @@ func (harborcluster *HarborCluster) Default() { //nolint:funlen
 	switch harborcluster.Spec.Cache.Kind {
 	case KindCacheRedis:
 		harborcluster.Spec.Cache.Spec.RedisFailover = nil
+		harborcluster.Spec.Cache.Spec.RedisReplication = nil
 	case KindCacheRedisFailover:
 		harborcluster.Spec.Cache.Spec.Redis = nil
+		harborcluster.Spec.Cache.Spec.RedisReplication = nil
+	case KindCacheRedisReplication:
+		harborcluster.Spec.Cache.Spec.Redis = nil
+		harborcluster.Spec.Cache.Spec.RedisFailover = nil
 	}
 
 	switch harborcluster.Spec.Database.Kind {
 	case KindDatabasePostgreSQL:
 		harborcluster.Spec.Database.Spec.ZlandoPostgreSQL = nil
+		harborcluster.Spec.Database.Spec.CloudNativePG = nil
 	case KindDatabaseZlandoPostgreSQL:
 		harborcluster.Spec.Database.Spec.PostgreSQL = nil
+		harborcluster.Spec.Database.Spec.CloudNativePG = nil
+	case KindDatabaseCloudNativePG:
+		harborcluster.Spec.Database.Spec.PostgreSQL = nil
+		harborcluster.Spec.Database.Spec.ZlandoPostgreSQL = nil
 	}
 
 	switch harborcluster.Spec.Storage.Kind {
@@ -141,6 +152,16 @@ func (harborcluster *HarborCluster) validate(old *HarborCluster) error {
 		allErrs = append(allErrs, err)
 	}
 
+	if err := harborcluster.Spec.Expose.Validate(); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	if err := harborcluster.Spec.Expose.ValidateInternalTLS(&harborcluster.Spec.InternalTLS); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	allErrs = append(allErrs, harborcluster.Spec.ValidateComponents()...)
+
 	// For database(psql), cache(Redis) and storage, either external services or in-cluster services MUST be configured
 	if err := harborcluster.validateStorage(); err != nil {
 		allErrs = append(allErrs, err)
@@ -176,6 +197,10 @@ func (harborcluster *HarborCluster) validate(old *HarborCluster) error {
 			"don't allow to switch database between incluster and external"))
 	}
 
+	if err := harborcluster.validateDatabaseUpgrade(old); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
 	if old.Spec.Storage.Kind != harborcluster.Spec.Storage.Kind {
 		allErrs = append(allErrs, field.Forbidden(
 			field.NewPath("spec").Child("storage"),
@@ -274,6 +299,55 @@ func (harborcluster *HarborCluster) validateDatabase() *field.Error {
 		return required(fp.Child("zlandoPostgreSQL"))
 	}
 
+	if harborcluster.Spec.Database.Kind == KindDatabaseCloudNativePG && harborcluster.Spec.Database.Spec.CloudNativePG == nil {
+		// Invalid and not acceptable
+		return required(fp.Child("cloudNativePG"))
+	}
+
+	return nil
+}
+
+func (harborcluster *HarborCluster) validateDatabaseUpgrade(old *HarborCluster) *field.Error {
+	if old.Spec.Database.Spec.CloudNativePG != nil && harborcluster.Spec.Database.Spec.CloudNativePG != nil {
+		oldVersion := old.Spec.Database.Spec.CloudNativePG.Version
+		newVersion := harborcluster.Spec.Database.Spec.CloudNativePG.Version
+
+		if oldVersion != newVersion {
+			fp := field.NewPath("spec").Child("database").Child("spec").Child("cloudNativePG").Child("version")
+
+			return field.Forbidden(fp, "the major version of a CloudNativePG database cannot be changed in place, backup and restore it into a new harbor cluster")
+		}
+
+		return nil
+	}
+
+	if old.Spec.Database.Spec.ZlandoPostgreSQL == nil || harborcluster.Spec.Database.Spec.ZlandoPostgreSQL == nil {
+		return nil
+	}
+
+	oldVersion := old.Spec.Database.Spec.ZlandoPostgreSQL.Version
+	newVersion := harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.Version
+
+	if oldVersion == "" || newVersion == "" {
+		return nil
+	}
+
+	fp := field.NewPath("spec").Child("database").Child("spec").Child("zlandoPostgreSql").Child("version")
+
+	oldMajor, err := strconv.Atoi(oldVersion)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	newMajor, err := strconv.Atoi(newVersion)
+	if err != nil {
+		return invalid(fp, newVersion, err.Error())
+	}
+
+	if newMajor < oldMajor {
+		return field.Forbidden(fp, fmt.Sprintf("downgrading postgresql from %s to %s is not supported", oldVersion, newVersion))
+	}
+
 	return nil
 }
 
@@ -293,6 +367,10 @@ func (harborcluster *HarborCluster) validateCache() *field.Error {
 		return required(fp.Child("redisFailover"))
 	}
 
+	if harborcluster.Spec.Cache.Kind == KindCacheRedisReplication && harborcluster.Spec.Cache.Spec.RedisReplication == nil {
+		return required(fp.Child("redisReplication"))
+	}
+
 	return nil
 }
 
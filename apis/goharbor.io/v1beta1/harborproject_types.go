@@ -2,6 +2,7 @@ package v1beta1
 
 import (
 	goyaml "gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8syaml "sigs.k8s.io/yaml"
 )
@@ -51,6 +52,20 @@ type HarborProjectSpec struct {
 	// Group or user memberships of the project.
 	// +kubebuilder:validation:Optional
 	HarborProjectMemberships []*HarborProjectMember `json:"memberships" yaml:"memberships"`
+	// Tag retention policy of the project. The rules and schedule of the policy are cleared when unset after being managed by the operator.
+	// +kubebuilder:validation:Optional
+	Retention *HarborProjectRetention `json:"retention,omitempty" yaml:"retention,omitempty"`
+	// Tag immutability rules of the project. The rules are deleted when unset after being managed by the operator.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=15
+	ImmutableTagRules []HarborProjectImmutableTagRule `json:"immutableTagRules,omitempty" yaml:"immutable_tag_rules,omitempty"`
+	// Webhook policies of the project, notifying endpoints of the events of the project.
+	// +kubebuilder:validation:Optional
+	Webhooks []HarborProjectWebhook `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+	// Configures the project as a pull-through cache of an upstream registry.
+	// The upstream registry of a project cannot be changed after its creation.
+	// +kubebuilder:validation:Optional
+	ProxyCache *HarborProjectProxyCache `json:"proxyCache,omitempty" yaml:"proxy_cache,omitempty"`
 	// HarborServerConfig contains the name of a HarborServerConfig resource describing the harbor instance to manage.
 	// +kubebuilder:validation:Required
 	HarborServerConfig string `json:"harborServerConfig"`
@@ -105,6 +120,171 @@ type HarborProjectMember struct {
 	Role string `json:"role" yaml:"role"`
 }
 
+// HarborProjectWebhook defines a webhook policy of a project.
+type HarborProjectWebhook struct {
+	// The name of the webhook policy, unique in the project.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name" yaml:"name"`
+	// The description of the webhook policy.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Whether the webhook policy is disabled.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// The events notified to the endpoint.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	EventTypes []HarborProjectWebhookEventType `json:"eventTypes" yaml:"event_types"`
+	// The format of the notifications, either `http` or `slack`.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;slack
+	// +kubebuilder:default=http
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// The URL of the endpoint.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.+"
+	Address string `json:"address" yaml:"address"`
+	// The key of a secret in the same namespace holding the value of the Authorization header sent to the endpoint.
+	// +kubebuilder:validation:Optional
+	AuthHeaderRef *corev1.SecretKeySelector `json:"authHeaderRef,omitempty" yaml:"auth_header_ref,omitempty"`
+	// Whether the certificate of the endpoint is not verified.
+	// +kubebuilder:validation:Optional
+	SkipCertVerify bool `json:"skipCertVerify,omitempty" yaml:"skip_cert_verify,omitempty"`
+}
+
+// HarborProjectWebhookEventType is an event type of Harbor notified by webhooks.
+// +kubebuilder:validation:Enum=PUSH_ARTIFACT;PULL_ARTIFACT;DELETE_ARTIFACT;SCANNING_COMPLETED;SCANNING_FAILED;SCANNING_STOPPED;QUOTA_EXCEED;QUOTA_WARNING;REPLICATION;TAG_RETENTION
+type HarborProjectWebhookEventType string
+
+// HarborProjectProxyCache defines the upstream registry of a proxy cache project.
+type HarborProjectProxyCache struct {
+	// The name of a HarborRegistryEndpoint in the same namespace describing the upstream registry.
+	// Exactly one of registryEndpointRef and registry has to be set.
+	// +kubebuilder:validation:Optional
+	RegistryEndpointRef string `json:"registryEndpointRef,omitempty" yaml:"registry_endpoint_ref,omitempty"`
+	// The upstream registry, registered in Harbor by the operator under the name `<projectName>-proxy-cache`.
+	// Exactly one of registryEndpointRef and registry has to be set.
+	// +kubebuilder:validation:Optional
+	Registry *HarborProjectProxyCacheRegistry `json:"registry,omitempty" yaml:"registry,omitempty"`
+	// The bandwidth limit of the pulls from the upstream registry in Kbps, -1 for no limit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:default=-1
+	BandwidthLimit int32 `json:"bandwidthLimit,omitempty" yaml:"bandwidth_limit,omitempty"`
+}
+
+// HarborProjectProxyCacheRegistry describes the upstream registry of a proxy cache project.
+type HarborProjectProxyCacheRegistry struct {
+	// The provider type of the registry.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=harbor;docker-hub;docker-registry;google-gcr;aws-ecr;azure-acr;jfrog-artifactory;quay;github-ghcr
+	Type string `json:"type" yaml:"type"`
+	// The URL of the registry.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.+"
+	URL string `json:"url" yaml:"url"`
+	// Whether the certificate of the registry is verified.
+	// +kubebuilder:validation:Optional
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// The name of a secret in the same namespace holding the credential of the registry.
+	// The access key and the access secret are read from the `accessKey` and `accessSecret` keys.
+	// Anonymous access is used if empty.
+	// +kubebuilder:validation:Optional
+	CredentialRef string `json:"credentialRef,omitempty" yaml:"credential_ref,omitempty"`
+	// The type of the credential.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=basic;oauth
+	// +kubebuilder:default=basic
+	CredentialType string `json:"credentialType,omitempty" yaml:"credential_type,omitempty"`
+}
+
+// GetProxyCacheRegistryName returns the name of the registry endpoint registered for the inline upstream registry.
+func (h *HarborProject) GetProxyCacheRegistryName() string {
+	return h.Spec.ProjectName + "-proxy-cache"
+}
+
+// HarborProjectRetention defines the tag retention policy of a project.
+// Artifacts matching none of the rules are deleted when the policy runs.
+type HarborProjectRetention struct {
+	// Cron schedule of the policy, in the 6 fields format of Harbor (`second minute hour day month weekday`).
+	// The policy is only run manually if empty.
+	// +kubebuilder:validation:Optional
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Retention rules, an artifact is retained if it matches any of the rules.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=15
+	Rules []HarborProjectRetentionRule `json:"rules" yaml:"rules"`
+}
+
+// HarborProjectRetentionTemplate is the kind of artifacts retained by a retention rule.
+// +kubebuilder:validation:Enum=always;latestPushedK;latestPulledN;nDaysSinceLastPush;nDaysSinceLastPull
+type HarborProjectRetentionTemplate string
+
+const (
+	// HarborProjectRetainAlways retains all the artifacts.
+	HarborProjectRetainAlways HarborProjectRetentionTemplate = "always"
+	// HarborProjectRetainLatestPushed retains the most recently pushed artifacts.
+	HarborProjectRetainLatestPushed HarborProjectRetentionTemplate = "latestPushedK"
+	// HarborProjectRetainLatestPulled retains the most recently pulled artifacts.
+	HarborProjectRetainLatestPulled HarborProjectRetentionTemplate = "latestPulledN"
+	// HarborProjectRetainPushedWithinDays retains the artifacts pushed within the last days.
+	HarborProjectRetainPushedWithinDays HarborProjectRetentionTemplate = "nDaysSinceLastPush"
+	// HarborProjectRetainPulledWithinDays retains the artifacts pulled within the last days.
+	HarborProjectRetainPulledWithinDays HarborProjectRetentionTemplate = "nDaysSinceLastPull"
+)
+
+// HarborProjectRetentionRule defines the artifacts retained by a retention rule.
+type HarborProjectRetentionRule struct {
+	// Whether the rule is disabled.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// The kind of artifacts retained among the matching ones.
+	// +kubebuilder:validation:Required
+	Template HarborProjectRetentionTemplate `json:"template" yaml:"template"`
+	// The number of artifacts or of days of the template. Ignored by the always template.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Count int64 `json:"count,omitempty" yaml:"count,omitempty"`
+	// The repositories the rule applies to.
+	// +kubebuilder:validation:Optional
+	Repositories HarborProjectRuleSelector `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	// The tags the rule applies to.
+	// +kubebuilder:validation:Optional
+	Tags HarborProjectRuleSelector `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Whether the untagged artifacts are matched by the rule too.
+	// +kubebuilder:validation:Optional
+	Untagged bool `json:"untagged,omitempty" yaml:"untagged,omitempty"`
+}
+
+// HarborProjectImmutableTagRule defines the tags which cannot be overwritten or deleted.
+type HarborProjectImmutableTagRule struct {
+	// Whether the rule is disabled.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// The repositories the rule applies to.
+	// +kubebuilder:validation:Optional
+	Repositories HarborProjectRuleSelector `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	// The immutable tags.
+	// +kubebuilder:validation:Optional
+	Tags HarborProjectRuleSelector `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// HarborProjectRuleSelector selects repositories or tags with a doublestar pattern.
+type HarborProjectRuleSelector struct {
+	// Whether the names matching the pattern are selected or excluded.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=matches;excludes
+	// +kubebuilder:default=matches
+	Decoration string `json:"decoration,omitempty" yaml:"decoration,omitempty"`
+	// Doublestar pattern of the names, e.g. `**`, `library/*` or `{v1,v2}*`. Comma separated patterns must be enclosed in braces.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="**"
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
 // HarborProjectStatusType defines the status type of project.
 type HarborProjectStatusType string
 
@@ -131,6 +311,21 @@ type HarborProjectStatus struct {
 	// MembershipHash provides a way to quickly notice changes in project membership.
 	// +kubebuilder:validation:Optional
 	MembershipHash string `json:"membershipHash,omitempty"`
+	// ProxyCacheRegistryID is the ID of the upstream registry of a proxy cache project.
+	// +kubebuilder:validation:Optional
+	ProxyCacheRegistryID int64 `json:"proxyCacheRegistryID,omitempty"`
+	// WebhookPolicies are the IDs of the webhook policies managed by the operator, by name.
+	// +kubebuilder:validation:Optional
+	WebhookPolicies map[string]int64 `json:"webhookPolicies,omitempty"`
+	// RetentionID is the ID of the project's tag retention policy.
+	// +kubebuilder:validation:Optional
+	RetentionID int64 `json:"retentionID,omitempty"`
+	// RetentionHash provides a way to quickly notice changes in the tag retention policy.
+	// +kubebuilder:validation:Optional
+	RetentionHash string `json:"retentionHash,omitempty"`
+	// ImmutableTagRulesHash provides a way to quickly notice changes in the tag immutability rules.
+	// +kubebuilder:validation:Optional
+	ImmutableTagRulesHash string `json:"immutableTagRulesHash,omitempty"`
 	// Reason represents status reason.
 	// +kubebuilder:validation:Optional
 	Reason string `json:"reason,omitempty"`
@@ -63,6 +63,41 @@ func (hp *HarborProject) Validate(old *HarborProject) error {
 		if hp.Spec.HarborServerConfig != old.Spec.HarborServerConfig {
 			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("harborServerConfig"), hp.Spec.HarborServerConfig, "field cannot be changed after initial creation"))
 		}
+
+		if (hp.Spec.ProxyCache == nil) != (old.Spec.ProxyCache == nil) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("proxyCache"), "a project cannot be turned into or from a proxy cache after initial creation"))
+		} else if hp.Spec.ProxyCache != nil &&
+			(hp.Spec.ProxyCache.RegistryEndpointRef != old.Spec.ProxyCache.RegistryEndpointRef || (hp.Spec.ProxyCache.Registry == nil) != (old.Spec.ProxyCache.Registry == nil)) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("proxyCache"), "the upstream registry cannot be changed after initial creation"))
+		}
+	}
+
+	if proxyCache := hp.Spec.ProxyCache; proxyCache != nil && (proxyCache.RegistryEndpointRef == "") == (proxyCache.Registry == nil) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("proxyCache"), proxyCache.RegistryEndpointRef, "exactly one of registryEndpointRef and registry has to be set"))
+	}
+
+	webhookNames := map[string]bool{}
+
+	for i, webhook := range hp.Spec.Webhooks {
+		if webhookNames[webhook.Name] {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("spec").Child("webhooks").Index(i).Child("name"), webhook.Name))
+		}
+
+		webhookNames[webhook.Name] = true
+	}
+
+	if retention := hp.Spec.Retention; retention != nil {
+		path := field.NewPath("spec").Child("retention")
+
+		if retention.Schedule != "" {
+			allErrs = append(allErrs, validateHarborCron(path.Child("schedule"), retention.Schedule)...)
+		}
+
+		for i, rule := range retention.Rules {
+			if rule.Template != HarborProjectRetainAlways && rule.Count <= 0 {
+				allErrs = append(allErrs, field.Invalid(path.Child("rules").Index(i).Child("count"), rule.Count, "must be positive for the "+string(rule.Template)+" template"))
+			}
+		}
 	}
 
 	if len(allErrs) == 0 {
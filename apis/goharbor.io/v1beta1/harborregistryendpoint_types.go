@@ -0,0 +1,121 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +resource:path=harborregistryendpoint
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hre"
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`,description="Registry provider type"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`,description="Registry URL"
+// +kubebuilder:printcolumn:name="HarborServerConfig",type=string,JSONPath=`.spec.harborServerConfig`,description="HarborServerConfiguration name"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="HarborRegistryEndpoint status"
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`,description="Registry health reported by Harbor",priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// HarborRegistryEndpoint is the Schema for the registry endpoints used by Harbor replications.
+type HarborRegistryEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRegistryEndpointSpec `json:"spec,omitempty"`
+
+	Status HarborRegistryEndpointStatus `json:"status,omitempty"`
+}
+
+// HarborRegistryEndpointSpec defines the spec of HarborRegistryEndpoint.
+type HarborRegistryEndpointSpec struct {
+	// HarborServerConfig contains the name of a HarborServerConfig resource describing the harbor instance to manage.
+	// +kubebuilder:validation:Required
+	HarborServerConfig string `json:"harborServerConfig"`
+	// The name of the registry endpoint in Harbor. Defaults to the name of the resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name,omitempty"`
+	// The description of the registry endpoint.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// The provider type of the registry.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=harbor;docker-hub;docker-registry;huawei-SWR;google-gcr;aws-ecr;azure-acr;ali-acr;jfrog-artifactory;quay;gitlab;github-ghcr;tencent-tcr
+	Type string `json:"type"`
+	// The URL of the registry.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.+"
+	URL string `json:"url"`
+	// Whether the certificate of the registry is verified.
+	// +kubebuilder:validation:Optional
+	Insecure bool `json:"insecure,omitempty"`
+	// The name of a secret in the same namespace holding the credential of the registry.
+	// The access key and the access secret are read from the `accessKey` and `accessSecret` keys.
+	// Anonymous access is used if empty.
+	// +kubebuilder:validation:Optional
+	CredentialRef string `json:"credentialRef,omitempty"`
+	// The type of the credential.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=basic;oauth
+	// +kubebuilder:default=basic
+	CredentialType string `json:"credentialType,omitempty"`
+}
+
+// GetRegistryName returns the name of the registry endpoint in Harbor.
+func (h *HarborRegistryEndpoint) GetRegistryName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.GetName()
+}
+
+// HarborReplicationStatusType defines the status type of the replication resources.
+type HarborReplicationStatusType string
+
+const (
+	// HarborReplicationStatusReady represents ready status.
+	HarborReplicationStatusReady HarborReplicationStatusType = "Success"
+	// HarborReplicationStatusFail represents fail status.
+	HarborReplicationStatusFail HarborReplicationStatusType = "Fail"
+	// HarborReplicationStatusUnknown represents unknown status.
+	HarborReplicationStatusUnknown HarborReplicationStatusType = "Unknown"
+)
+
+// HarborRegistryEndpointStatus defines the status of HarborRegistryEndpoint.
+type HarborRegistryEndpointStatus struct {
+	// Status represents harbor registry endpoint status.
+	// +kubebuilder:validation:Optional
+	Status HarborReplicationStatusType `json:"status,omitempty"`
+	// RegistryID represents ID of the managed registry endpoint.
+	// +kubebuilder:validation:Optional
+	RegistryID int64 `json:"registryID,omitempty"`
+	// Health is the health of the registry as reported by Harbor.
+	// +kubebuilder:validation:Optional
+	Health string `json:"health,omitempty"`
+	// Reason represents status reason.
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+	// Message provides human-readable message.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// LastApplyTime represents the last apply configuration time.
+	// +kubebuilder:validation:Optional
+	LastApplyTime *metav1.Time `json:"lastApplyTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// HarborRegistryEndpointList contains a list of HarborRegistryEndpoints.
+type HarborRegistryEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRegistryEndpoint `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRegistryEndpoint{}, &HarborRegistryEndpointList{})
+}
@@ -0,0 +1,73 @@
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hrelog = logf.Log.WithName("harborregistryendpoint-resource")
+
+func (hre *HarborRegistryEndpoint) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hre).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-harborregistryendpoint,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborregistryendpoints,versions=v1beta1,name=vharborregistryendpoint.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborRegistryEndpoint{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hre *HarborRegistryEndpoint) ValidateCreate() error {
+	hrelog.Info("validate create", "name", hre.Name)
+
+	return hre.Validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hre *HarborRegistryEndpoint) ValidateUpdate(old runtime.Object) error {
+	hrelog.Info("validate update", "name", hre.Name)
+
+	obj, ok := old.(*HarborRegistryEndpoint)
+	if !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return hre.Validate(obj)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hre *HarborRegistryEndpoint) ValidateDelete() error {
+	hrelog.Info("validate delete", "name", hre.Name)
+
+	return nil
+}
+
+func (hre *HarborRegistryEndpoint) Validate(old *HarborRegistryEndpoint) error {
+	var allErrs field.ErrorList
+
+	if old != nil { // update harborregistryendpoint resource
+		if hre.Spec.HarborServerConfig != old.Spec.HarborServerConfig {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("harborServerConfig"), hre.Spec.HarborServerConfig, "field cannot be changed after initial creation"))
+		}
+
+		if hre.Spec.Type != old.Spec.Type {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("type"), hre.Spec.Type, "field cannot be changed after initial creation"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborRegistryEndpoint"}, hre.Name, allErrs)
+}
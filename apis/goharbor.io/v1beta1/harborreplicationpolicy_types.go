@@ -0,0 +1,207 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +resource:path=harborreplicationpolicy
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hrp"
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.sourceRegistryRef`,description="Source HarborRegistryEndpoint, the local Harbor if empty"
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destinationRegistryRef`,description="Destination HarborRegistryEndpoint, the local Harbor if empty"
+// +kubebuilder:printcolumn:name="Trigger",type=string,JSONPath=`.spec.trigger.type`,description="Replication trigger type"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="HarborReplicationPolicy status"
+// +kubebuilder:printcolumn:name="Last Execution",type=string,JSONPath=`.status.lastExecution.status`,description="Status of the last replication execution"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// HarborReplicationPolicy is the Schema for the Harbor replication policies.
+type HarborReplicationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborReplicationPolicySpec `json:"spec,omitempty"`
+
+	Status HarborReplicationPolicyStatus `json:"status,omitempty"`
+}
+
+const (
+	ReplicationTriggerManual     = "manual"
+	ReplicationTriggerEventBased = "event_based"
+	ReplicationTriggerScheduled  = "scheduled"
+)
+
+const (
+	ReplicationFilterName     = "name"
+	ReplicationFilterTag      = "tag"
+	ReplicationFilterLabel    = "label"
+	ReplicationFilterResource = "resource"
+)
+
+// HarborReplicationPolicySpec defines the spec of HarborReplicationPolicy.
+type HarborReplicationPolicySpec struct {
+	// HarborServerConfig contains the name of a HarborServerConfig resource describing the harbor instance to manage.
+	// +kubebuilder:validation:Required
+	HarborServerConfig string `json:"harborServerConfig"`
+	// The name of the replication policy in Harbor. Defaults to the name of the resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name,omitempty"`
+	// The description of the replication policy.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// The name of the HarborRegistryEndpoint in the same namespace to pull the artifacts from.
+	// The artifacts are pulled from the local Harbor if empty.
+	// Exactly one of sourceRegistryRef and destinationRegistryRef has to be set.
+	// +kubebuilder:validation:Optional
+	SourceRegistryRef string `json:"sourceRegistryRef,omitempty"`
+	// The name of the HarborRegistryEndpoint in the same namespace to push the artifacts to.
+	// The artifacts are pushed to the local Harbor if empty.
+	// Exactly one of sourceRegistryRef and destinationRegistryRef has to be set.
+	// +kubebuilder:validation:Optional
+	DestinationRegistryRef string `json:"destinationRegistryRef,omitempty"`
+	// The namespace the artifacts are replicated to. The source namespace is kept if empty.
+	// +kubebuilder:validation:Optional
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+	// How the destination namespace is flattened: -1 replaces all the levels of the source repository path,
+	// 0 keeps it unchanged and n replaces its first n levels.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=3
+	DestinationNamespaceReplaceCount *int8 `json:"destinationNamespaceReplaceCount,omitempty"`
+	// The filters selecting the replicated artifacts.
+	// +kubebuilder:validation:Optional
+	Filters []HarborReplicationFilter `json:"filters,omitempty"`
+	// The trigger of the replication.
+	// +kubebuilder:validation:Required
+	Trigger HarborReplicationTrigger `json:"trigger"`
+	// Whether the artifacts existing at the destination are overwritten.
+	// +kubebuilder:validation:Optional
+	Override bool `json:"override,omitempty"`
+	// Whether the deletion of the artifacts is replicated. Only used by event based replications.
+	// +kubebuilder:validation:Optional
+	ReplicateDeletion bool `json:"replicateDeletion,omitempty"`
+	// Whether the policy is enabled.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	Enabled *bool `json:"enabled,omitempty"`
+	// The bandwidth limit of the replication in KB/s, -1 for no limit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	Speed *int32 `json:"speed,omitempty"`
+	// Whether the artifacts are copied by chunk.
+	// +kubebuilder:validation:Optional
+	CopyByChunk *bool `json:"copyByChunk,omitempty"`
+}
+
+// GetPolicyName returns the name of the replication policy in Harbor.
+func (h *HarborReplicationPolicy) GetPolicyName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.GetName()
+}
+
+// HarborReplicationFilter selects the artifacts of a replication.
+type HarborReplicationFilter struct {
+	// The type of the filter.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=name;tag;label;resource
+	Type string `json:"type"`
+	// The value of the filter. Name and tag filters accept doublestar patterns,
+	// label filters a comma separated list of labels and resource filters either `image` or `artifact`.
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+	// Whether the matching artifacts are included or excluded. Only used by tag and label filters.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=matches;excludes
+	Decoration string `json:"decoration,omitempty"`
+}
+
+// HarborReplicationTrigger defines when a replication is run.
+type HarborReplicationTrigger struct {
+	// The type of the trigger.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=manual;event_based;scheduled
+	// +kubebuilder:default=manual
+	Type string `json:"type,omitempty"`
+	// The schedule of a scheduled replication, in the 6 fields cron format of Harbor (seconds first). Times are in UTC.
+	// +kubebuilder:validation:Optional
+	Cron string `json:"cron,omitempty"`
+}
+
+// HarborReplicationPolicyStatus defines the status of HarborReplicationPolicy.
+type HarborReplicationPolicyStatus struct {
+	// Status represents harbor replication policy status.
+	// +kubebuilder:validation:Optional
+	Status HarborReplicationStatusType `json:"status,omitempty"`
+	// PolicyID represents ID of the managed replication policy.
+	// +kubebuilder:validation:Optional
+	PolicyID int64 `json:"policyID,omitempty"`
+	// LastExecution is the last execution of the replication policy.
+	// +kubebuilder:validation:Optional
+	LastExecution *HarborReplicationExecution `json:"lastExecution,omitempty"`
+	// Reason represents status reason.
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+	// Message provides human-readable message.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// LastApplyTime represents the last apply configuration time.
+	// +kubebuilder:validation:Optional
+	LastApplyTime *metav1.Time `json:"lastApplyTime,omitempty"`
+}
+
+// HarborReplicationExecution describes an execution of a replication policy.
+type HarborReplicationExecution struct {
+	// ID of the execution.
+	ID int64 `json:"id"`
+	// Status of the execution.
+	// +kubebuilder:validation:Optional
+	Status string `json:"status,omitempty"`
+	// StatusText is the detail of the status.
+	// +kubebuilder:validation:Optional
+	StatusText string `json:"statusText,omitempty"`
+	// Trigger of the execution.
+	// +kubebuilder:validation:Optional
+	Trigger string `json:"trigger,omitempty"`
+	// StartTime is the time the execution started.
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time the execution ended.
+	// +kubebuilder:validation:Optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Total is the count of the tasks of the execution.
+	// +kubebuilder:validation:Optional
+	Total int64 `json:"total,omitempty"`
+	// Succeed is the count of the succeeded tasks.
+	// +kubebuilder:validation:Optional
+	Succeed int64 `json:"succeed,omitempty"`
+	// Failed is the count of the failed tasks.
+	// +kubebuilder:validation:Optional
+	Failed int64 `json:"failed,omitempty"`
+	// InProgress is the count of the running tasks.
+	// +kubebuilder:validation:Optional
+	InProgress int64 `json:"inProgress,omitempty"`
+	// Stopped is the count of the stopped tasks.
+	// +kubebuilder:validation:Optional
+	Stopped int64 `json:"stopped,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// HarborReplicationPolicyList contains a list of HarborReplicationPolicies.
+type HarborReplicationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborReplicationPolicy `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborReplicationPolicy{}, &HarborReplicationPolicyList{})
+}
@@ -0,0 +1,128 @@
+package v1beta1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goharbor/harbor-operator/pkg/utils/cron"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hrplog = logf.Log.WithName("harborreplicationpolicy-resource")
+
+func (hrp *HarborReplicationPolicy) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hrp).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-harborreplicationpolicy,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborreplicationpolicies,versions=v1beta1,name=vharborreplicationpolicy.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborReplicationPolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hrp *HarborReplicationPolicy) ValidateCreate() error {
+	hrplog.Info("validate create", "name", hrp.Name)
+
+	return hrp.Validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hrp *HarborReplicationPolicy) ValidateUpdate(old runtime.Object) error {
+	hrplog.Info("validate update", "name", hrp.Name)
+
+	obj, ok := old.(*HarborReplicationPolicy)
+	if !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return hrp.Validate(obj)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hrp *HarborReplicationPolicy) ValidateDelete() error {
+	hrplog.Info("validate delete", "name", hrp.Name)
+
+	return nil
+}
+
+func (hrp *HarborReplicationPolicy) Validate(old *HarborReplicationPolicy) error {
+	var allErrs field.ErrorList
+
+	spec := field.NewPath("spec")
+
+	if old != nil { // update harborreplicationpolicy resource
+		if hrp.Spec.HarborServerConfig != old.Spec.HarborServerConfig {
+			allErrs = append(allErrs, field.Invalid(spec.Child("harborServerConfig"), hrp.Spec.HarborServerConfig, "field cannot be changed after initial creation"))
+		}
+	}
+
+	if (hrp.Spec.SourceRegistryRef == "") == (hrp.Spec.DestinationRegistryRef == "") {
+		allErrs = append(allErrs, field.Invalid(spec.Child("sourceRegistryRef"), hrp.Spec.SourceRegistryRef, "exactly one of sourceRegistryRef and destinationRegistryRef has to be set"))
+	}
+
+	for i, filter := range hrp.Spec.Filters {
+		allErrs = append(allErrs, filter.validate(spec.Child("filters").Index(i))...)
+	}
+
+	allErrs = append(allErrs, hrp.Spec.Trigger.validate(spec.Child("trigger"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborReplicationPolicy"}, hrp.Name, allErrs)
+}
+
+func (f *HarborReplicationFilter) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch f.Type {
+	case ReplicationFilterResource:
+		if f.Value != "image" && f.Value != "artifact" {
+			allErrs = append(allErrs, field.NotSupported(path.Child("value"), f.Value, []string{"image", "artifact"}))
+		}
+	case ReplicationFilterTag, ReplicationFilterLabel:
+	default:
+		if f.Decoration != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("decoration"), "only tag and label filters can be decorated"))
+		}
+	}
+
+	if strings.TrimSpace(f.Value) == "" {
+		allErrs = append(allErrs, field.Required(path.Child("value"), "the value of the filter is empty"))
+	}
+
+	return allErrs
+}
+
+func (t *HarborReplicationTrigger) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if t.Type != ReplicationTriggerScheduled {
+		if t.Cron != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("cron"), "only scheduled triggers have a schedule"))
+		}
+
+		return allErrs
+	}
+
+	return validateHarborCron(path.Child("cron"), t.Cron)
+}
+
+// validateHarborCron validates a cron expression in the 6 fields format of Harbor, seconds first.
+func validateHarborCron(path *field.Path, value string) field.ErrorList {
+	if _, err := cron.ParseWithSeconds(value); err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+
+	return nil
+}
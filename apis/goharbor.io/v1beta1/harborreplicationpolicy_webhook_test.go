@@ -0,0 +1,67 @@
+package v1beta1_test
+
+import (
+	"github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("HarborReplicationPolicy webhook", func() {
+	newPolicy := func(trigger v1beta1.HarborReplicationTrigger) *v1beta1.HarborReplicationPolicy {
+		return &v1beta1.HarborReplicationPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nightly",
+				Namespace: "harbor",
+			},
+			Spec: v1beta1.HarborReplicationPolicySpec{
+				HarborServerConfig: "harbor",
+				SourceRegistryRef:  "docker-hub",
+				Trigger:            trigger,
+			},
+		}
+	}
+
+	DescribeTable("Scheduled triggers",
+		func(cron string, valid bool) {
+			err := newPolicy(v1beta1.HarborReplicationTrigger{
+				Type: v1beta1.ReplicationTriggerScheduled,
+				Cron: cron,
+			}).ValidateCreate()
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("daily", "0 0 2 * * *", true),
+		Entry("every 30 seconds", "*/30 * * * * *", true),
+		Entry("seconds out of range", "61 0 2 * * *", false),
+		Entry("invalid seconds", "x 0 2 * * *", false),
+		Entry("without seconds", "0 2 * * *", false),
+		Entry("empty", "", false),
+	)
+
+	It("Should refuse a schedule on manual triggers", func() {
+		Expect(newPolicy(v1beta1.HarborReplicationTrigger{
+			Type: v1beta1.ReplicationTriggerManual,
+			Cron: "0 0 2 * * *",
+		}).ValidateCreate()).To(HaveOccurred())
+	})
+
+	It("Should require exactly one remote registry", func() {
+		policy := newPolicy(v1beta1.HarborReplicationTrigger{Type: v1beta1.ReplicationTriggerManual})
+		policy.Spec.DestinationRegistryRef = "quay"
+
+		Expect(policy.ValidateCreate()).To(HaveOccurred())
+	})
+
+	It("Should refuse to change the Harbor server configuration", func() {
+		old := newPolicy(v1beta1.HarborReplicationTrigger{Type: v1beta1.ReplicationTriggerManual})
+
+		policy := old.DeepCopy()
+		policy.Spec.HarborServerConfig = "other"
+
+		Expect(policy.ValidateUpdate(old)).To(HaveOccurred())
+	})
+})
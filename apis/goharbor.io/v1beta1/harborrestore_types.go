@@ -0,0 +1,72 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HarborRestoreSpec defines the desired state of HarborRestore.
+type HarborRestoreSpec struct {
+	// +kubebuilder:validation:Required
+	// HarborClusterRef is the name of the HarborCluster to restore into, in the same namespace.
+	// Its in-cluster services must be ready, their data is replaced by the backup.
+	HarborClusterRef string `json:"harborClusterRef"`
+
+	// +kubebuilder:validation:Required
+	// BackupRef is the name of a completed HarborBackup of the namespace.
+	BackupRef string `json:"backupRef"`
+
+	// +kubebuilder:validation:Optional
+	// Components to restore. All the components of the backup are restored if empty.
+	Components []HarborBackupComponent `json:"components,omitempty"`
+}
+
+// HarborRestoreStatus defines the observed state of HarborRestore.
+type HarborRestoreStatus struct {
+	// +kubebuilder:validation:Optional
+	Phase HarborBackupPhase `json:"phase,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Components is the status of each restored component.
+	Components []HarborBackupComponentStatus `json:"components,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hrs"
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.harborClusterRef`,description="The HarborCluster restored",priority=0
+// +kubebuilder:printcolumn:name="Backup",type=string,JSONPath=`.spec.backupRef`,description="The HarborBackup restored",priority=0
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The phase of the restore",priority=0
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// HarborRestore is the Schema for the restores of a HarborBackup into a HarborCluster.
+type HarborRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborRestoreSpec   `json:"spec,omitempty"`
+	Status HarborRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HarborRestoreList contains a list of HarborRestore.
+type HarborRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRestore `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRestore{}, &HarborRestoreList{})
+}
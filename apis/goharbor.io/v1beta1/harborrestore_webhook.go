@@ -0,0 +1,60 @@
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hrslog = logf.Log.WithName("harborrestore-resource")
+
+func (hr *HarborRestore) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hr).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=update,path=/validate-goharbor-io-v1beta1-harborrestore,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborrestores,versions=v1beta1,name=vharborrestore.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborRestore{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hr *HarborRestore) ValidateCreate() error {
+	hrslog.Info("validate create", "name", hr.Name)
+
+	return nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hr *HarborRestore) ValidateUpdate(old runtime.Object) error {
+	hrslog.Info("validate update", "name", hr.Name)
+
+	obj, ok := old.(*HarborRestore)
+	if !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	if equality.Semantic.DeepEqual(hr.Spec, obj.Spec) {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborRestore"}, hr.Name, field.ErrorList{
+		field.Forbidden(field.NewPath("spec"), "the spec of a restore cannot be changed"),
+	})
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hr *HarborRestore) ValidateDelete() error {
+	hrslog.Info("validate delete", "name", hr.Name)
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +resource:path=harborrobotaccount
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="hra"
+// +kubebuilder:printcolumn:name="Robot",type=string,JSONPath=`.status.robotName`,description="Robot account name in Harbor"
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secret.name`,description="Secret holding the credential"
+// +kubebuilder:printcolumn:name="HarborServerConfig",type=string,JSONPath=`.spec.harborServerConfig`,description="HarborServerConfiguration name"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="HarborRobotAccount status"
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,description="Expiration time of the robot account"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// HarborRobotAccount is the Schema for the Harbor system level robot accounts.
+type HarborRobotAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HarborRobotAccountSpec `json:"spec,omitempty"`
+
+	Status HarborRobotAccountStatus `json:"status,omitempty"`
+}
+
+// RobotAccountSecretFormat is the format of the secret holding the credential of a robot account.
+// +kubebuilder:validation:Enum=dockerconfigjson;basic-auth;plain
+type RobotAccountSecretFormat string
+
+const (
+	// RobotAccountSecretDockerConfigJSON is a kubernetes.io/dockerconfigjson secret, usable as image pull secret.
+	RobotAccountSecretDockerConfigJSON RobotAccountSecretFormat = "dockerconfigjson"
+	// RobotAccountSecretBasicAuth is a kubernetes.io/basic-auth secret with the username and password keys.
+	RobotAccountSecretBasicAuth RobotAccountSecretFormat = "basic-auth"
+	// RobotAccountSecretPlain is an Opaque secret with the accessKey and accessSecret keys,
+	// usable as credential of HarborRegistryEndpoint and HarborServerConfiguration.
+	RobotAccountSecretPlain RobotAccountSecretFormat = "plain"
+)
+
+// HarborRobotAccountSpec defines the spec of HarborRobotAccount.
+type HarborRobotAccountSpec struct {
+	// HarborServerConfig contains the name of a HarborServerConfig resource describing the harbor instance to manage.
+	// +kubebuilder:validation:Required
+	HarborServerConfig string `json:"harborServerConfig"`
+	// The name of the robot account in Harbor, without the robot prefix. Defaults to the name of the resource.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^[a-z0-9]+(?:[._-][a-z0-9]+)*$"
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name,omitempty"`
+	// The description of the robot account.
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// The validity of the robot account in days, -1 for never expiring robot accounts.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:default=-1
+	Duration int64 `json:"duration,omitempty"`
+	// Whether the robot account is disabled.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+	// The permissions of the robot account.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Permissions []HarborRobotPermission `json:"permissions"`
+	// The secret the credential of the robot account is written to.
+	// +kubebuilder:validation:Optional
+	Secret HarborRobotAccountSecret `json:"secret,omitempty"`
+	// How long before the expiration of the robot account its secret is rotated.
+	// The robot account is renewed for another duration by the rotation.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="72h"
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+}
+
+// GetRobotName returns the name of the robot account in Harbor, without the robot prefix.
+func (h *HarborRobotAccount) GetRobotName() string {
+	if h.Spec.Name != "" {
+		return h.Spec.Name
+	}
+
+	return h.GetName()
+}
+
+// GetSecretName returns the name of the secret holding the credential of the robot account.
+func (h *HarborRobotAccount) GetSecretName() string {
+	if h.Spec.Secret.Name != "" {
+		return h.Spec.Secret.Name
+	}
+
+	return h.GetName()
+}
+
+// HarborRobotPermission grants accesses on a namespace of Harbor.
+type HarborRobotPermission struct {
+	// The kind of the namespace, either `project` or `system`.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=project;system
+	// +kubebuilder:default=project
+	Kind string `json:"kind,omitempty"`
+	// The namespace, the name of a project or `*` for all the projects. Always `/` for the system kind.
+	// +kubebuilder:validation:Required
+	Namespace string `json:"namespace"`
+	// The accesses granted on the namespace.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Access []HarborRobotAccess `json:"access"`
+}
+
+// HarborRobotAccess is an action on a Harbor resource.
+type HarborRobotAccess struct {
+	// The resource, e.g. `repository`, `artifact`, `tag` or `helm-chart`.
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+	// The action, e.g. `pull`, `push`, `list`, `read`, `create` or `delete`.
+	// +kubebuilder:validation:Required
+	Action string `json:"action"`
+	// The effect of the access.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=allow;deny
+	Effect string `json:"effect,omitempty"`
+}
+
+// HarborRobotAccountSecret describes the secret holding the credential of a robot account.
+type HarborRobotAccountSecret struct {
+	// The name of the secret in the namespace of the resource. Defaults to the name of the resource.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// The format of the secret.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=dockerconfigjson
+	Format RobotAccountSecretFormat `json:"format,omitempty"`
+}
+
+// HarborRobotAccountStatus defines the status of HarborRobotAccount.
+type HarborRobotAccountStatus struct {
+	// Status represents harbor robot account status.
+	// +kubebuilder:validation:Optional
+	Status HarborRobotAccountStatusType `json:"status,omitempty"`
+	// RobotID represents ID of the managed robot account.
+	// +kubebuilder:validation:Optional
+	RobotID int64 `json:"robotID,omitempty"`
+	// RobotName is the full name of the robot account, used as username.
+	// +kubebuilder:validation:Optional
+	RobotName string `json:"robotName,omitempty"`
+	// ExpiresAt is the expiration time of the robot account, empty if it never expires.
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// LastRotationTime is the last time the secret of the robot account was generated.
+	// +kubebuilder:validation:Optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// Reason represents status reason.
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+	// Message provides human-readable message.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// LastApplyTime represents the last apply configuration time.
+	// +kubebuilder:validation:Optional
+	LastApplyTime *metav1.Time `json:"lastApplyTime,omitempty"`
+}
+
+// HarborRobotAccountStatusType defines the status type of robot account.
+type HarborRobotAccountStatusType string
+
+const (
+	// HarborRobotAccountStatusReady represents ready status.
+	HarborRobotAccountStatusReady HarborRobotAccountStatusType = "Success"
+	// HarborRobotAccountStatusFail represents fail status.
+	HarborRobotAccountStatusFail HarborRobotAccountStatusType = "Fail"
+	// HarborRobotAccountStatusUnknown represents unknown status.
+	HarborRobotAccountStatusUnknown HarborRobotAccountStatusType = "Unknown"
+)
+
+// +kubebuilder:object:root=true
+// HarborRobotAccountList contains a list of HarborRobotAccounts.
+type HarborRobotAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HarborRobotAccount `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&HarborRobotAccount{}, &HarborRobotAccountList{})
+}
@@ -0,0 +1,100 @@
+package v1beta1
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var hralog = logf.Log.WithName("harborrobotaccount-resource")
+
+const robotDurationUnit = 24 * time.Hour
+
+func (hra *HarborRobotAccount) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(hra).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-harborrobotaccount,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=harborrobotaccounts,versions=v1beta1,name=vharborrobotaccount.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &HarborRobotAccount{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (hra *HarborRobotAccount) ValidateCreate() error {
+	hralog.Info("validate create", "name", hra.Name)
+
+	return hra.Validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (hra *HarborRobotAccount) ValidateUpdate(old runtime.Object) error {
+	hralog.Info("validate update", "name", hra.Name)
+
+	obj, ok := old.(*HarborRobotAccount)
+	if !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return hra.Validate(obj)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (hra *HarborRobotAccount) ValidateDelete() error {
+	hralog.Info("validate delete", "name", hra.Name)
+
+	return nil
+}
+
+func (hra *HarborRobotAccount) Validate(old *HarborRobotAccount) error {
+	var allErrs field.ErrorList
+
+	spec := field.NewPath("spec")
+
+	if old != nil { // update harborrobotaccount resource
+		if hra.Spec.HarborServerConfig != old.Spec.HarborServerConfig {
+			allErrs = append(allErrs, field.Invalid(spec.Child("harborServerConfig"), hra.Spec.HarborServerConfig, "field cannot be changed after initial creation"))
+		}
+
+		if hra.GetRobotName() != old.GetRobotName() {
+			allErrs = append(allErrs, field.Invalid(spec.Child("name"), hra.Spec.Name, "field cannot be changed after initial creation"))
+		}
+	}
+
+	for i, permission := range hra.Spec.Permissions {
+		path := spec.Child("permissions").Index(i).Child("namespace")
+
+		switch {
+		case permission.Kind == "system" && permission.Namespace != "/":
+			allErrs = append(allErrs, field.Invalid(path, permission.Namespace, "the namespace of system permissions is /"))
+		case permission.Kind != "system" && permission.Namespace == "":
+			allErrs = append(allErrs, field.Required(path, "the name of a project or * is required"))
+		}
+	}
+
+	if rotateBefore := hra.Spec.RotateBefore; rotateBefore != nil {
+		path := spec.Child("rotateBefore")
+
+		switch {
+		case rotateBefore.Duration <= 0:
+			allErrs = append(allErrs, field.Invalid(path, rotateBefore.Duration.String(), "must be positive"))
+		case hra.Spec.Duration > 0 && rotateBefore.Duration >= time.Duration(hra.Spec.Duration)*robotDurationUnit:
+			allErrs = append(allErrs, field.Invalid(path, rotateBefore.Duration.String(), "must be shorter than the duration of the robot account"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "HarborRobotAccount"}, hra.Name, allErrs)
+}
@@ -1,6 +1,8 @@
 package v1beta1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -47,6 +49,87 @@ type HarborServerConfigurationSpec struct {
 	// Default to the empty LabelSelector, which matches everything.
 	// +optional
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ImageVerification enables the verification of the rewritten images through the Harbor registry API before mutating the pods.
+	// The original image is kept when the rewritten image does not exist or when Harbor cannot be reached.
+	// +kubebuilder:validation:Optional
+	ImageVerification *HarborServerImageVerification `json:"imageVerification,omitempty"`
+
+	// ImagePolicy enforces a supply-chain policy on the images pulled from the Harbor server.
+	// +kubebuilder:validation:Optional
+	ImagePolicy *HarborServerImagePolicy `json:"imagePolicy,omitempty"`
+
+	// HealthCheck configures the periodic probing of the Harbor server.
+	// +kubebuilder:validation:Optional
+	HealthCheck *HarborServerHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// HarborServerHealthCheck configures the periodic probing of the health, the version and the credential of the Harbor server.
+type HarborServerHealthCheck struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="5m"
+	// Interval between two probes of the Harbor server.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="30s"
+	// Timeout of each Harbor API call of a probe.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DigestPinningMode defines whether the tags of the images are resolved to their digest.
+// +kubebuilder:validation:Enum=Disabled;Preferred;Required
+type DigestPinningMode string
+
+const (
+	// DigestPinningDisabled keeps the tags of the images.
+	DigestPinningDisabled DigestPinningMode = "Disabled"
+	// DigestPinningPreferred pins the images by digest when their tag exists in Harbor.
+	DigestPinningPreferred DigestPinningMode = "Preferred"
+	// DigestPinningRequired pins the images by digest and denies the pods whose images cannot be resolved.
+	DigestPinningRequired DigestPinningMode = "Required"
+)
+
+// HarborServerImagePolicy configures the supply-chain policy of the images pulled from the Harbor server.
+// The policy applies to the rewritten images and to the images already referring to the Harbor server.
+type HarborServerImagePolicy struct {
+	// DigestPinning resolves the tags of the images to their digest in Harbor and patches the pods with repo@sha256:... references.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="Disabled"
+	DigestPinning DigestPinningMode `json:"digestPinning,omitempty"`
+
+	// DenyVulnerable denies the pods whose images have no scan report, or have vulnerabilities
+	// of the severity of their project or above, when the project prevents vulnerable images from running.
+	// +kubebuilder:validation:Optional
+	DenyVulnerable bool `json:"denyVulnerable,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="5s"
+	// Timeout of the Harbor API calls for an image.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HarborServerImageVerification configures the verification of the rewritten images.
+type HarborServerImageVerification struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="2s"
+	// Timeout of the verification of an image.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="5m"
+	// CacheDuration is the duration the result of the verification of an image is cached for.
+	CacheDuration *metav1.Duration `json:"cacheDuration,omitempty"`
 }
 
 // AccessCredential is a namespaced credential to keep the access key and secret for the harbor server configuration.
@@ -83,6 +166,34 @@ type HarborServerConfigurationStatus struct {
 	// Message provides human-readable message.
 	// +kubebuilder:validation:Optional
 	Message string `json:"message,omitempty"`
+	// Version is the version of the Harbor server observed by the last probe.
+	// +kubebuilder:validation:Optional
+	Version string `json:"version,omitempty"`
+	// Conditions report the health of the Harbor server and of its components,
+	// the validity of the credential and whether the observed version matches the spec.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// HarborServerConfigurationConditionHealthy reports the overall health of the Harbor server.
+	HarborServerConfigurationConditionHealthy = "Healthy"
+	// HarborServerConfigurationConditionCredentialValid reports whether Harbor accepts the access credential.
+	HarborServerConfigurationConditionCredentialValid = "CredentialValid"
+	// HarborServerConfigurationConditionVersionMatched reports whether the observed version matches the version of the spec.
+	HarborServerConfigurationConditionVersionMatched = "VersionMatched"
+)
+
+// HarborServerComponentConditionType returns the type of the condition reporting the health of a Harbor component,
+// e.g. CoreHealthy for the core component.
+func HarborServerComponentConditionType(component string) string {
+	if component == "" {
+		return HarborServerConfigurationConditionHealthy
+	}
+
+	return strings.ToUpper(component[:1]) + component[1:] + HarborServerConfigurationConditionHealthy
 }
 
 // +kubebuilder:object:root=true
@@ -93,6 +204,7 @@ type HarborServerConfigurationStatus struct {
 // +kubebuilder:printcolumn:name="Harbor Server",type=string,JSONPath=`.spec.serverURL`,description="The public URL to the Harbor server",priority=0
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="The status of the Harbor server",priority=0
 // +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`,description="The version of the Harbor server",priority=5
+// +kubebuilder:printcolumn:name="Observed Version",type=string,JSONPath=`.status.version`,description="The version of the Harbor server observed by the last probe",priority=5
 // HarborServerConfiguration is the Schema for the harborserverconfigurations API.
 type HarborServerConfiguration struct {
 	metav1.TypeMeta   `json:",inline"`
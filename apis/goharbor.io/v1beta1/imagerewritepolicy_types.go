@@ -0,0 +1,132 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +resource:path=imagerewritepolicy
+// +kubebuilder:resource:categories="goharbor",shortName="irp"
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`,description="Priority of the policy"
+// +kubebuilder:printcolumn:name="Registry",type=string,JSONPath=`.spec.match.registry`,description="Matched registries"
+// +kubebuilder:printcolumn:name="Project",type=string,JSONPath=`.spec.target.project`,description="Harbor project the images are pulled through"
+// +kubebuilder:printcolumn:name="HarborServerConfig",type=string,JSONPath=`.spec.harborServerConfig`,description="HarborServerConfiguration name"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// ImageRewritePolicy rewrites the images of the pods of its namespace to pull them through a Harbor project.
+type ImageRewritePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImageRewritePolicySpec `json:"spec,omitempty"`
+}
+
+// ImageRewritePolicySpec defines the spec of ImageRewritePolicy.
+type ImageRewritePolicySpec struct {
+	// HarborServerConfig contains the name of a HarborServerConfig resource describing the harbor instance the images are pulled through.
+	// The default HarborServerConfiguration is used if empty.
+	// +kubebuilder:validation:Optional
+	HarborServerConfig string `json:"harborServerConfig,omitempty"`
+	// Policies with a higher priority are evaluated first, the first matching policy rewrites the image.
+	// At the same priority, namespaced policies are evaluated before cluster ones.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=0
+	Priority int32 `json:"priority,omitempty"`
+	// The images rewritten by the policy.
+	// +kubebuilder:validation:Required
+	Match ImageRewriteMatch `json:"match"`
+	// Where the matching images are pulled from.
+	// +kubebuilder:validation:Required
+	Target ImageRewriteTarget `json:"target"`
+}
+
+// ImageRewriteMatch selects images, an image has to match all the fields.
+type ImageRewriteMatch struct {
+	// Glob of the registry of the images, e.g. `docker.io` or `*.gcr.io`. All the registries are matched if empty.
+	// Images without registry are from `docker.io`.
+	// +kubebuilder:validation:Optional
+	Registry string `json:"registry,omitempty"`
+	// The repositories of the images. All the repositories are matched if empty.
+	// +kubebuilder:validation:Optional
+	Repository *ImageRewriteRepositoryMatch `json:"repository,omitempty"`
+	// Glob of the tag of the images, e.g. `v1.*`. All the images are matched if empty, images referenced only by digest included.
+	// +kubebuilder:validation:Optional
+	Tag string `json:"tag,omitempty"`
+}
+
+// ImageRewriteRepositoryMatch selects repositories with a glob or a regular expression.
+// Exactly one of glob and regex has to be set.
+type ImageRewriteRepositoryMatch struct {
+	// Glob of the repository, e.g. `library/*`. `*` does not match `/`.
+	// Repositories of docker.io without namespace are in the `library` namespace.
+	// +kubebuilder:validation:Optional
+	Glob string `json:"glob,omitempty"`
+	// Regular expression matching the whole repository, e.g. `(library|bitnami)/.+`.
+	// +kubebuilder:validation:Optional
+	Regex string `json:"regex,omitempty"`
+}
+
+// ImageRewriteTarget is the Harbor project the images are pulled through.
+type ImageRewriteTarget struct {
+	// The name of the Harbor project, usually a proxy cache project of the matched registry.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[a-z0-9]+(?:[._-][a-z0-9]+)*$"
+	// +kubebuilder:validation:MaxLength=255
+	Project string `json:"project"`
+}
+
+// +kubebuilder:object:root=true
+// ImageRewritePolicyList contains a list of ImageRewritePolicies.
+type ImageRewritePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageRewritePolicy `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +resource:path=clusterimagerewritepolicy
+// +kubebuilder:resource:categories="goharbor",shortName="cirp",scope="Cluster"
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`,description="Priority of the policy"
+// +kubebuilder:printcolumn:name="Registry",type=string,JSONPath=`.spec.match.registry`,description="Matched registries"
+// +kubebuilder:printcolumn:name="Project",type=string,JSONPath=`.spec.target.project`,description="Harbor project the images are pulled through"
+// +kubebuilder:printcolumn:name="HarborServerConfig",type=string,JSONPath=`.spec.harborServerConfig`,description="HarborServerConfiguration name"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`,description="Timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+// ClusterImageRewritePolicy rewrites the images of the pods of the selected namespaces to pull them through a Harbor project.
+type ClusterImageRewritePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterImageRewritePolicySpec `json:"spec,omitempty"`
+}
+
+// ClusterImageRewritePolicySpec defines the spec of ClusterImageRewritePolicy.
+type ClusterImageRewritePolicySpec struct {
+	ImageRewritePolicySpec `json:",inline"`
+
+	// NamespaceSelector selects the namespaces the policy applies to.
+	// Default to the empty LabelSelector, which matches everything.
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ClusterImageRewritePolicyList contains a list of ClusterImageRewritePolicies.
+type ClusterImageRewritePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterImageRewritePolicy `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&ImageRewritePolicy{}, &ImageRewritePolicyList{}, &ClusterImageRewritePolicy{}, &ClusterImageRewritePolicyList{})
+}
@@ -0,0 +1,151 @@
+package v1beta1
+
+import (
+	"context"
+	"path"
+	"regexp"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var irplog = logf.Log.WithName("imagerewritepolicy-resource")
+
+func (irp *ImageRewritePolicy) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(irp).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-imagerewritepolicy,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=imagerewritepolicies,versions=v1beta1,name=vimagerewritepolicy.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &ImageRewritePolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (irp *ImageRewritePolicy) ValidateCreate() error {
+	irplog.Info("validate create", "name", irp.Name)
+
+	return irp.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (irp *ImageRewritePolicy) ValidateUpdate(old runtime.Object) error {
+	irplog.Info("validate update", "name", irp.Name)
+
+	if _, ok := old.(*ImageRewritePolicy); !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return irp.Validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (irp *ImageRewritePolicy) ValidateDelete() error {
+	irplog.Info("validate delete", "name", irp.Name)
+
+	return nil
+}
+
+func (irp *ImageRewritePolicy) Validate() error {
+	allErrs := irp.Spec.validate(field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ImageRewritePolicy"}, irp.Name, allErrs)
+}
+
+func (cirp *ClusterImageRewritePolicy) SetupWebhookWithManager(_ context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(cirp).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-goharbor-io-v1beta1-clusterimagerewritepolicy,mutating=false,failurePolicy=fail,groups=goharbor.io,resources=clusterimagerewritepolicies,versions=v1beta1,name=vclusterimagerewritepolicy.kb.io,admissionReviewVersions={"v1beta1","v1"},sideEffects=None
+
+var _ webhook.Validator = &ClusterImageRewritePolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (cirp *ClusterImageRewritePolicy) ValidateCreate() error {
+	irplog.Info("validate create", "name", cirp.Name)
+
+	return cirp.Validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (cirp *ClusterImageRewritePolicy) ValidateUpdate(old runtime.Object) error {
+	irplog.Info("validate update", "name", cirp.Name)
+
+	if _, ok := old.(*ClusterImageRewritePolicy); !ok {
+		return errors.Errorf("failed type assertion on kind: %s", old.GetObjectKind().GroupVersionKind().String())
+	}
+
+	return cirp.Validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (cirp *ClusterImageRewritePolicy) ValidateDelete() error {
+	irplog.Info("validate delete", "name", cirp.Name)
+
+	return nil
+}
+
+func (cirp *ClusterImageRewritePolicy) Validate() error {
+	spec := field.NewPath("spec")
+
+	allErrs := cirp.Spec.ImageRewritePolicySpec.validate(spec)
+
+	if cirp.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cirp.Spec.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(spec.Child("namespaceSelector"), cirp.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ClusterImageRewritePolicy"}, cirp.Name, allErrs)
+}
+
+func (spec *ImageRewritePolicySpec) validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	match := path.Child("match")
+
+	allErrs = append(allErrs, validateGlob(match.Child("registry"), spec.Match.Registry)...)
+	allErrs = append(allErrs, validateGlob(match.Child("tag"), spec.Match.Tag)...)
+
+	if repository := spec.Match.Repository; repository != nil {
+		repositoryPath := match.Child("repository")
+
+		switch {
+		case (repository.Glob == "") == (repository.Regex == ""):
+			allErrs = append(allErrs, field.Invalid(repositoryPath, repository, "exactly one of glob and regex has to be set"))
+		case repository.Glob != "":
+			allErrs = append(allErrs, validateGlob(repositoryPath.Child("glob"), repository.Glob)...)
+		default:
+			if _, err := regexp.Compile(repository.Regex); err != nil {
+				allErrs = append(allErrs, field.Invalid(repositoryPath.Child("regex"), repository.Regex, err.Error()))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateGlob(fieldPath *field.Path, glob string) field.ErrorList {
+	if _, err := path.Match(glob, ""); err != nil {
+		return field.ErrorList{field.Invalid(fieldPath, glob, err.Error())}
+	}
+
+	return nil
+}
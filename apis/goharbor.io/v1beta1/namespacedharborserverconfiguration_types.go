@@ -0,0 +1,55 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories="goharbor",shortName="nhsc"
+// +kubebuilder:printcolumn:name="Harbor Server",type=string,JSONPath=`.spec.serverURL`,description="The public URL to the Harbor server",priority=0
+// +kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.default`,description="Whether the Harbor server is the default of the namespace",priority=0
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="The status of the Harbor server",priority=0
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`,description="The version of the Harbor server",priority=5
+// +kubebuilder:printcolumn:name="Observed Version",type=string,JSONPath=`.status.version`,description="The version of the Harbor server observed by the last probe",priority=5
+// NamespacedHarborServerConfiguration is the Schema for the namespacedharborserverconfigurations API.
+// It configures a Harbor server for the resources of its namespace only, the access secret must be in the same namespace.
+// It takes precedence over the HarborServerConfiguration with the same name and over the default HarborServerConfiguration.
+type NamespacedHarborServerConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborServerConfigurationSpec   `json:"spec,omitempty"`
+	Status HarborServerConfigurationStatus `json:"status,omitempty"`
+}
+
+// ToHarborServerConfiguration returns the HarborServerConfiguration equivalent to the namespaced configuration,
+// with the access credential in the namespace of the configuration.
+func (nhsc *NamespacedHarborServerConfiguration) ToHarborServerConfiguration() *HarborServerConfiguration {
+	hsc := &HarborServerConfiguration{
+		ObjectMeta: *nhsc.ObjectMeta.DeepCopy(),
+		Spec:       *nhsc.Spec.DeepCopy(),
+		Status:     *nhsc.Status.DeepCopy(),
+	}
+
+	if hsc.Spec.AccessCredential != nil {
+		hsc.Spec.AccessCredential.Namespace = nhsc.GetNamespace()
+	}
+
+	return hsc
+}
+
+// +kubebuilder:object:root=true
+
+// NamespacedHarborServerConfigurationList contains a list of NamespacedHarborServerConfiguration.
+type NamespacedHarborServerConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespacedHarborServerConfiguration `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&NamespacedHarborServerConfiguration{}, &NamespacedHarborServerConfigurationList{})
+}
@@ -13,18 +13,76 @@ type PullSecretBindingSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
 	// RobotID points to the robot account id used for secret binding
-	// +kubebuilder:validation:Required
-	RobotID string `json:"robotId"`
+	// +kubebuilder:validation:Optional
+	RobotID string `json:"robotId,omitempty"`
 
 	// ProjectID points to the project associated with the secret binding
-	// +kubebuilder:validation:Required
-	ProjectID string `json:"projectId"`
+	// +kubebuilder:validation:Optional
+	ProjectID string `json:"projectId,omitempty"`
 
 	// Indicate which harbor server configuration is referred
-	HarborServerConfig string `json:"harborServerConfig"`
+	// +kubebuilder:validation:Optional
+	HarborServerConfig string `json:"harborServerConfig,omitempty"`
+
+	// Registries are the additional Harbor projects whose robot account credentials are merged into the pull secret.
+	// +kubebuilder:validation:Optional
+	Registries []PullSecretBindingRegistry `json:"registries,omitempty"`
 
 	// Indicate which service account binds the pull secret
-	ServiceAccount string `json:"serviceAccount"`
+	// +kubebuilder:validation:Optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// ServiceAccounts are the additional service accounts binding the pull secret.
+	// +kubebuilder:validation:Optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+
+	// ServiceAccountSelector selects the service accounts of the namespace binding the pull secret.
+	// The empty selector selects all the service accounts of the namespace.
+	// +kubebuilder:validation:Optional
+	ServiceAccountSelector *metav1.LabelSelector `json:"serviceAccountSelector,omitempty"`
+
+	// How long before the expiration of a robot account its secret is rotated.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern="([0-9]+h)?([0-9]+m)?([0-9]+s)?([0-9]+ms)?([0-9]+us)?([0-9]+µs)?([0-9]+ns)?"
+	// +kubebuilder:default="72h"
+	RotateBefore *metav1.Duration `json:"rotateBefore,omitempty"`
+
+	// The validity in days the expiring robot accounts are renewed for by the rotation, -1 to never expire them.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:default=30
+	RenewDuration int64 `json:"renewDuration,omitempty"`
+}
+
+// PullSecretBindingRegistry is a Harbor project whose robot account credentials are added to the pull secret.
+type PullSecretBindingRegistry struct {
+	// HarborServerConfig is the name of the Harbor server configuration of the project.
+	// +kubebuilder:validation:Required
+	HarborServerConfig string `json:"harborServerConfig"`
+
+	// ProjectID points to the Harbor project.
+	// +kubebuilder:validation:Required
+	ProjectID string `json:"projectId"`
+
+	// RobotID points to the robot account of the project pulling the images.
+	// +kubebuilder:validation:Required
+	RobotID string `json:"robotId"`
+}
+
+// GetRegistries returns the Harbor projects of the binding, starting with the project of the spec when it is set.
+func (spec *PullSecretBindingSpec) GetRegistries() []PullSecretBindingRegistry {
+	registries := make([]PullSecretBindingRegistry, 0, len(spec.Registries)+1)
+
+	if spec.HarborServerConfig != "" {
+		registries = append(registries, PullSecretBindingRegistry{
+			HarborServerConfig: spec.HarborServerConfig,
+			ProjectID:          spec.ProjectID,
+			RobotID:            spec.RobotID,
+		})
+	}
+
+	return append(registries, spec.Registries...)
 }
 
 // PullSecretBindingStatusType defines the status type of configuration.
@@ -51,6 +109,44 @@ type PullSecretBindingStatus struct {
 	// Message provides human-readable message.
 	// +kubebuilder:validation:Optional
 	Message string `json:"message,omitempty"`
+	// ServiceAccounts are the service accounts bound to the pull secret.
+	// +kubebuilder:validation:Optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+	// Robots are the robot accounts whose credentials are in the pull secret.
+	// +kubebuilder:validation:Optional
+	Robots []PullSecretBindingRobot `json:"robots,omitempty"`
+	// ExpiresAt is the earliest expiration time of the robot accounts, empty if they never expire.
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// LastRotationTime is the last time the secret of a robot account was generated.
+	// +kubebuilder:validation:Optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// PendingRotations are the robot accounts, as <harborServerConfig>/<robotId>, whose secret is being generated again.
+	// They are rotated again if the pull secret could not be updated with their new secret.
+	// +kubebuilder:validation:Optional
+	PendingRotations []string `json:"pendingRotations,omitempty"`
+	// Conditions of the binding.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// PullSecretBindingConditionSecretRotated reports whether the secrets of the robot accounts are up to date.
+const PullSecretBindingConditionSecretRotated = "SecretRotated"
+
+// PullSecretBindingRobot is a robot account whose credential is in the pull secret.
+type PullSecretBindingRobot struct {
+	// HarborServerConfig is the name of the Harbor server configuration of the robot account.
+	HarborServerConfig string `json:"harborServerConfig"`
+	// RobotID is the ID of the robot account.
+	RobotID string `json:"robotId"`
+	// RobotName is the full name of the robot account, used as username.
+	// +kubebuilder:validation:Optional
+	RobotName string `json:"robotName,omitempty"`
+	// ExpiresAt is the expiration time of the robot account, empty if it never expires.
+	// +kubebuilder:validation:Optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -61,6 +157,7 @@ type PullSecretBindingStatus struct {
 // +kubebuilder:printcolumn:name="Harbor Server",type=string,JSONPath=`.spec.harborServerConfig`,description="The Harbor server configuration CR reference",priority=0
 // +kubebuilder:printcolumn:name="Service Account",type=string,JSONPath=`.spec.serviceAccount`,description="The service account binding the pull secret",priority=0
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`,description="The status of the Harbor server",priority=0
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,description="Earliest expiration time of the robot accounts",priority=0
 
 // PullSecretBinding is the Schema for the pullsecretbindings API.
 type PullSecretBinding struct {
@@ -76,6 +76,11 @@ func (in *CacheSpec) DeepCopyInto(out *CacheSpec) {
 		*out = new(RedisFailoverSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RedisReplication != nil {
+		in, out := &in.RedisReplication, &out.RedisReplication
+		*out = new(RedisReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheSpec.
@@ -578,6 +583,109 @@ func (in *ChartMuseumSpec) DeepCopy() *ChartMuseumSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGSpec) DeepCopyInto(out *CloudNativePGSpec) {
+	*out = *in
+	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGSpec.
+func (in *CloudNativePGSpec) DeepCopy() *CloudNativePGSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageRewritePolicy) DeepCopyInto(out *ClusterImageRewritePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageRewritePolicy.
+func (in *ClusterImageRewritePolicy) DeepCopy() *ClusterImageRewritePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageRewritePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageRewritePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageRewritePolicyList) DeepCopyInto(out *ClusterImageRewritePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterImageRewritePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageRewritePolicyList.
+func (in *ClusterImageRewritePolicyList) DeepCopy() *ClusterImageRewritePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageRewritePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageRewritePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageRewritePolicySpec) DeepCopyInto(out *ClusterImageRewritePolicySpec) {
+	*out = *in
+	in.ImageRewritePolicySpec.DeepCopyInto(&out.ImageRewritePolicySpec)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageRewritePolicySpec.
+func (in *ClusterImageRewritePolicySpec) DeepCopy() *ClusterImageRewritePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageRewritePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Core) DeepCopyInto(out *Core) {
 	*out = *in
@@ -1009,6 +1117,11 @@ func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 		*out = new(ZlandoPostgreSQLSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CloudNativePG != nil {
+		in, out := &in.CloudNativePG, &out.CloudNativePG
+		*out = new(CloudNativePGSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSpec.
@@ -1386,7 +1499,7 @@ func (in *Harbor) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborCluster) DeepCopyInto(out *HarborCluster) {
+func (in *HarborBackup) DeepCopyInto(out *HarborBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1394,18 +1507,18 @@ func (in *HarborCluster) DeepCopyInto(out *HarborCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborCluster.
-func (in *HarborCluster) DeepCopy() *HarborCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackup.
+func (in *HarborBackup) DeepCopy() *HarborBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborCluster)
+	out := new(HarborBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborCluster) DeepCopyObject() runtime.Object {
+func (in *HarborBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1413,50 +1526,61 @@ func (in *HarborCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborClusterCondition) DeepCopyInto(out *HarborClusterCondition) {
+func (in *HarborBackupBucketTarget) DeepCopyInto(out *HarborBackupBucketTarget) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupBucketTarget.
+func (in *HarborBackupBucketTarget) DeepCopy() *HarborBackupBucketTarget {
+	if in == nil {
+		return nil
 	}
+	out := new(HarborBackupBucketTarget)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterCondition.
-func (in *HarborClusterCondition) DeepCopy() *HarborClusterCondition {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupComponentStatus) DeepCopyInto(out *HarborBackupComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupComponentStatus.
+func (in *HarborBackupComponentStatus) DeepCopy() *HarborBackupComponentStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborClusterCondition)
+	out := new(HarborBackupComponentStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborClusterList) DeepCopyInto(out *HarborClusterList) {
+func (in *HarborBackupList) DeepCopyInto(out *HarborBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HarborCluster, len(*in))
+		*out = make([]HarborBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterList.
-func (in *HarborClusterList) DeepCopy() *HarborClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupList.
+func (in *HarborBackupList) DeepCopy() *HarborBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborClusterList)
+	out := new(HarborBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborClusterList) DeepCopyObject() runtime.Object {
+func (in *HarborBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1464,136 +1588,73 @@ func (in *HarborClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborClusterSpec) DeepCopyInto(out *HarborClusterSpec) {
+func (in *HarborBackupPVCTarget) DeepCopyInto(out *HarborBackupPVCTarget) {
 	*out = *in
-	in.EmbeddedHarborSpec.DeepCopyInto(&out.EmbeddedHarborSpec)
-	in.Cache.DeepCopyInto(&out.Cache)
-	in.Database.DeepCopyInto(&out.Database)
-	in.Storage.DeepCopyInto(&out.Storage)
-	if in.Network != nil {
-		in, out := &in.Network, &out.Network
-		*out = new(v1alpha1.Network)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Trace != nil {
-		in, out := &in.Trace, &out.Trace
-		*out = new(v1alpha1.TraceSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterSpec.
-func (in *HarborClusterSpec) DeepCopy() *HarborClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupPVCTarget.
+func (in *HarborBackupPVCTarget) DeepCopy() *HarborBackupPVCTarget {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborClusterSpec)
+	out := new(HarborBackupPVCTarget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborClusterStatus) DeepCopyInto(out *HarborClusterStatus) {
+func (in *HarborBackupSchedule) DeepCopyInto(out *HarborBackupSchedule) {
 	*out = *in
-	out.Operator = in.Operator
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]HarborClusterCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterStatus.
-func (in *HarborClusterStatus) DeepCopy() *HarborClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupSchedule.
+func (in *HarborBackupSchedule) DeepCopy() *HarborBackupSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborClusterStatus)
+	out := new(HarborBackupSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborComponentsSpec) DeepCopyInto(out *HarborComponentsSpec) {
-	*out = *in
-	if in.Portal != nil {
-		in, out := &in.Portal, &out.Portal
-		*out = new(PortalComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Core.DeepCopyInto(&out.Core)
-	in.JobService.DeepCopyInto(&out.JobService)
-	in.Registry.DeepCopyInto(&out.Registry)
-	if in.RegistryController != nil {
-		in, out := &in.RegistryController, &out.RegistryController
-		*out = new(v1alpha1.ComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ChartMuseum != nil {
-		in, out := &in.ChartMuseum, &out.ChartMuseum
-		*out = new(ChartMuseumComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Exporter != nil {
-		in, out := &in.Exporter, &out.Exporter
-		*out = new(ExporterComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Trivy != nil {
-		in, out := &in.Trivy, &out.Trivy
-		*out = new(TrivyComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Notary != nil {
-		in, out := &in.Notary, &out.Notary
-		*out = new(NotaryComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Redis != nil {
-		in, out := &in.Redis, &out.Redis
-		*out = new(ExternalRedisSpec)
-		**out = **in
-	}
-	if in.Database != nil {
-		in, out := &in.Database, &out.Database
-		*out = new(HarborDatabaseSpec)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborComponentsSpec.
-func (in *HarborComponentsSpec) DeepCopy() *HarborComponentsSpec {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborBackupSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(HarborComponentsSpec)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfiguration) DeepCopyInto(out *HarborConfiguration) {
+func (in *HarborBackupScheduleList) DeepCopyInto(out *HarborBackupScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborBackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfiguration.
-func (in *HarborConfiguration) DeepCopy() *HarborConfiguration {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupScheduleList.
+func (in *HarborBackupScheduleList) DeepCopy() *HarborBackupScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfiguration)
+	out := new(HarborBackupScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborConfiguration) DeepCopyObject() runtime.Object {
+func (in *HarborBackupScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1601,397 +1662,1529 @@ func (in *HarborConfiguration) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationEmail) DeepCopyInto(out *HarborConfigurationEmail) {
+func (in *HarborBackupScheduleSpec) DeepCopyInto(out *HarborBackupScheduleSpec) {
 	*out = *in
-	if in.EmailInsecure != nil {
-		in, out := &in.EmailInsecure, &out.EmailInsecure
-		*out = new(bool)
+	if in.SuccessfulBackupsHistoryLimit != nil {
+		in, out := &in.SuccessfulBackupsHistoryLimit, &out.SuccessfulBackupsHistoryLimit
+		*out = new(int32)
 		**out = **in
 	}
-	if in.EmailSSL != nil {
-		in, out := &in.EmailSSL, &out.EmailSSL
-		*out = new(bool)
+	if in.FailedBackupsHistoryLimit != nil {
+		in, out := &in.FailedBackupsHistoryLimit, &out.FailedBackupsHistoryLimit
+		*out = new(int32)
 		**out = **in
 	}
+	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationEmail.
-func (in *HarborConfigurationEmail) DeepCopy() *HarborConfigurationEmail {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupScheduleSpec.
+func (in *HarborBackupScheduleSpec) DeepCopy() *HarborBackupScheduleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationEmail)
+	out := new(HarborBackupScheduleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationHTTPAuthProxy) DeepCopyInto(out *HarborConfigurationHTTPAuthProxy) {
+func (in *HarborBackupScheduleStatus) DeepCopyInto(out *HarborBackupScheduleStatus) {
 	*out = *in
-	if in.HTTPAuthproxyVerifyCert != nil {
-		in, out := &in.HTTPAuthproxyVerifyCert, &out.HTTPAuthproxyVerifyCert
-		*out = new(bool)
-		**out = **in
-	}
-	if in.HTTPAuthproxySkipSearch != nil {
-		in, out := &in.HTTPAuthproxySkipSearch, &out.HTTPAuthproxySkipSearch
-		*out = new(bool)
-		**out = **in
-	}
-	if in.HTTPAuthproxyServerCertificate != nil {
-		in, out := &in.HTTPAuthproxyServerCertificate, &out.HTTPAuthproxyServerCertificate
-		*out = new(bool)
-		**out = **in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationHTTPAuthProxy.
-func (in *HarborConfigurationHTTPAuthProxy) DeepCopy() *HarborConfigurationHTTPAuthProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupScheduleStatus.
+func (in *HarborBackupScheduleStatus) DeepCopy() *HarborBackupScheduleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationHTTPAuthProxy)
+	out := new(HarborBackupScheduleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationLdap) DeepCopyInto(out *HarborConfigurationLdap) {
+func (in *HarborBackupSpec) DeepCopyInto(out *HarborBackupSpec) {
 	*out = *in
-	if in.LdapVerifyCert != nil {
-		in, out := &in.LdapVerifyCert, &out.LdapVerifyCert
-		*out = new(bool)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]HarborBackupComponent, len(*in))
+		copy(*out, *in)
+	}
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupSpec.
+func (in *HarborBackupSpec) DeepCopy() *HarborBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupStatus) DeepCopyInto(out *HarborBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]HarborBackupComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupStatus.
+func (in *HarborBackupStatus) DeepCopy() *HarborBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborBackupTarget) DeepCopyInto(out *HarborBackupTarget) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(HarborBackupPVCTarget)
+		**out = **in
+	}
+	if in.Bucket != nil {
+		in, out := &in.Bucket, &out.Bucket
+		*out = new(HarborBackupBucketTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborBackupTarget.
+func (in *HarborBackupTarget) DeepCopy() *HarborBackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborBackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborCluster) DeepCopyInto(out *HarborCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborCluster.
+func (in *HarborCluster) DeepCopy() *HarborCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborClusterCondition) DeepCopyInto(out *HarborClusterCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterCondition.
+func (in *HarborClusterCondition) DeepCopy() *HarborClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborClusterList) DeepCopyInto(out *HarborClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterList.
+func (in *HarborClusterList) DeepCopy() *HarborClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborClusterSpec) DeepCopyInto(out *HarborClusterSpec) {
+	*out = *in
+	in.EmbeddedHarborSpec.DeepCopyInto(&out.EmbeddedHarborSpec)
+	in.Cache.DeepCopyInto(&out.Cache)
+	in.Database.DeepCopyInto(&out.Database)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(v1alpha1.Network)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trace != nil {
+		in, out := &in.Trace, &out.Trace
+		*out = new(v1alpha1.TraceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterSpec.
+func (in *HarborClusterSpec) DeepCopy() *HarborClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborClusterStatus) DeepCopyInto(out *HarborClusterStatus) {
+	*out = *in
+	out.Operator = in.Operator
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HarborClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborClusterStatus.
+func (in *HarborClusterStatus) DeepCopy() *HarborClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborComponentsSpec) DeepCopyInto(out *HarborComponentsSpec) {
+	*out = *in
+	if in.Portal != nil {
+		in, out := &in.Portal, &out.Portal
+		*out = new(PortalComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Core.DeepCopyInto(&out.Core)
+	in.JobService.DeepCopyInto(&out.JobService)
+	in.Registry.DeepCopyInto(&out.Registry)
+	if in.RegistryController != nil {
+		in, out := &in.RegistryController, &out.RegistryController
+		*out = new(v1alpha1.ComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChartMuseum != nil {
+		in, out := &in.ChartMuseum, &out.ChartMuseum
+		*out = new(ChartMuseumComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exporter != nil {
+		in, out := &in.Exporter, &out.Exporter
+		*out = new(ExporterComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trivy != nil {
+		in, out := &in.Trivy, &out.Trivy
+		*out = new(TrivyComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notary != nil {
+		in, out := &in.Notary, &out.Notary
+		*out = new(NotaryComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(ExternalRedisSpec)
+		**out = **in
+	}
+	if in.Database != nil {
+		in, out := &in.Database, &out.Database
+		*out = new(HarborDatabaseSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborComponentsSpec.
+func (in *HarborComponentsSpec) DeepCopy() *HarborComponentsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborComponentsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfiguration) DeepCopyInto(out *HarborConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfiguration.
+func (in *HarborConfiguration) DeepCopy() *HarborConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationEmail) DeepCopyInto(out *HarborConfigurationEmail) {
+	*out = *in
+	if in.EmailInsecure != nil {
+		in, out := &in.EmailInsecure, &out.EmailInsecure
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EmailSSL != nil {
+		in, out := &in.EmailSSL, &out.EmailSSL
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationEmail.
+func (in *HarborConfigurationEmail) DeepCopy() *HarborConfigurationEmail {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationEmail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationHTTPAuthProxy) DeepCopyInto(out *HarborConfigurationHTTPAuthProxy) {
+	*out = *in
+	if in.HTTPAuthproxyVerifyCert != nil {
+		in, out := &in.HTTPAuthproxyVerifyCert, &out.HTTPAuthproxyVerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HTTPAuthproxySkipSearch != nil {
+		in, out := &in.HTTPAuthproxySkipSearch, &out.HTTPAuthproxySkipSearch
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HTTPAuthproxyServerCertificate != nil {
+		in, out := &in.HTTPAuthproxyServerCertificate, &out.HTTPAuthproxyServerCertificate
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationHTTPAuthProxy.
+func (in *HarborConfigurationHTTPAuthProxy) DeepCopy() *HarborConfigurationHTTPAuthProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationHTTPAuthProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationLdap) DeepCopyInto(out *HarborConfigurationLdap) {
+	*out = *in
+	if in.LdapVerifyCert != nil {
+		in, out := &in.LdapVerifyCert, &out.LdapVerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationLdap.
+func (in *HarborConfigurationLdap) DeepCopy() *HarborConfigurationLdap {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationLdap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationList) DeepCopyInto(out *HarborConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationList.
+func (in *HarborConfigurationList) DeepCopy() *HarborConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationModel) DeepCopyInto(out *HarborConfigurationModel) {
+	*out = *in
+	in.HarborConfigurationEmail.DeepCopyInto(&out.HarborConfigurationEmail)
+	in.HarborConfigurationLdap.DeepCopyInto(&out.HarborConfigurationLdap)
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SelfRegistration != nil {
+		in, out := &in.SelfRegistration, &out.SelfRegistration
+		*out = new(bool)
+		**out = **in
+	}
+	in.HarborConfigurationHTTPAuthProxy.DeepCopyInto(&out.HarborConfigurationHTTPAuthProxy)
+	in.HarborConfigurationUaa.DeepCopyInto(&out.HarborConfigurationUaa)
+	in.HarborConfigurationOidc.DeepCopyInto(&out.HarborConfigurationOidc)
+	if in.NotificationEnable != nil {
+		in, out := &in.NotificationEnable, &out.NotificationEnable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QuotaPerProjectEnable != nil {
+		in, out := &in.QuotaPerProjectEnable, &out.QuotaPerProjectEnable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationModel.
+func (in *HarborConfigurationModel) DeepCopy() *HarborConfigurationModel {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationOidc) DeepCopyInto(out *HarborConfigurationOidc) {
+	*out = *in
+	if in.OidcVerifyCert != nil {
+		in, out := &in.OidcVerifyCert, &out.OidcVerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OidcAutoOnboard != nil {
+		in, out := &in.OidcAutoOnboard, &out.OidcAutoOnboard
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationOidc.
+func (in *HarborConfigurationOidc) DeepCopy() *HarborConfigurationOidc {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationOidc)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationSpec) DeepCopyInto(out *HarborConfigurationSpec) {
+	*out = *in
+	in.Configuration.DeepCopyInto(&out.Configuration)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationSpec.
+func (in *HarborConfigurationSpec) DeepCopy() *HarborConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationStatus) DeepCopyInto(out *HarborConfigurationStatus) {
+	*out = *in
+	if in.LastApplyTime != nil {
+		in, out := &in.LastApplyTime, &out.LastApplyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastConfiguration != nil {
+		in, out := &in.LastConfiguration, &out.LastConfiguration
+		*out = new(HarborConfigurationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationStatus.
+func (in *HarborConfigurationStatus) DeepCopy() *HarborConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborConfigurationUaa) DeepCopyInto(out *HarborConfigurationUaa) {
+	*out = *in
+	if in.UaaVerifyCert != nil {
+		in, out := &in.UaaVerifyCert, &out.UaaVerifyCert
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationUaa.
+func (in *HarborConfigurationUaa) DeepCopy() *HarborConfigurationUaa {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborConfigurationUaa)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborDatabaseSpec) DeepCopyInto(out *HarborDatabaseSpec) {
+	*out = *in
+	out.PostgresCredentials = in.PostgresCredentials
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]v1alpha1.PostgresHostSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborDatabaseSpec.
+func (in *HarborDatabaseSpec) DeepCopy() *HarborDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExporterCacheSpec) DeepCopyInto(out *HarborExporterCacheSpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CleanInterval != nil {
+		in, out := &in.CleanInterval, &out.CleanInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExporterCacheSpec.
+func (in *HarborExporterCacheSpec) DeepCopy() *HarborExporterCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExporterCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeComponentSpec) DeepCopyInto(out *HarborExposeComponentSpec) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(v1alpha1.ComponentsTLSSpec)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(HarborExposeIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(HarborExposeGatewaySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(HarborExposeServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeComponentSpec.
+func (in *HarborExposeComponentSpec) DeepCopy() *HarborExposeComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeGatewayParentReference) DeepCopyInto(out *HarborExposeGatewayParentReference) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.SectionName != nil {
+		in, out := &in.SectionName, &out.SectionName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeGatewayParentReference.
+func (in *HarborExposeGatewayParentReference) DeepCopy() *HarborExposeGatewayParentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeGatewayParentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeGatewaySpec) DeepCopyInto(out *HarborExposeGatewaySpec) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]HarborExposeGatewayParentReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeGatewaySpec.
+func (in *HarborExposeGatewaySpec) DeepCopy() *HarborExposeGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeIngressSpec) DeepCopyInto(out *HarborExposeIngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeIngressSpec.
+func (in *HarborExposeIngressSpec) DeepCopy() *HarborExposeIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeServiceSpec) DeepCopyInto(out *HarborExposeServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTPNodePort != nil {
+		in, out := &in.HTTPNodePort, &out.HTTPNodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HTTPSNodePort != nil {
+		in, out := &in.HTTPSNodePort, &out.HTTPSNodePort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(v1alpha1.ComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeServiceSpec.
+func (in *HarborExposeServiceSpec) DeepCopy() *HarborExposeServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborExposeSpec) DeepCopyInto(out *HarborExposeSpec) {
+	*out = *in
+	in.Core.DeepCopyInto(&out.Core)
+	if in.Notary != nil {
+		in, out := &in.Notary, &out.Notary
+		*out = new(HarborExposeComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeSpec.
+func (in *HarborExposeSpec) DeepCopy() *HarborExposeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborExposeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborInternalTLSSpec) DeepCopyInto(out *HarborInternalTLSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborInternalTLSSpec.
+func (in *HarborInternalTLSSpec) DeepCopy() *HarborInternalTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborInternalTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborList) DeepCopyInto(out *HarborList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Harbor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborList.
+func (in *HarborList) DeepCopy() *HarborList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProject) DeepCopyInto(out *HarborProject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProject.
+func (in *HarborProject) DeepCopy() *HarborProject {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborProject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectImmutableTagRule) DeepCopyInto(out *HarborProjectImmutableTagRule) {
+	*out = *in
+	out.Repositories = in.Repositories
+	out.Tags = in.Tags
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectImmutableTagRule.
+func (in *HarborProjectImmutableTagRule) DeepCopy() *HarborProjectImmutableTagRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectImmutableTagRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectList) DeepCopyInto(out *HarborProjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborProject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectList.
+func (in *HarborProjectList) DeepCopy() *HarborProjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborProjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectMember) DeepCopyInto(out *HarborProjectMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectMember.
+func (in *HarborProjectMember) DeepCopy() *HarborProjectMember {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectMetadata) DeepCopyInto(out *HarborProjectMetadata) {
+	*out = *in
+	if in.EnableContentTrust != nil {
+		in, out := &in.EnableContentTrust, &out.EnableContentTrust
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableContentTrustCosign != nil {
+		in, out := &in.EnableContentTrustCosign, &out.EnableContentTrustCosign
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoScan != nil {
+		in, out := &in.AutoScan, &out.AutoScan
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreventVulnerable != nil {
+		in, out := &in.PreventVulnerable, &out.PreventVulnerable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Public != nil {
+		in, out := &in.Public, &out.Public
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReuseSysCveAllowlist != nil {
+		in, out := &in.ReuseSysCveAllowlist, &out.ReuseSysCveAllowlist
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectMetadata.
+func (in *HarborProjectMetadata) DeepCopy() *HarborProjectMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectProxyCache) DeepCopyInto(out *HarborProjectProxyCache) {
+	*out = *in
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(HarborProjectProxyCacheRegistry)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationLdap.
-func (in *HarborConfigurationLdap) DeepCopy() *HarborConfigurationLdap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectProxyCache.
+func (in *HarborProjectProxyCache) DeepCopy() *HarborProjectProxyCache {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectProxyCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectProxyCacheRegistry) DeepCopyInto(out *HarborProjectProxyCacheRegistry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectProxyCacheRegistry.
+func (in *HarborProjectProxyCacheRegistry) DeepCopy() *HarborProjectProxyCacheRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectProxyCacheRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectRetention) DeepCopyInto(out *HarborProjectRetention) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]HarborProjectRetentionRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectRetention.
+func (in *HarborProjectRetention) DeepCopy() *HarborProjectRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectRetentionRule) DeepCopyInto(out *HarborProjectRetentionRule) {
+	*out = *in
+	out.Repositories = in.Repositories
+	out.Tags = in.Tags
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectRetentionRule.
+func (in *HarborProjectRetentionRule) DeepCopy() *HarborProjectRetentionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectRetentionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectRuleSelector) DeepCopyInto(out *HarborProjectRuleSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectRuleSelector.
+func (in *HarborProjectRuleSelector) DeepCopy() *HarborProjectRuleSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborProjectRuleSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProjectSpec) DeepCopyInto(out *HarborProjectSpec) {
+	*out = *in
+	if in.CveAllowList != nil {
+		in, out := &in.CveAllowList, &out.CveAllowList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HarborProjectMetadata != nil {
+		in, out := &in.HarborProjectMetadata, &out.HarborProjectMetadata
+		*out = new(HarborProjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HarborProjectMemberships != nil {
+		in, out := &in.HarborProjectMemberships, &out.HarborProjectMemberships
+		*out = make([]*HarborProjectMember, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(HarborProjectMember)
+				**out = **in
+			}
+		}
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(HarborProjectRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImmutableTagRules != nil {
+		in, out := &in.ImmutableTagRules, &out.ImmutableTagRules
+		*out = make([]HarborProjectImmutableTagRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]HarborProjectWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProxyCache != nil {
+		in, out := &in.ProxyCache, &out.ProxyCache
+		*out = new(HarborProjectProxyCache)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectSpec.
+func (in *HarborProjectSpec) DeepCopy() *HarborProjectSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationLdap)
+	out := new(HarborProjectSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationList) DeepCopyInto(out *HarborConfigurationList) {
+func (in *HarborProjectStatus) DeepCopyInto(out *HarborProjectStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]HarborConfiguration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.WebhookPolicies != nil {
+		in, out := &in.WebhookPolicies, &out.WebhookPolicies
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.LastApplyTime != nil {
+		in, out := &in.LastApplyTime, &out.LastApplyTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationList.
-func (in *HarborConfigurationList) DeepCopy() *HarborConfigurationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectStatus.
+func (in *HarborProjectStatus) DeepCopy() *HarborProjectStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationList)
+	out := new(HarborProjectStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborConfigurationList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationModel) DeepCopyInto(out *HarborConfigurationModel) {
+func (in *HarborProjectWebhook) DeepCopyInto(out *HarborProjectWebhook) {
 	*out = *in
-	in.HarborConfigurationEmail.DeepCopyInto(&out.HarborConfigurationEmail)
-	in.HarborConfigurationLdap.DeepCopyInto(&out.HarborConfigurationLdap)
-	if in.ReadOnly != nil {
-		in, out := &in.ReadOnly, &out.ReadOnly
-		*out = new(bool)
-		**out = **in
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]HarborProjectWebhookEventType, len(*in))
+		copy(*out, *in)
 	}
-	if in.SelfRegistration != nil {
-		in, out := &in.SelfRegistration, &out.SelfRegistration
-		*out = new(bool)
-		**out = **in
+	if in.AuthHeaderRef != nil {
+		in, out := &in.AuthHeaderRef, &out.AuthHeaderRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
 	}
-	in.HarborConfigurationHTTPAuthProxy.DeepCopyInto(&out.HarborConfigurationHTTPAuthProxy)
-	in.HarborConfigurationUaa.DeepCopyInto(&out.HarborConfigurationUaa)
-	in.HarborConfigurationOidc.DeepCopyInto(&out.HarborConfigurationOidc)
-	if in.NotificationEnable != nil {
-		in, out := &in.NotificationEnable, &out.NotificationEnable
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectWebhook.
+func (in *HarborProjectWebhook) DeepCopy() *HarborProjectWebhook {
+	if in == nil {
+		return nil
 	}
-	if in.QuotaPerProjectEnable != nil {
-		in, out := &in.QuotaPerProjectEnable, &out.QuotaPerProjectEnable
-		*out = new(bool)
-		**out = **in
+	out := new(HarborProjectWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborProxySpec) DeepCopyInto(out *HarborProxySpec) {
+	*out = *in
+	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationModel.
-func (in *HarborConfigurationModel) DeepCopy() *HarborConfigurationModel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProxySpec.
+func (in *HarborProxySpec) DeepCopy() *HarborProxySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationModel)
+	out := new(HarborProxySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationOidc) DeepCopyInto(out *HarborConfigurationOidc) {
+func (in *HarborRegistryEndpoint) DeepCopyInto(out *HarborRegistryEndpoint) {
 	*out = *in
-	if in.OidcVerifyCert != nil {
-		in, out := &in.OidcVerifyCert, &out.OidcVerifyCert
-		*out = new(bool)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpoint.
+func (in *HarborRegistryEndpoint) DeepCopy() *HarborRegistryEndpoint {
+	if in == nil {
+		return nil
 	}
-	if in.OidcAutoOnboard != nil {
-		in, out := &in.OidcAutoOnboard, &out.OidcAutoOnboard
-		*out = new(bool)
-		**out = **in
+	out := new(HarborRegistryEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRegistryEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationOidc.
-func (in *HarborConfigurationOidc) DeepCopy() *HarborConfigurationOidc {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRegistryEndpointList) DeepCopyInto(out *HarborRegistryEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborRegistryEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointList.
+func (in *HarborRegistryEndpointList) DeepCopy() *HarborRegistryEndpointList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationOidc)
+	out := new(HarborRegistryEndpointList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRegistryEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationSpec) DeepCopyInto(out *HarborConfigurationSpec) {
+func (in *HarborRegistryEndpointSpec) DeepCopyInto(out *HarborRegistryEndpointSpec) {
 	*out = *in
-	in.Configuration.DeepCopyInto(&out.Configuration)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationSpec.
-func (in *HarborConfigurationSpec) DeepCopy() *HarborConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointSpec.
+func (in *HarborRegistryEndpointSpec) DeepCopy() *HarborRegistryEndpointSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationSpec)
+	out := new(HarborRegistryEndpointSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationStatus) DeepCopyInto(out *HarborConfigurationStatus) {
+func (in *HarborRegistryEndpointStatus) DeepCopyInto(out *HarborRegistryEndpointStatus) {
 	*out = *in
 	if in.LastApplyTime != nil {
 		in, out := &in.LastApplyTime, &out.LastApplyTime
 		*out = (*in).DeepCopy()
 	}
-	if in.LastConfiguration != nil {
-		in, out := &in.LastConfiguration, &out.LastConfiguration
-		*out = new(HarborConfigurationSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationStatus.
-func (in *HarborConfigurationStatus) DeepCopy() *HarborConfigurationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRegistryEndpointStatus.
+func (in *HarborRegistryEndpointStatus) DeepCopy() *HarborRegistryEndpointStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationStatus)
+	out := new(HarborRegistryEndpointStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborConfigurationUaa) DeepCopyInto(out *HarborConfigurationUaa) {
+func (in *HarborReplicationExecution) DeepCopyInto(out *HarborReplicationExecution) {
 	*out = *in
-	if in.UaaVerifyCert != nil {
-		in, out := &in.UaaVerifyCert, &out.UaaVerifyCert
-		*out = new(bool)
-		**out = **in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborConfigurationUaa.
-func (in *HarborConfigurationUaa) DeepCopy() *HarborConfigurationUaa {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationExecution.
+func (in *HarborReplicationExecution) DeepCopy() *HarborReplicationExecution {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborConfigurationUaa)
+	out := new(HarborReplicationExecution)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborDatabaseSpec) DeepCopyInto(out *HarborDatabaseSpec) {
+func (in *HarborReplicationFilter) DeepCopyInto(out *HarborReplicationFilter) {
 	*out = *in
-	out.PostgresCredentials = in.PostgresCredentials
-	if in.Hosts != nil {
-		in, out := &in.Hosts, &out.Hosts
-		*out = make([]v1alpha1.PostgresHostSpec, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborDatabaseSpec.
-func (in *HarborDatabaseSpec) DeepCopy() *HarborDatabaseSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationFilter.
+func (in *HarborReplicationFilter) DeepCopy() *HarborReplicationFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborDatabaseSpec)
+	out := new(HarborReplicationFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborExporterCacheSpec) DeepCopyInto(out *HarborExporterCacheSpec) {
+func (in *HarborReplicationPolicy) DeepCopyInto(out *HarborReplicationPolicy) {
 	*out = *in
-	if in.Duration != nil {
-		in, out := &in.Duration, &out.Duration
-		*out = new(v1.Duration)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicy.
+func (in *HarborReplicationPolicy) DeepCopy() *HarborReplicationPolicy {
+	if in == nil {
+		return nil
 	}
-	if in.CleanInterval != nil {
-		in, out := &in.CleanInterval, &out.CleanInterval
-		*out = new(v1.Duration)
-		**out = **in
+	out := new(HarborReplicationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborReplicationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExporterCacheSpec.
-func (in *HarborExporterCacheSpec) DeepCopy() *HarborExporterCacheSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborReplicationPolicyList) DeepCopyInto(out *HarborReplicationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HarborReplicationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicyList.
+func (in *HarborReplicationPolicyList) DeepCopy() *HarborReplicationPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborExporterCacheSpec)
+	out := new(HarborReplicationPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborReplicationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborExposeComponentSpec) DeepCopyInto(out *HarborExposeComponentSpec) {
+func (in *HarborReplicationPolicySpec) DeepCopyInto(out *HarborReplicationPolicySpec) {
 	*out = *in
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(v1alpha1.ComponentsTLSSpec)
+	if in.DestinationNamespaceReplaceCount != nil {
+		in, out := &in.DestinationNamespaceReplaceCount, &out.DestinationNamespaceReplaceCount
+		*out = new(int8)
 		**out = **in
 	}
-	if in.Ingress != nil {
-		in, out := &in.Ingress, &out.Ingress
-		*out = new(HarborExposeIngressSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]HarborReplicationFilter, len(*in))
+		copy(*out, *in)
+	}
+	out.Trigger = in.Trigger
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Speed != nil {
+		in, out := &in.Speed, &out.Speed
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CopyByChunk != nil {
+		in, out := &in.CopyByChunk, &out.CopyByChunk
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeComponentSpec.
-func (in *HarborExposeComponentSpec) DeepCopy() *HarborExposeComponentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicySpec.
+func (in *HarborReplicationPolicySpec) DeepCopy() *HarborReplicationPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborExposeComponentSpec)
+	out := new(HarborReplicationPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborExposeIngressSpec) DeepCopyInto(out *HarborExposeIngressSpec) {
+func (in *HarborReplicationPolicyStatus) DeepCopyInto(out *HarborReplicationPolicyStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.LastExecution != nil {
+		in, out := &in.LastExecution, &out.LastExecution
+		*out = new(HarborReplicationExecution)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.IngressClassName != nil {
-		in, out := &in.IngressClassName, &out.IngressClassName
-		*out = new(string)
-		**out = **in
+	if in.LastApplyTime != nil {
+		in, out := &in.LastApplyTime, &out.LastApplyTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeIngressSpec.
-func (in *HarborExposeIngressSpec) DeepCopy() *HarborExposeIngressSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationPolicyStatus.
+func (in *HarborReplicationPolicyStatus) DeepCopy() *HarborReplicationPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborExposeIngressSpec)
+	out := new(HarborReplicationPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborExposeSpec) DeepCopyInto(out *HarborExposeSpec) {
+func (in *HarborReplicationTrigger) DeepCopyInto(out *HarborReplicationTrigger) {
 	*out = *in
-	in.Core.DeepCopyInto(&out.Core)
-	if in.Notary != nil {
-		in, out := &in.Notary, &out.Notary
-		*out = new(HarborExposeComponentSpec)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborExposeSpec.
-func (in *HarborExposeSpec) DeepCopy() *HarborExposeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborReplicationTrigger.
+func (in *HarborReplicationTrigger) DeepCopy() *HarborReplicationTrigger {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborExposeSpec)
+	out := new(HarborReplicationTrigger)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborInternalTLSSpec) DeepCopyInto(out *HarborInternalTLSSpec) {
+func (in *HarborRestore) DeepCopyInto(out *HarborRestore) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborInternalTLSSpec.
-func (in *HarborInternalTLSSpec) DeepCopy() *HarborInternalTLSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestore.
+func (in *HarborRestore) DeepCopy() *HarborRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborInternalTLSSpec)
+	out := new(HarborRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HarborRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborList) DeepCopyInto(out *HarborList) {
+func (in *HarborRestoreList) DeepCopyInto(out *HarborRestoreList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Harbor, len(*in))
+		*out = make([]HarborRestore, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborList.
-func (in *HarborList) DeepCopy() *HarborList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreList.
+func (in *HarborRestoreList) DeepCopy() *HarborRestoreList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborList)
+	out := new(HarborRestoreList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborList) DeepCopyObject() runtime.Object {
+func (in *HarborRestoreList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1999,7 +3192,70 @@ func (in *HarborList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProject) DeepCopyInto(out *HarborProject) {
+func (in *HarborRestoreSpec) DeepCopyInto(out *HarborRestoreSpec) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]HarborBackupComponent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreSpec.
+func (in *HarborRestoreSpec) DeepCopy() *HarborRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRestoreStatus) DeepCopyInto(out *HarborRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]HarborBackupComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRestoreStatus.
+func (in *HarborRestoreStatus) DeepCopy() *HarborRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccess) DeepCopyInto(out *HarborRobotAccess) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccess.
+func (in *HarborRobotAccess) DeepCopy() *HarborRobotAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborRobotAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborRobotAccount) DeepCopyInto(out *HarborRobotAccount) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -2007,18 +3263,18 @@ func (in *HarborProject) DeepCopyInto(out *HarborProject) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProject.
-func (in *HarborProject) DeepCopy() *HarborProject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccount.
+func (in *HarborRobotAccount) DeepCopy() *HarborRobotAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProject)
+	out := new(HarborRobotAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborProject) DeepCopyObject() runtime.Object {
+func (in *HarborRobotAccount) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2026,31 +3282,31 @@ func (in *HarborProject) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProjectList) DeepCopyInto(out *HarborProjectList) {
+func (in *HarborRobotAccountList) DeepCopyInto(out *HarborRobotAccountList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HarborProject, len(*in))
+		*out = make([]HarborRobotAccount, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectList.
-func (in *HarborProjectList) DeepCopy() *HarborProjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountList.
+func (in *HarborRobotAccountList) DeepCopy() *HarborRobotAccountList {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProjectList)
+	out := new(HarborRobotAccountList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HarborProjectList) DeepCopyObject() runtime.Object {
+func (in *HarborRobotAccountList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -2058,137 +3314,91 @@ func (in *HarborProjectList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProjectMember) DeepCopyInto(out *HarborProjectMember) {
+func (in *HarborRobotAccountSecret) DeepCopyInto(out *HarborRobotAccountSecret) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectMember.
-func (in *HarborProjectMember) DeepCopy() *HarborProjectMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountSecret.
+func (in *HarborRobotAccountSecret) DeepCopy() *HarborRobotAccountSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProjectMember)
+	out := new(HarborRobotAccountSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProjectMetadata) DeepCopyInto(out *HarborProjectMetadata) {
+func (in *HarborRobotAccountSpec) DeepCopyInto(out *HarborRobotAccountSpec) {
 	*out = *in
-	if in.EnableContentTrust != nil {
-		in, out := &in.EnableContentTrust, &out.EnableContentTrust
-		*out = new(bool)
-		**out = **in
-	}
-	if in.EnableContentTrustCosign != nil {
-		in, out := &in.EnableContentTrustCosign, &out.EnableContentTrustCosign
-		*out = new(bool)
-		**out = **in
-	}
-	if in.AutoScan != nil {
-		in, out := &in.AutoScan, &out.AutoScan
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PreventVulnerable != nil {
-		in, out := &in.PreventVulnerable, &out.PreventVulnerable
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Public != nil {
-		in, out := &in.Public, &out.Public
-		*out = new(bool)
-		**out = **in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]HarborRobotPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.ReuseSysCveAllowlist != nil {
-		in, out := &in.ReuseSysCveAllowlist, &out.ReuseSysCveAllowlist
-		*out = new(bool)
+	out.Secret = in.Secret
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectMetadata.
-func (in *HarborProjectMetadata) DeepCopy() *HarborProjectMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountSpec.
+func (in *HarborRobotAccountSpec) DeepCopy() *HarborRobotAccountSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProjectMetadata)
+	out := new(HarborRobotAccountSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProjectSpec) DeepCopyInto(out *HarborProjectSpec) {
+func (in *HarborRobotAccountStatus) DeepCopyInto(out *HarborRobotAccountStatus) {
 	*out = *in
-	if in.CveAllowList != nil {
-		in, out := &in.CveAllowList, &out.CveAllowList
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.HarborProjectMetadata != nil {
-		in, out := &in.HarborProjectMetadata, &out.HarborProjectMetadata
-		*out = new(HarborProjectMetadata)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HarborProjectMemberships != nil {
-		in, out := &in.HarborProjectMemberships, &out.HarborProjectMemberships
-		*out = make([]*HarborProjectMember, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(HarborProjectMember)
-				**out = **in
-			}
-		}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectSpec.
-func (in *HarborProjectSpec) DeepCopy() *HarborProjectSpec {
-	if in == nil {
-		return nil
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
 	}
-	out := new(HarborProjectSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProjectStatus) DeepCopyInto(out *HarborProjectStatus) {
-	*out = *in
 	if in.LastApplyTime != nil {
 		in, out := &in.LastApplyTime, &out.LastApplyTime
 		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProjectStatus.
-func (in *HarborProjectStatus) DeepCopy() *HarborProjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotAccountStatus.
+func (in *HarborRobotAccountStatus) DeepCopy() *HarborRobotAccountStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProjectStatus)
+	out := new(HarborRobotAccountStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborProxySpec) DeepCopyInto(out *HarborProxySpec) {
+func (in *HarborRobotPermission) DeepCopyInto(out *HarborRobotPermission) {
 	*out = *in
-	in.ProxySpec.DeepCopyInto(&out.ProxySpec)
-	if in.Components != nil {
-		in, out := &in.Components, &out.Components
-		*out = make([]string, len(*in))
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = make([]HarborRobotAccess, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborProxySpec.
-func (in *HarborProxySpec) DeepCopy() *HarborProxySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborRobotPermission.
+func (in *HarborRobotPermission) DeepCopy() *HarborRobotPermission {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborProxySpec)
+	out := new(HarborRobotPermission)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2199,7 +3409,7 @@ func (in *HarborServerConfiguration) DeepCopyInto(out *HarborServerConfiguration
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerConfiguration.
@@ -2260,39 +3470,131 @@ func (in *HarborServerConfigurationSpec) DeepCopyInto(out *HarborServerConfigura
 		*out = new(AccessCredential)
 		**out = **in
 	}
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.NamespaceSelector != nil {
-		in, out := &in.NamespaceSelector, &out.NamespaceSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(HarborServerImageVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		*out = new(HarborServerImagePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HarborServerHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerConfigurationSpec.
+func (in *HarborServerConfigurationSpec) DeepCopy() *HarborServerConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborServerConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborServerConfigurationStatus) DeepCopyInto(out *HarborServerConfigurationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerConfigurationStatus.
+func (in *HarborServerConfigurationStatus) DeepCopy() *HarborServerConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborServerConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborServerHealthCheck) DeepCopyInto(out *HarborServerHealthCheck) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerHealthCheck.
+func (in *HarborServerHealthCheck) DeepCopy() *HarborServerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HarborServerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HarborServerImagePolicy) DeepCopyInto(out *HarborServerImagePolicy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerConfigurationSpec.
-func (in *HarborServerConfigurationSpec) DeepCopy() *HarborServerConfigurationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerImagePolicy.
+func (in *HarborServerImagePolicy) DeepCopy() *HarborServerImagePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborServerConfigurationSpec)
+	out := new(HarborServerImagePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HarborServerConfigurationStatus) DeepCopyInto(out *HarborServerConfigurationStatus) {
+func (in *HarborServerImageVerification) DeepCopyInto(out *HarborServerImageVerification) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CacheDuration != nil {
+		in, out := &in.CacheDuration, &out.CacheDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerConfigurationStatus.
-func (in *HarborServerConfigurationStatus) DeepCopy() *HarborServerConfigurationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HarborServerImageVerification.
+func (in *HarborServerImageVerification) DeepCopy() *HarborServerImageVerification {
 	if in == nil {
 		return nil
 	}
-	out := new(HarborServerConfigurationStatus)
+	out := new(HarborServerImageVerification)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2564,6 +3866,131 @@ func (in *HarborStorageTrivyStorageSpec) DeepCopy() *HarborStorageTrivyStorageSp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteMatch) DeepCopyInto(out *ImageRewriteMatch) {
+	*out = *in
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(ImageRewriteRepositoryMatch)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteMatch.
+func (in *ImageRewriteMatch) DeepCopy() *ImageRewriteMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewritePolicy) DeepCopyInto(out *ImageRewritePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewritePolicy.
+func (in *ImageRewritePolicy) DeepCopy() *ImageRewritePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewritePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRewritePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewritePolicyList) DeepCopyInto(out *ImageRewritePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageRewritePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewritePolicyList.
+func (in *ImageRewritePolicyList) DeepCopy() *ImageRewritePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewritePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageRewritePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewritePolicySpec) DeepCopyInto(out *ImageRewritePolicySpec) {
+	*out = *in
+	in.Match.DeepCopyInto(&out.Match)
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewritePolicySpec.
+func (in *ImageRewritePolicySpec) DeepCopy() *ImageRewritePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewritePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteRepositoryMatch) DeepCopyInto(out *ImageRewriteRepositoryMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteRepositoryMatch.
+func (in *ImageRewriteRepositoryMatch) DeepCopy() *ImageRewriteRepositoryMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteRepositoryMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRewriteTarget) DeepCopyInto(out *ImageRewriteTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRewriteTarget.
+func (in *ImageRewriteTarget) DeepCopy() *ImageRewriteTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRewriteTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobService) DeepCopyInto(out *JobService) {
 	*out = *in
@@ -2953,6 +4380,65 @@ func (in *MinIOSpec) DeepCopy() *MinIOSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedHarborServerConfiguration) DeepCopyInto(out *NamespacedHarborServerConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedHarborServerConfiguration.
+func (in *NamespacedHarborServerConfiguration) DeepCopy() *NamespacedHarborServerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedHarborServerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedHarborServerConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedHarborServerConfigurationList) DeepCopyInto(out *NamespacedHarborServerConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedHarborServerConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedHarborServerConfigurationList.
+func (in *NamespacedHarborServerConfigurationList) DeepCopy() *NamespacedHarborServerConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedHarborServerConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedHarborServerConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotaryComponentSpec) DeepCopyInto(out *NotaryComponentSpec) {
 	*out = *in
@@ -3432,13 +4918,28 @@ func (in *PostgreSQLSpec) DeepCopy() *PostgreSQLSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgreSQLUpgradeSpec) DeepCopyInto(out *PostgreSQLUpgradeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgreSQLUpgradeSpec.
+func (in *PostgreSQLUpgradeSpec) DeepCopy() *PostgreSQLUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgreSQLUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSecretBinding) DeepCopyInto(out *PullSecretBinding) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretBinding.
@@ -3491,9 +4992,63 @@ func (in *PullSecretBindingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullSecretBindingRegistry) DeepCopyInto(out *PullSecretBindingRegistry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretBindingRegistry.
+func (in *PullSecretBindingRegistry) DeepCopy() *PullSecretBindingRegistry {
+	if in == nil {
+		return nil
+	}
+	out := new(PullSecretBindingRegistry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullSecretBindingRobot) DeepCopyInto(out *PullSecretBindingRobot) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretBindingRobot.
+func (in *PullSecretBindingRobot) DeepCopy() *PullSecretBindingRobot {
+	if in == nil {
+		return nil
+	}
+	out := new(PullSecretBindingRobot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSecretBindingSpec) DeepCopyInto(out *PullSecretBindingSpec) {
 	*out = *in
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make([]PullSecretBindingRegistry, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountSelector != nil {
+		in, out := &in.ServiceAccountSelector, &out.ServiceAccountSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RotateBefore != nil {
+		in, out := &in.RotateBefore, &out.RotateBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretBindingSpec.
@@ -3509,6 +5064,38 @@ func (in *PullSecretBindingSpec) DeepCopy() *PullSecretBindingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSecretBindingStatus) DeepCopyInto(out *PullSecretBindingStatus) {
 	*out = *in
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Robots != nil {
+		in, out := &in.Robots, &out.Robots
+		*out = make([]PullSecretBindingRobot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingRotations != nil {
+		in, out := &in.PendingRotations, &out.PendingRotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullSecretBindingStatus.
@@ -3547,6 +5134,27 @@ func (in *RedisFailoverSpec) DeepCopy() *RedisFailoverSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisReplicationSpec) DeepCopyInto(out *RedisReplicationSpec) {
+	*out = *in
+	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(RedisServer)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisReplicationSpec.
+func (in *RedisReplicationSpec) DeepCopy() *RedisReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSentinel) DeepCopyInto(out *RedisSentinel) {
 	*out = *in
@@ -5352,6 +6960,11 @@ func (in *ZlandoPostgreSQLSpec) DeepCopyInto(out *ZlandoPostgreSQLSpec) {
 	*out = *in
 	in.ImageSpec.DeepCopyInto(&out.ImageSpec)
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(PostgreSQLUpgradeSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZlandoPostgreSQLSpec.
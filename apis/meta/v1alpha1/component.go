@@ -7,7 +7,12 @@ import (
 
 	"github.com/goharbor/harbor-operator/pkg/config"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/kustomize/kstatus/status"
 )
 
@@ -104,6 +109,145 @@ type ComponentSpec struct {
 	// Cannot be updated.
 	// More info: https://kubernetes.io/docs/concepts/configuration/manage-compute-resources-container/
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// If specified, the pod's scheduling constraints, e.g. the anti-affinity spreading the replicas across the nodes.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// TopologySpreadConstraints describes how the pods of the component ought to spread across topology domains, e.g. zones.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PriorityClassName is the name of the PriorityClass of the pods.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// RuntimeClassName is the name of the RuntimeClass used to run the pods.
+	// More info: https://kubernetes.io/docs/concepts/containers/runtime-class/
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PodSecurityContext holds the pod-level security attributes.
+	// Default to the user and the group of the Harbor images, with the RuntimeDefault seccomp profile.
+	// The attributes set are merged over the defaults.
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// SecurityContext holds the security attributes of the containers.
+	// Default to the requirements of the restricted Pod Security Standard: no privilege escalation,
+	// all the capabilities dropped, non-root user and RuntimeDefault seccomp profile.
+	// The attributes set are merged over the defaults.
+	// More info: https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// PodDisruptionBudget limits the number of pods of the component disrupted at the same time, e.g. by node drains.
+	// No PodDisruptionBudget is created when it is not specified, and the existing one is deleted.
+	// More info: https://kubernetes.io/docs/concepts/workloads/pods/disruptions/
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Autoscaling creates a HorizontalPodAutoscaler scaling the component, the replicas are then ignored.
+	// Supported by the core, jobservice, portal, registry and trivy components, and by the proxy of the service expose.
+	// Requires Kubernetes 1.23+.
+	// More info: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler of a component.
+// When no target is specified, the component is scaled to use 80% of its requested CPU.
+type AutoscalingSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// MinReplicas is the lower limit for the number of replicas.
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// MaxReplicas is the upper limit for the number of replicas.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TargetCPUUtilizationPercentage is the target average CPU utilization, in percentage of the requested CPU.
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// TargetMemoryUtilizationPercentage is the target average memory utilization, in percentage of the requested memory.
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Metrics are the additional metrics to scale on, e.g. custom or external metrics.
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Behavior configures the scaling behavior in both Up and Down directions.
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+const defaultTargetCPUUtilizationPercentage int32 = 80
+
+// PodDisruptionBudgetSpec configures the PodDisruptionBudget of a component.
+// At most one of minAvailable and maxUnavailable can be specified.
+// When none is specified, at most one pod is unavailable when the component runs or can be scaled to several replicas,
+// and no PodDisruptionBudget is created for a single replica, so that the node drains are not blocked.
+type PodDisruptionBudgetSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// MinAvailable is the number or the percentage of pods that must still be available after an eviction.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XIntOrString
+	// MaxUnavailable is the number or the percentage of pods that can be unavailable after an eviction.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+var ErrInvalidPodDisruptionBudget = errors.New("only one of minAvailable and maxUnavailable can be specified")
+
+// Validate rejects the specs setting both minAvailable and maxUnavailable, rootPath being the path of the spec.
+func (spec *PodDisruptionBudgetSpec) Validate(rootPath *field.Path) *field.Error {
+	if spec == nil {
+		return nil
+	}
+
+	if spec.MinAvailable != nil && spec.MaxUnavailable != nil {
+		return field.Forbidden(rootPath.Child("maxUnavailable"), ErrInvalidPodDisruptionBudget.Error())
+	}
+
+	return nil
+}
+
+// Validate checks the spec of the component, rootPath being the path of the component.
+func (c *ComponentSpec) Validate(rootPath *field.Path) *field.Error {
+	if c == nil {
+		return nil
+	}
+
+	return c.PodDisruptionBudget.Validate(rootPath.Child("podDisruptionBudget"))
+}
+
+// DefaultSecurityContext returns the security context of the containers meeting the restricted Pod Security Standard.
+func DefaultSecurityContext() *corev1.SecurityContext {
+	varFalse := false
+	varTrue := true
+
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &varFalse,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		RunAsNonRoot: &varTrue,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
 }
 
 func (c *ComponentSpec) ApplyToDeployment(deploy *appsv1.Deployment) {
@@ -127,6 +271,253 @@ func (c *ComponentSpec) ApplyToDeployment(deploy *appsv1.Deployment) {
 	deploy.Spec.Template.Spec.ImagePullSecrets = c.ImagePullSecrets
 	deploy.Spec.Template.Spec.NodeSelector = c.NodeSelector
 	deploy.Spec.Template.Spec.Tolerations = c.Tolerations
+	deploy.Spec.Template.Spec.Affinity = c.Affinity
+	deploy.Spec.Template.Spec.TopologySpreadConstraints = c.TopologySpreadConstraints
+	deploy.Spec.Template.Spec.PriorityClassName = c.PriorityClassName
+	deploy.Spec.Template.Spec.RuntimeClassName = c.RuntimeClassName
+
+	c.applySecurityContext(&deploy.Spec.Template.Spec)
+}
+
+// GetPodDisruptionBudget returns the PodDisruptionBudget of the pods of the deployment,
+// nil when the component does not need any.
+func (c *ComponentSpec) GetPodDisruptionBudget(deploy *appsv1.Deployment) (*policyv1.PodDisruptionBudget, error) {
+	if c.PodDisruptionBudget == nil {
+		return nil, nil
+	}
+
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: deploy.Spec.Selector.DeepCopy(),
+	}
+
+	switch {
+	case c.PodDisruptionBudget.MinAvailable != nil && c.PodDisruptionBudget.MaxUnavailable != nil:
+		return nil, ErrInvalidPodDisruptionBudget
+	case c.PodDisruptionBudget.MinAvailable != nil:
+		minAvailable := *c.PodDisruptionBudget.MinAvailable
+		spec.MinAvailable = &minAvailable
+	case c.PodDisruptionBudget.MaxUnavailable != nil:
+		maxUnavailable := *c.PodDisruptionBudget.MaxUnavailable
+		spec.MaxUnavailable = &maxUnavailable
+	default:
+		if c.getMaxReplicas(deploy) <= 1 {
+			return nil, nil
+		}
+
+		maxUnavailable := intstr.FromInt(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploy.GetName(),
+			Namespace: deploy.GetNamespace(),
+		},
+		Spec: spec,
+	}, nil
+}
+
+func (c *ComponentSpec) getMaxReplicas(deploy *appsv1.Deployment) int32 {
+	if c.Autoscaling != nil {
+		return c.Autoscaling.MaxReplicas
+	}
+
+	if deploy.Spec.Replicas == nil {
+		return 1
+	}
+
+	return *deploy.Spec.Replicas
+}
+
+// GetHorizontalPodAutoscaler returns the HorizontalPodAutoscaler of the deployment, nil when the component is not autoscaled.
+func (c *ComponentSpec) GetHorizontalPodAutoscaler(deploy *appsv1.Deployment) *autoscalingv2.HorizontalPodAutoscaler {
+	if c.Autoscaling == nil {
+		return nil
+	}
+
+	metrics := make([]autoscalingv2.MetricSpec, 0, len(c.Autoscaling.Metrics)+2) //nolint:gomnd
+
+	if c.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, *c.Autoscaling.TargetCPUUtilizationPercentage))
+	}
+
+	if c.Autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, *c.Autoscaling.TargetMemoryUtilizationPercentage))
+	}
+
+	for _, metric := range c.Autoscaling.Metrics {
+		metrics = append(metrics, *metric.DeepCopy())
+	}
+
+	if len(metrics) == 0 {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, defaultTargetCPUUtilizationPercentage))
+	}
+
+	minReplicas := int32(1)
+	if c.Autoscaling.MinReplicas != nil {
+		minReplicas = *c.Autoscaling.MinReplicas
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploy.GetName(),
+			Namespace: deploy.GetNamespace(),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       deploy.GetName(),
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: c.Autoscaling.MaxReplicas,
+			Metrics:     metrics,
+			Behavior:    c.Autoscaling.Behavior.DeepCopy(),
+		},
+	}
+}
+
+func resourceMetric(name corev1.ResourceName, averageUtilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &averageUtilization,
+			},
+		},
+	}
+}
+
+// applySecurityContext sets the security contexts of the pod and of its containers.
+// The attributes of the component are merged over the default pod security context of the component,
+// with the RuntimeDefault seccomp profile when none is set, and over the default security context of the containers.
+func (c *ComponentSpec) applySecurityContext(pod *corev1.PodSpec) {
+	if pod.SecurityContext == nil {
+		pod.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	if pod.SecurityContext.SeccompProfile == nil {
+		pod.SecurityContext.SeccompProfile = &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		}
+	}
+
+	mergePodSecurityContext(pod.SecurityContext, c.PodSecurityContext)
+
+	securityContext := DefaultSecurityContext()
+	mergeSecurityContext(securityContext, c.SecurityContext)
+
+	for i := range pod.InitContainers {
+		pod.InitContainers[i].SecurityContext = securityContext.DeepCopy()
+	}
+
+	for i := range pod.Containers {
+		pod.Containers[i].SecurityContext = securityContext.DeepCopy()
+	}
+}
+
+// mergePodSecurityContext sets the attributes of the pod security context which are set in the override.
+func mergePodSecurityContext(securityContext, override *corev1.PodSecurityContext) {
+	if override == nil {
+		return
+	}
+
+	override = override.DeepCopy()
+
+	if override.SELinuxOptions != nil {
+		securityContext.SELinuxOptions = override.SELinuxOptions
+	}
+
+	if override.WindowsOptions != nil {
+		securityContext.WindowsOptions = override.WindowsOptions
+	}
+
+	if override.RunAsUser != nil {
+		securityContext.RunAsUser = override.RunAsUser
+	}
+
+	if override.RunAsGroup != nil {
+		securityContext.RunAsGroup = override.RunAsGroup
+	}
+
+	if override.RunAsNonRoot != nil {
+		securityContext.RunAsNonRoot = override.RunAsNonRoot
+	}
+
+	if override.SupplementalGroups != nil {
+		securityContext.SupplementalGroups = override.SupplementalGroups
+	}
+
+	if override.FSGroup != nil {
+		securityContext.FSGroup = override.FSGroup
+	}
+
+	if override.Sysctls != nil {
+		securityContext.Sysctls = override.Sysctls
+	}
+
+	if override.FSGroupChangePolicy != nil {
+		securityContext.FSGroupChangePolicy = override.FSGroupChangePolicy
+	}
+
+	if override.SeccompProfile != nil {
+		securityContext.SeccompProfile = override.SeccompProfile
+	}
+}
+
+// mergeSecurityContext sets the attributes of the container security context which are set in the override.
+func mergeSecurityContext(securityContext, override *corev1.SecurityContext) {
+	if override == nil {
+		return
+	}
+
+	override = override.DeepCopy()
+
+	if override.Capabilities != nil {
+		securityContext.Capabilities = override.Capabilities
+	}
+
+	if override.Privileged != nil {
+		securityContext.Privileged = override.Privileged
+	}
+
+	if override.SELinuxOptions != nil {
+		securityContext.SELinuxOptions = override.SELinuxOptions
+	}
+
+	if override.WindowsOptions != nil {
+		securityContext.WindowsOptions = override.WindowsOptions
+	}
+
+	if override.RunAsUser != nil {
+		securityContext.RunAsUser = override.RunAsUser
+	}
+
+	if override.RunAsGroup != nil {
+		securityContext.RunAsGroup = override.RunAsGroup
+	}
+
+	if override.RunAsNonRoot != nil {
+		securityContext.RunAsNonRoot = override.RunAsNonRoot
+	}
+
+	if override.ReadOnlyRootFilesystem != nil {
+		securityContext.ReadOnlyRootFilesystem = override.ReadOnlyRootFilesystem
+	}
+
+	if override.AllowPrivilegeEscalation != nil {
+		securityContext.AllowPrivilegeEscalation = override.AllowPrivilegeEscalation
+	}
+
+	if override.ProcMount != nil {
+		securityContext.ProcMount = override.ProcMount
+	}
+
+	if override.SeccompProfile != nil {
+		securityContext.SeccompProfile = override.SeccompProfile
+	}
 }
 
 // ComponentStatus represents the current status of the resource.
@@ -0,0 +1,342 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+func TestApplyToDeployment(t *testing.T) {
+	pullPolicy := corev1.PullAlways
+
+	spec := &harbormetav1.ComponentSpec{
+		Replicas:           pointer.Int32(3),
+		ServiceAccountName: "harbor",
+		ImageSpec: harbormetav1.ImageSpec{
+			ImagePullPolicy:  &pullPolicy,
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+		},
+		NodeSelector:      map[string]string{"disk": "ssd"},
+		TemplateLabels:    map[string]string{"team": "registry"},
+		PriorityClassName: "high-priority",
+		RuntimeClassName:  pointer.String("gvisor"),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+
+	deploy := newDeployment()
+	spec.ApplyToDeployment(deploy)
+
+	require.Equal(t, pointer.Int32(3), deploy.Spec.Replicas)
+
+	podSpec := deploy.Spec.Template.Spec
+	require.Equal(t, "harbor", podSpec.ServiceAccountName)
+	require.Equal(t, spec.ImagePullSecrets, podSpec.ImagePullSecrets)
+	require.Equal(t, spec.NodeSelector, podSpec.NodeSelector)
+	require.Equal(t, "high-priority", podSpec.PriorityClassName)
+	require.Equal(t, "gvisor", *podSpec.RuntimeClassName)
+	require.Equal(t, map[string]string{"app": "registry", "team": "registry"}, deploy.Spec.Template.Labels)
+
+	for _, container := range podSpec.Containers {
+		require.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
+		require.Equal(t, spec.Resources, container.Resources)
+	}
+}
+
+func TestApplyToDeploymentDefaultSecurityContext(t *testing.T) {
+	deploy := newDeployment()
+	(&harbormetav1.ComponentSpec{}).ApplyToDeployment(deploy)
+
+	podSpec := deploy.Spec.Template.Spec
+	require.Equal(t, &corev1.PodSecurityContext{
+		RunAsUser:      pointer.Int64(10000),
+		RunAsGroup:     pointer.Int64(10000),
+		FSGroup:        pointer.Int64(10000),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}, podSpec.SecurityContext, "the defaults of the component are kept")
+
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		require.Equal(t, harbormetav1.DefaultSecurityContext(), container.SecurityContext, container.Name)
+	}
+
+	for _, container := range podSpec.Containers {
+		require.Equal(t, corev1.PullIfNotPresent, container.ImagePullPolicy)
+	}
+}
+
+func TestApplyToDeploymentSecurityContext(t *testing.T) {
+	spec := &harbormetav1.ComponentSpec{
+		PodSecurityContext: &corev1.PodSecurityContext{
+			RunAsUser:          pointer.Int64(20000),
+			SupplementalGroups: []int64{30000},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			ReadOnlyRootFilesystem: pointer.Bool(true),
+			RunAsNonRoot:           pointer.Bool(false),
+		},
+	}
+
+	deploy := newDeployment()
+	spec.ApplyToDeployment(deploy)
+
+	podSpec := deploy.Spec.Template.Spec
+	require.Equal(t, &corev1.PodSecurityContext{
+		RunAsUser:          pointer.Int64(20000),
+		RunAsGroup:         pointer.Int64(10000),
+		FSGroup:            pointer.Int64(10000),
+		SupplementalGroups: []int64{30000},
+		SeccompProfile:     &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}, podSpec.SecurityContext, "the attributes set are merged over the defaults of the component")
+
+	expected := harbormetav1.DefaultSecurityContext()
+	expected.ReadOnlyRootFilesystem = pointer.Bool(true)
+	expected.RunAsNonRoot = pointer.Bool(false)
+
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		require.Equal(t, expected, container.SecurityContext, container.Name)
+	}
+
+	spec.PodSecurityContext.SupplementalGroups[0] = 40000
+	require.Equal(t, []int64{30000}, podSpec.SecurityContext.SupplementalGroups, "not shared with the spec")
+}
+
+func TestGetPodDisruptionBudget(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+
+	cases := map[string]struct {
+		spec     harbormetav1.ComponentSpec
+		replicas *int32
+		expected *policyv1.PodDisruptionBudgetSpec
+		err      error
+	}{
+		"not configured": {
+			spec:     harbormetav1.ComponentSpec{},
+			replicas: pointer.Int32(3),
+		},
+		"default with several replicas": {
+			spec:     harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{}},
+			replicas: pointer.Int32(3),
+			expected: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &one},
+		},
+		"default with a single replica": {
+			spec:     harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{}},
+			replicas: pointer.Int32(1),
+		},
+		"default with the default replicas": {
+			spec: harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{}},
+		},
+		"default with autoscaling": {
+			spec: harbormetav1.ComponentSpec{
+				PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{},
+				Autoscaling:         &harbormetav1.AutoscalingSpec{MaxReplicas: 5},
+			},
+			replicas: pointer.Int32(1),
+			expected: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &one},
+		},
+		"minAvailable": {
+			spec:     harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{MinAvailable: &half}},
+			replicas: pointer.Int32(1),
+			expected: &policyv1.PodDisruptionBudgetSpec{MinAvailable: &half},
+		},
+		"maxUnavailable": {
+			spec:     harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{MaxUnavailable: &half}},
+			replicas: pointer.Int32(4),
+			expected: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &half},
+		},
+		"both bounds": {
+			spec: harbormetav1.ComponentSpec{PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{
+				MinAvailable:   &one,
+				MaxUnavailable: &half,
+			}},
+			replicas: pointer.Int32(3),
+			err:      harbormetav1.ErrInvalidPodDisruptionBudget,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			deploy := newDeployment()
+			deploy.Spec.Replicas = tc.replicas
+
+			pdb, err := tc.spec.GetPodDisruptionBudget(deploy)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expected == nil {
+				require.Nil(t, pdb)
+
+				return
+			}
+
+			require.Equal(t, "harbor", pdb.GetNamespace())
+			require.Equal(t, "harbor-registry", pdb.GetName())
+			require.Equal(t, deploy.Spec.Selector, pdb.Spec.Selector)
+			require.Equal(t, tc.expected.MinAvailable, pdb.Spec.MinAvailable)
+			require.Equal(t, tc.expected.MaxUnavailable, pdb.Spec.MaxUnavailable)
+		})
+	}
+}
+
+func TestGetHorizontalPodAutoscaler(t *testing.T) {
+	deploy := newDeployment()
+
+	require.Nil(t, (&harbormetav1.ComponentSpec{}).GetHorizontalPodAutoscaler(deploy))
+
+	t.Run("default", func(t *testing.T) {
+		hpa := (&harbormetav1.ComponentSpec{
+			Autoscaling: &harbormetav1.AutoscalingSpec{MaxReplicas: 5},
+		}).GetHorizontalPodAutoscaler(deploy)
+
+		require.Equal(t, "harbor", hpa.GetNamespace())
+		require.Equal(t, "harbor-registry", hpa.GetName())
+		require.Equal(t, autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "harbor-registry",
+		}, hpa.Spec.ScaleTargetRef)
+		require.Equal(t, pointer.Int32(1), hpa.Spec.MinReplicas)
+		require.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+		require.Equal(t, []autoscalingv2.MetricSpec{utilizationMetric(corev1.ResourceCPU, 80)}, hpa.Spec.Metrics)
+		require.Nil(t, hpa.Spec.Behavior)
+	})
+
+	t.Run("targets", func(t *testing.T) {
+		external := autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "queue_length"},
+				Target: autoscalingv2.MetricTarget{
+					Type:  autoscalingv2.ValueMetricType,
+					Value: resource.NewQuantity(30, resource.DecimalSI),
+				},
+			},
+		}
+		behavior := &autoscalingv2.HorizontalPodAutoscalerBehavior{
+			ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: pointer.Int32(600)},
+		}
+
+		hpa := (&harbormetav1.ComponentSpec{
+			Autoscaling: &harbormetav1.AutoscalingSpec{
+				MinReplicas:                       pointer.Int32(2),
+				MaxReplicas:                       10,
+				TargetCPUUtilizationPercentage:    pointer.Int32(60),
+				TargetMemoryUtilizationPercentage: pointer.Int32(70),
+				Metrics:                           []autoscalingv2.MetricSpec{external},
+				Behavior:                          behavior,
+			},
+		}).GetHorizontalPodAutoscaler(deploy)
+
+		require.Equal(t, pointer.Int32(2), hpa.Spec.MinReplicas)
+		require.Equal(t, int32(10), hpa.Spec.MaxReplicas)
+		require.Equal(t, []autoscalingv2.MetricSpec{
+			utilizationMetric(corev1.ResourceCPU, 60),
+			utilizationMetric(corev1.ResourceMemory, 70),
+			external,
+		}, hpa.Spec.Metrics)
+		require.Equal(t, behavior, hpa.Spec.Behavior)
+		require.NotSame(t, behavior, hpa.Spec.Behavior)
+	})
+
+	t.Run("custom metrics only", func(t *testing.T) {
+		pods := autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(100, resource.DecimalSI),
+				},
+			},
+		}
+
+		hpa := (&harbormetav1.ComponentSpec{
+			Autoscaling: &harbormetav1.AutoscalingSpec{
+				MaxReplicas: 3,
+				Metrics:     []autoscalingv2.MetricSpec{pods},
+			},
+		}).GetHorizontalPodAutoscaler(deploy)
+
+		require.Equal(t, []autoscalingv2.MetricSpec{pods}, hpa.Spec.Metrics)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	one := intstr.FromInt(1)
+	path := field.NewPath("spec", "core")
+
+	require.Nil(t, (*harbormetav1.ComponentSpec)(nil).Validate(path))
+	require.Nil(t, (&harbormetav1.ComponentSpec{}).Validate(path))
+	require.Nil(t, (&harbormetav1.ComponentSpec{
+		PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{MinAvailable: &one},
+	}).Validate(path))
+
+	err := (&harbormetav1.ComponentSpec{
+		PodDisruptionBudget: &harbormetav1.PodDisruptionBudgetSpec{MinAvailable: &one, MaxUnavailable: &one},
+	}).Validate(path)
+	require.NotNil(t, err)
+	require.Equal(t, field.ErrorTypeForbidden, err.Type)
+	require.Equal(t, "spec.core.podDisruptionBudget.maxUnavailable", err.Field)
+}
+
+func utilizationMetric(name corev1.ResourceName, averageUtilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &averageUtilization,
+			},
+		},
+	}
+}
+
+func newDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "harbor",
+			Name:      "harbor-registry",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "registry"},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "registry"},
+				},
+				Spec: corev1.PodSpec{
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsUser:  pointer.Int64(10000),
+						RunAsGroup: pointer.Int64(10000),
+						FSGroup:    pointer.Int64(10000),
+					},
+					InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+					Containers: []corev1.Container{
+						{Name: "registry", Image: "goharbor/registry-photon"},
+						{Name: "registryctl", Image: "goharbor/harbor-registryctl"},
+					},
+				},
+			},
+		},
+	}
+}
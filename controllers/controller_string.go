@@ -26,11 +26,19 @@ func _() {
 	_ = x[HarborServerConfiguration-15]
 	_ = x[PullSecretBinding-16]
 	_ = x[Namespace-17]
+	_ = x[HarborBackup-18]
+	_ = x[HarborRestore-19]
+	_ = x[HarborBackupSchedule-20]
+	_ = x[HarborRegistryEndpoint-21]
+	_ = x[HarborReplicationPolicy-22]
+	_ = x[HarborRobotAccount-23]
+	_ = x[ImageRewritePolicy-24]
+	_ = x[NamespacedHarborServerConfiguration-25]
 }
 
-const _Controller_name = "corejobserviceportalregistryregistryctlchartmuseumexporternotaryservernotarysignertrivyharborharborclusterharborconfigurationcmharborconfigurationharborprojectharborserverconfigurationpullsecretbindingnamespace"
+const _Controller_name = "corejobserviceportalregistryregistryctlchartmuseumexporternotaryservernotarysignertrivyharborharborclusterharborconfigurationcmharborconfigurationharborprojectharborserverconfigurationpullsecretbindingnamespaceharborbackupharborrestoreharborbackupscheduleharborregistryendpointharborreplicationpolicyharborrobotaccountimagerewritepolicynamespacedharborserverconfiguration"
 
-var _Controller_index = [...]uint8{0, 4, 14, 20, 28, 39, 50, 58, 70, 82, 87, 93, 106, 127, 146, 159, 184, 201, 210}
+var _Controller_index = [...]uint16{0, 4, 14, 20, 28, 39, 50, 58, 70, 82, 87, 93, 106, 127, 146, 159, 184, 201, 210, 222, 235, 255, 277, 300, 318, 336, 371}
 
 func (i Controller) String() string {
 	if i < 0 || i >= Controller(len(_Controller_index)-1) {
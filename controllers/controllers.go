@@ -11,24 +11,32 @@ import (
 type Controller int
 
 const (
-	Core                      Controller = iota // core
-	JobService                                  // jobservice
-	Portal                                      // portal
-	Registry                                    // registry
-	RegistryController                          // registryctl
-	ChartMuseum                                 // chartmuseum
-	Exporter                                    // exporter
-	NotaryServer                                // notaryserver
-	NotarySigner                                // notarysigner
-	Trivy                                       // trivy
-	Harbor                                      // harbor
-	HarborCluster                               // harborcluster
-	HarborConfigurationCm                       // harborconfigurationcm
-	HarborConfiguration                         // harborconfiguration
-	HarborProject                               // harborproject
-	HarborServerConfiguration                   // harborserverconfiguration
-	PullSecretBinding                           // pullsecretbinding
-	Namespace                                   // namespace
+	Core                                Controller = iota // core
+	JobService                                            // jobservice
+	Portal                                                // portal
+	Registry                                              // registry
+	RegistryController                                    // registryctl
+	ChartMuseum                                           // chartmuseum
+	Exporter                                              // exporter
+	NotaryServer                                          // notaryserver
+	NotarySigner                                          // notarysigner
+	Trivy                                                 // trivy
+	Harbor                                                // harbor
+	HarborCluster                                         // harborcluster
+	HarborConfigurationCm                                 // harborconfigurationcm
+	HarborConfiguration                                   // harborconfiguration
+	HarborProject                                         // harborproject
+	HarborServerConfiguration                             // harborserverconfiguration
+	PullSecretBinding                                     // pullsecretbinding
+	Namespace                                             // namespace
+	HarborBackup                                          // harborbackup
+	HarborRestore                                         // harborrestore
+	HarborBackupSchedule                                  // harborbackupschedule
+	HarborRegistryEndpoint                                // harborregistryendpoint
+	HarborReplicationPolicy                               // harborreplicationpolicy
+	HarborRobotAccount                                    // harborrobotaccount
+	ImageRewritePolicy                                    // imagerewritepolicy
+	NamespacedHarborServerConfiguration                   // namespacedharborserverconfiguration
 )
 
 func (c Controller) GetFQDN() string {
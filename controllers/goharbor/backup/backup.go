@@ -0,0 +1,207 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Reconcile runs a job per component of the backup and tracks their completion.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("resource", req.NamespacedName)
+
+	backup := &goharborv1.HarborBackup{}
+	if err := r.Client.Get(ctx, req.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor backup %v", req)
+	}
+
+	if isTerminated(backup.Status.Phase) {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Start reconciling")
+
+	harborcluster := &goharborv1.HarborCluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: backup.Spec.HarborClusterRef}, harborcluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "error get harborCluster %s", backup.Spec.HarborClusterRef)
+		}
+
+		return ctrl.Result{}, r.fail(ctx, backup, fmt.Sprintf("harborCluster %s not found", backup.Spec.HarborClusterRef))
+	}
+
+	components := backup.Spec.Components
+	if len(components) == 0 {
+		components = inClusterComponents(harborcluster)
+	}
+
+	if len(components) == 0 {
+		return ctrl.Result{}, r.fail(ctx, backup, "harborCluster has no in-cluster service to backup")
+	}
+
+	for _, component := range components {
+		if !isInCluster(harborcluster, component) {
+			return ctrl.Result{}, r.fail(ctx, backup, fmt.Sprintf("%s of harborCluster is not an in-cluster service", component))
+		}
+	}
+
+	if backup.Status.StartTime == nil {
+		now := metav1.Now()
+		backup.Status.StartTime = &now
+	}
+
+	backup.Status.Phase = goharborv1.HarborBackupPhaseRunning
+	backup.Status.Message = ""
+
+	for _, component := range components {
+		componentStatus, err := r.reconcileComponent(ctx, backup, harborcluster, component)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "cannot backup %s", component)
+		}
+
+		setComponentStatus(&backup.Status.Components, componentStatus)
+	}
+
+	backup.Status.Phase, backup.Status.Message = aggregatePhase(backup.Status.Components)
+	if isTerminated(backup.Status.Phase) {
+		now := metav1.Now()
+		backup.Status.CompletionTime = &now
+	}
+
+	log.Info("Reconcile end", "phase", backup.Status.Phase)
+
+	return ctrl.Result{}, r.Client.Status().Update(ctx, backup)
+}
+
+func (r *Reconciler) reconcileComponent(ctx context.Context, backup *goharborv1.HarborBackup, harborcluster *goharborv1.HarborCluster, component goharborv1.HarborBackupComponent) (goharborv1.HarborBackupComponentStatus, error) {
+	status := goharborv1.HarborBackupComponentStatus{
+		Component: component,
+		Phase:     goharborv1.HarborBackupPhaseRunning,
+		JobName:   jobName(backup.GetName(), component),
+		Location:  componentLocation(backup.Spec.Target, backup.GetName(), component),
+	}
+
+	job := &batchv1.Job{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: backup.GetNamespace(), Name: status.JobName}, job)
+	if err == nil {
+		status.Phase, status.Message = jobPhase(job)
+
+		return status, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return status, err
+	}
+
+	dataJob, err := r.Protectors[component].BackupJob(ctx, harborcluster)
+	if err != nil {
+		return status, err
+	}
+
+	job, err = generateJob(ctx, harborcluster, dataJob, backup.Spec.Target, status.Location, true)
+	if err != nil {
+		return status, err
+	}
+
+	job.SetName(status.JobName)
+	job.Labels[BackupLabel] = backup.GetName()
+	job.Labels[ComponentLabel] = string(component)
+
+	if err := controllerutil.SetControllerReference(backup, job, r.Scheme); err != nil {
+		return status, err
+	}
+
+	if err := r.Client.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return status, err
+	}
+
+	return status, nil
+}
+
+func (r *Reconciler) fail(ctx context.Context, backup *goharborv1.HarborBackup, message string) error {
+	now := metav1.Now()
+
+	backup.Status.Phase = goharborv1.HarborBackupPhaseFailed
+	backup.Status.Message = message
+	backup.Status.CompletionTime = &now
+
+	return r.Client.Status().Update(ctx, backup)
+}
+
+// inClusterComponents returns the components of the harbor cluster provided by in-cluster services.
+func inClusterComponents(harborcluster *goharborv1.HarborCluster) []goharborv1.HarborBackupComponent {
+	components := []goharborv1.HarborBackupComponent{}
+
+	for _, component := range []goharborv1.HarborBackupComponent{
+		goharborv1.HarborBackupComponentDatabase,
+		goharborv1.HarborBackupComponentCache,
+		goharborv1.HarborBackupComponentStorage,
+	} {
+		if isInCluster(harborcluster, component) {
+			components = append(components, component)
+		}
+	}
+
+	return components
+}
+
+func isInCluster(harborcluster *goharborv1.HarborCluster, component goharborv1.HarborBackupComponent) bool {
+	switch component {
+	case goharborv1.HarborBackupComponentDatabase:
+		return harborcluster.Spec.Database.Spec.IsInCluster()
+	case goharborv1.HarborBackupComponentCache:
+		return harborcluster.Spec.Cache.Spec.IsInCluster()
+	case goharborv1.HarborBackupComponentStorage:
+		return harborcluster.Spec.Storage.Spec.MinIO != nil
+	}
+
+	return false
+}
+
+func isTerminated(phase goharborv1.HarborBackupPhase) bool {
+	return phase == goharborv1.HarborBackupPhaseCompleted || phase == goharborv1.HarborBackupPhaseFailed
+}
+
+func setComponentStatus(components *[]goharborv1.HarborBackupComponentStatus, status goharborv1.HarborBackupComponentStatus) {
+	for i := range *components {
+		if (*components)[i].Component == status.Component {
+			(*components)[i] = status
+
+			return
+		}
+	}
+
+	*components = append(*components, status)
+}
+
+// aggregatePhase returns the phase of a backup or a restore from the phases of its components.
+func aggregatePhase(components []goharborv1.HarborBackupComponentStatus) (goharborv1.HarborBackupPhase, string) {
+	phase := goharborv1.HarborBackupPhaseCompleted
+
+	for _, component := range components {
+		switch component.Phase { //nolint:exhaustive
+		case goharborv1.HarborBackupPhaseFailed:
+			return goharborv1.HarborBackupPhaseFailed, fmt.Sprintf("%s: %s", component.Component, component.Message)
+		case goharborv1.HarborBackupPhaseCompleted:
+		default:
+			phase = goharborv1.HarborBackupPhaseRunning
+		}
+	}
+
+	return phase, ""
+}
@@ -0,0 +1,58 @@
+package backup_test
+
+import (
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/backup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatePhase(t *testing.T) {
+	component := func(name goharborv1.HarborBackupComponent, phase goharborv1.HarborBackupPhase, message string) goharborv1.HarborBackupComponentStatus {
+		return goharborv1.HarborBackupComponentStatus{Component: name, Phase: phase, Message: message}
+	}
+
+	for name, tc := range map[string]struct {
+		components []goharborv1.HarborBackupComponentStatus
+		phase      goharborv1.HarborBackupPhase
+		message    string
+	}{
+		"all completed": {
+			components: []goharborv1.HarborBackupComponentStatus{
+				component(goharborv1.HarborBackupComponentDatabase, goharborv1.HarborBackupPhaseCompleted, ""),
+				component(goharborv1.HarborBackupComponentStorage, goharborv1.HarborBackupPhaseCompleted, ""),
+			},
+			phase: goharborv1.HarborBackupPhaseCompleted,
+		},
+		"one running": {
+			components: []goharborv1.HarborBackupComponentStatus{
+				component(goharborv1.HarborBackupComponentDatabase, goharborv1.HarborBackupPhaseCompleted, ""),
+				component(goharborv1.HarborBackupComponentStorage, goharborv1.HarborBackupPhaseRunning, ""),
+			},
+			phase: goharborv1.HarborBackupPhaseRunning,
+		},
+		"one failed": {
+			components: []goharborv1.HarborBackupComponentStatus{
+				component(goharborv1.HarborBackupComponentDatabase, goharborv1.HarborBackupPhaseRunning, ""),
+				component(goharborv1.HarborBackupComponentCache, goharborv1.HarborBackupPhaseFailed, "BackoffLimitExceeded"),
+			},
+			phase:   goharborv1.HarborBackupPhaseFailed,
+			message: "Cache: BackoffLimitExceeded",
+		},
+		"unknown phase": {
+			components: []goharborv1.HarborBackupComponentStatus{
+				component(goharborv1.HarborBackupComponentDatabase, "", ""),
+			},
+			phase: goharborv1.HarborBackupPhaseRunning,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			phase, message := backup.AggregatePhase(tc.components)
+			require.Equal(t, tc.phase, phase)
+			require.Equal(t, tc.message, message)
+		})
+	}
+}
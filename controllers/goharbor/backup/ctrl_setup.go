@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/cache"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/storage"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// Reconciler reconciles a HarborBackup object.
+type Reconciler struct {
+	*commonCtrl.Controller
+	Scheme *runtime.Scheme
+
+	Protectors map[goharborv1.HarborBackupComponent]lcm.DataProtector
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups;harborrestores;harborbackupschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups/status;harborrestores/status;harborbackupschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=databases.spotahome.com,resources=redisfailovers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=redis.redis.opstreelabs.in,resources=redisreplications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=acid.zalan.do,resources=postgresqls,verbs=get;list;watch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch
+
+// Enhancements to RBAC
+// see: https://sdk.operatorframework.io/docs/faqs/#after-deploying-my-operator-why-do-i-see-errors-like-is-forbidden-cannot-set-blockownerdeletion-if-an-ownerreference-refers-to-a-resource-you-cant-set-finalizers-on-
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborbackups/finalizers;harborrestores/finalizers;harborbackupschedules/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	r.Protectors, err = newDataProtectors(r.Log, mgr, r.ConfigStore)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1.HarborBackup{}).
+		Owns(&batchv1.Job{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		Complete(r)
+}
+
+func (r *Reconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborBackup"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
+
+// New HarborBackup reconciler.
+func New(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &Reconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborBackup, nil, configStore)
+
+	return r, nil
+}
+
+// newDataProtectors returns the controllers of the in-cluster services able to dump and restore their data.
+func newDataProtectors(log logr.Logger, mgr ctrl.Manager, configStore *configstore.Store) (map[goharborv1.HarborBackupComponent]lcm.DataProtector, error) {
+	dClient, err := k8s.DynamicClient()
+	if err != nil {
+		log.Error(err, "unable to create dynamic client")
+
+		return nil, err
+	}
+
+	lcmControllers := map[goharborv1.HarborBackupComponent]lcm.Controller{
+		goharborv1.HarborBackupComponentCache: cache.NewRedisController(
+			k8s.WithLog(log.WithName("cache")),
+			k8s.WithScheme(mgr.GetScheme()),
+			k8s.WithDClient(dClient),
+			k8s.WithClient(mgr.GetClient()),
+			k8s.WithConfigStore(configStore),
+		),
+		goharborv1.HarborBackupComponentDatabase: database.NewDatabaseController(
+			k8s.WithLog(log.WithName("database")),
+			k8s.WithScheme(mgr.GetScheme()),
+			k8s.WithDClient(dClient),
+			k8s.WithClient(mgr.GetClient()),
+			k8s.WithConfigStore(configStore),
+		),
+		goharborv1.HarborBackupComponentStorage: storage.NewMinIOController(
+			k8s.WithLog(log.WithName("storage")),
+			k8s.WithScheme(mgr.GetScheme()),
+			k8s.WithClient(mgr.GetClient()),
+			k8s.WithConfigStore(configStore),
+		),
+	}
+
+	protectors := make(map[goharborv1.HarborBackupComponent]lcm.DataProtector, len(lcmControllers))
+
+	for component, lcmController := range lcmControllers {
+		protector, ok := lcmController.(lcm.DataProtector)
+		if !ok {
+			return nil, errors.Errorf("%s controller cannot backup its data", component)
+		}
+
+		protectors[component] = protector
+	}
+
+	return protectors, nil
+}
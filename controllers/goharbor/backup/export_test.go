@@ -0,0 +1,8 @@
+package backup
+
+var (
+	AggregatePhase = aggregatePhase
+	GenerateJob    = generateJob
+	JobName        = jobName
+	PruneHistory   = (*ScheduleReconciler).pruneHistory
+)
@@ -0,0 +1,206 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/storage"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	backupMountPath  = "/backup"
+	backupVolumeName = "backup"
+
+	// BackupLabel is the label of the jobs of a backup.
+	BackupLabel = "goharbor.io/harbor-backup"
+	// RestoreLabel is the label of the jobs of a restore.
+	RestoreLabel = "goharbor.io/harbor-restore"
+	// ScheduleLabel is the label of the backups created by a schedule.
+	ScheduleLabel = "goharbor.io/harbor-backup-schedule"
+	// ComponentLabel is the label of the component of a job.
+	ComponentLabel = "goharbor.io/harbor-backup-component"
+)
+
+// jobNameHashLength is the length of the hash suffixing the truncated names of the jobs.
+const jobNameHashLength = 8
+
+var defaultBackoffLimit int32 = 3
+
+// componentLocation returns the path of the data of a component in the target.
+func componentLocation(target goharborv1.HarborBackupTarget, backupName string, component goharborv1.HarborBackupComponent) string {
+	var prefix string
+
+	switch {
+	case target.PersistentVolumeClaim != nil:
+		prefix = target.PersistentVolumeClaim.Path
+	case target.Bucket != nil:
+		prefix = target.Bucket.Path
+	}
+
+	return strings.TrimPrefix(path.Join(prefix, backupName, strings.ToLower(string(component))), "/")
+}
+
+// jobName returns the name of the job of the component.
+// The name of a job is at most 63 characters long, as the job-name label of its pods:
+// longer names are truncated and suffixed with a hash of the full name to stay unique.
+func jobName(name string, component goharborv1.HarborBackupComponent) string {
+	name = strings.ToLower(name)
+	suffix := "-" + strings.ToLower(string(component))
+
+	if len(name)+len(suffix) <= validation.LabelValueMaxLength {
+		return name + suffix
+	}
+
+	hash := fmt.Sprintf("-%x", sha256.Sum256([]byte(name)))[:jobNameHashLength+1]
+	prefix := strings.TrimRight(name[:validation.LabelValueMaxLength-len(suffix)-len(hash)], "-.")
+
+	return prefix + hash + suffix
+}
+
+// generateJob returns the job running the data job of a component against the target.
+// The data is written into the target if upload is true, it is read from the target otherwise.
+func generateJob(ctx context.Context, harborcluster *goharborv1.HarborCluster, dataJob *lcm.DataJob, target goharborv1.HarborBackupTarget, location string, upload bool) (*batchv1.Job, error) {
+	volume := corev1.Volume{
+		Name: backupVolumeName,
+	}
+
+	mount := corev1.VolumeMount{
+		Name:      backupVolumeName,
+		MountPath: backupMountPath,
+	}
+
+	if target.PersistentVolumeClaim != nil {
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: target.PersistentVolumeClaim.ClaimName,
+		}
+		mount.SubPath = location
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+
+	env := []corev1.EnvVar{{
+		Name:  lcm.BackupDirEnv,
+		Value: backupMountPath,
+	}}
+
+	if target.Bucket != nil {
+		env = append(env, bucketEnv(target.Bucket, location)...)
+	}
+
+	// The containers are copied, the env and the mounts appended to them must not alter the data job.
+	containers := []corev1.Container{*dataJob.Container.DeepCopy()}
+	imagePullSecrets := append([]corev1.LocalObjectReference{}, dataJob.ImagePullSecrets...)
+
+	var initContainers []corev1.Container
+
+	// The data transits through a scratch volume if the data job cannot handle the bucket target.
+	if target.Bucket != nil && !dataJob.RemoteTarget {
+		transfer, err := storage.TransferJob(ctx, harborcluster, upload)
+		if err != nil {
+			return nil, err
+		}
+
+		imagePullSecrets = append(imagePullSecrets, transfer.ImagePullSecrets...)
+
+		if upload {
+			initContainers = containers
+			containers = []corev1.Container{*transfer.Container.DeepCopy()}
+		} else {
+			initContainers = []corev1.Container{*transfer.Container.DeepCopy()}
+		}
+	}
+
+	for _, list := range [][]corev1.Container{initContainers, containers} {
+		for i := range list {
+			list[i].Env = append(list[i].Env, env...)
+			list[i].VolumeMounts = append(list[i].VolumeMounts, mount)
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: harborcluster.Namespace,
+			Labels: map[string]string{
+				k8s.HarborClusterNameLabel: harborcluster.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &defaultBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: imagePullSecrets,
+					InitContainers:   initContainers,
+					Containers:       containers,
+					Volumes:          []corev1.Volume{volume},
+				},
+			},
+		},
+	}, nil
+}
+
+func bucketEnv(bucket *goharborv1.HarborBackupBucketTarget, location string) []corev1.EnvVar {
+	credentials := corev1.LocalObjectReference{Name: bucket.CredentialsRef}
+
+	env := []corev1.EnvVar{{
+		Name:  lcm.BackupTargetEndpointEnv,
+		Value: bucket.Endpoint,
+	}, {
+		Name:  lcm.BackupTargetURLEnv,
+		Value: path.Join(bucket.Bucket, location),
+	}, {
+		Name: lcm.BackupTargetAccessKeyEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: credentials,
+				Key:                  "accesskey",
+			},
+		},
+	}, {
+		Name: lcm.BackupTargetSecretKeyEnv,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: credentials,
+				Key:                  "secretkey",
+			},
+		},
+	}}
+
+	if bucket.Insecure {
+		env = append(env, corev1.EnvVar{
+			Name:  lcm.BackupTargetInsecureEnv,
+			Value: strconv.FormatBool(bucket.Insecure),
+		})
+	}
+
+	return env
+}
+
+// jobPhase returns the phase of the component from the conditions of its job.
+func jobPhase(job *batchv1.Job) (goharborv1.HarborBackupPhase, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type { //nolint:exhaustive
+		case batchv1.JobComplete:
+			return goharborv1.HarborBackupPhaseCompleted, ""
+		case batchv1.JobFailed:
+			return goharborv1.HarborBackupPhaseFailed, condition.Message
+		}
+	}
+
+	return goharborv1.HarborBackupPhaseRunning, ""
+}
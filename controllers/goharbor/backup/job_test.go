@@ -0,0 +1,117 @@
+package backup_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/backup"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestJobName(t *testing.T) {
+	require.Equal(t, "sample-database", backup.JobName("Sample", goharborv1.HarborBackupComponentDatabase))
+
+	prefix := strings.Repeat("daily-backup-of-the-production-cluster-", 2)
+
+	first := backup.JobName(prefix+"1650000000", goharborv1.HarborBackupComponentDatabase)
+	second := backup.JobName(prefix+"1650086400", goharborv1.HarborBackupComponentDatabase)
+
+	for _, name := range []string{first, second} {
+		require.LessOrEqual(t, len(name), validation.LabelValueMaxLength, name)
+		require.True(t, strings.HasSuffix(name, "-database"), name)
+		require.Empty(t, validation.IsDNS1123Subdomain(name), name)
+	}
+
+	require.NotEqual(t, first, second, "unique once truncated")
+	require.Equal(t, first, backup.JobName(prefix+"1650000000", goharborv1.HarborBackupComponentDatabase), "stable")
+}
+
+func TestGenerateJob(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := &goharborv1.HarborCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "cluster"},
+	}
+
+	// Spare capacity: appending to the env of the data job would share its backing array
+	env := make([]corev1.EnvVar, 1, 8)
+	env[0] = corev1.EnvVar{Name: "PGHOST", Value: "postgresql"}
+
+	dataJob := &lcm.DataJob{
+		Container: corev1.Container{
+			Name:         "pg-dump",
+			Image:        "spilo",
+			Env:          env,
+			VolumeMounts: make([]corev1.VolumeMount, 0, 8),
+		},
+		ImagePullSecrets: make([]corev1.LocalObjectReference, 0, 8),
+	}
+
+	target := goharborv1.HarborBackupTarget{
+		PersistentVolumeClaim: &goharborv1.HarborBackupPVCTarget{ClaimName: "backups"},
+	}
+
+	job, err := backup.GenerateJob(ctx, harborcluster, dataJob, target, "sample/database", true)
+	require.NoError(t, err)
+
+	require.Len(t, job.Spec.Template.Spec.Containers, 1)
+	require.Empty(t, job.Spec.Template.Spec.InitContainers)
+
+	container := job.Spec.Template.Spec.Containers[0]
+	require.Equal(t, []corev1.EnvVar{
+		{Name: "PGHOST", Value: "postgresql"},
+		{Name: lcm.BackupDirEnv, Value: "/backup"},
+	}, container.Env)
+	require.Equal(t, []corev1.VolumeMount{{Name: "backup", MountPath: "/backup", SubPath: "sample/database"}}, container.VolumeMounts)
+	require.Equal(t, "backups", job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	require.Len(t, dataJob.Container.Env, 1, "the data job is not altered")
+	require.Empty(t, dataJob.Container.VolumeMounts, "the data job is not altered")
+
+	// The jobs generated from the same data job do not share their containers
+	other, err := backup.GenerateJob(ctx, harborcluster, dataJob, target, "other/database", true)
+	require.NoError(t, err)
+
+	other.Spec.Template.Spec.Containers[0].Env[1].Value = "/other"
+
+	require.Equal(t, "/backup", job.Spec.Template.Spec.Containers[0].Env[1].Value)
+	require.Equal(t, "sample/database", job.Spec.Template.Spec.Containers[0].VolumeMounts[0].SubPath)
+
+	bucket := goharborv1.HarborBackupTarget{
+		Bucket: &goharborv1.HarborBackupBucketTarget{
+			Endpoint:       "https://s3.example.com",
+			Bucket:         "backups",
+			CredentialsRef: "s3-credentials",
+		},
+	}
+
+	dataJob.RemoteTarget = true
+
+	remote, err := backup.GenerateJob(ctx, harborcluster, dataJob, bucket, "sample/database", true)
+	require.NoError(t, err)
+
+	require.Len(t, remote.Spec.Template.Spec.Containers, 1)
+	require.Empty(t, remote.Spec.Template.Spec.InitContainers, "the data job uploads by itself")
+	require.NotNil(t, remote.Spec.Template.Spec.Volumes[0].EmptyDir)
+
+	names := []string{}
+	for _, env := range remote.Spec.Template.Spec.Containers[0].Env {
+		names = append(names, env.Name)
+	}
+
+	require.Equal(t, []string{
+		"PGHOST",
+		lcm.BackupDirEnv,
+		lcm.BackupTargetEndpointEnv,
+		lcm.BackupTargetURLEnv,
+		lcm.BackupTargetAccessKeyEnv,
+		lcm.BackupTargetSecretKeyEnv,
+	}, names)
+	require.Equal(t, "backups/sample/database", remote.Spec.Template.Spec.Containers[0].Env[3].Value)
+}
@@ -0,0 +1,256 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// restoreRequeueDelay is the delay between the checks of the backup and of the harbor cluster readiness.
+const restoreRequeueDelay = 30 * time.Second
+
+// RestoreReconciler reconciles a HarborRestore object.
+type RestoreReconciler struct {
+	*commonCtrl.Controller
+	Scheme *runtime.Scheme
+
+	Protectors map[goharborv1.HarborBackupComponent]lcm.DataProtector
+}
+
+func (r *RestoreReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	r.Protectors, err = newDataProtectors(r.Log, mgr, r.ConfigStore)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1.HarborRestore{}).
+		Owns(&batchv1.Job{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		Complete(r)
+}
+
+func (r *RestoreReconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborRestore"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
+
+// NewRestore HarborRestore reconciler.
+func NewRestore(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &RestoreReconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborRestore, nil, configStore)
+
+	return r, nil
+}
+
+// Reconcile waits for the backup and the in-cluster services of the harbor cluster,
+// then runs a job per component replacing the data of the service with the backup.
+func (r *RestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:funlen
+	log := r.Log.WithValues("resource", req.NamespacedName)
+
+	restore := &goharborv1.HarborRestore{}
+	if err := r.Client.Get(ctx, req.NamespacedName, restore); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor restore %v", req)
+	}
+
+	if isTerminated(restore.Status.Phase) {
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Start reconciling")
+
+	backup := &goharborv1.HarborBackup{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: restore.Spec.BackupRef}, backup); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "error get harbor backup %s", restore.Spec.BackupRef)
+		}
+
+		return ctrl.Result{}, r.fail(ctx, restore, fmt.Sprintf("harborBackup %s not found", restore.Spec.BackupRef))
+	}
+
+	switch backup.Status.Phase { //nolint:exhaustive
+	case goharborv1.HarborBackupPhaseCompleted:
+	case goharborv1.HarborBackupPhaseFailed:
+		return ctrl.Result{}, r.fail(ctx, restore, fmt.Sprintf("harborBackup %s failed", backup.GetName()))
+	default:
+		return r.pending(ctx, restore, fmt.Sprintf("waiting for the completion of harborBackup %s", backup.GetName()))
+	}
+
+	harborcluster := &goharborv1.HarborCluster{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: restore.Spec.HarborClusterRef}, harborcluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrapf(err, "error get harborCluster %s", restore.Spec.HarborClusterRef)
+		}
+
+		return r.pending(ctx, restore, fmt.Sprintf("waiting for harborCluster %s", restore.Spec.HarborClusterRef))
+	}
+
+	components := restore.Spec.Components
+	if len(components) == 0 {
+		for _, component := range backup.Status.Components {
+			components = append(components, component.Component)
+		}
+	}
+
+	for _, component := range components {
+		backupStatus := backup.Status.GetComponent(component)
+		if backupStatus == nil || backupStatus.Phase != goharborv1.HarborBackupPhaseCompleted {
+			return ctrl.Result{}, r.fail(ctx, restore, fmt.Sprintf("%s is not in harborBackup %s", component, backup.GetName()))
+		}
+
+		if !isInCluster(harborcluster, component) {
+			return ctrl.Result{}, r.fail(ctx, restore, fmt.Sprintf("%s of harborCluster is not an in-cluster service", component))
+		}
+	}
+
+	// Jobs are only created once the services are up, running jobs are tracked whatever the services state.
+	if restore.Status.StartTime == nil {
+		for _, component := range components {
+			if !isReady(harborcluster, component) {
+				return r.pending(ctx, restore, fmt.Sprintf("waiting for the readiness of %s of harborCluster", component))
+			}
+		}
+
+		now := metav1.Now()
+		restore.Status.StartTime = &now
+	}
+
+	restore.Status.Phase = goharborv1.HarborBackupPhaseRunning
+	restore.Status.Message = ""
+
+	for _, component := range components {
+		componentStatus, err := r.reconcileComponent(ctx, restore, backup, harborcluster, component)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "cannot restore %s", component)
+		}
+
+		setComponentStatus(&restore.Status.Components, componentStatus)
+	}
+
+	restore.Status.Phase, restore.Status.Message = aggregatePhase(restore.Status.Components)
+	if isTerminated(restore.Status.Phase) {
+		now := metav1.Now()
+		restore.Status.CompletionTime = &now
+	}
+
+	log.Info("Reconcile end", "phase", restore.Status.Phase)
+
+	return ctrl.Result{}, r.Client.Status().Update(ctx, restore)
+}
+
+func (r *RestoreReconciler) reconcileComponent(ctx context.Context, restore *goharborv1.HarborRestore, backup *goharborv1.HarborBackup, harborcluster *goharborv1.HarborCluster, component goharborv1.HarborBackupComponent) (goharborv1.HarborBackupComponentStatus, error) {
+	status := goharborv1.HarborBackupComponentStatus{
+		Component: component,
+		Phase:     goharborv1.HarborBackupPhaseRunning,
+		JobName:   jobName(restore.GetName(), component),
+		Location:  backup.Status.GetComponent(component).Location,
+	}
+
+	job := &batchv1.Job{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: restore.GetNamespace(), Name: status.JobName}, job)
+	if err == nil {
+		status.Phase, status.Message = jobPhase(job)
+
+		return status, nil
+	}
+
+	if !apierrors.IsNotFound(err) {
+		return status, err
+	}
+
+	dataJob, err := r.Protectors[component].RestoreJob(ctx, harborcluster)
+	if err != nil {
+		return status, err
+	}
+
+	job, err = generateJob(ctx, harborcluster, dataJob, backup.Spec.Target, status.Location, false)
+	if err != nil {
+		return status, err
+	}
+
+	job.SetName(status.JobName)
+	job.Labels[RestoreLabel] = restore.GetName()
+	job.Labels[ComponentLabel] = string(component)
+
+	if err := controllerutil.SetControllerReference(restore, job, r.Scheme); err != nil {
+		return status, err
+	}
+
+	if err := r.Client.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return status, err
+	}
+
+	return status, nil
+}
+
+func (r *RestoreReconciler) pending(ctx context.Context, restore *goharborv1.HarborRestore, message string) (ctrl.Result, error) {
+	r.Log.Info(message, "resource", restore.GetName())
+
+	restore.Status.Phase = goharborv1.HarborBackupPhasePending
+	restore.Status.Message = message
+
+	return ctrl.Result{RequeueAfter: restoreRequeueDelay}, r.Client.Status().Update(ctx, restore)
+}
+
+func (r *RestoreReconciler) fail(ctx context.Context, restore *goharborv1.HarborRestore, message string) error {
+	now := metav1.Now()
+
+	restore.Status.Phase = goharborv1.HarborBackupPhaseFailed
+	restore.Status.Message = message
+	restore.Status.CompletionTime = &now
+
+	return r.Client.Status().Update(ctx, restore)
+}
+
+// isReady returns whether the in-cluster service of the component is ready.
+func isReady(harborcluster *goharborv1.HarborCluster, component goharborv1.HarborBackupComponent) bool {
+	conditionType := map[goharborv1.HarborBackupComponent]goharborv1.HarborClusterConditionType{
+		goharborv1.HarborBackupComponentDatabase: goharborv1.DatabaseReady,
+		goharborv1.HarborBackupComponentCache:    goharborv1.CacheReady,
+		goharborv1.HarborBackupComponentStorage:  goharborv1.StorageReady,
+	}[component]
+
+	for _, condition := range harborcluster.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
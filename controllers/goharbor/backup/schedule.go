@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/cron"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ScheduleReconciler reconciles a HarborBackupSchedule object.
+type ScheduleReconciler struct {
+	*commonCtrl.Controller
+	Scheme *runtime.Scheme
+}
+
+func (r *ScheduleReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1.HarborBackupSchedule{}).
+		Owns(&goharborv1.HarborBackup{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		Complete(r)
+}
+
+func (r *ScheduleReconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborBackupSchedule"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
+
+// NewSchedule HarborBackupSchedule reconciler.
+func NewSchedule(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &ScheduleReconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborBackupSchedule, nil, configStore)
+
+	return r, nil
+}
+
+// Reconcile prunes the history of the backups of the schedule and creates a backup when it is due.
+func (r *ScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("resource", req.NamespacedName)
+
+	schedule := &goharborv1.HarborBackupSchedule{}
+	if err := r.Client.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor backup schedule %v", req)
+	}
+
+	log.Info("Start reconciling")
+
+	if err := r.pruneHistory(ctx, schedule); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "cannot prune backups history")
+	}
+
+	cronSchedule, err := cron.Parse(schedule.Spec.Schedule)
+	if err != nil {
+		schedule.Status.Message = fmt.Sprintf("invalid schedule: %v", err)
+
+		return ctrl.Result{}, r.Client.Status().Update(ctx, schedule)
+	}
+
+	schedule.Status.Message = ""
+
+	if schedule.Spec.Suspend {
+		return ctrl.Result{}, r.Client.Status().Update(ctx, schedule)
+	}
+
+	last := schedule.GetCreationTimestamp().Time
+	if schedule.Status.LastScheduleTime != nil {
+		last = schedule.Status.LastScheduleTime.Time
+	}
+
+	now := time.Now().UTC()
+
+	next := cronSchedule.Next(last.UTC())
+	if next.IsZero() {
+		schedule.Status.Message = "the schedule never activates"
+
+		return ctrl.Result{}, r.Client.Status().Update(ctx, schedule)
+	}
+
+	if !next.After(now) {
+		running, err := r.lastBackupRunning(ctx, schedule)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "cannot get last backup")
+		}
+
+		if running {
+			// The schedule owns its backups, it is reconciled again when the last one terminates.
+			schedule.Status.Message = fmt.Sprintf("waiting for the completion of harborBackup %s", schedule.Status.LastBackup)
+
+			log.Info("Backup skipped", "running", schedule.Status.LastBackup)
+
+			return ctrl.Result{}, r.Client.Status().Update(ctx, schedule)
+		}
+
+		// Missed activations are not caught up, a single backup is created for the latest one.
+		for missed := cronSchedule.Next(next); !missed.IsZero() && !missed.After(now); missed = cronSchedule.Next(missed) {
+			next = missed
+		}
+
+		backup, err := r.createBackup(ctx, schedule, next)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "cannot create backup")
+		}
+
+		log.Info("Backup created", "backup", backup.GetName())
+
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: next}
+		schedule.Status.LastBackup = backup.GetName()
+
+		next = cronSchedule.Next(now)
+	}
+
+	log.Info("Reconcile end", "next", next)
+
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, r.Client.Status().Update(ctx, schedule)
+}
+
+func (r *ScheduleReconciler) createBackup(ctx context.Context, schedule *goharborv1.HarborBackupSchedule, scheduleTime time.Time) (*goharborv1.HarborBackup, error) {
+	backup := &goharborv1.HarborBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", schedule.GetName(), scheduleTime.Unix()),
+			Namespace: schedule.GetNamespace(),
+			Labels: map[string]string{
+				ScheduleLabel: schedule.GetName(),
+			},
+		},
+		Spec: *schedule.Spec.Template.DeepCopy(),
+	}
+
+	if err := controllerutil.SetControllerReference(schedule, backup, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.Client.Create(ctx, backup); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// lastBackupRunning returns whether the last backup created by the schedule is not terminated yet.
+func (r *ScheduleReconciler) lastBackupRunning(ctx context.Context, schedule *goharborv1.HarborBackupSchedule) (bool, error) {
+	if schedule.Status.LastBackup == "" {
+		return false, nil
+	}
+
+	backup := &goharborv1.HarborBackup{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: schedule.GetNamespace(), Name: schedule.Status.LastBackup}, backup)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return !isTerminated(backup.Status.Phase), nil
+}
+
+// pruneHistory deletes the oldest terminated backups of the schedule beyond the history limits.
+func (r *ScheduleReconciler) pruneHistory(ctx context.Context, schedule *goharborv1.HarborBackupSchedule) error {
+	backups := &goharborv1.HarborBackupList{}
+	if err := r.Client.List(ctx, backups, client.InNamespace(schedule.GetNamespace()), client.MatchingLabels{ScheduleLabel: schedule.GetName()}); err != nil {
+		return err
+	}
+
+	sort.Slice(backups.Items, func(i, j int) bool {
+		return backups.Items[j].CreationTimestamp.Before(&backups.Items[i].CreationTimestamp)
+	})
+
+	successfulLimit, failedLimit := 3, 1
+
+	if schedule.Spec.SuccessfulBackupsHistoryLimit != nil {
+		successfulLimit = int(*schedule.Spec.SuccessfulBackupsHistoryLimit)
+	}
+
+	if schedule.Spec.FailedBackupsHistoryLimit != nil {
+		failedLimit = int(*schedule.Spec.FailedBackupsHistoryLimit)
+	}
+
+	successful, failed := 0, 0
+
+	for i := range backups.Items {
+		backup := &backups.Items[i]
+
+		switch backup.Status.Phase { //nolint:exhaustive
+		case goharborv1.HarborBackupPhaseCompleted:
+			if successful == 0 {
+				schedule.Status.LastSuccessfulBackup = backup.GetName()
+			}
+
+			successful++
+			if successful <= successfulLimit {
+				continue
+			}
+		case goharborv1.HarborBackupPhaseFailed:
+			failed++
+			if failed <= failedLimit {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, backup); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package backup_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/backup"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPruneHistory(t *testing.T) {
+	ctx := context.TODO()
+
+	schedule := newSchedule("0 2 * * *", time.Now().Add(-30*24*time.Hour))
+
+	objects := []client.Object{schedule}
+
+	// One backup a day, the oldest first
+	for i, phase := range []goharborv1.HarborBackupPhase{
+		goharborv1.HarborBackupPhaseCompleted,
+		goharborv1.HarborBackupPhaseFailed,
+		goharborv1.HarborBackupPhaseCompleted,
+		goharborv1.HarborBackupPhaseCompleted,
+		goharborv1.HarborBackupPhaseFailed,
+		goharborv1.HarborBackupPhaseCompleted,
+		goharborv1.HarborBackupPhaseCompleted,
+		goharborv1.HarborBackupPhaseRunning,
+	} {
+		objects = append(objects, newBackup(schedule, string(rune('a'+i)), time.Now().Add(time.Duration(i-10)*24*time.Hour), phase))
+	}
+
+	r := newScheduleReconciler(t, objects...)
+
+	require.NoError(t, backup.PruneHistory(r, ctx, schedule))
+	require.Equal(t, []string{"daily-d", "daily-e", "daily-f", "daily-g", "daily-h"}, listBackups(ctx, t, r))
+	require.Equal(t, "daily-g", schedule.Status.LastSuccessfulBackup)
+
+	successful, failed := int32(0), int32(0)
+	schedule.Spec.SuccessfulBackupsHistoryLimit = &successful
+	schedule.Spec.FailedBackupsHistoryLimit = &failed
+
+	require.NoError(t, backup.PruneHistory(r, ctx, schedule))
+	require.Equal(t, []string{"daily-h"}, listBackups(ctx, t, r), "running backups are kept")
+}
+
+func TestScheduleReconcile(t *testing.T) {
+	ctx := context.TODO()
+
+	schedule := newSchedule("0 2 * * *", time.Now().Add(-3*24*time.Hour))
+
+	r := newScheduleReconciler(t, schedule)
+
+	res, err := r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+	require.Greater(t, res.RequeueAfter, time.Duration(0))
+	require.LessOrEqual(t, res.RequeueAfter, 24*time.Hour)
+
+	schedule = getSchedule(ctx, t, r)
+	require.NotNil(t, schedule.Status.LastScheduleTime)
+	require.Equal(t, 2, schedule.Status.LastScheduleTime.UTC().Hour())
+	require.True(t, schedule.Status.LastScheduleTime.Add(24*time.Hour).After(time.Now()), "missed activations are not caught up")
+	require.Equal(t, []string{schedule.Status.LastBackup}, listBackups(ctx, t, r))
+
+	created := &goharborv1.HarborBackup{}
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Status.LastBackup}, created))
+	require.Equal(t, "cluster", created.Spec.HarborClusterRef)
+	require.Equal(t, schedule.Name, created.Labels[backup.ScheduleLabel])
+
+	// Not due before the next activation
+	res, err = r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+	require.Greater(t, res.RequeueAfter, time.Duration(0))
+	require.Len(t, listBackups(ctx, t, r), 1)
+}
+
+func TestScheduleReconcileLastBackupRunning(t *testing.T) {
+	ctx := context.TODO()
+
+	schedule := newSchedule("0 2 * * *", time.Now().Add(-10*24*time.Hour))
+
+	last := newBackup(schedule, "running", time.Now().Add(-3*24*time.Hour), goharborv1.HarborBackupPhaseRunning)
+
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: time.Now().Add(-3 * 24 * time.Hour)}
+	schedule.Status.LastBackup = last.Name
+
+	r := newScheduleReconciler(t, schedule, last)
+
+	res, err := r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter, "reconciled again when the last backup terminates")
+	require.Equal(t, []string{last.Name}, listBackups(ctx, t, r))
+
+	schedule = getSchedule(ctx, t, r)
+	require.Equal(t, last.Name, schedule.Status.LastBackup)
+	require.Contains(t, schedule.Status.Message, last.Name)
+
+	last.Status.Phase = goharborv1.HarborBackupPhaseFailed
+	require.NoError(t, r.Client.Status().Update(ctx, last))
+
+	_, err = r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+
+	schedule = getSchedule(ctx, t, r)
+	require.NotEqual(t, last.Name, schedule.Status.LastBackup)
+	require.Empty(t, schedule.Status.Message)
+	require.Len(t, listBackups(ctx, t, r), 2)
+}
+
+func TestScheduleReconcileSuspended(t *testing.T) {
+	ctx := context.TODO()
+
+	schedule := newSchedule("0 2 * * *", time.Now().Add(-3*24*time.Hour))
+	schedule.Spec.Suspend = true
+
+	r := newScheduleReconciler(t, schedule)
+
+	_, err := r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+	require.Empty(t, listBackups(ctx, t, r))
+}
+
+func TestScheduleReconcileInvalid(t *testing.T) {
+	ctx := context.TODO()
+
+	schedule := newSchedule("every day", time.Now().Add(-3*24*time.Hour))
+
+	r := newScheduleReconciler(t, schedule)
+
+	_, err := r.Reconcile(ctx, scheduleRequest(schedule))
+	require.NoError(t, err)
+	require.Empty(t, listBackups(ctx, t, r))
+	require.Contains(t, getSchedule(ctx, t, r).Status.Message, "invalid schedule")
+}
+
+func newSchedule(spec string, creation time.Time) *goharborv1.HarborBackupSchedule {
+	return &goharborv1.HarborBackupSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "harbor",
+			Name:              "daily",
+			CreationTimestamp: metav1.NewTime(creation),
+		},
+		Spec: goharborv1.HarborBackupScheduleSpec{
+			Schedule: spec,
+			Template: goharborv1.HarborBackupSpec{
+				HarborClusterRef: "cluster",
+				Target: goharborv1.HarborBackupTarget{
+					PersistentVolumeClaim: &goharborv1.HarborBackupPVCTarget{ClaimName: "backups"},
+				},
+			},
+		},
+	}
+}
+
+func newBackup(schedule *goharborv1.HarborBackupSchedule, suffix string, creation time.Time, phase goharborv1.HarborBackupPhase) *goharborv1.HarborBackup {
+	return &goharborv1.HarborBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         schedule.Namespace,
+			Name:              schedule.Name + "-" + suffix,
+			CreationTimestamp: metav1.NewTime(creation),
+			Labels: map[string]string{
+				backup.ScheduleLabel: schedule.Name,
+			},
+		},
+		Spec: schedule.Spec.Template,
+		Status: goharborv1.HarborBackupStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func newScheduleReconciler(t *testing.T, objects ...client.Object) *backup.ScheduleReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	r := &backup.ScheduleReconciler{
+		Controller: &commonCtrl.Controller{Log: logr.Discard()},
+		Scheme:     scheme,
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	return r
+}
+
+func scheduleRequest(schedule *goharborv1.HarborBackupSchedule) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: schedule.Namespace, Name: schedule.Name}}
+}
+
+func getSchedule(ctx context.Context, t *testing.T, r *backup.ScheduleReconciler) *goharborv1.HarborBackupSchedule {
+	schedule := &goharborv1.HarborBackupSchedule{}
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "harbor", Name: "daily"}, schedule))
+
+	return schedule
+}
+
+func listBackups(ctx context.Context, t *testing.T, r *backup.ScheduleReconciler) []string {
+	backups := &goharborv1.HarborBackupList{}
+	require.NoError(t, r.Client.List(ctx, backups))
+
+	names := []string{}
+	for _, backup := range backups.Items {
+		names = append(names, backup.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -49,6 +49,16 @@ func (r *Reconciler) AddResources(ctx context.Context, resource resources.Resour
 		return errors.Wrapf(err, "cannot add deployment %s", deployment.GetName())
 	}
 
+	podDisruptionBudget, err := chartMuseum.Spec.ComponentSpec.GetPodDisruptionBudget(deployment)
+	if err != nil {
+		return errors.Wrap(err, "cannot get pod disruption budget")
+	}
+
+	_, err = r.Controller.AddPodDisruptionBudgetToManage(ctx, podDisruptionBudget)
+	if err != nil {
+		return errors.Wrap(err, "cannot add pod disruption budget")
+	}
+
 	err = r.AddNetworkPolicies(ctx, chartMuseum)
 
 	return errors.Wrap(err, "network policies")
@@ -54,11 +54,32 @@ func (r *Reconciler) AddResources(ctx context.Context, resource resources.Resour
 		return errors.Wrap(err, "cannot get deployment")
 	}
 
+	horizontalPodAutoscaler := core.Spec.ComponentSpec.GetHorizontalPodAutoscaler(deployment)
+	if horizontalPodAutoscaler != nil {
+		// The replicas are left to the horizontal pod autoscaler
+		deployment.Spec.Replicas = nil
+	}
+
 	_, err = r.Controller.AddDeploymentToManage(ctx, deployment, configMapResource, secretResource)
 	if err != nil {
 		return errors.Wrapf(err, "cannot add deployment %s", deployment.GetName())
 	}
 
+	podDisruptionBudget, err := core.Spec.ComponentSpec.GetPodDisruptionBudget(deployment)
+	if err != nil {
+		return errors.Wrap(err, "cannot get pod disruption budget")
+	}
+
+	_, err = r.Controller.AddPodDisruptionBudgetToManage(ctx, podDisruptionBudget)
+	if err != nil {
+		return errors.Wrap(err, "cannot add pod disruption budget")
+	}
+
+	_, err = r.Controller.AddHorizontalPodAutoscalerToManage(ctx, horizontalPodAutoscaler)
+	if err != nil {
+		return errors.Wrap(err, "cannot add horizontal pod autoscaler")
+	}
+
 	err = r.AddNetworkPolicies(ctx, core)
 
 	return errors.Wrap(err, "network policies")
@@ -39,6 +39,16 @@ func (r *Reconciler) AddResources(ctx context.Context, resource resources.Resour
 		return errors.Wrapf(err, "add deployment %s", deployment.GetName())
 	}
 
+	podDisruptionBudget, err := exporter.Spec.ComponentSpec.GetPodDisruptionBudget(deployment)
+	if err != nil {
+		return errors.Wrap(err, "get pod disruption budget")
+	}
+
+	_, err = r.Controller.AddPodDisruptionBudgetToManage(ctx, podDisruptionBudget)
+	if err != nil {
+		return errors.Wrap(err, "add pod disruption budget")
+	}
+
 	err = r.AddNetworkPolicies(ctx, exporter)
 
 	return errors.Wrap(err, "add network policies")
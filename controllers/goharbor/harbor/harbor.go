@@ -7,17 +7,24 @@ import (
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/controllers"
 	"github.com/goharbor/harbor-operator/pkg/config"
+	"github.com/goharbor/harbor-operator/pkg/config/template"
 	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
 	"github.com/goharbor/harbor-operator/pkg/event-filter/class"
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
+// DefaultConfigTemplateFileName is the configuration template of the front proxy of the core exposed with a service.
+const DefaultConfigTemplateFileName = "proxy-config.conf.tmpl"
+
 // Reconciler reconciles a Harbor object.
 type Reconciler struct {
 	*commonCtrl.Controller
@@ -28,7 +35,12 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=goharbor.io,resources=chartmuseums;cores;exporters;jobservices;notaryservers;notarysigners;portals;registries;registrycontrollers;trivies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;tlsroutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps;services,verbs=get;list;watch;create;update;patch;delete
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	err := r.Controller.SetupWithManager(ctx, mgr)
@@ -36,6 +48,19 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return errors.Wrap(err, "cannot setup common controller")
 	}
 
+	templateConfig, err := r.Template(ctx)
+	if err != nil {
+		return errors.Wrap(err, "template")
+	}
+
+	if err := mgr.AddReadyzCheck(r.NormalizeName(ctx, "template"), templateConfig.ReadyzCheck); err != nil {
+		return errors.Wrap(err, "cannot add template ready check")
+	}
+
+	if err := mgr.AddHealthzCheck(r.NormalizeName(ctx, "template"), templateConfig.HealthzCheck); err != nil {
+		return errors.Wrap(err, "cannot add template health check")
+	}
+
 	className, err := r.GetClassName(ctx)
 	if err != nil {
 		return errors.Wrap(err, "cannot get class name")
@@ -46,7 +71,12 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return errors.Wrap(err, "cannot get concurrent reconcile")
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	autoscalingSupported, err := r.IsHorizontalPodAutoscalerSupported()
+	if err != nil {
+		return errors.Wrap(err, "cannot check horizontal pod autoscaler support")
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		WithEventFilter(&class.Filter{
 			ClassName: className,
 		}).
@@ -64,10 +94,20 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		Owns(&certv1.Issuer{}).
 		Owns(&certv1.Certificate{}).
 		Owns(&netv1.NetworkPolicy{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Service{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrentReconcile,
-		}).
-		Complete(r)
+		})
+
+	// The HorizontalPodAutoscalers are only watched when the autoscaling/v2 API is served, since Kubernetes 1.23.
+	if autoscalingSupported {
+		builder = builder.Owns(&autoscalingv2.HorizontalPodAutoscaler{})
+	}
+
+	return builder.Complete(r)
 }
 
 func (r *Reconciler) getAdminPasswordRef(ctx context.Context, harbor *goharborv1.Harbor) string {
@@ -93,6 +133,17 @@ func (r *Reconciler) getCoreURL(ctx context.Context, harbor *goharborv1.Harbor)
 	}).String()
 }
 
+func (r *Reconciler) Template(ctx context.Context) (*template.ConfigTemplate, error) {
+	templateConfig, err := template.FromConfigStore(r.ConfigStore, DefaultConfigTemplateFileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "from configstore")
+	}
+
+	templateConfig.Register(r.ConfigStore)
+
+	return templateConfig, nil
+}
+
 func New(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
 	r := &Reconciler{}
 
@@ -8,9 +8,12 @@ import (
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/harbor"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/internal/test"
+	"github.com/goharbor/harbor-operator/pkg/config"
 	"github.com/goharbor/harbor-operator/pkg/image"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/ovh/configstore"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -129,4 +132,206 @@ var _ = Describe("Harbor", func() {
 			})
 		}
 	})
+
+	Context("GetCoreRoute", func() {
+		BeforeEach(func() {
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				route, err := r.GetCoreRoute(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				return route
+			}
+		})
+
+		for _, text := range []string{
+			"Default",
+			"Parent references",
+		} {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/core-route/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/core-route/" + filename + "-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
+
+	Context("GetNotaryServerRoute", func() {
+		BeforeEach(func() {
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				route, err := r.GetNotaryServerRoute(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				return route
+			}
+		})
+
+		for _, text := range []string{
+			"Default",
+			"Passthrough",
+		} {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/notary-route/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/notary-route/" + filename + "-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
+
+	Context("GetNotaryServerIngress", func() {
+		BeforeEach(func() {
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				ingress, err := r.GetNotaryServerIngress(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				ingress.SetGroupVersionKind(schema.FromAPIVersionAndKind("networking.k8s.io/v1", "Ingress"))
+
+				return ingress
+			}
+		})
+
+		for _, text := range []string{
+			"Core gateway",
+			"Core service",
+		} {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/notary-ingress/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/notary-ingress/" + filename + "-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
+
+	Context("GetExposeProxyService", func() {
+		BeforeEach(func() {
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				service, err := r.GetExposeProxyService(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				service.SetGroupVersionKind(schema.FromAPIVersionAndKind("v1", "Service"))
+
+				return service
+			}
+		})
+
+		for _, text := range proxyCases {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/proxy/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/proxy/" + filename + "-service-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
+
+	Context("GetExposeProxyDeployment", func() {
+		BeforeEach(func() {
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				deployment, err := r.GetExposeProxyDeployment(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				deployment.SetGroupVersionKind(schema.FromAPIVersionAndKind("apps/v1", "Deployment"))
+
+				return deployment
+			}
+		})
+
+		for _, text := range proxyCases {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/proxy/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/proxy/" + filename + "-deployment-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
+
+	Context("GetExposeProxyConfigMap", func() {
+		BeforeEach(func() {
+			r.ConfigStore.InMemory("test-proxy-template").
+				Add(configstore.NewItem(config.TemplateDirectoryKey, "../../../config/config/assets", 100))
+
+			_, err := r.Template(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			getComponent = func(h *goharborv1.Harbor) runtime.Object {
+				configMap, err := r.GetExposeProxyConfigMap(ctx, h)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(configMap.GetName()).To(Equal("example-harbor-nginx"))
+				Expect(configMap.GetAnnotations()).NotTo(BeEmpty())
+
+				// Only the configuration is compared, the checksum of the template changing with the template
+				return &corev1.ConfigMap{
+					Data: map[string]string{
+						harbor.ProxyConfigName: string(configMap.BinaryData[harbor.ProxyConfigName]),
+					},
+				}
+			}
+		})
+
+		for _, text := range proxyCases {
+			text := text
+
+			filename := strings.Join(strings.Split(strings.ToLower(text), " "), "-")
+
+			Context(text, func() {
+				BeforeEach(func() {
+					input = fileString("./manifests/proxy/" + filename + ".yaml")
+				})
+
+				It("Should pass", func() {
+					expected := fileString("./manifests/proxy/" + filename + "-config-expected.yaml")
+					Expect(output).To(BeEquivalentTo(expected))
+				})
+			})
+		}
+	})
 })
+
+var proxyCases = []string{
+	"Load balancer with tls",
+	"Node port",
+}
@@ -65,6 +65,21 @@ func (r *Reconciler) GetCoreIngress(ctx context.Context, harbor *goharborv1.Harb
 }
 
 func (r *Reconciler) GetCoreIngressRules(ctx context.Context, harbor *goharborv1.Harbor) ([]netv1.IngressRule, error) {
+	coreBackend, portalBackend := r.GetCoreIngressBackends(ctx, harbor)
+
+	ruleValue, err := r.GetCoreIngressRuleValue(ctx, harbor, &coreBackend, portalBackend)
+	if err != nil {
+		return nil, errors.Wrap(err, "rule value")
+	}
+
+	return []netv1.IngressRule{{
+		Host:             harbor.Spec.Expose.Core.Ingress.Host,
+		IngressRuleValue: *ruleValue,
+	}}, nil
+}
+
+// GetCoreIngressBackends returns the backends of the core and of the portal, nil when the portal is disabled.
+func (r *Reconciler) GetCoreIngressBackends(ctx context.Context, harbor *goharborv1.Harbor) (netv1.IngressBackend, *netv1.IngressBackend) {
 	var portalBackend *netv1.IngressBackend
 
 	coreBackend := netv1.IngressBackend{
@@ -88,15 +103,7 @@ func (r *Reconciler) GetCoreIngressRules(ctx context.Context, harbor *goharborv1
 		}
 	}
 
-	ruleValue, err := r.GetCoreIngressRuleValue(ctx, harbor, &coreBackend, portalBackend)
-	if err != nil {
-		return nil, errors.Wrap(err, "rule value")
-	}
-
-	return []netv1.IngressRule{{
-		Host:             harbor.Spec.Expose.Core.Ingress.Host,
-		IngressRuleValue: *ruleValue,
-	}}, nil
+	return coreBackend, portalBackend
 }
 
 type NotaryIngress graph.Resource
@@ -117,7 +124,7 @@ func (r *Reconciler) GetNotaryServerIngress(ctx context.Context, harbor *goharbo
 		return nil, nil
 	}
 
-	if harbor.Spec.Expose.Notary.Ingress == nil {
+	if harbor.Spec.Expose.Notary == nil || harbor.Spec.Expose.Notary.Ingress == nil {
 		return nil, nil
 	}
 
@@ -223,12 +230,12 @@ func (r *Reconciler) GetNotaryIngressAnnotations(ctx context.Context, harbor *go
 		// resolve 413(Too Large Entity) error when push large image. It only works for NGINX ingress.
 		"nginx.ingress.kubernetes.io/proxy-body-size": "0",
 	}
-	if harbor.Spec.Expose.Core.Ingress.Controller == harbormetav1.IngressControllerNCP {
+	if harbor.Spec.Expose.Notary.Ingress.Controller == harbormetav1.IngressControllerNCP {
 		annotations["ncp/use-regex"] = NCPIngressValueTrue
 		if harbor.Spec.InternalTLS.IsEnabled() {
 			annotations["ncp/http-redirect"] = NCPIngressValueTrue
 		}
-	} else if harbor.Spec.Expose.Core.Ingress.Controller == harbormetav1.IngressControllerContour {
+	} else if harbor.Spec.Expose.Notary.Ingress.Controller == harbormetav1.IngressControllerContour {
 		if harbor.Spec.InternalTLS.IsEnabled() {
 			annotations["ingress.kubernetes.io/force-ssl-redirect"] = ContourIngressValueTrue
 		}
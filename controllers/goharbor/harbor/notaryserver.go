@@ -195,8 +195,8 @@ func (r *Reconciler) GetNotaryServer(ctx context.Context, harbor *goharborv1.Har
 		harbormetav1.NetworkPoliciesAnnotationName: harbormetav1.NetworkPoliciesAnnotationDisabled,
 	}
 
-	if harbor.Spec.Expose.Core.Ingress != nil {
-		annotation[harbormetav1.IngressControllerAnnotationName] = string(harbor.Spec.Expose.Core.Ingress.Controller)
+	if harbor.Spec.Expose.Notary != nil && harbor.Spec.Expose.Notary.Ingress != nil {
+		annotation[harbormetav1.IngressControllerAnnotationName] = string(harbor.Spec.Expose.Notary.Ingress.Controller)
 	}
 
 	return &goharborv1.NotaryServer{
@@ -0,0 +1,353 @@
+package harbor
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	conftemplate "github.com/goharbor/harbor-operator/pkg/config/template"
+	"github.com/goharbor/harbor-operator/pkg/graph"
+	"github.com/goharbor/harbor-operator/pkg/image"
+	"github.com/goharbor/harbor-operator/pkg/resources/checksum"
+	"github.com/goharbor/harbor-operator/pkg/version"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	ProxyName                         = "nginx"
+	ProxyConfigName                   = "nginx.conf"
+	ProxyConfigVolumeName             = "config"
+	ProxyConfigPath                   = "/etc/nginx"
+	ProxyCertificatesVolumeName       = "certificates"
+	ProxyCertificatesPath             = "/etc/nginx/ssl"
+	ProxyHealthPath                   = "/healthz"
+	ProxyHTTPPortName                 = "http"
+	ProxyHTTPSPortName                = "https"
+	proxyHTTPPort               int32 = 8080
+	proxyHTTPSPort              int32 = 8443
+)
+
+var (
+	proxyFSGroup    int64 = 10000
+	proxyRunAsGroup int64 = 10000
+	proxyRunAsUser  int64 = 10000
+)
+
+// ProxyLocation routes the requests matching the path prefix to the backend.
+type ProxyLocation struct {
+	Path    string
+	Backend string
+}
+
+// ProxyConfig is the data of the configuration template of the front proxy.
+type ProxyConfig struct {
+	*goharborv1.Harbor
+
+	TLS       bool
+	Locations []ProxyLocation
+}
+
+type ExposeProxy graph.Resource
+
+// AddExposeProxy adds the front proxy of the core exposed with a service,
+// which routes the requests to the core and to the portal like the rules of the core ingress.
+func (r *Reconciler) AddExposeProxy(ctx context.Context, harbor *goharborv1.Harbor) (ExposeProxy, error) {
+	if harbor.Spec.Expose.Core.Service == nil {
+		return nil, nil
+	}
+
+	configMap, err := r.GetExposeProxyConfigMap(ctx, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get configmap")
+	}
+
+	configMapRes, err := r.Controller.AddConfigMapToManage(ctx, configMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot add configmap %s", configMap.GetName())
+	}
+
+	deployment, err := r.GetExposeProxyDeployment(ctx, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get deployment")
+	}
+
+	componentSpec := harbor.GetExposeProxySpec(ctx)
+
+	horizontalPodAutoscaler := componentSpec.GetHorizontalPodAutoscaler(deployment)
+	if horizontalPodAutoscaler != nil {
+		// The replicas are left to the horizontal pod autoscaler
+		deployment.Spec.Replicas = nil
+	}
+
+	deploymentRes, err := r.Controller.AddDeploymentToManage(ctx, deployment, configMapRes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot add deployment %s", deployment.GetName())
+	}
+
+	podDisruptionBudget, err := componentSpec.GetPodDisruptionBudget(deployment)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get pod disruption budget")
+	}
+
+	_, err = r.Controller.AddPodDisruptionBudgetToManage(ctx, podDisruptionBudget)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot add pod disruption budget")
+	}
+
+	_, err = r.Controller.AddHorizontalPodAutoscalerToManage(ctx, horizontalPodAutoscaler)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot add horizontal pod autoscaler")
+	}
+
+	service, err := r.GetExposeProxyService(ctx, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get service")
+	}
+
+	_, err = r.Controller.AddServiceToManage(ctx, service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot add service %s", service.GetName())
+	}
+
+	return ExposeProxy(deploymentRes), nil
+}
+
+func (r *Reconciler) GetExposeProxyConfigMap(ctx context.Context, harbor *goharborv1.Harbor) (*corev1.ConfigMap, error) {
+	templateConfig, err := r.ConfigStore.GetItemValue(conftemplate.ConfigTemplateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get template")
+	}
+
+	coreBackend, portalBackend := r.GetCoreIngressBackends(ctx, harbor)
+
+	ruleValue, err := r.GetCoreIngressRuleValue(ctx, harbor, &coreBackend, portalBackend)
+	if err != nil {
+		return nil, errors.Wrap(err, "rule value")
+	}
+
+	proxyConfig := &ProxyConfig{
+		Harbor: harbor,
+		TLS:    harbor.Spec.Expose.Core.TLS.Enabled(),
+	}
+
+	for _, ingressPath := range ruleValue.HTTP.Paths {
+		proxyConfig.Locations = append(proxyConfig.Locations, ProxyLocation{
+			Path:    ingressPath.Path,
+			Backend: r.getProxyBackend(harbor, ingressPath.Backend),
+		})
+	}
+
+	content, err := r.GetTemplatedConfig(ctx, templateConfig, proxyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.NormalizeName(ctx, harbor.GetName(), ProxyName),
+			Namespace: harbor.GetNamespace(),
+			Annotations: map[string]string{
+				checksum.GetStaticID("template"): fmt.Sprintf("%x", sha256.Sum256([]byte(templateConfig))),
+			},
+		},
+		BinaryData: map[string][]byte{
+			ProxyConfigName: content,
+		},
+	}, nil
+}
+
+func (r *Reconciler) getProxyBackend(harbor *goharborv1.Harbor, backend netv1.IngressBackend) string {
+	return fmt.Sprintf("%s://%s:%d", harbor.Spec.InternalTLS.GetScheme(), backend.Service.Name, backend.Service.Port.Number)
+}
+
+func (r *Reconciler) GetExposeProxyDeployment(ctx context.Context, harbor *goharborv1.Harbor) (*appsv1.Deployment, error) { //nolint:funlen
+	componentSpec := harbor.GetExposeProxySpec(ctx)
+
+	image, err := image.GetImage(ctx, goharborv1.ExposeProxyImageComponent,
+		image.WithImageFromSpec(componentSpec.Image),
+		image.WithHarborVersion(harbor.Spec.Version))
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get image")
+	}
+
+	name := r.NormalizeName(ctx, harbor.GetName(), ProxyName)
+	namespace := harbor.GetNamespace()
+
+	volumes := []corev1.Volume{{
+		Name: ProxyConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: name,
+				},
+				Optional: &varFalse,
+			},
+		},
+	}}
+	volumeMounts := []corev1.VolumeMount{{
+		Name:      ProxyConfigVolumeName,
+		MountPath: path.Join(ProxyConfigPath, ProxyConfigName),
+		SubPath:   ProxyConfigName,
+	}}
+
+	ports := []corev1.ContainerPort{{
+		Name:          ProxyHTTPPortName,
+		ContainerPort: proxyHTTPPort,
+		Protocol:      corev1.ProtocolTCP,
+	}}
+
+	if harbor.Spec.Expose.Core.TLS.Enabled() {
+		volumes = append(volumes, corev1.Volume{
+			Name: ProxyCertificatesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: harbor.Spec.Expose.Core.TLS.CertificateRef,
+				},
+			},
+		})
+
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      ProxyCertificatesVolumeName,
+			MountPath: ProxyCertificatesPath,
+			ReadOnly:  true,
+		})
+
+		ports = append(ports, corev1.ContainerPort{
+			Name:          ProxyHTTPSPortName,
+			ContainerPort: proxyHTTPSPort,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+
+	// The health endpoint is served over HTTP even when the TLS is enabled
+	httpGET := &corev1.HTTPGetAction{
+		Path:   ProxyHealthPath,
+		Port:   intstr.FromString(ProxyHTTPPortName),
+		Scheme: corev1.URISchemeHTTP,
+	}
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: version.SetVersion(nil, harbor.Spec.Version),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					r.Label("name"):      name,
+					r.Label("namespace"): namespace,
+				},
+			},
+			Replicas: componentSpec.Replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: componentSpec.TemplateAnnotations,
+					Labels: map[string]string{
+						r.Label("name"):      name,
+						r.Label("namespace"): namespace,
+					},
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: &varFalse,
+					Volumes:                      volumes,
+					SecurityContext: &corev1.PodSecurityContext{
+						FSGroup:    &proxyFSGroup,
+						RunAsGroup: &proxyRunAsGroup,
+						RunAsUser:  &proxyRunAsUser,
+					},
+					Containers: []corev1.Container{{
+						Name:         ProxyName,
+						Image:        image,
+						Ports:        ports,
+						VolumeMounts: volumeMounts,
+						LivenessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: httpGET,
+							},
+						},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: httpGET,
+							},
+						},
+					}},
+				},
+			},
+			Paused: false,
+		},
+	}
+
+	componentSpec.ApplyToDeployment(deploy)
+
+	return deploy, nil
+}
+
+func (r *Reconciler) GetExposeProxyService(ctx context.Context, harbor *goharborv1.Harbor) (*corev1.Service, error) {
+	spec := harbor.Spec.Expose.Core.Service
+	name := r.NormalizeName(ctx, harbor.GetName(), ProxyName)
+	namespace := harbor.GetNamespace()
+
+	serviceType := spec.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeLoadBalancer
+	}
+
+	ports := []corev1.ServicePort{{
+		Name:       ProxyHTTPPortName,
+		Port:       harbormetav1.HTTPPort,
+		TargetPort: intstr.FromString(ProxyHTTPPortName),
+		Protocol:   corev1.ProtocolTCP,
+	}}
+
+	if spec.HTTPNodePort != nil {
+		ports[0].NodePort = *spec.HTTPNodePort
+	}
+
+	if harbor.Spec.Expose.Core.TLS.Enabled() {
+		port := corev1.ServicePort{
+			Name:       ProxyHTTPSPortName,
+			Port:       harbormetav1.HTTPSPort,
+			TargetPort: intstr.FromString(ProxyHTTPSPortName),
+			Protocol:   corev1.ProtocolTCP,
+		}
+
+		if spec.HTTPSNodePort != nil {
+			port.NodePort = *spec.HTTPSNodePort
+		}
+
+		ports = append(ports, port)
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: spec.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                  serviceType,
+			Ports:                 ports,
+			ExternalTrafficPolicy: spec.ExternalTrafficPolicy,
+			Selector: map[string]string{
+				r.Label("name"):      name,
+				r.Label("namespace"): namespace,
+			},
+		},
+	}
+
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		service.Spec.LoadBalancerClass = spec.LoadBalancerClass
+		service.Spec.LoadBalancerSourceRanges = spec.LoadBalancerSourceRanges
+	}
+
+	return service, nil
+}
@@ -125,6 +125,21 @@ func (r *Reconciler) AddResources(ctx context.Context, resource resources.Resour
 		return errors.Wrapf(err, "add %s ingress", controllers.NotaryServer)
 	}
 
+	_, err = r.AddExposeProxy(ctx, harbor)
+	if err != nil {
+		return errors.Wrapf(err, "add %s proxy", controllers.Core)
+	}
+
+	_, err = r.AddCoreRoute(ctx, harbor, core, portal)
+	if err != nil {
+		return errors.Wrapf(err, "add %s route", controllers.Core)
+	}
+
+	_, err = r.AddNotaryRoute(ctx, harbor, notaryServer)
+	if err != nil {
+		return errors.Wrapf(err, "add %s route", controllers.NotaryServer)
+	}
+
 	err = r.AddNetworkPolicies(ctx, harbor)
 	if err != nil {
 		return errors.Wrapf(err, "add network policies")
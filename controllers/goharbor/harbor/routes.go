@@ -0,0 +1,201 @@
+package harbor
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/graph"
+	"github.com/pkg/errors"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	GatewayAPIGroup = "gateway.networking.k8s.io"
+	GatewayKind     = "Gateway"
+)
+
+var (
+	HTTPRouteGVK = schema.GroupVersionKind{
+		Group:   GatewayAPIGroup,
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	}
+
+	TLSRouteGVK = schema.GroupVersionKind{
+		Group:   GatewayAPIGroup,
+		Version: "v1alpha2",
+		Kind:    "TLSRoute",
+	}
+)
+
+type CoreRoute graph.Resource
+
+func (r *Reconciler) AddCoreRoute(ctx context.Context, harbor *goharborv1.Harbor, core Core, portal Portal) (CoreRoute, error) {
+	route, err := r.GetCoreRoute(ctx, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get core route")
+	}
+
+	if route == nil {
+		return nil, nil
+	}
+
+	routeRes, err := r.Controller.AddNonCheckableResource(ctx, route, core, portal)
+
+	return CoreRoute(routeRes), errors.Wrap(err, "cannot add core route")
+}
+
+// GetCoreRoute returns the HTTPRoute of the core and of the portal,
+// with the same paths as the rules of the core ingress.
+func (r *Reconciler) GetCoreRoute(ctx context.Context, harbor *goharborv1.Harbor) (*unstructured.Unstructured, error) {
+	gateway := harbor.Spec.Expose.Core.Gateway
+	if gateway == nil {
+		return nil, nil
+	}
+
+	coreBackend, portalBackend := r.GetCoreIngressBackends(ctx, harbor)
+
+	ruleValue, err := r.GetCoreIngressRuleValue(ctx, harbor, &coreBackend, portalBackend)
+	if err != nil {
+		return nil, errors.Wrap(err, "rule value")
+	}
+
+	rules := make([]interface{}, 0, len(ruleValue.HTTP.Paths))
+
+	for _, path := range ruleValue.HTTP.Paths {
+		rules = append(rules, map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":  "PathPrefix",
+						"value": path.Path,
+					},
+				},
+			},
+			"backendRefs": []interface{}{routeBackendRef(path.Backend)},
+		})
+	}
+
+	return r.getRoute(ctx, harbor, HTTPRouteGVK, r.NormalizeName(ctx, harbor.GetName()), gateway, rules), nil
+}
+
+type NotaryRoute graph.Resource
+
+func (r *Reconciler) AddNotaryRoute(ctx context.Context, harbor *goharborv1.Harbor, notary NotaryServer) (NotaryRoute, error) {
+	route, err := r.GetNotaryServerRoute(ctx, harbor)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get notary route")
+	}
+
+	if route == nil {
+		return nil, nil
+	}
+
+	routeRes, err := r.Controller.AddNonCheckableResource(ctx, route, notary)
+
+	return NotaryRoute(routeRes), errors.Wrap(err, "cannot add notary route")
+}
+
+// GetNotaryServerRoute returns the HTTPRoute of the notary server,
+// or its TLSRoute when the TLS connections are passed through to the notary server.
+func (r *Reconciler) GetNotaryServerRoute(ctx context.Context, harbor *goharborv1.Harbor) (*unstructured.Unstructured, error) {
+	if harbor.Spec.Notary == nil {
+		return nil, nil
+	}
+
+	if harbor.Spec.Expose.Notary == nil || harbor.Spec.Expose.Notary.Gateway == nil {
+		return nil, nil
+	}
+
+	gateway := harbor.Spec.Expose.Notary.Gateway
+
+	backendRef := routeBackendRef(netv1.IngressBackend{
+		Service: &netv1.IngressServiceBackend{
+			Name: r.NormalizeName(ctx, harbor.GetName(), controllers.NotaryServer.String()),
+			Port: netv1.ServiceBackendPort{
+				Number: harbor.Spec.InternalTLS.GetInternalPort(harbormetav1.NotaryServerTLS),
+			},
+		},
+	})
+
+	name := r.NormalizeName(ctx, harbor.GetName(), controllers.NotaryServer.String())
+
+	if gateway.Passthrough {
+		return r.getRoute(ctx, harbor, TLSRouteGVK, name, gateway, []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{backendRef},
+			},
+		}), nil
+	}
+
+	return r.getRoute(ctx, harbor, HTTPRouteGVK, name, gateway, []interface{}{
+		map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":  "PathPrefix",
+						"value": "/",
+					},
+				},
+			},
+			"backendRefs": []interface{}{backendRef},
+		},
+	}), nil
+}
+
+func (r *Reconciler) getRoute(ctx context.Context, harbor *goharborv1.Harbor, gvk schema.GroupVersionKind, name string, gateway *goharborv1.HarborExposeGatewaySpec, rules []interface{}) *unstructured.Unstructured {
+	parentRefs := make([]interface{}, 0, len(gateway.ParentRefs))
+
+	for _, ref := range gateway.ParentRefs {
+		parentRef := map[string]interface{}{
+			"group": GatewayAPIGroup,
+			"kind":  GatewayKind,
+			"name":  ref.Name,
+		}
+
+		if ref.Namespace != nil {
+			parentRef["namespace"] = *ref.Namespace
+		}
+
+		if ref.SectionName != nil {
+			parentRef["sectionName"] = *ref.SectionName
+		}
+
+		if ref.Port != nil {
+			parentRef["port"] = int64(*ref.Port)
+		}
+
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"parentRefs": parentRefs,
+				"hostnames":  []interface{}{gateway.Host},
+				"rules":      rules,
+			},
+		},
+	}
+
+	route.SetGroupVersionKind(gvk)
+	route.SetName(name)
+	route.SetNamespace(harbor.GetNamespace())
+
+	if len(gateway.Annotations) > 0 {
+		route.SetAnnotations(gateway.Annotations)
+	}
+
+	return route
+}
+
+func routeBackendRef(backend netv1.IngressBackend) map[string]interface{} {
+	return map[string]interface{}{
+		"name": backend.Service.Name,
+		"port": int64(backend.Service.Port.Number),
+	}
+}
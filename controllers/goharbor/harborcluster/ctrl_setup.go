@@ -21,15 +21,18 @@ import (
 	redisOp "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	postgresv1 "github.com/zalando/postgres-operator/pkg/apis/acid.zalan.do/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
 
 const (
-	minioCRD    = "tenants.minio.min.io"
-	redisCRD    = "redisfailovers.databases.spotahome.com"
-	postgresCRD = "postgresqls.acid.zalan.do"
+	minioCRD            = "tenants.minio.min.io"
+	redisCRD            = "redisfailovers.databases.spotahome.com"
+	postgresCRD         = "postgresqls.acid.zalan.do"
+	cnpgCRD             = "clusters.postgresql.cnpg.io"
+	redisReplicationCRD = "redisreplications.redis.redis.opstreelabs.in"
 )
 
 // TODO: Refactor to inherit the common reconciler in future
@@ -47,11 +50,13 @@ type Reconciler struct {
 	*commonCtrl.Controller // TODO: move the Reconcile to pkg/controller.Controller
 }
 
-// +kubebuilder:rbac:groups=goharbor.io,resources=harborclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborclusters,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=goharbor.io,resources=harborclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=databases.spotahome.com,resources=*,verbs=*
+// +kubebuilder:rbac:groups=redis.redis.opstreelabs.in,resources=*,verbs=*
 // +kubebuilder:rbac:groups=acid.zalan.do,resources=*,verbs=*
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=*,verbs=*
 // +kubebuilder:rbac:groups=minio.min.io,resources=*,verbs=*
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets;deployments,verbs=get;list;watch;create;update;patch;delete
@@ -59,6 +64,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
 
 // Enhancements to RBAC
 // see: https://sdk.operatorframework.io/docs/faqs/#after-deploying-my-operator-why-do-i-see-errors-like-is-forbidden-cannot-set-blockownerdeletion-if-an-ownerreference-refers-to-a-resource-you-cant-set-finalizers-on-
@@ -113,13 +120,21 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		k8s.WithScheme(mgr.GetScheme()),
 		k8s.WithClient(mgr.GetClient()))
 
+	cnpgCluster := &unstructured.Unstructured{}
+	cnpgCluster.SetGroupVersionKind(database.CloudNativePGClusterGVK)
+
+	redisReplication := &unstructured.Unstructured{}
+	redisReplication.SetGroupVersionKind(cache.RedisReplicationGVK)
+
 	return builder.ControllerManagedBy(mgr).
 		For(&goharborv1.HarborCluster{}).
 		Owns(&batchv1.Job{}).
 		Owns(&goharborv1.Harbor{}).
 		TryOwns(&minio.Tenant{}, minioCRD).
 		TryOwns(&postgresv1.Postgresql{}, postgresCRD).
+		TryOwns(cnpgCluster, cnpgCRD).
 		TryOwns(&redisOp.RedisFailover{}, redisCRD).
+		TryOwns(redisReplication, redisReplicationCRD).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrentReconcile,
 		}).
@@ -0,0 +1,80 @@
+package harborcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	finalizerID = "harborcluster.goharbor.io/finalizer"
+
+	// The deletion of volumes and the readiness of snapshots are not watched.
+	deletionRequeueDelay = 10 * time.Second
+)
+
+type teardown struct {
+	ctrl          lcm.Controller
+	conditionType goharborv1.HarborClusterConditionType
+}
+
+// finalize tears down the in-cluster cache and database according to their deletion policy,
+// the finalizer is removed once all of them are done.
+func (r *Reconciler) finalize(ctx context.Context, harborcluster *goharborv1.HarborCluster) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(harborcluster, finalizerID) {
+		return ctrl.Result{}, nil
+	}
+
+	st := newStatus(harborcluster).
+		WithContext(ctx).
+		WithClient(r.Client).
+		WithLog(r.Log)
+
+	teardowns := []teardown{}
+
+	if harborcluster.Spec.Cache.Spec.IsInCluster() {
+		teardowns = append(teardowns, teardown{ctrl: r.CacheCtrl, conditionType: goharborv1.CacheReady})
+	}
+
+	if harborcluster.Spec.Database.Spec.IsInCluster() {
+		teardowns = append(teardowns, teardown{ctrl: r.DatabaseCtrl, conditionType: goharborv1.DatabaseReady})
+	}
+
+	pending := false
+
+	for _, t := range teardowns {
+		crStatus, err := t.ctrl.Delete(ctx, harborcluster)
+		if crStatus != nil {
+			st.UpdateCondition(t.conditionType, crStatus.Condition)
+
+			pending = true
+		}
+
+		if err != nil {
+			if er := st.Update(); er != nil {
+				r.Log.Error(er, "failed to update status of harbor cluster")
+			}
+
+			return ctrl.Result{}, fmt.Errorf("delete %s error: %w", t.conditionType, err)
+		}
+	}
+
+	if pending {
+		r.Log.Info("waiting for the in-cluster services to be deleted")
+
+		return ctrl.Result{RequeueAfter: deletionRequeueDelay}, st.Update()
+	}
+
+	controllerutil.RemoveFinalizer(harborcluster, finalizerID)
+
+	if err := r.Client.Update(ctx, harborcluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("remove finalizer error: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // Reconcile logic of the HarborCluster.
@@ -34,7 +35,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 	if !harborcluster.ObjectMeta.DeletionTimestamp.IsZero() {
 		r.Log.Info("harbor cluster is being deleted", "name", req.NamespacedName)
 
-		return ctrl.Result{}, nil
+		return r.finalize(ctx, harborcluster)
+	}
+
+	if !controllerutil.ContainsFinalizer(harborcluster, finalizerID) {
+		controllerutil.AddFinalizer(harborcluster, finalizerID)
+
+		if err := r.Client.Update(ctx, harborcluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("add finalizer error: %w", err)
+		}
 	}
 
 	if err := r.PrepareStatus(ctx, harborcluster); err != nil {
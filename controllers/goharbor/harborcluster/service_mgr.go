@@ -107,11 +107,11 @@ func (s *ServiceManager) Apply() error { //nolint:funlen
 
 	switch s.component {
 	case goharborv1.ComponentCache:
-		if s.cluster.Spec.Cache.Spec.RedisFailover == nil {
+		if !s.cluster.Spec.Cache.Spec.IsInCluster() {
 			useInCluster = false
 		}
 	case goharborv1.ComponentDatabase:
-		if s.cluster.Spec.Database.Spec.ZlandoPostgreSQL == nil {
+		if !s.cluster.Spec.Database.Spec.IsInCluster() {
 			useInCluster = false
 		}
 	case goharborv1.ComponentStorage:
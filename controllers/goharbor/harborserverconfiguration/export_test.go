@@ -0,0 +1,14 @@
+package harborserverconfiguration
+
+var (
+	Probe         = probe
+	ProbeFailed   = probeFailed
+	ProbeInterval = probeInterval
+	ProbeTimeout  = probeTimeout
+
+	ServerHealthy    = serverHealthy
+	ComponentHealthy = componentHealthy
+	CredentialValid  = credentialValid
+	VersionMatched   = versionMatched
+	ServerInfo       = serverInfo
+)
@@ -5,17 +5,20 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-logr/logr"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/controllers"
 	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
 	harborClient "github.com/goharbor/harbor-operator/pkg/rest"
 	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
 	"github.com/ovh/configstore"
-	"github.com/pkg/errors"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
 const (
@@ -54,6 +57,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		if apierr.IsNotFound(err) {
 			// It could have been deleted after reconcile request coming in.
 			log.Info("Harbor server configuration does not exist")
+			deleteMetrics("", req.Name)
 
 			return ctrl.Result{}, nil
 		}
@@ -65,42 +69,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		if err != nil {
 			hsc.Status.Status = goharborv1.HarborServerConfigurationStatusFail
 			hsc.Status.Message = err.Error()
-		} else {
-			hsc.Status.Status = goharborv1.HarborServerConfigurationStatusReady
-			hsc.Status.Reason = ""
-			hsc.Status.Message = ""
 		}
 
 		log.Info("Reconcile end", "result", res, "error", err, "updateStatusError", r.Client.Status().Update(ctx, hsc))
 	}()
 
-	// Create harbor client
-	harborv2, err := harborClient.CreateHarborV2Client(ctx, r.Client, hsc)
-	if err != nil {
-		log.Error(err, "failed to create harbor client")
-
-		return ctrl.Result{}, err
-	}
-
-	r.Harbor = harborv2.WithContext(ctx)
-
 	// Check if the configuration is being deleted
 	if !hsc.ObjectMeta.DeletionTimestamp.IsZero() {
 		log.Info("Harbor server configuration is being deleted")
+		deleteMetrics("", hsc.Name)
 
 		return ctrl.Result{}, nil
 	}
 
-	// Check server health and construct status
-	err = r.checkServerHealth()
-	if err != nil {
+	// Probe the server and construct status
+	if err := probeServer(ctx, log, r.Client, hsc, "", hsc.Name, &hsc.Status); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	log.Info("Finished HarborServerConfiguration Reconciler")
-	// The health should be rechecked after a reasonable cycle
+	// The health should be rechecked after the probe interval
 	return ctrl.Result{
-		RequeueAfter: defaultCycle,
+		RequeueAfter: probeInterval(&hsc.Spec),
 	}, nil
 }
 
@@ -110,29 +100,30 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 	r.Scheme = mgr.GetScheme()
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&goharborv1.HarborServerConfiguration{}).
+		// The status updates of the probes do not trigger new probes
+		For(&goharborv1.HarborServerConfiguration{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
 
-func (r *Reconciler) checkServerHealth() error {
-	errStr := ""
-
-	healthPayload, err := r.Harbor.CheckHealth()
+// probeServer creates the Harbor client of the configuration, then probes the Harbor server.
+func probeServer(ctx context.Context, log logr.Logger, c client.Client, hsc *goharborv1.HarborServerConfiguration, namespace, name string, status *goharborv1.HarborServerConfigurationStatus) error {
+	server, err := harborClient.CreateHarborServer(ctx, c, hsc)
 	if err != nil {
-		r.Log.Error(err, "check harbor server health failed.")
+		log.Error(err, "failed to read the access secret")
+		probeFailed(namespace, name, status, metav1.ConditionFalse, "AccessSecretInvalid", err)
 
 		return err
 	}
 
-	for _, comp := range healthPayload.Components {
-		if len(comp.Error) > 0 {
-			errStr += "Component " + comp.Name + ": " + comp.Error
-		}
-	}
+	harborv2, err := v2.NewWithServer(server)
+	if err != nil {
+		log.Error(err, "failed to create harbor client")
+		probeFailed(namespace, name, status, metav1.ConditionUnknown, "ClientError", err)
 
-	if len(errStr) == 0 {
-		return nil
+		return err
 	}
 
-	return errors.Errorf(errStr)
+	probe(log, harborv2.WithContext(ctx).WithTimeout(probeTimeout(&hsc.Spec)), namespace, name, &hsc.Spec, status)
+
+	return nil
 }
@@ -0,0 +1,310 @@
+package harborserverconfiguration
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultProbeTimeout = 30 * time.Second
+	// minProbeInterval avoids hammering Harbor with a misconfigured interval.
+	minProbeInterval = 10 * time.Second
+	// healthStatusHealthy is the status of the healthy Harbor components.
+	healthStatusHealthy = "healthy"
+)
+
+// probeInterval returns the interval between two probes of the Harbor server.
+func probeInterval(spec *goharborv1.HarborServerConfigurationSpec) time.Duration {
+	if spec.HealthCheck == nil || spec.HealthCheck.Interval == nil {
+		return defaultCycle
+	}
+
+	if spec.HealthCheck.Interval.Duration < minProbeInterval {
+		return minProbeInterval
+	}
+
+	return spec.HealthCheck.Interval.Duration
+}
+
+// probeTimeout returns the timeout of the Harbor API calls of a probe.
+func probeTimeout(spec *goharborv1.HarborServerConfigurationSpec) time.Duration {
+	if spec.HealthCheck == nil || spec.HealthCheck.Timeout == nil || spec.HealthCheck.Timeout.Duration <= 0 {
+		return defaultProbeTimeout
+	}
+
+	return spec.HealthCheck.Timeout.Duration
+}
+
+// probe checks the health of the Harbor server and of its components, the validity of the credential
+// and the version of the server, then records the result in the conditions of the status and in the metrics.
+func probe(log logr.Logger, harbor *v2.Client, namespace, name string, spec *goharborv1.HarborServerConfigurationSpec, status *goharborv1.HarborServerConfigurationStatus) {
+	labels := serverMetricLabels(namespace, name)
+
+	healthy := checkServerHealth(log, harbor, labels, status)
+
+	credErr := harbor.CheckCredential()
+	setCredentialCondition(status, credErr)
+	credentialValid.With(labels).Set(boolToGauge(credErr == nil))
+
+	if credErr == nil {
+		// The version is only returned to the authenticated users
+		version, err := harbor.GetHarborVersion()
+		if err != nil {
+			log.Error(err, "get harbor server version failed")
+		} else {
+			status.Version = version
+		}
+	}
+
+	matched := setVersionCondition(status, spec.Version)
+	versionMatched.With(labels).Set(boolToGauge(matched))
+
+	serverInfo.DeletePartialMatch(labels)
+
+	if status.Version != "" {
+		serverInfo.With(prometheus.Labels{
+			labelNamespace:                 namespace,
+			labelHarborServerConfiguration: name,
+			labelVersion:                   status.Version,
+		}).Set(1)
+	}
+
+	switch {
+	case !healthy:
+		condition := meta.FindStatusCondition(status.Conditions, goharborv1.HarborServerConfigurationConditionHealthy)
+		setStatus(status, goharborv1.HarborServerConfigurationStatusFail, condition.Reason, condition.Message)
+	case credErr != nil:
+		setStatus(status, goharborv1.HarborServerConfigurationStatusFail, "InvalidCredential", credErr.Error())
+	default:
+		setStatus(status, goharborv1.HarborServerConfigurationStatusReady, "", "")
+	}
+}
+
+// probeFailed records in the status and in the metrics that the Harbor server cannot be probed,
+// e.g. because its access secret is invalid.
+func probeFailed(namespace, name string, status *goharborv1.HarborServerConfigurationStatus, credential metav1.ConditionStatus, reason string, err error) {
+	labels := serverMetricLabels(namespace, name)
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    goharborv1.HarborServerConfigurationConditionHealthy,
+		Status:  metav1.ConditionUnknown,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+
+	setComponentsUnknown(status, reason)
+	componentHealthy.DeletePartialMatch(labels)
+	serverHealthy.With(labels).Set(0)
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:    goharborv1.HarborServerConfigurationConditionCredentialValid,
+		Status:  credential,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+	credentialValid.With(labels).Set(boolToGauge(credential == metav1.ConditionTrue))
+
+	setStatus(status, goharborv1.HarborServerConfigurationStatusFail, reason, err.Error())
+}
+
+// checkServerHealth sets the conditions of the overall health and of the health of each component of the Harbor server.
+func checkServerHealth(log logr.Logger, harbor *v2.Client, labels prometheus.Labels, status *goharborv1.HarborServerConfigurationStatus) bool {
+	healthPayload, err := harbor.CheckHealth()
+	if err != nil {
+		log.Error(err, "check harbor server health failed.")
+
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:    goharborv1.HarborServerConfigurationConditionHealthy,
+			Status:  metav1.ConditionFalse,
+			Reason:  "Unreachable",
+			Message: err.Error(),
+		})
+
+		setComponentsUnknown(status, "Unreachable")
+		componentHealthy.DeletePartialMatch(labels)
+		serverHealthy.With(labels).Set(0)
+
+		return false
+	}
+
+	components := map[string]bool{}
+	errStr := ""
+
+	componentHealthy.DeletePartialMatch(labels)
+
+	for _, comp := range healthPayload.Components {
+		conditionType := goharborv1.HarborServerComponentConditionType(comp.Name)
+		components[conditionType] = true
+
+		componentLabels := prometheus.Labels{labelComponent: comp.Name}
+		for k, v := range labels {
+			componentLabels[k] = v
+		}
+
+		condition := metav1.Condition{
+			Type:    conditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Healthy",
+			Message: fmt.Sprintf("Component %s is healthy", comp.Name),
+		}
+
+		if comp.Status != healthStatusHealthy || len(comp.Error) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "Unhealthy"
+			condition.Message = fmt.Sprintf("Component %s is %s", comp.Name, comp.Status)
+
+			if len(comp.Error) > 0 {
+				condition.Message = comp.Error
+			}
+
+			errStr += "Component " + comp.Name + ": " + condition.Message + ". "
+		}
+
+		meta.SetStatusCondition(&status.Conditions, condition)
+		componentHealthy.With(componentLabels).Set(boolToGauge(condition.Status == metav1.ConditionTrue))
+	}
+
+	removeStaleComponents(status, components)
+
+	healthy := healthPayload.Status == healthStatusHealthy && len(errStr) == 0
+
+	condition := metav1.Condition{
+		Type:    goharborv1.HarborServerConfigurationConditionHealthy,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Healthy",
+		Message: "The Harbor server is healthy",
+	}
+
+	if !healthy {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Unhealthy"
+		condition.Message = strings.TrimSpace(errStr)
+
+		if condition.Message == "" {
+			condition.Message = fmt.Sprintf("The Harbor server is %s", healthPayload.Status)
+		}
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+	serverHealthy.With(labels).Set(boolToGauge(healthy))
+
+	return healthy
+}
+
+func setCredentialCondition(status *goharborv1.HarborServerConfigurationStatus, err error) {
+	condition := metav1.Condition{
+		Type:    goharborv1.HarborServerConfigurationConditionCredentialValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  "CredentialAccepted",
+		Message: "The Harbor server accepts the access credential",
+	}
+
+	switch {
+	case errors.Is(err, v2.ErrInvalidCredential):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CredentialRejected"
+		condition.Message = "The Harbor server rejects the access credential"
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "CheckFailed"
+		condition.Message = err.Error()
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// setVersionCondition compares the major, minor and patch numbers of the observed version with the expected version.
+func setVersionCondition(status *goharborv1.HarborServerConfigurationStatus, expected string) bool {
+	condition := metav1.Condition{
+		Type:   goharborv1.HarborServerConfigurationConditionVersionMatched,
+		Status: metav1.ConditionUnknown,
+		Reason: "VersionUnknown",
+	}
+
+	defer func() {
+		meta.SetStatusCondition(&status.Conditions, condition)
+	}()
+
+	if status.Version == "" {
+		condition.Message = "The version of the Harbor server is not known yet"
+
+		return false
+	}
+
+	// The observed version looks like v2.4.1-c4b06d79
+	observed, err := semver.NewVersion(strings.TrimPrefix(status.Version, "v"))
+	if err != nil {
+		condition.Message = fmt.Sprintf("Cannot parse the observed version %s: %v", status.Version, err)
+
+		return false
+	}
+
+	version, err := semver.NewVersion(expected)
+	if err != nil {
+		condition.Message = fmt.Sprintf("Cannot parse the version %s: %v", expected, err)
+
+		return false
+	}
+
+	if observed.Major() != version.Major() || observed.Minor() != version.Minor() || observed.Patch() != version.Patch() {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "VersionMismatch"
+		condition.Message = fmt.Sprintf("The Harbor server runs %s, %s expected", status.Version, expected)
+
+		return false
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "VersionMatched"
+	condition.Message = fmt.Sprintf("The Harbor server runs %s", status.Version)
+
+	return true
+}
+
+// isComponentCondition checks whether the condition reports the health of a Harbor component.
+func isComponentCondition(conditionType string) bool {
+	return conditionType != goharborv1.HarborServerConfigurationConditionHealthy &&
+		strings.HasSuffix(conditionType, goharborv1.HarborServerConfigurationConditionHealthy)
+}
+
+func setComponentsUnknown(status *goharborv1.HarborServerConfigurationStatus, reason string) {
+	for i := range status.Conditions {
+		if isComponentCondition(status.Conditions[i].Type) {
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:    status.Conditions[i].Type,
+				Status:  metav1.ConditionUnknown,
+				Reason:  reason,
+				Message: "The health of the Harbor server cannot be checked",
+			})
+		}
+	}
+}
+
+// removeStaleComponents removes the conditions of the components Harbor does not report anymore.
+func removeStaleComponents(status *goharborv1.HarborServerConfigurationStatus, components map[string]bool) {
+	conditions := status.Conditions[:0]
+
+	for _, condition := range status.Conditions {
+		if !isComponentCondition(condition.Type) || components[condition.Type] {
+			conditions = append(conditions, condition)
+		}
+	}
+
+	status.Conditions = conditions
+}
+
+func setStatus(status *goharborv1.HarborServerConfigurationStatus, statusType goharborv1.HarborServerConfigurationStatusType, reason, message string) {
+	status.Status = statusType
+	status.Reason = reason
+	status.Message = message
+}
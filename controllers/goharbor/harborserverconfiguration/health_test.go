@@ -0,0 +1,274 @@
+package harborserverconfiguration_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/harborserverconfiguration"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testVersion = "v2.4.1-c4b06d79"
+
+func TestProbeInterval(t *testing.T) {
+	spec := &goharborv1.HarborServerConfigurationSpec{}
+	require.Equal(t, 5*time.Minute, harborserverconfiguration.ProbeInterval(spec))
+	require.Equal(t, 30*time.Second, harborserverconfiguration.ProbeTimeout(spec))
+
+	spec.HealthCheck = &goharborv1.HarborServerHealthCheck{
+		Interval: &metav1.Duration{Duration: time.Minute},
+		Timeout:  &metav1.Duration{Duration: 5 * time.Second},
+	}
+	require.Equal(t, time.Minute, harborserverconfiguration.ProbeInterval(spec))
+	require.Equal(t, 5*time.Second, harborserverconfiguration.ProbeTimeout(spec))
+
+	spec.HealthCheck.Interval.Duration = time.Second
+	spec.HealthCheck.Timeout.Duration = 0
+	require.Equal(t, 10*time.Second, harborserverconfiguration.ProbeInterval(spec), "at least 10s")
+	require.Equal(t, 30*time.Second, harborserverconfiguration.ProbeTimeout(spec))
+}
+
+func TestProbe(t *testing.T) { //nolint:funlen
+	healthy := &models.OverallHealthStatus{
+		Status: "healthy",
+		Components: []*models.ComponentHealthStatus{
+			{Name: "core", Status: "healthy"},
+			{Name: "jobservice", Status: "healthy"},
+		},
+	}
+
+	unhealthy := &models.OverallHealthStatus{
+		Status: "unhealthy",
+		Components: []*models.ComponentHealthStatus{
+			{Name: "core", Status: "healthy"},
+			{Name: "jobservice", Status: "unhealthy", Error: "failed to connect to redis"},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		health      *models.OverallHealthStatus
+		password    string
+		version     string
+		status      goharborv1.HarborServerConfigurationStatusType
+		reason      string
+		conditions  map[string]metav1.ConditionStatus
+		jobservice  float64
+		credential  float64
+		matched     float64
+		serverValue float64
+	}{
+		"healthy": {
+			health:   healthy,
+			password: "Harbor12345",
+			version:  "2.4.1",
+			status:   goharborv1.HarborServerConfigurationStatusReady,
+			conditions: map[string]metav1.ConditionStatus{
+				goharborv1.HarborServerConfigurationConditionHealthy: metav1.ConditionTrue,
+				"CoreHealthy":       metav1.ConditionTrue,
+				"JobserviceHealthy": metav1.ConditionTrue,
+				goharborv1.HarborServerConfigurationConditionCredentialValid: metav1.ConditionTrue,
+				goharborv1.HarborServerConfigurationConditionVersionMatched:  metav1.ConditionTrue,
+			},
+			jobservice:  1,
+			credential:  1,
+			matched:     1,
+			serverValue: 1,
+		},
+		"unhealthy component": {
+			health:   unhealthy,
+			password: "Harbor12345",
+			version:  "2.4.1",
+			status:   goharborv1.HarborServerConfigurationStatusFail,
+			reason:   "Unhealthy",
+			conditions: map[string]metav1.ConditionStatus{
+				goharborv1.HarborServerConfigurationConditionHealthy: metav1.ConditionFalse,
+				"CoreHealthy":       metav1.ConditionTrue,
+				"JobserviceHealthy": metav1.ConditionFalse,
+				goharborv1.HarborServerConfigurationConditionCredentialValid: metav1.ConditionTrue,
+				goharborv1.HarborServerConfigurationConditionVersionMatched:  metav1.ConditionTrue,
+			},
+			credential: 1,
+			matched:    1,
+		},
+		"credential rejected": {
+			health:   healthy,
+			password: "wrong",
+			version:  "2.4.1",
+			status:   goharborv1.HarborServerConfigurationStatusFail,
+			reason:   "InvalidCredential",
+			conditions: map[string]metav1.ConditionStatus{
+				goharborv1.HarborServerConfigurationConditionHealthy:         metav1.ConditionTrue,
+				goharborv1.HarborServerConfigurationConditionCredentialValid: metav1.ConditionFalse,
+				goharborv1.HarborServerConfigurationConditionVersionMatched:  metav1.ConditionUnknown,
+			},
+			jobservice:  1,
+			serverValue: 1,
+		},
+		"version mismatch": {
+			health:   healthy,
+			password: "Harbor12345",
+			version:  "2.5.0",
+			status:   goharborv1.HarborServerConfigurationStatusReady,
+			conditions: map[string]metav1.ConditionStatus{
+				goharborv1.HarborServerConfigurationConditionHealthy:        metav1.ConditionTrue,
+				goharborv1.HarborServerConfigurationConditionVersionMatched: metav1.ConditionFalse,
+			},
+			jobservice:  1,
+			credential:  1,
+			serverValue: 1,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			server := newHarborAPI(t, tc.health)
+
+			harbor, err := v2.NewWithServer(model.NewHarborServer(server.URL, "admin", tc.password, false))
+			require.NoError(t, err)
+
+			name := strings.ReplaceAll(name, " ", "-")
+			spec := &goharborv1.HarborServerConfigurationSpec{Version: tc.version}
+			status := &goharborv1.HarborServerConfigurationStatus{
+				Conditions: []metav1.Condition{{
+					Type:   "RegistryHealthy",
+					Status: metav1.ConditionTrue,
+					Reason: "Healthy",
+				}},
+			}
+
+			harborserverconfiguration.Probe(logr.Discard(), harbor, "harbor", name, spec, status)
+
+			require.Equal(t, tc.status, status.Status)
+			require.Equal(t, tc.reason, status.Reason)
+
+			for conditionType, conditionStatus := range tc.conditions {
+				condition := meta.FindStatusCondition(status.Conditions, conditionType)
+				require.NotNil(t, condition, conditionType)
+				require.Equal(t, conditionStatus, condition.Status, conditionType)
+			}
+
+			require.Nil(t, meta.FindStatusCondition(status.Conditions, "RegistryHealthy"), "the components not reported anymore are removed")
+
+			labels := prometheus.Labels{"namespace": "harbor", "harbor_server_configuration": name}
+			require.Equal(t, tc.serverValue, testutil.ToFloat64(harborserverconfiguration.ServerHealthy.With(labels)))
+			require.Equal(t, tc.credential, testutil.ToFloat64(harborserverconfiguration.CredentialValid.With(labels)))
+			require.Equal(t, tc.matched, testutil.ToFloat64(harborserverconfiguration.VersionMatched.With(labels)))
+
+			labels["component"] = "jobservice"
+			require.Equal(t, tc.jobservice, testutil.ToFloat64(harborserverconfiguration.ComponentHealthy.With(labels)))
+		})
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	server := newHarborAPI(t, nil)
+	server.Close()
+
+	harbor, err := v2.NewWithServer(model.NewHarborServer(server.URL, "admin", "Harbor12345", false))
+	require.NoError(t, err)
+
+	status := &goharborv1.HarborServerConfigurationStatus{
+		Version: testVersion,
+		Conditions: []metav1.Condition{{
+			Type:   "CoreHealthy",
+			Status: metav1.ConditionTrue,
+			Reason: "Healthy",
+		}},
+	}
+
+	harborserverconfiguration.Probe(logr.Discard(), harbor, "harbor", "unreachable", &goharborv1.HarborServerConfigurationSpec{Version: "2.4.1"}, status)
+
+	require.Equal(t, goharborv1.HarborServerConfigurationStatusFail, status.Status)
+	require.Equal(t, "Unreachable", status.Reason)
+
+	condition := meta.FindStatusCondition(status.Conditions, "CoreHealthy")
+	require.Equal(t, metav1.ConditionUnknown, condition.Status, "the health of the components is not known")
+
+	condition = meta.FindStatusCondition(status.Conditions, goharborv1.HarborServerConfigurationConditionCredentialValid)
+	require.Equal(t, metav1.ConditionUnknown, condition.Status)
+	require.Equal(t, "CheckFailed", condition.Reason)
+
+	require.True(t, meta.IsStatusConditionTrue(status.Conditions, goharborv1.HarborServerConfigurationConditionVersionMatched), "the last observed version is kept")
+}
+
+func TestProbeFailed(t *testing.T) {
+	status := &goharborv1.HarborServerConfigurationStatus{
+		Conditions: []metav1.Condition{{
+			Type:   "CoreHealthy",
+			Status: metav1.ConditionTrue,
+			Reason: "Healthy",
+		}},
+	}
+
+	harborserverconfiguration.ProbeFailed("harbor", "failed", status, metav1.ConditionFalse, "InvalidAccessSecret", errors.New("access secret not found"))
+
+	require.Equal(t, goharborv1.HarborServerConfigurationStatusFail, status.Status)
+	require.Equal(t, "InvalidAccessSecret", status.Reason)
+	require.Equal(t, "access secret not found", status.Message)
+
+	for conditionType, conditionStatus := range map[string]metav1.ConditionStatus{
+		goharborv1.HarborServerConfigurationConditionHealthy: metav1.ConditionUnknown,
+		"CoreHealthy": metav1.ConditionUnknown,
+		goharborv1.HarborServerConfigurationConditionCredentialValid: metav1.ConditionFalse,
+	} {
+		condition := meta.FindStatusCondition(status.Conditions, conditionType)
+		require.NotNil(t, condition, conditionType)
+		require.Equal(t, conditionStatus, condition.Status, conditionType)
+	}
+
+	labels := prometheus.Labels{"namespace": "harbor", "harbor_server_configuration": "failed"}
+	require.Equal(t, float64(0), testutil.ToFloat64(harborserverconfiguration.ServerHealthy.With(labels)))
+	require.Equal(t, float64(0), testutil.ToFloat64(harborserverconfiguration.CredentialValid.With(labels)))
+}
+
+// newHarborAPI returns a Harbor API reporting the health status, accepting the admin:Harbor12345 credential.
+func newHarborAPI(t *testing.T, health *models.OverallHealthStatus) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		authenticated := username == "admin" && password == "Harbor12345"
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v2.0/health":
+			require.NoError(t, json.NewEncoder(w).Encode(health))
+		case "/v2/":
+			if !authenticated {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{}))
+		case "/api/v2.0/systeminfo":
+			info := map[string]interface{}{}
+			if authenticated {
+				info["harbor_version"] = testVersion
+			}
+
+			require.NoError(t, json.NewEncoder(w).Encode(info))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
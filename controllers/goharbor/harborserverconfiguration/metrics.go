@@ -0,0 +1,70 @@
+package harborserverconfiguration
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	labelNamespace                 = "namespace"
+	labelHarborServerConfiguration = "harbor_server_configuration"
+	labelComponent                 = "component"
+	labelVersion                   = "version"
+)
+
+var (
+	serverLabels = []string{labelNamespace, labelHarborServerConfiguration}
+
+	serverHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harbor_operator_harbor_server_healthy",
+		Help: "Whether the Harbor server is healthy (1) or not (0).",
+	}, serverLabels)
+
+	componentHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harbor_operator_harbor_server_component_healthy",
+		Help: "Whether the component of the Harbor server is healthy (1) or not (0).",
+	}, append(serverLabels, labelComponent))
+
+	credentialValid = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harbor_operator_harbor_server_credential_valid",
+		Help: "Whether the Harbor server accepts the access credential (1) or not (0).",
+	}, serverLabels)
+
+	versionMatched = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harbor_operator_harbor_server_version_matched",
+		Help: "Whether the observed version of the Harbor server matches the configured version (1) or not (0).",
+	}, serverLabels)
+
+	serverInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harbor_operator_harbor_server_info",
+		Help: "The version of the Harbor server observed by the last probe.",
+	}, append(serverLabels, labelVersion))
+)
+
+func init() { //nolint:gochecknoinits
+	metrics.Registry.MustRegister(serverHealthy, componentHealthy, credentialValid, versionMatched, serverInfo)
+}
+
+func serverMetricLabels(namespace, name string) prometheus.Labels {
+	return prometheus.Labels{
+		labelNamespace:                 namespace,
+		labelHarborServerConfiguration: name,
+	}
+}
+
+// deleteMetrics removes the metrics of the Harbor server configuration.
+func deleteMetrics(namespace, name string) {
+	labels := serverMetricLabels(namespace, name)
+
+	for _, vec := range []*prometheus.GaugeVec{serverHealthy, componentHealthy, credentialValid, versionMatched, serverInfo} {
+		vec.DeletePartialMatch(labels)
+	}
+}
+
+func boolToGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,101 @@
+package harborserverconfiguration
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/resources"
+	"github.com/ovh/configstore"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// NewNamespaced NamespacedHarborServerConfiguration reconciler.
+func NewNamespaced(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &NamespacedReconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.NamespacedHarborServerConfiguration, nil, configStore)
+
+	return r, nil
+}
+
+// NamespacedReconciler reconciles a NamespacedHarborServerConfiguration object.
+type NamespacedReconciler struct {
+	*commonCtrl.Controller
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations/status,verbs=get;update;patch
+
+// Reconcile the NamespacedHarborServerConfiguration.
+func (r *NamespacedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	log := r.Log.WithValues("namespacedharborserverconfiguration", req.NamespacedName)
+	log.Info("Starting NamespacedHarborServerConfiguration Reconciler")
+
+	nhsc := &goharborv1.NamespacedHarborServerConfiguration{}
+	if err := r.Client.Get(ctx, req.NamespacedName, nhsc); err != nil {
+		if apierr.IsNotFound(err) {
+			// It could have been deleted after reconcile request coming in.
+			log.Info("Namespaced harbor server configuration does not exist")
+			deleteMetrics(req.Namespace, req.Name)
+
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get NamespacedHarborServerConfiguration error: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			nhsc.Status.Status = goharborv1.HarborServerConfigurationStatusFail
+			nhsc.Status.Message = err.Error()
+		}
+
+		log.Info("Reconcile end", "result", res, "error", err, "updateStatusError", r.Client.Status().Update(ctx, nhsc))
+	}()
+
+	if !nhsc.ObjectMeta.DeletionTimestamp.IsZero() {
+		log.Info("Namespaced harbor server configuration is being deleted")
+		deleteMetrics(nhsc.Namespace, nhsc.Name)
+
+		return ctrl.Result{}, nil
+	}
+
+	// The access secret is always read in the namespace of the configuration
+	if err := probeServer(ctx, log, r.Client, nhsc.ToHarborServerConfiguration(), nhsc.Namespace, nhsc.Name, &nhsc.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Finished NamespacedHarborServerConfiguration Reconciler")
+	// The health should be rechecked after the probe interval
+	return ctrl.Result{
+		RequeueAfter: probeInterval(&nhsc.Spec),
+	}, nil
+}
+
+// SetupWithManager for NamespacedHarborServerConfiguration reconcile controller.
+func (r *NamespacedReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// The status updates of the probes do not trigger new probes
+		For(&goharborv1.NamespacedHarborServerConfiguration{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func (r *NamespacedReconciler) NewEmpty(_ context.Context) resources.Resource {
+	return &goharborv1.NamespacedHarborServerConfiguration{}
+}
+
+func (r *NamespacedReconciler) AddResources(ctx context.Context, resource resources.Resource) error {
+	return nil
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 )
@@ -32,6 +34,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=goharbor.io,resources=jobservices/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets;services,verbs=get;list;watch;create;update;patch;delete
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
@@ -63,20 +67,32 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return errors.Wrap(err, "cannot get concurrent reconcile")
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	autoscalingSupported, err := r.IsHorizontalPodAutoscalerSupported()
+	if err != nil {
+		return errors.Wrap(err, "cannot check horizontal pod autoscaler support")
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		WithEventFilter(&class.Filter{
 			ClassName: className,
 		}).
 		For(r.NewEmpty(ctx)).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.Service{}).
 		Owns(&netv1.NetworkPolicy{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: concurrentReconcile,
-		}).
-		Complete(r)
+		})
+
+	// The HorizontalPodAutoscalers are only watched when the autoscaling/v2 API is served, since Kubernetes 1.23.
+	if autoscalingSupported {
+		builder = builder.Owns(&autoscalingv2.HorizontalPodAutoscaler{})
+	}
+
+	return builder.Complete(r)
 }
 
 func (r *Reconciler) Template(ctx context.Context) (*template.ConfigTemplate, error) {
@@ -0,0 +1,3 @@
+package namespace
+
+var FindDefaultHarborCfg = (*Reconciler).findDefaultHarborCfg
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	gostrings "strings"
 
 	"github.com/go-logr/logr"
 	v2models "github.com/goharbor/go-client/pkg/sdk/v2.0/models"
@@ -17,6 +18,7 @@ import (
 	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -24,12 +26,16 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
-	defaultSaName = "default"
-	baseInt10     = 10
-	baseBitSize   = 64
+	defaultSaName      = "default"
+	allServiceAccounts = "*"
+	baseInt10          = 10
+	baseBitSize        = 64
 )
 
 // New Namespace reconciler.
@@ -50,7 +56,8 @@ type Reconciler struct {
 
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=namespaces/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=goharbor.io,resources=pullsecretbindings,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=pullsecretbindings,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborserverconfigurations;namespacedharborserverconfigurations,verbs=get;list;watch
 
 // Reconcile the Namespace.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) { //nolint:funlen
@@ -99,36 +106,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// Pull secret issuer is set and then check if the required default binding exists
-	// Confirm the service account name
-	// Use default SA if not set inside annotation
-	saName := defaultSaName
-	if setSa, ok := ns.Annotations[consts.AnnotationAccount]; ok {
-		saName = setSa
-	}
-	// Check if custom service account exist
-	sa := &corev1.ServiceAccount{}
-	saNamespacedName := types.NamespacedName{
-		Namespace: ns.Name,
-		Name:      saName,
-	}
-
-	if err := r.Client.Get(ctx, saNamespacedName, sa); err != nil {
-		if apierr.IsNotFound(err) {
-			return ctrl.Result{}, fmt.Errorf("service account %s not found in namespace %s: %w", saName, ns.Name, err)
-		}
-
-		return ctrl.Result{}, fmt.Errorf("get service account %s in namespace %s error: %w", saName, ns.Name, err)
+	// Confirm the service accounts binding the pull secret
+	targets, err := r.bindingTargets(ctx, ns)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// Find PSB
-	for _, bd := range bindings.Items {
-		if bd.Spec.HarborServerConfig == harborCfg.Name && bd.Spec.ServiceAccount == saName {
+	for i, bd := range bindings.Items {
+		if bd.Spec.HarborServerConfig == harborCfg.Name {
 			// Found it and reconcile is done
 			// TODO: the PSB might be useless if the credentials or projects are changed
 			// Need to check if the PSB is still valid.
 			log.Info("psb exist for this namespace")
 
-			return ctrl.Result{}, nil
+			if sameTargets(&bd.Spec, targets) {
+				return ctrl.Result{}, nil
+			}
+
+			log.Info("update service accounts of psb", "name", bd.Name)
+
+			setTargets(&bindings.Items[i].Spec, targets)
+
+			return ctrl.Result{}, r.Client.Update(ctx, &bindings.Items[i], &client.UpdateOptions{})
 		}
 	}
 
@@ -153,7 +153,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// PSB doesn't exist, create one
 	log.Info("creating pull secret binding")
 
-	psb, err := r.createPullSecretBinding(ctx, ns, harborCfg.Name, saName, robotID, projID)
+	psb, err := r.createPullSecretBinding(ctx, ns, harborCfg.Name, targets, robotID, projID)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -180,20 +180,102 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Namespace{}).
+		Watches(&source.Kind{Type: &goharborv1.NamespacedHarborServerConfiguration{}}, handler.EnqueueRequestsFromMapFunc(r.namespaceOf)).
 		Complete(r)
 }
 
-func (r *Reconciler) getNewBindingCR(ns string, harborCfg string, sa string) *goharborv1.PullSecretBinding {
-	return &goharborv1.PullSecretBinding{
+// namespaceOf enqueues the namespace of the namespaced hsc, it may become the default hsc of the namespace.
+func (r *Reconciler) namespaceOf(obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: obj.GetNamespace()},
+	}}
+}
+
+func (r *Reconciler) getNewBindingCR(ns string, harborCfg string, targets *goharborv1.PullSecretBindingSpec) *goharborv1.PullSecretBinding {
+	bd := &goharborv1.PullSecretBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      strings.RandomName("Binding"),
 			Namespace: ns,
 		},
 		Spec: goharborv1.PullSecretBindingSpec{
 			HarborServerConfig: harborCfg,
-			ServiceAccount:     sa,
 		},
 	}
+
+	setTargets(&bd.Spec, targets)
+
+	return bd
+}
+
+// bindingTargets returns the service accounts binding the pull secret, set in the annotations of the namespace.
+// The service account annotation is a comma separated list of names, or * for all the service accounts of the namespace.
+// Use default SA if none is set inside annotations.
+func (r *Reconciler) bindingTargets(ctx context.Context, ns *corev1.Namespace) (*goharborv1.PullSecretBindingSpec, error) {
+	targets := &goharborv1.PullSecretBindingSpec{}
+
+	if value, ok := ns.Annotations[consts.AnnotationAccountSelector]; ok {
+		selector, err := metav1.ParseToLabelSelector(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service account selector %q in namespace %s: %w", value, ns.Name, err)
+		}
+
+		targets.ServiceAccountSelector = selector
+	}
+
+	names := []string{}
+
+	if value, ok := ns.Annotations[consts.AnnotationAccount]; ok {
+		for _, name := range gostrings.Split(value, ",") {
+			if name = gostrings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	} else if targets.ServiceAccountSelector == nil {
+		names = append(names, defaultSaName)
+	}
+
+	for _, name := range names {
+		if name == allServiceAccounts {
+			targets.ServiceAccountSelector = &metav1.LabelSelector{}
+
+			continue
+		}
+
+		// Check if custom service account exist
+		sa := &corev1.ServiceAccount{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: ns.Name, Name: name}, sa); err != nil {
+			if apierr.IsNotFound(err) {
+				return nil, fmt.Errorf("service account %s not found in namespace %s: %w", name, ns.Name, err)
+			}
+
+			return nil, fmt.Errorf("get service account %s in namespace %s error: %w", name, ns.Name, err)
+		}
+
+		targets.ServiceAccounts = append(targets.ServiceAccounts, name)
+	}
+
+	return targets, nil
+}
+
+// setTargets sets the service accounts of the binding, a single service account is kept in the serviceAccount field.
+func setTargets(spec, targets *goharborv1.PullSecretBindingSpec) {
+	spec.ServiceAccount = ""
+	spec.ServiceAccounts = targets.ServiceAccounts
+	spec.ServiceAccountSelector = targets.ServiceAccountSelector
+
+	if len(targets.ServiceAccounts) == 1 {
+		spec.ServiceAccount = targets.ServiceAccounts[0]
+		spec.ServiceAccounts = nil
+	}
+}
+
+func sameTargets(spec, targets *goharborv1.PullSecretBindingSpec) bool {
+	expected := &goharborv1.PullSecretBindingSpec{}
+	setTargets(expected, targets)
+
+	return spec.ServiceAccount == expected.ServiceAccount &&
+		equality.Semantic.DeepEqual(spec.ServiceAccounts, expected.ServiceAccounts) &&
+		equality.Semantic.DeepEqual(spec.ServiceAccountSelector, expected.ServiceAccountSelector)
 }
 
 func (r *Reconciler) validateProject(projectName string) (string, error) {
@@ -248,46 +330,34 @@ func (r *Reconciler) createProjectAndRobot(proj string) (string, string, error)
 }
 
 func (r *Reconciler) findDefaultHarborCfg(ctx context.Context, log logr.Logger, ns *corev1.Namespace) (*goharborv1.HarborServerConfiguration, error) {
-	// check annotation first
+	// check annotation first, the namespaced hsc of the namespace takes precedence over the cluster hsc
 	harborCfg, yes := ns.Annotations[consts.AnnotationHarborServer]
 	if yes && harborCfg != "" {
-		hsc := &goharborv1.HarborServerConfiguration{}
-
-		err := r.Client.Get(ctx, types.NamespacedName{Name: harborCfg}, hsc)
+		hsc, err := harborClient.GetHarborServerConfiguration(ctx, r.Client, ns.Name, harborCfg)
 		if err != nil {
-			if apierr.IsNotFound(err) {
-				log.Info("hsc specified in annotation doesn't exist")
-
-				return nil, nil
-			}
-
 			return nil, fmt.Errorf("error when finding hsc specified in annotation: %w", err)
 		}
 
+		if hsc == nil {
+			log.Info("hsc specified in annotation doesn't exist")
+		}
+
 		return hsc, nil
 	}
 
 	log.Info("no default hsc found in annotation for namespace " + ns.Name)
 
-	// then find global default hsc
-	hscs := &goharborv1.HarborServerConfigurationList{}
-
-	err := r.Client.List(ctx, hscs)
+	// then find the default namespaced hsc of the namespace and the global default hsc
+	hsc, err := harborClient.GetDefaultHarborServerConfiguration(ctx, r.Client, ns.Name)
 	if err != nil {
-		return nil, fmt.Errorf("error listing harborCfg: %w", err)
+		return nil, fmt.Errorf("error finding default harborCfg: %w", err)
 	}
 
-	if len(hscs.Items) > 0 {
-		for _, hsc := range hscs.Items {
-			if hsc.Spec.Default {
-				log.Info("found global default hsc: " + hsc.Name)
-
-				return &hsc, nil
-			}
-		}
+	if hsc != nil {
+		log.Info("found default hsc: " + hsc.Name)
 	}
 
-	return nil, nil
+	return hsc, nil
 }
 
 func (r *Reconciler) removeStalePSB(ctx context.Context, log logr.Logger, bindings *goharborv1.PullSecretBindingList) error {
@@ -306,8 +376,8 @@ func (r *Reconciler) removeStalePSB(ctx context.Context, log logr.Logger, bindin
 	return nil
 }
 
-func (r *Reconciler) createPullSecretBinding(ctx context.Context, ns *corev1.Namespace, harborCfg, saName, robotID, projID string) (*goharborv1.PullSecretBinding, error) {
-	defaultBinding := r.getNewBindingCR(ns.Name, harborCfg, saName)
+func (r *Reconciler) createPullSecretBinding(ctx context.Context, ns *corev1.Namespace, harborCfg string, targets *goharborv1.PullSecretBindingSpec, robotID, projID string) (*goharborv1.PullSecretBinding, error) {
+	defaultBinding := r.getNewBindingCR(ns.Name, harborCfg, targets)
 	if err := controllerutil.SetControllerReference(ns, defaultBinding, r.Scheme); err != nil {
 		return nil, fmt.Errorf("set ctrl reference error: %w", err)
 	}
@@ -0,0 +1,371 @@
+package namespace_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/namespace"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/consts"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace  = "tenant"
+	clusterURL     = "https://cluster.example.com"
+	namespacedHSC  = "tenant-harbor"
+	clusterHSC     = "cluster-harbor"
+	credentialsRef = "harbor-credentials"
+)
+
+func TestFindDefaultHarborCfg(t *testing.T) {
+	for name, tc := range map[string]struct {
+		annotation string
+		objects    []client.Object
+		expected   string
+		url        string
+	}{
+		"namespaced default first": {
+			objects:  []client.Object{newNamespacedHSC(namespacedHSC, "https://tenant.example.com", true), newClusterHSC(clusterHSC, true)},
+			expected: namespacedHSC,
+			url:      "https://tenant.example.com",
+		},
+		"cluster default then": {
+			objects:  []client.Object{newNamespacedHSC(namespacedHSC, "https://tenant.example.com", false), newClusterHSC(clusterHSC, true)},
+			expected: clusterHSC,
+			url:      clusterURL,
+		},
+		"no default": {
+			objects: []client.Object{newNamespacedHSC(namespacedHSC, "https://tenant.example.com", false), newClusterHSC(clusterHSC, false)},
+		},
+		"annotation over the defaults": {
+			annotation: clusterHSC,
+			objects:    []client.Object{newNamespacedHSC(namespacedHSC, "https://tenant.example.com", true), newClusterHSC(clusterHSC, false)},
+			expected:   clusterHSC,
+			url:        clusterURL,
+		},
+		"annotation resolved in the namespace first": {
+			annotation: clusterHSC,
+			objects:    []client.Object{newNamespacedHSC(clusterHSC, "https://tenant.example.com", false), newClusterHSC(clusterHSC, true)},
+			expected:   clusterHSC,
+			url:        "https://tenant.example.com",
+		},
+		"annotation not found": {
+			annotation: "missing",
+			objects:    []client.Object{newNamespacedHSC(namespacedHSC, "https://tenant.example.com", true), newClusterHSC(clusterHSC, true)},
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ns := newNamespace(nil)
+			if tc.annotation != "" {
+				ns.Annotations[consts.AnnotationHarborServer] = tc.annotation
+			}
+
+			r := newReconciler(t, tc.objects...)
+
+			hsc, err := namespace.FindDefaultHarborCfg(r, context.TODO(), logr.Discard(), ns)
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				require.Nil(t, hsc)
+
+				return
+			}
+
+			require.NotNil(t, hsc)
+			require.Equal(t, tc.expected, hsc.Name)
+			require.Equal(t, tc.url, hsc.Spec.ServerURL)
+		})
+	}
+}
+
+func TestReconcileCreatesBinding(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+
+	for name, tc := range map[string]struct {
+		annotations map[string]string
+		expected    goharborv1.PullSecretBindingSpec
+		fails       bool
+	}{
+		"default service account": {
+			expected: goharborv1.PullSecretBindingSpec{ServiceAccount: "default"},
+		},
+		"single service account": {
+			annotations: map[string]string{consts.AnnotationAccount: "builder"},
+			expected:    goharborv1.PullSecretBindingSpec{ServiceAccount: "builder"},
+		},
+		"multiple service accounts": {
+			annotations: map[string]string{consts.AnnotationAccount: "builder, deployer,"},
+			expected:    goharborv1.PullSecretBindingSpec{ServiceAccounts: []string{"builder", "deployer"}},
+		},
+		"all service accounts": {
+			annotations: map[string]string{consts.AnnotationAccount: "*"},
+			expected:    goharborv1.PullSecretBindingSpec{ServiceAccountSelector: &metav1.LabelSelector{}},
+		},
+		"service account selector": {
+			annotations: map[string]string{consts.AnnotationAccountSelector: "team=payments"},
+			expected:    goharborv1.PullSecretBindingSpec{ServiceAccountSelector: selector},
+		},
+		"service accounts and selector": {
+			annotations: map[string]string{
+				consts.AnnotationAccount:         "builder,deployer",
+				consts.AnnotationAccountSelector: "team=payments",
+			},
+			expected: goharborv1.PullSecretBindingSpec{ServiceAccounts: []string{"builder", "deployer"}, ServiceAccountSelector: selector},
+		},
+		"missing service account": {
+			annotations: map[string]string{consts.AnnotationAccount: "builder,missing"},
+			fails:       true,
+		},
+		"invalid selector": {
+			annotations: map[string]string{consts.AnnotationAccountSelector: "team in payments"},
+			fails:       true,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+
+			serverURL := newHarbor(t)
+
+			annotations := map[string]string{
+				consts.AnnotationProject: "library",
+				consts.AnnotationRobot:   "8",
+			}
+			for k, v := range tc.annotations {
+				annotations[k] = v
+			}
+
+			// The cluster default is not reachable, the namespaced default takes precedence
+			r := newReconciler(t,
+				newNamespace(annotations),
+				newNamespacedHSC(namespacedHSC, serverURL, true),
+				newClusterHSC(clusterHSC, true),
+				newCredentials(),
+				newServiceAccount("default"),
+				newServiceAccount("builder"),
+				newServiceAccount("deployer"),
+			)
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+			if tc.fails {
+				require.Error(t, err)
+				require.Empty(t, listBindings(ctx, t, r))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			bindings := listBindings(ctx, t, r)
+			require.Len(t, bindings, 1)
+
+			expected := tc.expected
+			expected.HarborServerConfig = namespacedHSC
+			expected.ProjectID = "30"
+			expected.RobotID = "8"
+
+			require.Equal(t, expected, bindings[0].Spec)
+			require.True(t, metav1.IsControlledBy(&bindings[0], newNamespace(nil)), "the binding is owned by the namespace")
+		})
+	}
+}
+
+func TestReconcileUpdatesServiceAccounts(t *testing.T) {
+	ctx := context.TODO()
+
+	registries := []goharborv1.PullSecretBindingRegistry{{
+		HarborServerConfig: clusterHSC,
+		ProjectID:          "12",
+		RobotID:            "4",
+	}}
+
+	binding := &goharborv1.PullSecretBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      "binding",
+		},
+		Spec: goharborv1.PullSecretBindingSpec{
+			HarborServerConfig: namespacedHSC,
+			ProjectID:          "30",
+			RobotID:            "8",
+			Registries:         registries,
+			ServiceAccount:     "default",
+		},
+	}
+
+	// No Harbor call is expected while the binding of the namespace exists
+	r := newReconciler(t,
+		newNamespace(map[string]string{
+			consts.AnnotationProject: "library",
+			consts.AnnotationRobot:   "8",
+			consts.AnnotationAccount: "builder,deployer",
+		}),
+		newNamespacedHSC(namespacedHSC, "https://tenant.example.com", true),
+		newServiceAccount("builder"),
+		newServiceAccount("deployer"),
+		binding,
+	)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+	require.NoError(t, err)
+
+	bindings := listBindings(ctx, t, r)
+	require.Len(t, bindings, 1)
+	require.Equal(t, goharborv1.PullSecretBindingSpec{
+		HarborServerConfig: namespacedHSC,
+		ProjectID:          "30",
+		RobotID:            "8",
+		Registries:         registries,
+		ServiceAccounts:    []string{"builder", "deployer"},
+	}, bindings[0].Spec, "the projects of the binding are kept")
+
+	resourceVersion := bindings[0].ResourceVersion
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+	require.NoError(t, err)
+
+	bindings = listBindings(ctx, t, r)
+	require.Len(t, bindings, 1)
+	require.Equal(t, resourceVersion, bindings[0].ResourceVersion, "the binding is up to date")
+}
+
+func TestReconcileRemovesStaleBindings(t *testing.T) {
+	ctx := context.TODO()
+
+	r := newReconciler(t,
+		newNamespace(map[string]string{consts.AnnotationProject: "library"}),
+		newNamespacedHSC(namespacedHSC, "https://tenant.example.com", false),
+		&goharborv1.PullSecretBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "binding"},
+			Spec:       goharborv1.PullSecretBindingSpec{HarborServerConfig: namespacedHSC},
+		},
+	)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: testNamespace}})
+	require.NoError(t, err)
+	require.Empty(t, listBindings(ctx, t, r))
+}
+
+func newReconciler(t *testing.T, objects ...client.Object) *namespace.Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	return &namespace.Reconciler{
+		Controller: &commonCtrl.Controller{Log: logr.Discard()},
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:     scheme,
+	}
+}
+
+// newHarbor returns the URL of a Harbor API serving the project library and its robot account.
+func newHarbor(t *testing.T) string {
+	payloads := map[string]interface{}{
+		"GET /api/v2.0/projects":             []*models.Project{{ProjectID: 30, Name: "library"}},
+		"GET /api/v2.0/projects/30/robots/8": &models.Robot{ID: 8, Name: "robot$library+ci"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := payloads[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(payload))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func listBindings(ctx context.Context, t *testing.T, r *namespace.Reconciler) []goharborv1.PullSecretBinding {
+	bindings := &goharborv1.PullSecretBindingList{}
+	require.NoError(t, r.Client.List(ctx, bindings, client.InNamespace(testNamespace)))
+
+	return bindings.Items
+}
+
+func newNamespace(annotations map[string]string) *corev1.Namespace {
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testNamespace,
+			UID:         "namespace-uid",
+			Annotations: annotations,
+		},
+	}
+}
+
+func newNamespacedHSC(name, url string, isDefault bool) *goharborv1.NamespacedHarborServerConfiguration {
+	return &goharborv1.NamespacedHarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL:        url,
+			Default:          isDefault,
+			AccessCredential: &goharborv1.AccessCredential{AccessSecretRef: credentialsRef},
+		},
+	}
+}
+
+func newClusterHSC(name string, isDefault bool) *goharborv1.HarborServerConfiguration {
+	return &goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL:        clusterURL,
+			Default:          isDefault,
+			AccessCredential: &goharborv1.AccessCredential{Namespace: "harbor", AccessSecretRef: credentialsRef},
+		},
+	}
+}
+
+func newCredentials() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      credentialsRef,
+		},
+		Data: map[string][]byte{
+			"accessKey":    []byte("admin"),
+			"accessSecret": []byte("Harbor12345"),
+		},
+	}
+}
+
+func newServiceAccount(name string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+	}
+}
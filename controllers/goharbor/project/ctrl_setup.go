@@ -43,6 +43,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=goharbor.io,resources=harborprojects/finalizers,verbs=update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
@@ -0,0 +1,14 @@
+package project
+
+var (
+	ReconcileImmutableTagRules   = (*Reconciler).reconcileImmutableTagRules
+	ReconcileRetention           = (*Reconciler).reconcileRetention
+	CreateDesiredImmutableRules  = createDesiredImmutableRules
+	CreateDesiredRetention       = createDesiredRetention
+	GenerateRulesHash            = generateRulesHash
+	ReconcileProxyCacheRegistry  = (*Reconciler).reconcileProxyCacheRegistry
+	ReconcileProxyCacheBandwidth = (*Reconciler).reconcileProxyCacheBandwidth
+	CheckProxyCacheProject       = (*Reconciler).checkProxyCacheProject
+	ReconcileWebhooks            = (*Reconciler).reconcileWebhooks
+	AreWebhooksEqual             = areWebhooksEqual
+)
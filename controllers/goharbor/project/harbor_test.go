@@ -0,0 +1,90 @@
+package project_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// harborAPI records the requests sent to a fake Harbor API.
+type harborAPI struct {
+	// requests are the methods and paths of the requests, in order.
+	requests []string
+	// bodies are the bodies of the last requests by method and path.
+	bodies map[string][]byte
+}
+
+// body decodes the body of the last request with the given method and path.
+func (api *harborAPI) body(t *testing.T, request string, v interface{}) {
+	body, ok := api.bodies[request]
+	require.True(t, ok, "no %s request", request)
+	require.NoError(t, json.Unmarshal(body, v))
+}
+
+// newReconciler returns a reconciler of the given objects connected to a Harbor API serving the given responses by method and path,
+// other requests are not found. A response is either a http.HandlerFunc, a payload encoded in JSON,
+// or nil for an empty response, created for POST requests.
+func newReconciler(t *testing.T, responses map[string]interface{}, objects ...client.Object) (*project.Reconciler, *harborAPI) {
+	api := &harborAPI{
+		bodies: map[string][]byte{},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.Path
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		api.requests = append(api.requests, request)
+		api.bodies[request] = body
+
+		response, ok := responses[request]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusNotFound)
+		case response == nil:
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+			}
+		default:
+			if handler, ok := response.(http.HandlerFunc); ok {
+				handler(w, r)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(response))
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	harbor, err := v2.NewWithServer(model.NewHarborServer(server.URL, "admin", "Harbor12345", false))
+	require.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	return &project.Reconciler{
+		Controller: &commonCtrl.Controller{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		},
+		Scheme: scheme,
+		Harbor: harbor,
+	}, api
+}
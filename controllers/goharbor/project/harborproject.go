@@ -10,7 +10,6 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -57,7 +56,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 	}()
 
 	// set harbor client
-	err = r.setHarborClient(ctx, hp.Spec.HarborServerConfig)
+	err = r.setHarborClient(ctx, hp.GetNamespace(), hp.Spec.HarborServerConfig)
 	if err != nil {
 		err = errors.Wrapf(err, "error get harbor client")
 		hp.Status.Reason = "HarborClientError"
@@ -69,6 +68,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		// The object is being deleted
 		if controllerutil.ContainsFinalizer(hp, finalizerID) {
 			// our finalizer is present, so lets handle any external dependency
+			if err := r.deleteWebhooks(hp); err != nil {
+				hp.Status.Reason = "DeleteProjectWebhooksError"
+
+				return ctrl.Result{}, err
+			}
+
 			if err := r.Harbor.DeleteProject(hp.Spec.ProjectName); err != nil {
 				hp.Status.Reason = "DeleteProjectError"
 				// if fail to delete the external dependency here, return with error
@@ -76,6 +81,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 				return ctrl.Result{}, err
 			}
 
+			// the inline upstream registry of a proxy cache can only be deleted with its project
+			if hp.Spec.ProxyCache != nil && hp.Spec.ProxyCache.Registry != nil && hp.Status.ProxyCacheRegistryID > 0 {
+				if err := r.Harbor.DeleteRegistry(hp.Status.ProxyCacheRegistryID); err != nil {
+					hp.Status.Reason = "DeleteProxyCacheRegistryError"
+
+					return ctrl.Result{}, err
+				}
+			}
+
 			// remove our finalizer from the list and update it.
 			controllerutil.RemoveFinalizer(hp, finalizerID)
 
@@ -95,6 +109,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		}
 	}
 
+	// resolve the upstream registry of proxy cache projects, required to create them
+	if err = r.reconcileProxyCacheRegistry(ctx, hp, log); err != nil {
+		err = errors.Wrapf(err, "error applying harbor project proxy cache registry")
+		hp.Status.Reason = "ProxyCacheRegistryError"
+
+		return ctrl.Result{}, err
+	}
+
 	projectExists, err := r.Harbor.ProjectExists(hp.Spec.ProjectName)
 	if err != nil {
 		err = errors.Wrapf(err, "error finding existing harbor project")
@@ -104,6 +126,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 	}
 
 	if projectExists {
+		// the upstream registry of proxy cache projects is only set at creation
+		if err = r.checkProxyCacheProject(hp); err != nil {
+			err = errors.Wrapf(err, "error checking harbor project proxy cache registry")
+			hp.Status.Reason = "ProxyCacheRegistryMismatch"
+
+			return ctrl.Result{}, err
+		}
+
 		// update project
 		if err = r.Harbor.UpdateProject(hp.Spec.ProjectName, hp); err != nil {
 			err = errors.Wrapf(err, "error update harbor project")
@@ -123,6 +153,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		hp.Status.ProjectID = id
 	}
 
+	// reconcile proxy cache bandwidth limit
+	if err = r.reconcileProxyCacheBandwidth(hp); err != nil {
+		err = errors.Wrapf(err, "error updating harbor project proxy cache bandwidth")
+		hp.Status.Reason = "UpdateProjectProxyCacheError"
+
+		return ctrl.Result{}, err
+	}
+
 	// reconcile project quota
 	if err = r.reconcileQuota(hp, log); err != nil {
 		err = errors.Wrapf(err, "error updating harbor project quota")
@@ -139,14 +177,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		return ctrl.Result{}, err
 	}
 
+	// reconcile project webhook policies
+	if err = r.reconcileWebhooks(ctx, hp, log); err != nil {
+		err = errors.Wrapf(err, "error updating harbor project webhooks")
+		hp.Status.Reason = "UpdateProjectWebhooksError"
+
+		return ctrl.Result{}, err
+	}
+
+	// reconcile project tag retention policy
+	if err = r.reconcileRetention(hp, log); err != nil {
+		err = errors.Wrapf(err, "error updating harbor project retention policy")
+		hp.Status.Reason = "UpdateProjectRetentionError"
+
+		return ctrl.Result{}, err
+	}
+
+	// reconcile project tag immutability rules
+	if err = r.reconcileImmutableTagRules(hp, log); err != nil {
+		err = errors.Wrapf(err, "error updating harbor project immutable tag rules")
+		hp.Status.Reason = "UpdateProjectImmutableTagRulesError"
+
+		return ctrl.Result{}, err
+	}
+
 	r.Log.Info("Reconcile is completed")
 
 	return ctrl.Result{RequeueAfter: time.Minute * time.Duration(r.RequeueAfterMinutes)}, nil
 }
 
 // setHarborClient sets harbor client.
-func (r *Reconciler) setHarborClient(ctx context.Context, harborServerConfigName string) error {
-	harborCfg, err := r.getHarborServerConfig(ctx, harborServerConfigName)
+func (r *Reconciler) setHarborClient(ctx context.Context, namespace, harborServerConfigName string) error {
+	harborCfg, err := harborClient.GetHarborServerConfiguration(ctx, r.Client, namespace, harborServerConfigName)
 	if err != nil {
 		return fmt.Errorf("error finding harborCfg: %w", err)
 	}
@@ -170,21 +232,3 @@ func (r *Reconciler) setHarborClient(ctx context.Context, harborServerConfigName
 
 	return nil
 }
-
-func (r *Reconciler) getHarborServerConfig(ctx context.Context, name string) (*goharborv1beta1.HarborServerConfiguration, error) {
-	hsc := &goharborv1beta1.HarborServerConfiguration{}
-	// HarborServerConfiguration is cluster scoped resource
-	namespacedName := types.NamespacedName{
-		Name: name,
-	}
-	if err := r.Client.Get(ctx, namespacedName, hsc); err != nil {
-		// Explicitly check not found error
-		if apierrors.IsNotFound(err) {
-			return nil, nil
-		}
-
-		return nil, err
-	}
-
-	return hsc, nil
-}
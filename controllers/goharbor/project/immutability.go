@@ -0,0 +1,118 @@
+package project
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+)
+
+const (
+	immutableAction   string = "immutable"
+	immutableTemplate string = "immutable_template"
+)
+
+func (r *Reconciler) reconcileImmutableTagRules(hp *goharborv1.HarborProject, log logr.Logger) error {
+	if len(hp.Spec.ImmutableTagRules) == 0 && hp.Status.ImmutableTagRulesHash == "" {
+		// immutability rules not managed by the operator
+		return nil
+	}
+
+	currentRules, err := r.Harbor.ListImmutableRules(hp.Spec.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	// detect changes via hash from status field
+	previousHash := hp.Status.ImmutableTagRulesHash
+
+	currentHash, err := generateRulesHash(currentRules, hp.Spec.ImmutableTagRules)
+	if err != nil {
+		return err
+	}
+
+	if previousHash == currentHash {
+		// no changes, finish reconcile
+		return nil
+	}
+
+	log.Info("reconcile immutable tag rules, changes detected.", "previousHash", previousHash, "currentHash", currentHash)
+
+	desiredRules := createDesiredImmutableRules(hp.Spec.ImmutableTagRules)
+
+	// rules are matched by position, the extra current rules are deleted and the missing ones created
+	for i, current := range currentRules {
+		if i >= len(desiredRules) {
+			log.Info("delete immutable tag rule", "id", current.ID)
+
+			if err := r.Harbor.DeleteImmutableRule(hp.Spec.ProjectName, current.ID); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		desiredRules[i].ID = current.ID
+
+		log.Info("update immutable tag rule", "id", current.ID)
+
+		if err := r.Harbor.UpdateImmutableRule(hp.Spec.ProjectName, current.ID, desiredRules[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := len(currentRules); i < len(desiredRules); i++ {
+		log.Info("create immutable tag rule", "index", i)
+
+		if err := r.Harbor.CreateImmutableRule(hp.Spec.ProjectName, desiredRules[i]); err != nil {
+			return err
+		}
+	}
+
+	if len(desiredRules) == 0 {
+		hp.Status.ImmutableTagRulesHash = ""
+
+		return nil
+	}
+
+	// update hash a final time
+	currentRules, err = r.Harbor.ListImmutableRules(hp.Spec.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	hp.Status.ImmutableTagRulesHash, err = generateRulesHash(currentRules, hp.Spec.ImmutableTagRules)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Immutable tag rules reconcile complete.", "project", hp.Spec.ProjectName)
+
+	return nil
+}
+
+// createDesiredImmutableRules creates the Harbor API rules from the immutability rules defined in the custom resource.
+func createDesiredImmutableRules(definedRules []goharborv1.HarborProjectImmutableTagRule) []*models.ImmutableRule {
+	desiredRules := make([]*models.ImmutableRule, 0, len(definedRules))
+
+	for _, rule := range definedRules {
+		desiredRules = append(desiredRules, &models.ImmutableRule{
+			Action:   immutableAction,
+			Disabled: rule.Disabled,
+			Template: immutableTemplate,
+			ScopeSelectors: map[string][]models.ImmutableSelector{
+				repositorySelectorKey: {{
+					Kind:       selectorKind,
+					Decoration: repositoryDecoration(rule.Repositories),
+					Pattern:    selectorPattern(rule.Repositories),
+				}},
+			},
+			TagSelectors: []*models.ImmutableSelector{{
+				Kind:       selectorKind,
+				Decoration: tagDecoration(rule.Tags),
+				Pattern:    selectorPattern(rule.Tags),
+			}},
+		})
+	}
+
+	return desiredRules
+}
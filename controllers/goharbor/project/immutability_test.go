@@ -0,0 +1,171 @@
+package project_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
+	"github.com/stretchr/testify/require"
+)
+
+const immutableRulesPath = "/api/v2.0/projects/library/immutabletagrules"
+
+func TestCreateDesiredImmutableRules(t *testing.T) {
+	rules := project.CreateDesiredImmutableRules([]goharborv1.HarborProjectImmutableTagRule{{
+		Tags: goharborv1.HarborProjectRuleSelector{Pattern: "v*"},
+	}, {
+		Disabled:     true,
+		Repositories: goharborv1.HarborProjectRuleSelector{Decoration: "excludes", Pattern: "tmp/**"},
+		Tags:         goharborv1.HarborProjectRuleSelector{Decoration: "excludes", Pattern: "latest"},
+	}})
+	require.Len(t, rules, 2)
+
+	require.Equal(t, "immutable", rules[0].Action)
+	require.Equal(t, "immutable_template", rules[0].Template)
+	require.False(t, rules[0].Disabled)
+	require.Equal(t, []models.ImmutableSelector{{Kind: "doublestar", Decoration: "repoMatches", Pattern: "**"}}, rules[0].ScopeSelectors["repository"])
+	require.Equal(t, []*models.ImmutableSelector{{Kind: "doublestar", Decoration: "matches", Pattern: "v*"}}, rules[0].TagSelectors)
+
+	require.True(t, rules[1].Disabled)
+	require.Equal(t, []models.ImmutableSelector{{Kind: "doublestar", Decoration: "repoExcludes", Pattern: "tmp/**"}}, rules[1].ScopeSelectors["repository"])
+	require.Equal(t, []*models.ImmutableSelector{{Kind: "doublestar", Decoration: "excludes", Pattern: "latest"}}, rules[1].TagSelectors)
+}
+
+func TestReconcileImmutableTagRules(t *testing.T) {
+	immutableRules := func(ids ...int64) []*models.ImmutableRule {
+		rules := make([]*models.ImmutableRule, 0, len(ids))
+		for _, id := range ids {
+			rules = append(rules, &models.ImmutableRule{ID: id, Action: "immutable", Template: "immutable_template"})
+		}
+
+		return rules
+	}
+
+	specRules := func(patterns ...string) []goharborv1.HarborProjectImmutableTagRule {
+		rules := make([]goharborv1.HarborProjectImmutableTagRule, 0, len(patterns))
+		for _, pattern := range patterns {
+			rules = append(rules, goharborv1.HarborProjectImmutableTagRule{
+				Tags: goharborv1.HarborProjectRuleSelector{Pattern: pattern},
+			})
+		}
+
+		return rules
+	}
+
+	hashOf := func(current []*models.ImmutableRule, desired []goharborv1.HarborProjectImmutableTagRule) string {
+		hash, err := project.GenerateRulesHash(current, desired)
+		require.NoError(t, err)
+
+		return hash
+	}
+
+	for name, tc := range map[string]struct {
+		current      []*models.ImmutableRule
+		desired      []goharborv1.HarborProjectImmutableTagRule
+		previousHash func(current []*models.ImmutableRule, desired []goharborv1.HarborProjectImmutableTagRule) string
+		requests     []string
+		cleared      bool
+	}{
+		"not managed": {
+			current: immutableRules(1),
+		},
+		"unchanged": {
+			current:      immutableRules(1),
+			desired:      specRules("v*"),
+			previousHash: hashOf,
+			requests:     []string{"GET " + immutableRulesPath},
+		},
+		"rules removed": {
+			current: immutableRules(1, 2, 3),
+			desired: specRules("v*", "release-*"),
+			requests: []string{
+				"GET " + immutableRulesPath,
+				"PUT " + immutableRulesPath + "/1",
+				"PUT " + immutableRulesPath + "/2",
+				"DELETE " + immutableRulesPath + "/3",
+				"GET " + immutableRulesPath,
+			},
+		},
+		"rules added": {
+			current: immutableRules(1),
+			desired: specRules("v*", "release-*", "stable"),
+			requests: []string{
+				"GET " + immutableRulesPath,
+				"PUT " + immutableRulesPath + "/1",
+				"POST " + immutableRulesPath,
+				"POST " + immutableRulesPath,
+				"GET " + immutableRulesPath,
+			},
+		},
+		"all rules removed": {
+			current: immutableRules(1, 2),
+			previousHash: func(current []*models.ImmutableRule, _ []goharborv1.HarborProjectImmutableTagRule) string {
+				return hashOf(current, specRules("v*", "release-*"))
+			},
+			requests: []string{
+				"GET " + immutableRulesPath,
+				"DELETE " + immutableRulesPath + "/1",
+				"DELETE " + immutableRulesPath + "/2",
+			},
+			cleared: true,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			responses := map[string]interface{}{
+				"GET " + immutableRulesPath:  tc.current,
+				"POST " + immutableRulesPath: nil,
+			}
+
+			for _, rule := range tc.current {
+				responses["PUT "+immutableRulesPath+"/"+strconv.FormatInt(rule.ID, 10)] = nil
+				responses["DELETE "+immutableRulesPath+"/"+strconv.FormatInt(rule.ID, 10)] = nil
+			}
+
+			r, api := newReconciler(t, responses)
+
+			hp := &goharborv1.HarborProject{}
+			hp.Spec.ProjectName = "library"
+			hp.Spec.ImmutableTagRules = tc.desired
+
+			if tc.previousHash != nil {
+				hp.Status.ImmutableTagRulesHash = tc.previousHash(tc.current, tc.desired)
+			}
+
+			require.NoError(t, project.ReconcileImmutableTagRules(r, hp, logr.Discard()))
+			require.Equal(t, tc.requests, api.requests)
+
+			switch {
+			case tc.cleared:
+				require.Empty(t, hp.Status.ImmutableTagRulesHash)
+			case tc.desired != nil:
+				require.Equal(t, hashOf(tc.current, tc.desired), hp.Status.ImmutableTagRulesHash)
+			}
+		})
+	}
+}
+
+func TestReconcileImmutableTagRulesUpdate(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + immutableRulesPath:        []*models.ImmutableRule{{ID: 4, Action: "immutable", Template: "immutable_template"}},
+		"PUT " + immutableRulesPath + "/4": nil,
+	})
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+	hp.Spec.ImmutableTagRules = []goharborv1.HarborProjectImmutableTagRule{{
+		Tags: goharborv1.HarborProjectRuleSelector{Pattern: "v*"},
+	}}
+
+	require.NoError(t, project.ReconcileImmutableTagRules(r, hp, logr.Discard()))
+
+	var rule models.ImmutableRule
+	api.body(t, "PUT "+immutableRulesPath+"/4", &rule)
+
+	require.Equal(t, int64(4), rule.ID, "matched by position")
+	require.Equal(t, []*models.ImmutableSelector{{Kind: "doublestar", Decoration: "matches", Pattern: "v*"}}, rule.TagSelectors)
+}
@@ -0,0 +1,170 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const proxySpeedMetadataKey string = "proxy_speed_kb"
+
+var (
+	ErrRegistryEndpointNotReady = errors.New("registry endpoint is not ready")
+	ErrRegistryConflict         = errors.New("registry endpoint already exists in Harbor and is not managed by the project")
+	ErrProxyCacheRegistryChange = errors.New("the upstream registry of an existing proxy cache project cannot be changed")
+)
+
+// reconcileProxyCacheRegistry resolves the upstream registry of a proxy cache project,
+// registering the inline upstream registry in Harbor if needed.
+func (r *Reconciler) reconcileProxyCacheRegistry(ctx context.Context, hp *goharborv1.HarborProject, log logr.Logger) error {
+	proxyCache := hp.Spec.ProxyCache
+	if proxyCache == nil {
+		return nil
+	}
+
+	if proxyCache.RegistryEndpointRef != "" {
+		hre := &goharborv1.HarborRegistryEndpoint{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: hp.GetNamespace(), Name: proxyCache.RegistryEndpointRef}, hre); err != nil {
+			return errors.Wrapf(err, "error get harbor registry endpoint %s", proxyCache.RegistryEndpointRef)
+		}
+
+		if hre.Spec.HarborServerConfig != hp.Spec.HarborServerConfig {
+			return errors.Errorf("registry endpoint %s is managed by the harbor server configuration %s", hre.GetName(), hre.Spec.HarborServerConfig)
+		}
+
+		if hre.Status.Status != goharborv1.HarborReplicationStatusReady || hre.Status.RegistryID <= 0 {
+			return fmt.Errorf("%w: %s", ErrRegistryEndpointNotReady, hre.GetName())
+		}
+
+		hp.Status.ProxyCacheRegistryID = hre.Status.RegistryID
+
+		return nil
+	}
+
+	credential, err := r.getProxyCacheCredential(ctx, hp)
+	if err != nil {
+		return errors.Wrapf(err, "error get registry credential")
+	}
+
+	var existing *models.Registry
+
+	if hp.Status.ProxyCacheRegistryID > 0 {
+		existing, err = r.Harbor.GetRegistry(hp.Status.ProxyCacheRegistryID)
+		if err != nil {
+			return err
+		}
+	}
+
+	registry := proxyCache.Registry
+
+	if existing == nil {
+		// The registry endpoint is deleted with the project, one not created by the operator is never adopted
+		conflict, err := r.Harbor.GetRegistryByName(hp.GetProxyCacheRegistryName())
+		if err != nil {
+			return err
+		}
+
+		if conflict != nil {
+			return fmt.Errorf("%w: %s", ErrRegistryConflict, conflict.Name)
+		}
+
+		log.Info("create proxy cache registry", "name", hp.GetProxyCacheRegistryName())
+
+		id, err := r.Harbor.CreateRegistry(&models.Registry{
+			Name:       hp.GetProxyCacheRegistryName(),
+			Type:       registry.Type,
+			URL:        registry.URL,
+			Insecure:   registry.Insecure,
+			Credential: credential,
+		})
+		if err != nil {
+			return err
+		}
+
+		hp.Status.ProxyCacheRegistryID = id
+
+		return nil
+	}
+
+	hp.Status.ProxyCacheRegistryID = existing.ID
+
+	// Empty keys switch the registry endpoint to anonymous access
+	name := hp.GetProxyCacheRegistryName()
+	update := &models.RegistryUpdate{
+		Name:           &name,
+		URL:            &registry.URL,
+		Insecure:       &registry.Insecure,
+		AccessKey:      &credential.AccessKey,
+		AccessSecret:   &credential.AccessSecret,
+		CredentialType: &credential.Type,
+	}
+
+	return r.Harbor.UpdateRegistry(existing.ID, update)
+}
+
+// checkProxyCacheProject checks that the existing project proxies the upstream registry of the resource,
+// it can only be set when the project is created.
+func (r *Reconciler) checkProxyCacheProject(hp *goharborv1.HarborProject) error {
+	if hp.Spec.ProxyCache == nil {
+		return nil
+	}
+
+	project, err := r.Harbor.GetProjectByName(hp.Spec.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	if project.RegistryID != hp.Status.ProxyCacheRegistryID {
+		return fmt.Errorf("%w: project %s proxies the registry %d, not %d",
+			ErrProxyCacheRegistryChange, hp.Spec.ProjectName, project.RegistryID, hp.Status.ProxyCacheRegistryID)
+	}
+
+	return nil
+}
+
+// getProxyCacheCredential reads the credential of the inline upstream registry from the referred secret.
+// An empty credential is returned for anonymous access.
+func (r *Reconciler) getProxyCacheCredential(ctx context.Context, hp *goharborv1.HarborProject) (*models.RegistryCredential, error) {
+	registry := hp.Spec.ProxyCache.Registry
+	if registry.CredentialRef == "" {
+		return &models.RegistryCredential{Type: registry.CredentialType}, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: hp.GetNamespace(), Name: registry.CredentialRef}, secret); err != nil {
+		return nil, err
+	}
+
+	accessKey, accessSecret, err := model.GetCredential(secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "secret %s", registry.CredentialRef)
+	}
+
+	return &models.RegistryCredential{
+		Type:         registry.CredentialType,
+		AccessKey:    accessKey,
+		AccessSecret: accessSecret,
+	}, nil
+}
+
+// reconcileProxyCacheBandwidth applies the bandwidth limit of a proxy cache project.
+func (r *Reconciler) reconcileProxyCacheBandwidth(hp *goharborv1.HarborProject) error {
+	if hp.Spec.ProxyCache == nil {
+		return nil
+	}
+
+	limit := hp.Spec.ProxyCache.BandwidthLimit
+	if limit == 0 {
+		limit = -1
+	}
+
+	return r.Harbor.SetProjectMetadata(hp.Spec.ProjectName, proxySpeedMetadataKey, strconv.Itoa(int(limit)))
+}
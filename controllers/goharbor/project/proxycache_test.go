@@ -0,0 +1,260 @@
+package project_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	registriesPath = "/api/v2.0/registries"
+	metadatasPath  = "/api/v2.0/projects/library/metadatas"
+)
+
+func TestReconcileProxyCacheRegistryEndpoint(t *testing.T) {
+	ctx := context.TODO()
+
+	endpoint := func(harborServerConfig string, status goharborv1.HarborReplicationStatusType) *goharborv1.HarborRegistryEndpoint {
+		hre := &goharborv1.HarborRegistryEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "docker-hub"},
+		}
+		hre.Spec.HarborServerConfig = harborServerConfig
+		hre.Status.Status = status
+		hre.Status.RegistryID = 4
+
+		return hre
+	}
+
+	for name, tc := range map[string]struct {
+		endpoint *goharborv1.HarborRegistryEndpoint
+		err      error
+	}{
+		"ready": {
+			endpoint: endpoint("harbor", goharborv1.HarborReplicationStatusReady),
+		},
+		"not ready": {
+			endpoint: endpoint("harbor", goharborv1.HarborReplicationStatusFail),
+			err:      project.ErrRegistryEndpointNotReady,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			r, api := newReconciler(t, nil, tc.endpoint)
+
+			hp := newProxyCacheProject()
+			hp.Spec.ProxyCache.RegistryEndpointRef = "docker-hub"
+
+			err := project.ReconcileProxyCacheRegistry(r, ctx, hp, logr.Discard())
+			require.Empty(t, api.requests, "registered by the endpoint")
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(4), hp.Status.ProxyCacheRegistryID)
+		})
+	}
+
+	t.Run("other Harbor", func(t *testing.T) {
+		r, _ := newReconciler(t, nil, endpoint("other", goharborv1.HarborReplicationStatusReady))
+
+		hp := newProxyCacheProject()
+		hp.Spec.ProxyCache.RegistryEndpointRef = "docker-hub"
+
+		require.Error(t, project.ReconcileProxyCacheRegistry(r, ctx, hp, logr.Discard()))
+	})
+}
+
+func TestReconcileProxyCacheRegistryCreate(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + registriesPath: []*models.Registry{{ID: 2, Name: "library-proxy-cache-old"}},
+		"POST " + registriesPath: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", registriesPath+"/6")
+			w.WriteHeader(http.StatusCreated)
+		}),
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "docker-hub"},
+		Data: map[string][]byte{
+			"accessKey":    []byte("user"),
+			"accessSecret": []byte("token"),
+		},
+	})
+
+	hp := newProxyCacheProject()
+	hp.Spec.ProxyCache.Registry.CredentialRef = "docker-hub"
+
+	require.NoError(t, project.ReconcileProxyCacheRegistry(r, context.TODO(), hp, logr.Discard()))
+	require.Equal(t, int64(6), hp.Status.ProxyCacheRegistryID)
+
+	var registry models.Registry
+	api.body(t, "POST "+registriesPath, &registry)
+
+	require.Equal(t, "library-proxy-cache", registry.Name)
+	require.Equal(t, "docker-hub", registry.Type)
+	require.Equal(t, "https://hub.docker.com", registry.URL)
+	require.Equal(t, &models.RegistryCredential{Type: "basic", AccessKey: "user", AccessSecret: "token"}, registry.Credential)
+}
+
+func TestReconcileProxyCacheRegistryUpdate(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + registriesPath + "/6": &models.Registry{ID: 6, Name: "library-proxy-cache"},
+		"PUT " + registriesPath + "/6": nil,
+	})
+
+	hp := newProxyCacheProject()
+	hp.Status.ProxyCacheRegistryID = 6
+
+	require.NoError(t, project.ReconcileProxyCacheRegistry(r, context.TODO(), hp, logr.Discard()))
+	require.Equal(t, []string{"GET " + registriesPath + "/6", "PUT " + registriesPath + "/6"}, api.requests)
+
+	var update models.RegistryUpdate
+	api.body(t, "PUT "+registriesPath+"/6", &update)
+
+	require.Equal(t, "https://hub.docker.com", *update.URL)
+	require.Empty(t, *update.AccessKey, "anonymous access")
+	require.Empty(t, *update.AccessSecret, "anonymous access")
+}
+
+func TestReconcileProxyCacheRegistryConflict(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + registriesPath: []*models.Registry{{ID: 2, Name: "library-proxy-cache"}},
+	})
+
+	hp := newProxyCacheProject()
+
+	err := project.ReconcileProxyCacheRegistry(r, context.TODO(), hp, logr.Discard())
+	require.ErrorIs(t, err, project.ErrRegistryConflict)
+	require.NotContains(t, api.requests, "PUT "+registriesPath+"/2", "not adopted")
+	require.Zero(t, hp.Status.ProxyCacheRegistryID)
+}
+
+func TestCheckProxyCacheProject(t *testing.T) {
+	for name, tc := range map[string]struct {
+		registryID int64
+		err        error
+	}{
+		"same registry": {
+			registryID: 2,
+		},
+		"other registry": {
+			registryID: 3,
+			err:        project.ErrProxyCacheRegistryChange,
+		},
+		"not a proxy cache": {
+			err: project.ErrProxyCacheRegistryChange,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			r, _ := newReconciler(t, map[string]interface{}{
+				"GET " + projectsPath: []*models.Project{{ProjectID: 1, Name: "library", RegistryID: tc.registryID}},
+			})
+
+			hp := newProxyCacheProject()
+			hp.Status.ProxyCacheRegistryID = 2
+
+			err := project.CheckProxyCacheProject(r, hp)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("no proxy cache", func(t *testing.T) {
+		r, api := newReconciler(t, nil)
+
+		hp := newProxyCacheProject()
+		hp.Spec.ProxyCache = nil
+
+		require.NoError(t, project.CheckProxyCacheProject(r, hp))
+		require.Empty(t, api.requests)
+	})
+}
+
+func TestReconcileProxyCacheBandwidth(t *testing.T) {
+	for name, tc := range map[string]struct {
+		limit    int32
+		current  map[string]string
+		requests []string
+		value    string
+	}{
+		"unlimited by default": {
+			current:  map[string]string{"proxy_speed_kb": "-1"},
+			requests: []string{"GET " + metadatasPath + "/proxy_speed_kb"},
+		},
+		"added": {
+			limit:    1024,
+			requests: []string{"GET " + metadatasPath + "/proxy_speed_kb", "POST " + metadatasPath + "/"},
+			value:    "1024",
+		},
+		"updated": {
+			limit:    2048,
+			current:  map[string]string{"proxy_speed_kb": "1024"},
+			requests: []string{"GET " + metadatasPath + "/proxy_speed_kb", "PUT " + metadatasPath + "/proxy_speed_kb"},
+			value:    "2048",
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			responses := map[string]interface{}{
+				// Harbor answers 200 rather than 201
+				"POST " + metadatasPath + "/":              http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+				"PUT " + metadatasPath + "/proxy_speed_kb": nil,
+			}
+
+			if tc.current != nil {
+				responses["GET "+metadatasPath+"/proxy_speed_kb"] = tc.current
+			}
+
+			r, api := newReconciler(t, responses)
+
+			hp := newProxyCacheProject()
+			hp.Spec.ProxyCache.BandwidthLimit = tc.limit
+
+			require.NoError(t, project.ReconcileProxyCacheBandwidth(r, hp))
+			require.Equal(t, tc.requests, api.requests)
+
+			if tc.value != "" {
+				var metadata map[string]string
+				api.body(t, tc.requests[len(tc.requests)-1], &metadata)
+				require.Equal(t, map[string]string{"proxy_speed_kb": tc.value}, metadata)
+			}
+		})
+	}
+}
+
+func newProxyCacheProject() *goharborv1.HarborProject {
+	hp := &goharborv1.HarborProject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "library"},
+	}
+
+	hp.Spec.HarborServerConfig = "harbor"
+	hp.Spec.ProjectName = "library"
+	hp.Spec.ProxyCache = &goharborv1.HarborProjectProxyCache{
+		Registry: &goharborv1.HarborProjectProxyCacheRegistry{
+			Type:           "docker-hub",
+			URL:            "https://hub.docker.com",
+			CredentialType: "basic",
+		},
+	}
+
+	return hp
+}
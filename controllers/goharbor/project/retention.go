@@ -0,0 +1,221 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/pkg/errors"
+)
+
+const (
+	retentionAlgorithm    string = "or"
+	retentionAction       string = "retain"
+	retentionScopeLevel   string = "project"
+	retentionTriggerKind  string = "Schedule"
+	selectorKind          string = "doublestar"
+	repositorySelectorKey string = "repository"
+	untaggedExtras        string = `{"untagged":true}`
+	taggedExtras          string = `{"untagged":false}`
+)
+
+func (r *Reconciler) reconcileRetention(hp *goharborv1.HarborProject, log logr.Logger) error {
+	if hp.Spec.Retention == nil && hp.Status.RetentionID == 0 {
+		// retention policy not managed by the operator
+		hp.Status.RetentionHash = ""
+
+		return nil
+	}
+
+	project, err := r.Harbor.GetProjectByName(hp.Spec.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	// the ID of the policy is kept in the project metadata by Harbor, also for the policies created by the UI
+	policyID := hp.Status.RetentionID
+	if policyID == 0 && project.Metadata != nil && project.Metadata.RetentionID != nil {
+		policyID, err = strconv.ParseInt(*project.Metadata.RetentionID, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid retention id %s in project metadata", *project.Metadata.RetentionID)
+		}
+	}
+
+	var current *models.RetentionPolicy
+
+	if policyID > 0 {
+		current, err = r.Harbor.GetRetention(policyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hp.Spec.Retention == nil && current == nil {
+		// the policy managed by the operator was removed from Harbor
+		hp.Status.RetentionID = 0
+		hp.Status.RetentionHash = ""
+
+		return nil
+	}
+
+	// detect changes via hash from status field
+	previousHash := hp.Status.RetentionHash
+
+	currentHash, err := generateRulesHash(current, hp.Spec.Retention)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && previousHash == currentHash {
+		// no changes, finish reconcile
+		return nil
+	}
+
+	log.Info("reconcile retention policy, changes detected.", "previousHash", previousHash, "currentHash", currentHash)
+
+	desired := createDesiredRetention(int64(project.ProjectID), hp.Spec.Retention)
+
+	if current == nil {
+		policyID, err = r.Harbor.CreateRetention(desired)
+		if err != nil {
+			return err
+		}
+	} else {
+		desired.ID = policyID
+
+		if err = r.Harbor.UpdateRetention(policyID, desired); err != nil {
+			return err
+		}
+	}
+
+	if hp.Spec.Retention == nil {
+		// Harbor keeps a single policy per project, it is emptied rather than deleted
+		log.Info("Retention policy cleared.", "project", hp.Spec.ProjectName, "id", policyID)
+
+		hp.Status.RetentionID = 0
+		hp.Status.RetentionHash = ""
+
+		return nil
+	}
+
+	hp.Status.RetentionID = policyID
+
+	// update hash a final time
+	current, err = r.Harbor.GetRetention(policyID)
+	if err != nil {
+		return err
+	}
+
+	hp.Status.RetentionHash, err = generateRulesHash(current, hp.Spec.Retention)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Retention policy reconcile complete.", "project", hp.Spec.ProjectName, "id", policyID)
+
+	return nil
+}
+
+// createDesiredRetention creates the Harbor API policy from the retention defined in the custom resource.
+// A nil retention results in a policy without any rule nor schedule.
+func createDesiredRetention(projectID int64, retention *goharborv1.HarborProjectRetention) *models.RetentionPolicy {
+	policy := &models.RetentionPolicy{
+		Algorithm: retentionAlgorithm,
+		Rules:     []*models.RetentionRule{},
+		Scope: &models.RetentionPolicyScope{
+			Level: retentionScopeLevel,
+			Ref:   projectID,
+		},
+		Trigger: &models.RetentionRuleTrigger{
+			Kind:       retentionTriggerKind,
+			References: map[string]interface{}{},
+			Settings:   map[string]interface{}{"cron": ""},
+		},
+	}
+
+	if retention == nil {
+		return policy
+	}
+
+	policy.Trigger.Settings = map[string]interface{}{"cron": retention.Schedule}
+
+	for _, rule := range retention.Rules {
+		params := map[string]interface{}{}
+		if rule.Template != goharborv1.HarborProjectRetainAlways {
+			params[string(rule.Template)] = rule.Count
+		}
+
+		extras := taggedExtras
+		if rule.Untagged {
+			extras = untaggedExtras
+		}
+
+		policy.Rules = append(policy.Rules, &models.RetentionRule{
+			Action:   retentionAction,
+			Disabled: rule.Disabled,
+			Params:   params,
+			Template: string(rule.Template),
+			ScopeSelectors: map[string][]models.RetentionSelector{
+				repositorySelectorKey: {{
+					Kind:       selectorKind,
+					Decoration: repositoryDecoration(rule.Repositories),
+					Pattern:    selectorPattern(rule.Repositories),
+				}},
+			},
+			TagSelectors: []*models.RetentionSelector{{
+				Kind:       selectorKind,
+				Decoration: tagDecoration(rule.Tags),
+				Pattern:    selectorPattern(rule.Tags),
+				Extras:     extras,
+			}},
+		})
+	}
+
+	return policy
+}
+
+// repositoryDecoration returns the decoration of Harbor for repository selectors.
+func repositoryDecoration(selector goharborv1.HarborProjectRuleSelector) string {
+	if selector.Decoration == "excludes" {
+		return "repoExcludes"
+	}
+
+	return "repoMatches"
+}
+
+func tagDecoration(selector goharborv1.HarborProjectRuleSelector) string {
+	if selector.Decoration == "excludes" {
+		return "excludes"
+	}
+
+	return "matches"
+}
+
+func selectorPattern(selector goharborv1.HarborProjectRuleSelector) string {
+	if selector.Pattern == "" {
+		return "**"
+	}
+
+	return selector.Pattern
+}
+
+// marshal the current rules from Harbor and the desired ones into json and hash them.
+func generateRulesHash(current, desired interface{}) (string, error) {
+	type rulesComp struct {
+		Current interface{}
+		Desired interface{}
+	}
+
+	rulesByteArr, err := json.Marshal(rulesComp{Current: current, Desired: desired})
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling rules for comparison")
+	}
+
+	currentHashArr := sha256.Sum256(rulesByteArr)
+
+	return hex.EncodeToString(currentHashArr[:]), nil
+}
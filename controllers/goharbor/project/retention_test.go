@@ -0,0 +1,209 @@
+package project_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	projectsPath   = "/api/v2.0/projects"
+	retentionsPath = "/api/v2.0/retentions"
+)
+
+func TestCreateDesiredRetention(t *testing.T) {
+	policy := project.CreateDesiredRetention(3, &goharborv1.HarborProjectRetention{
+		Schedule: "0 0 0 * * *",
+		Rules: []goharborv1.HarborProjectRetentionRule{{
+			Template: goharborv1.HarborProjectRetainLatestPushed,
+			Count:    10,
+			Tags:     goharborv1.HarborProjectRuleSelector{Pattern: "v*"},
+		}, {
+			Template:     goharborv1.HarborProjectRetainAlways,
+			Untagged:     true,
+			Disabled:     true,
+			Repositories: goharborv1.HarborProjectRuleSelector{Decoration: "excludes", Pattern: "tmp/**"},
+		}},
+	})
+
+	require.Equal(t, "or", policy.Algorithm)
+	require.Equal(t, &models.RetentionPolicyScope{Level: "project", Ref: 3}, policy.Scope)
+	require.Equal(t, map[string]interface{}{"cron": "0 0 0 * * *"}, policy.Trigger.Settings)
+	require.Len(t, policy.Rules, 2)
+
+	require.Equal(t, "latestPushedK", policy.Rules[0].Template)
+	require.Equal(t, map[string]interface{}{"latestPushedK": int64(10)}, policy.Rules[0].Params)
+	require.Equal(t, []models.RetentionSelector{{Kind: "doublestar", Decoration: "repoMatches", Pattern: "**"}}, policy.Rules[0].ScopeSelectors["repository"])
+	require.Equal(t, []*models.RetentionSelector{{Kind: "doublestar", Decoration: "matches", Pattern: "v*", Extras: `{"untagged":false}`}}, policy.Rules[0].TagSelectors)
+
+	require.Equal(t, "always", policy.Rules[1].Template)
+	require.Empty(t, policy.Rules[1].Params)
+	require.True(t, policy.Rules[1].Disabled)
+	require.Equal(t, []models.RetentionSelector{{Kind: "doublestar", Decoration: "repoExcludes", Pattern: "tmp/**"}}, policy.Rules[1].ScopeSelectors["repository"])
+	require.Equal(t, []*models.RetentionSelector{{Kind: "doublestar", Decoration: "matches", Pattern: "**", Extras: `{"untagged":true}`}}, policy.Rules[1].TagSelectors)
+
+	cleared := project.CreateDesiredRetention(3, nil)
+	require.Empty(t, cleared.Rules)
+	require.Equal(t, map[string]interface{}{"cron": ""}, cleared.Trigger.Settings)
+}
+
+func TestReconcileRetention(t *testing.T) {
+	retention := &goharborv1.HarborProjectRetention{
+		Schedule: "0 0 0 * * *",
+		Rules: []goharborv1.HarborProjectRetentionRule{{
+			Template: goharborv1.HarborProjectRetainLatestPushed,
+			Count:    10,
+		}},
+	}
+
+	current := &models.RetentionPolicy{ID: 5, Algorithm: "or"}
+
+	hashOf := func(current *models.RetentionPolicy, desired *goharborv1.HarborProjectRetention) string {
+		hash, err := project.GenerateRulesHash(current, desired)
+		require.NoError(t, err)
+
+		return hash
+	}
+
+	libraryProject := func(retentionID *string) []*models.Project {
+		return []*models.Project{{
+			ProjectID: 3,
+			Name:      "library",
+			Metadata:  &models.ProjectMetadata{RetentionID: retentionID},
+		}}
+	}
+
+	for name, tc := range map[string]struct {
+		retention    *goharborv1.HarborProjectRetention
+		retentionID  int64
+		previousHash string
+		responses    map[string]interface{}
+		requests     []string
+		expectedID   int64
+		expectedHash string
+	}{
+		"not managed": {
+			responses: map[string]interface{}{
+				"GET " + projectsPath: libraryProject(pointer.String("5")),
+			},
+		},
+		"created": {
+			retention: retention,
+			responses: map[string]interface{}{
+				"GET " + projectsPath: libraryProject(nil),
+				"POST " + retentionsPath: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Location", retentionsPath+"/7")
+					w.WriteHeader(http.StatusCreated)
+				}),
+				"GET " + retentionsPath + "/7": &models.RetentionPolicy{ID: 7},
+			},
+			requests: []string{
+				"GET " + projectsPath,
+				"POST " + retentionsPath,
+				"GET " + retentionsPath + "/7",
+			},
+			expectedID:   7,
+			expectedHash: hashOf(&models.RetentionPolicy{ID: 7}, retention),
+		},
+		"created by the UI": {
+			retention: retention,
+			responses: map[string]interface{}{
+				"GET " + projectsPath:          libraryProject(pointer.String("5")),
+				"GET " + retentionsPath + "/5": current,
+				"PUT " + retentionsPath + "/5": nil,
+			},
+			requests: []string{
+				"GET " + projectsPath,
+				"GET " + retentionsPath + "/5",
+				"PUT " + retentionsPath + "/5",
+				"GET " + retentionsPath + "/5",
+			},
+			expectedID:   5,
+			expectedHash: hashOf(current, retention),
+		},
+		"unchanged": {
+			retention:    retention,
+			retentionID:  5,
+			previousHash: hashOf(current, retention),
+			responses: map[string]interface{}{
+				"GET " + projectsPath:          libraryProject(pointer.String("5")),
+				"GET " + retentionsPath + "/5": current,
+			},
+			requests: []string{
+				"GET " + projectsPath,
+				"GET " + retentionsPath + "/5",
+			},
+			expectedID:   5,
+			expectedHash: hashOf(current, retention),
+		},
+		"cleared": {
+			retentionID:  5,
+			previousHash: hashOf(current, retention),
+			responses: map[string]interface{}{
+				"GET " + projectsPath:          libraryProject(pointer.String("5")),
+				"GET " + retentionsPath + "/5": current,
+				"PUT " + retentionsPath + "/5": nil,
+			},
+			requests: []string{
+				"GET " + projectsPath,
+				"GET " + retentionsPath + "/5",
+				"PUT " + retentionsPath + "/5",
+			},
+		},
+		"removed from Harbor": {
+			retentionID:  5,
+			previousHash: hashOf(current, retention),
+			responses: map[string]interface{}{
+				"GET " + projectsPath: libraryProject(nil),
+			},
+			requests: []string{
+				"GET " + projectsPath,
+				"GET " + retentionsPath + "/5",
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			r, api := newReconciler(t, tc.responses)
+
+			hp := &goharborv1.HarborProject{}
+			hp.Spec.ProjectName = "library"
+			hp.Spec.Retention = tc.retention
+			hp.Status.RetentionID = tc.retentionID
+			hp.Status.RetentionHash = tc.previousHash
+
+			require.NoError(t, project.ReconcileRetention(r, hp, logr.Discard()))
+			require.Equal(t, tc.requests, api.requests)
+			require.Equal(t, tc.expectedID, hp.Status.RetentionID)
+			require.Equal(t, tc.expectedHash, hp.Status.RetentionHash)
+		})
+	}
+}
+
+func TestReconcileRetentionCleared(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + projectsPath:          []*models.Project{{ProjectID: 3, Name: "library"}},
+		"GET " + retentionsPath + "/5": &models.RetentionPolicy{ID: 5},
+		"PUT " + retentionsPath + "/5": nil,
+	})
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+	hp.Status.RetentionID = 5
+
+	require.NoError(t, project.ReconcileRetention(r, hp, logr.Discard()))
+
+	var policy models.RetentionPolicy
+	api.body(t, "PUT "+retentionsPath+"/5", &policy)
+
+	require.Equal(t, int64(5), policy.ID)
+	require.Empty(t, policy.Rules, "Harbor keeps a single policy per project, it is emptied")
+	require.Equal(t, map[string]interface{}{"cron": ""}, policy.Trigger.Settings)
+}
@@ -0,0 +1,191 @@
+package project
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const defaultWebhookType string = "http"
+
+// ErrWebhookPolicyNotManaged is returned when a webhook policy of the resource has the name of a policy not applied by the operator.
+var ErrWebhookPolicyNotManaged = errors.New("a webhook policy with the same name exists and is not managed by the operator")
+
+// reconcileWebhooks applies the webhook policies of the project.
+// The policies applied by the operator are tracked by ID in the status, only those are updated
+// or deleted when removed from the resource: the policies created in Harbor are left untouched.
+func (r *Reconciler) reconcileWebhooks(ctx context.Context, hp *goharborv1.HarborProject, log logr.Logger) error {
+	if len(hp.Spec.Webhooks) == 0 && len(hp.Status.WebhookPolicies) == 0 {
+		// webhook policies not managed by the operator
+		return nil
+	}
+
+	currentPolicies, err := r.Harbor.ListWebhookPolicies(hp.Spec.ProjectName)
+	if err != nil {
+		return err
+	}
+
+	currentByID := make(map[int64]*models.WebhookPolicy, len(currentPolicies))
+	currentByName := make(map[string]*models.WebhookPolicy, len(currentPolicies))
+
+	for _, policy := range currentPolicies {
+		currentByID[policy.ID] = policy
+		currentByName[policy.Name] = policy
+	}
+
+	managed := make(map[string]int64, len(hp.Spec.Webhooks))
+
+	for i := range hp.Spec.Webhooks {
+		desired, err := r.createDesiredWebhook(ctx, hp, &hp.Spec.Webhooks[i])
+		if err != nil {
+			return err
+		}
+
+		var current *models.WebhookPolicy
+		if id, ok := hp.Status.WebhookPolicies[desired.Name]; ok {
+			current = currentByID[id]
+		}
+
+		if current == nil {
+			if _, ok := currentByName[desired.Name]; ok {
+				return errors.Wrapf(ErrWebhookPolicyNotManaged, "webhook policy %s", desired.Name)
+			}
+
+			log.Info("create webhook policy", "name", desired.Name)
+
+			id, err := r.Harbor.CreateWebhookPolicy(hp.Spec.ProjectName, desired)
+			if err != nil {
+				return err
+			}
+
+			managed[desired.Name] = id
+
+			continue
+		}
+
+		managed[desired.Name] = current.ID
+
+		if current.Name == desired.Name && areWebhooksEqual(current, desired) {
+			continue
+		}
+
+		log.Info("update webhook policy", "name", desired.Name)
+
+		desired.ID = current.ID
+
+		if err := r.Harbor.UpdateWebhookPolicy(hp.Spec.ProjectName, current.ID, desired); err != nil {
+			return err
+		}
+	}
+
+	for name, id := range hp.Status.WebhookPolicies {
+		if _, ok := managed[name]; ok {
+			continue
+		}
+
+		if _, ok := currentByID[id]; !ok {
+			// already deleted
+			continue
+		}
+
+		log.Info("delete webhook policy", "name", name)
+
+		if err := r.Harbor.DeleteWebhookPolicy(hp.Spec.ProjectName, id); err != nil {
+			return err
+		}
+	}
+
+	if len(managed) == 0 {
+		managed = nil
+	}
+
+	hp.Status.WebhookPolicies = managed
+
+	return nil
+}
+
+// deleteWebhooks deletes the webhook policies applied by the operator.
+func (r *Reconciler) deleteWebhooks(hp *goharborv1.HarborProject) error {
+	for _, id := range hp.Status.WebhookPolicies {
+		if err := r.Harbor.DeleteWebhookPolicy(hp.Spec.ProjectName, id); err != nil {
+			return err
+		}
+	}
+
+	hp.Status.WebhookPolicies = nil
+
+	return nil
+}
+
+// createDesiredWebhook creates the Harbor API policy from the webhook defined in the custom resource.
+func (r *Reconciler) createDesiredWebhook(ctx context.Context, hp *goharborv1.HarborProject, webhook *goharborv1.HarborProjectWebhook) (*models.WebhookPolicy, error) {
+	target := &models.WebhookTargetObject{
+		Type:           webhook.Type,
+		Address:        webhook.Address,
+		SkipCertVerify: webhook.SkipCertVerify,
+	}
+
+	if target.Type == "" {
+		target.Type = defaultWebhookType
+	}
+
+	if ref := webhook.AuthHeaderRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: hp.GetNamespace(), Name: ref.Name}, secret); err != nil {
+			return nil, errors.Wrapf(err, "error get auth header of webhook %s", webhook.Name)
+		}
+
+		value, ok := secret.Data[ref.Key]
+		if !ok && (ref.Optional == nil || !*ref.Optional) {
+			return nil, errors.Errorf("key %s not found in secret %s", ref.Key, ref.Name)
+		}
+
+		target.AuthHeader = string(value)
+	}
+
+	eventTypes := make([]string, 0, len(webhook.EventTypes))
+	for _, eventType := range webhook.EventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	return &models.WebhookPolicy{
+		Name:        webhook.Name,
+		Description: webhook.Description,
+		Enabled:     !webhook.Disabled,
+		EventTypes:  eventTypes,
+		Targets:     []*models.WebhookTargetObject{target},
+	}, nil
+}
+
+func areWebhooksEqual(current, desired *models.WebhookPolicy) bool {
+	if current.Description != desired.Description || current.Enabled != desired.Enabled ||
+		len(current.Targets) != len(desired.Targets) || len(current.EventTypes) != len(desired.EventTypes) {
+		return false
+	}
+
+	for i, target := range current.Targets {
+		if *target != *desired.Targets[i] {
+			return false
+		}
+	}
+
+	currentEvents := append([]string{}, current.EventTypes...)
+	desiredEvents := append([]string{}, desired.EventTypes...)
+
+	sort.Strings(currentEvents)
+	sort.Strings(desiredEvents)
+
+	for i := range currentEvents {
+		if currentEvents[i] != desiredEvents[i] {
+			return false
+		}
+	}
+
+	return true
+}
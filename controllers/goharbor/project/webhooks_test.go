@@ -0,0 +1,267 @@
+package project_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const webhookPoliciesPath = "/api/v2.0/projects/library/webhook/policies"
+
+func TestAreWebhooksEqual(t *testing.T) {
+	policy := func(mutate func(*models.WebhookPolicy)) *models.WebhookPolicy {
+		p := &models.WebhookPolicy{
+			ID:          3,
+			Name:        "ci",
+			Description: "notify the CI",
+			Enabled:     true,
+			EventTypes:  []string{"PUSH_ARTIFACT", "SCANNING_COMPLETED"},
+			Targets: []*models.WebhookTargetObject{{
+				Type:    "http",
+				Address: "https://ci.example.com/hook",
+			}},
+		}
+
+		if mutate != nil {
+			mutate(p)
+		}
+
+		return p
+	}
+
+	for name, tc := range map[string]struct {
+		desired *models.WebhookPolicy
+		equal   bool
+	}{
+		"equal": {
+			desired: policy(func(p *models.WebhookPolicy) { p.ID = 0 }),
+			equal:   true,
+		},
+		"events in another order": {
+			desired: policy(func(p *models.WebhookPolicy) { p.EventTypes = []string{"SCANNING_COMPLETED", "PUSH_ARTIFACT"} }),
+			equal:   true,
+		},
+		"description": {
+			desired: policy(func(p *models.WebhookPolicy) { p.Description = "" }),
+		},
+		"disabled": {
+			desired: policy(func(p *models.WebhookPolicy) { p.Enabled = false }),
+		},
+		"other event": {
+			desired: policy(func(p *models.WebhookPolicy) { p.EventTypes = []string{"PUSH_ARTIFACT", "DELETE_ARTIFACT"} }),
+		},
+		"less events": {
+			desired: policy(func(p *models.WebhookPolicy) { p.EventTypes = []string{"PUSH_ARTIFACT"} }),
+		},
+		"address": {
+			desired: policy(func(p *models.WebhookPolicy) { p.Targets[0].Address = "https://ci.example.com/other" }),
+		},
+		"auth header": {
+			desired: policy(func(p *models.WebhookPolicy) { p.Targets[0].AuthHeader = "Bearer token" }),
+		},
+		"no target": {
+			desired: policy(func(p *models.WebhookPolicy) { p.Targets = nil }),
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			current := policy(nil)
+
+			require.Equal(t, tc.equal, project.AreWebhooksEqual(current, tc.desired))
+			require.Equal(t, []string{"PUSH_ARTIFACT", "SCANNING_COMPLETED"}, current.EventTypes, "events not sorted in place")
+		})
+	}
+}
+
+func TestReconcileWebhooks(t *testing.T) {
+	current := []*models.WebhookPolicy{{
+		ID:         1,
+		Name:       "ci",
+		Enabled:    true,
+		EventTypes: []string{"PUSH_ARTIFACT"},
+		Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ci.example.com/hook"}},
+	}, {
+		ID:         2,
+		Name:       "slack",
+		Enabled:    true,
+		EventTypes: []string{"SCANNING_FAILED"},
+		Targets:    []*models.WebhookTargetObject{{Type: "slack", Address: "https://hooks.slack.com/services/x"}},
+	}, {
+		ID:         3,
+		Name:       "manual",
+		Enabled:    true,
+		EventTypes: []string{"QUOTA_EXCEED"},
+		Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ops.example.com/hook"}},
+	}}
+
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + webhookPoliciesPath: current,
+		"POST " + webhookPoliciesPath: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Location", webhookPoliciesPath+"/4")
+			w.WriteHeader(http.StatusCreated)
+		}),
+		"PUT " + webhookPoliciesPath + "/2":    nil,
+		"DELETE " + webhookPoliciesPath + "/1": nil,
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "scanner"},
+		Data: map[string][]byte{
+			"header": []byte("Bearer token"),
+		},
+	})
+
+	hp := &goharborv1.HarborProject{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "library"},
+	}
+	hp.Spec.ProjectName = "library"
+	hp.Spec.Webhooks = []goharborv1.HarborProjectWebhook{{
+		Name:       "slack",
+		EventTypes: []goharborv1.HarborProjectWebhookEventType{"SCANNING_FAILED", "SCANNING_COMPLETED"},
+		Type:       "slack",
+		Address:    "https://hooks.slack.com/services/x",
+	}, {
+		Name:       "scanner",
+		EventTypes: []goharborv1.HarborProjectWebhookEventType{"PUSH_ARTIFACT"},
+		Address:    "https://scanner.example.com/hook",
+		AuthHeaderRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "scanner"},
+			Key:                  "header",
+		},
+	}}
+	// ci was applied by the operator and removed from the resource, manual was never managed
+	hp.Status.WebhookPolicies = map[string]int64{"ci": 1, "slack": 2}
+
+	require.NoError(t, project.ReconcileWebhooks(r, context.TODO(), hp, logr.Discard()))
+
+	require.ElementsMatch(t, []string{
+		"GET " + webhookPoliciesPath,
+		"PUT " + webhookPoliciesPath + "/2",
+		"POST " + webhookPoliciesPath,
+		"DELETE " + webhookPoliciesPath + "/1",
+	}, api.requests)
+	require.Equal(t, map[string]int64{"slack": 2, "scanner": 4}, hp.Status.WebhookPolicies)
+
+	var created models.WebhookPolicy
+	api.body(t, "POST "+webhookPoliciesPath, &created)
+
+	require.Equal(t, "scanner", created.Name)
+	require.True(t, created.Enabled)
+	require.Equal(t, []*models.WebhookTargetObject{{
+		Type:       "http",
+		Address:    "https://scanner.example.com/hook",
+		AuthHeader: "Bearer token",
+	}}, created.Targets)
+
+	var updated models.WebhookPolicy
+	api.body(t, "PUT "+webhookPoliciesPath+"/2", &updated)
+
+	require.Equal(t, int64(2), updated.ID)
+	require.Equal(t, []string{"SCANNING_FAILED", "SCANNING_COMPLETED"}, updated.EventTypes)
+}
+
+func TestReconcileWebhooksUnchanged(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + webhookPoliciesPath: []*models.WebhookPolicy{{
+			ID:         1,
+			Name:       "ci",
+			Enabled:    true,
+			EventTypes: []string{"PUSH_ARTIFACT"},
+			Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ci.example.com/hook"}},
+		}},
+	})
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+	hp.Spec.Webhooks = []goharborv1.HarborProjectWebhook{{
+		Name:       "ci",
+		EventTypes: []goharborv1.HarborProjectWebhookEventType{"PUSH_ARTIFACT"},
+		Address:    "https://ci.example.com/hook",
+	}}
+
+	hp.Status.WebhookPolicies = map[string]int64{"ci": 1}
+
+	require.NoError(t, project.ReconcileWebhooks(r, context.TODO(), hp, logr.Discard()))
+	require.Equal(t, []string{"GET " + webhookPoliciesPath}, api.requests)
+	require.Equal(t, map[string]int64{"ci": 1}, hp.Status.WebhookPolicies)
+}
+
+func TestReconcileWebhooksConflict(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + webhookPoliciesPath: []*models.WebhookPolicy{{
+			ID:         1,
+			Name:       "ci",
+			Enabled:    true,
+			EventTypes: []string{"QUOTA_EXCEED"},
+			Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ops.example.com/hook"}},
+		}},
+	})
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+	hp.Spec.Webhooks = []goharborv1.HarborProjectWebhook{{
+		Name:       "ci",
+		EventTypes: []goharborv1.HarborProjectWebhookEventType{"PUSH_ARTIFACT"},
+		Address:    "https://ci.example.com/hook",
+	}}
+
+	err := project.ReconcileWebhooks(r, context.TODO(), hp, logr.Discard())
+	require.ErrorIs(t, err, project.ErrWebhookPolicyNotManaged)
+	require.Equal(t, []string{"GET " + webhookPoliciesPath}, api.requests, "the policy created in Harbor is not adopted")
+	require.Empty(t, hp.Status.WebhookPolicies)
+}
+
+func TestReconcileWebhooksRenamed(t *testing.T) {
+	r, api := newReconciler(t, map[string]interface{}{
+		"GET " + webhookPoliciesPath: []*models.WebhookPolicy{{
+			ID:         1,
+			Name:       "renamed",
+			Enabled:    true,
+			EventTypes: []string{"PUSH_ARTIFACT"},
+			Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ci.example.com/hook"}},
+		}, {
+			ID:         2,
+			Name:       "removed",
+			Enabled:    true,
+			EventTypes: []string{"PUSH_ARTIFACT"},
+			Targets:    []*models.WebhookTargetObject{{Type: "http", Address: "https://ops.example.com/hook"}},
+		}},
+		"PUT " + webhookPoliciesPath + "/1": nil,
+	})
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+	hp.Spec.Webhooks = []goharborv1.HarborProjectWebhook{{
+		Name:       "ci",
+		EventTypes: []goharborv1.HarborProjectWebhookEventType{"PUSH_ARTIFACT"},
+		Address:    "https://ci.example.com/hook",
+	}}
+	// ci was renamed in Harbor, the policy named removed was created in Harbor after the managed one was deleted
+	hp.Status.WebhookPolicies = map[string]int64{"ci": 1, "removed": 3}
+
+	require.NoError(t, project.ReconcileWebhooks(r, context.TODO(), hp, logr.Discard()))
+	require.Equal(t, []string{"GET " + webhookPoliciesPath, "PUT " + webhookPoliciesPath + "/1"}, api.requests)
+	require.Equal(t, map[string]int64{"ci": 1}, hp.Status.WebhookPolicies)
+
+	var updated models.WebhookPolicy
+	api.body(t, "PUT "+webhookPoliciesPath+"/1", &updated)
+
+	require.Equal(t, "ci", updated.Name)
+}
+
+func TestReconcileWebhooksNotManaged(t *testing.T) {
+	r, api := newReconciler(t, nil)
+
+	hp := &goharborv1.HarborProject{}
+	hp.Spec.ProjectName = "library"
+
+	require.NoError(t, project.ReconcileWebhooks(r, context.TODO(), hp, logr.Discard()))
+	require.Empty(t, api.requests)
+}
@@ -0,0 +1,12 @@
+package pullsecretbinding
+
+type BindingRegistry = bindingRegistry
+
+var (
+	EnsureRegSec      = (*Reconciler).ensureRegSec
+	GetAuthKeys       = getAuthKeys
+	NeedsRotation     = needsRotation
+	Rotate            = rotate
+	RequeueAfter      = requeueAfter
+	SetRotationStatus = (*rotation).setStatus
+)
@@ -1,29 +1,36 @@
 package pullsecretbinding
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	gostrings "strings"
 	"time"
 
-	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/controllers"
 	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
 	"github.com/goharbor/harbor-operator/pkg/registry/secret"
+	"github.com/goharbor/harbor-operator/pkg/rest"
 	"github.com/goharbor/harbor-operator/pkg/rest/model"
 	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
 	"github.com/goharbor/harbor-operator/pkg/utils/consts"
 	"github.com/goharbor/harbor-operator/pkg/utils/strings"
 	"github.com/ovh/configstore"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -53,6 +60,7 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=goharbor.io,resources=pullsecretbindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=goharbor.io,resources=pullsecretbindings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=goharbor.io,resources=harborserverconfigurations,verbs=get
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch
 
@@ -71,24 +79,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 	}
 
 	// Check binding resources
-	server, sa, res, err := r.checkBindingRes(ctx, bd)
-	if err != nil {
+	registries, res, err := r.checkBindingRes(ctx, bd)
+	if err != nil || registries == nil {
 		return res, err
-	} else {
-		if server == nil || sa == nil {
-			return res, err
-		}
 	}
 
-	// Create harbor client
-	harborv2, err := v2.NewWithServer(server)
-	if err != nil {
-		log.Error(err, "failed to create harbor client")
-
-		return ctrl.Result{}, err
-	}
-
-	r.Harbor = harborv2.WithContext(ctx)
+	// The harbor client of the first registry manages the project of the binding
+	r.Harbor = registries[0].Harbor
 
 	// Check if the binding is being deleted
 	if bd.ObjectMeta.DeletionTimestamp.IsZero() {
@@ -106,6 +103,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 				return ctrl.Result{}, err
 			}
 
+			if err := r.unbindServiceAccounts(ctx, bd); err != nil {
+				return ctrl.Result{}, err
+			}
+
 			bd.ObjectMeta.Finalizers = strings.RemoveString(bd.ObjectMeta.Finalizers, finalizerID)
 			if err := r.Client.Update(ctx, bd, &client.UpdateOptions{}); err != nil {
 				return ctrl.Result{}, err
@@ -126,50 +127,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		}
 	}()
 
-	projID, robotID := parseIntID(bd.Spec.ProjectID), parseIntID(bd.Spec.RobotID)
+	// Get the service accounts binding the pull secret
+	sas, err := r.getServiceAccounts(ctx, bd)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get service accounts error: %w", err)
+	}
 
-	// Bind robot to service account
+	// Make registry secret with the credentials of all the registries
 	// TODO: may cause dirty robots at the harbor project side
-	// TODO: check secret binding by get secret and service account
-	_, ok := bd.Annotations[consts.AnnotationRobotSecretRef]
-	if !ok {
-		// Need to create a new one as we only have one time to get the robot token
-		robot, err := r.Harbor.GetRobotAccount(projID, robotID)
-		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("create robot account error: %w", err)
-		}
+	regsec, rotation, err := r.ensureRegSec(ctx, bd, registries)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("create registry secret error: %w", err)
+	}
 
-		// Make registry secret
-		regsec, err := r.createRegSec(ctx, bd.Namespace, server.ServerURL, robot, bd)
-		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("create registry secret error: %w", err)
-		}
-		// Add secret to service account
-		if sa.ImagePullSecrets == nil {
-			sa.ImagePullSecrets = make([]corev1.LocalObjectReference, 0)
-		}
-		sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{
-			Name: regsec.Name,
-		})
+	// Add secret to service accounts
+	bound, err := r.bindServiceAccounts(ctx, sas, regsec.Name)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("bind service accounts error: %w", err)
+	}
 
-		// Update
-		if err := r.Client.Update(ctx, sa, &client.UpdateOptions{}); err != nil {
-			return ctrl.Result{}, fmt.Errorf("update error: %w", err)
-		}
+	status := bd.Status.DeepCopy()
+	status.Status = "ready"
+	status.Message = ""
+	status.ServiceAccounts = bound
+	rotation.setStatus(status)
 
-		// Update binding
-		if err := controllerutil.SetControllerReference(bd, regsec, r.Scheme); err != nil {
-			r.Log.Error(err, "set controller reference", "owner", bd.ObjectMeta, "controlled", regsec.ObjectMeta)
-		}
-		setAnnotation(bd, consts.AnnotationRobotSecretRef, regsec.Name)
-		if err := r.update(ctx, bd); err != nil {
-			return ctrl.Result{}, fmt.Errorf("update error: %w", err)
-		}
+	if rotation.err != nil {
+		status.Status = "error"
+		status.Message = rotation.err.Error()
 	}
 
-	// TODO: add conditions
-	if bd.Status.Status != "ready" {
-		bd.Status.Status = "ready"
+	if !equality.Semantic.DeepEqual(&bd.Status, status) {
+		bd.Status = *status
 		if err := r.Status().Update(ctx, bd); err != nil {
 			if apierr.IsConflict(err) {
 				log.Error(err, "failed to update status")
@@ -179,9 +168,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.
 		}
 	}
 
-	// Loop
+	if rotation.err != nil {
+		// Retry the rotation, the previous secrets of the robot accounts are kept meanwhile
+		return ctrl.Result{}, rotation.err
+	}
+
+	// Loop, the secrets are rotated before the expiration of the robot accounts
 	return ctrl.Result{
-		RequeueAfter: defaultCycle,
+		RequeueAfter: requeueAfter(bd),
 	}, nil
 }
 
@@ -191,9 +185,29 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&goharborv1.PullSecretBinding{}).
+		Watches(&source.Kind{Type: &corev1.ServiceAccount{}}, handler.EnqueueRequestsFromMapFunc(r.bindingsOfServiceAccount)).
 		Complete(r)
 }
 
+// bindingsOfServiceAccount enqueues the bindings of the namespace of the service account, it may be selected by them.
+func (r *Reconciler) bindingsOfServiceAccount(obj client.Object) []reconcile.Request {
+	bindings := &goharborv1.PullSecretBindingList{}
+	if err := r.Client.List(context.Background(), bindings, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list bindings", "namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bindings.Items))
+	for _, bd := range bindings.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: bd.Namespace, Name: bd.Name},
+		})
+	}
+
+	return requests
+}
+
 func (r *Reconciler) update(ctx context.Context, binding *goharborv1.PullSecretBinding) error {
 	if err := r.Client.Update(ctx, binding, &client.UpdateOptions{}); err != nil {
 		return err
@@ -233,63 +247,183 @@ func (r *Reconciler) getConfigData(ctx context.Context, hsc *goharborv1.HarborSe
 	return s, nil
 }
 
-func (r *Reconciler) checkBindingRes(ctx context.Context, psb *goharborv1.PullSecretBinding) (*model.HarborServer, *corev1.ServiceAccount, ctrl.Result, error) {
-	// Get server configuration
-	hsc, err := r.getHarborServerConfig(ctx, psb.Spec.HarborServerConfig)
-	if err != nil {
-		// Retry later
-		return nil, nil, ctrl.Result{}, fmt.Errorf("get server configuration error: %w", err)
-	}
+// bindingRegistry is a Harbor project of the binding with the connection data of its Harbor server.
+type bindingRegistry struct {
+	goharborv1.PullSecretBindingRegistry
 
-	if hsc == nil {
-		// Not exist
-		r.Log.Info("harbor server configuration does not exists", "name", psb.Spec.HarborServerConfig)
+	Server *model.HarborServer
+	Harbor *v2.Client
+}
+
+func (r *Reconciler) checkBindingRes(ctx context.Context, psb *goharborv1.PullSecretBinding) ([]*bindingRegistry, ctrl.Result, error) {
+	specs := psb.Spec.GetRegistries()
+	if len(specs) == 0 {
+		r.Log.Info("no harbor server configuration referred in binding", "name", psb.Name)
 		// Do not need to reconcile again
-		return nil, nil, ctrl.Result{}, nil
+		return nil, ctrl.Result{}, nil
 	}
 
-	if hsc.Status.Status == goharborv1.HarborServerConfigurationStatusUnknown || hsc.Status.Status == goharborv1.HarborServerConfigurationStatusFail {
-		return nil, nil, ctrl.Result{}, fmt.Errorf("status of Harbor server referred in configuration %s is unexpected: %s", hsc.Name, hsc.Status.Status)
+	registries := make([]*bindingRegistry, 0, len(specs))
+
+	for _, spec := range specs {
+		// Get server configuration
+		hsc, err := rest.GetHarborServerConfiguration(ctx, r.Client, psb.GetNamespace(), spec.HarborServerConfig)
+		if err != nil {
+			// Retry later
+			return nil, ctrl.Result{}, fmt.Errorf("get server configuration error: %w", err)
+		}
+
+		if hsc == nil {
+			// Not exist
+			r.Log.Info("harbor server configuration does not exists", "name", spec.HarborServerConfig)
+			// Do not need to reconcile again
+			return nil, ctrl.Result{}, nil
+		}
+
+		if hsc.Status.Status == goharborv1.HarborServerConfigurationStatusUnknown || hsc.Status.Status == goharborv1.HarborServerConfigurationStatusFail {
+			return nil, ctrl.Result{}, fmt.Errorf("status of Harbor server referred in configuration %s is unexpected: %s", hsc.Name, hsc.Status.Status)
+		}
+
+		hs, err := r.getConfigData(ctx, hsc)
+		if err != nil {
+			return nil, ctrl.Result{}, fmt.Errorf("get config data error: %w", err)
+		}
+
+		// Create harbor client
+		harborv2, err := v2.NewWithServer(hs)
+		if err != nil {
+			return nil, ctrl.Result{}, fmt.Errorf("create harbor client error: %w", err)
+		}
+
+		registries = append(registries, &bindingRegistry{
+			PullSecretBindingRegistry: spec,
+			Server:                    hs,
+			Harbor:                    harborv2.WithContext(ctx),
+		})
 	}
 
-	// Get the specified service account
-	sa, err := r.getServiceAccount(ctx, psb.Namespace, psb.Spec.ServiceAccount)
-	if err != nil {
-		// Retry later
-		return nil, nil, ctrl.Result{}, fmt.Errorf("get service account error: %w", err)
+	return registries, ctrl.Result{}, nil
+}
+
+// getServiceAccounts returns the service accounts named or selected by the binding, sorted by name.
+func (r *Reconciler) getServiceAccounts(ctx context.Context, psb *goharborv1.PullSecretBinding) ([]*corev1.ServiceAccount, error) {
+	sas := map[string]*corev1.ServiceAccount{}
+
+	names := psb.Spec.ServiceAccounts
+	if psb.Spec.ServiceAccount != "" {
+		names = append([]string{psb.Spec.ServiceAccount}, names...)
 	}
 
-	if sa == nil {
-		// Not exist
-		r.Log.Info("service account does not exist", "name", psb.Spec.ServiceAccount)
-		// Do not need to reconcile again
-		return nil, nil, ctrl.Result{}, nil
+	for _, name := range names {
+		sa, err := r.getServiceAccount(ctx, psb.Namespace, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if sa == nil {
+			// Not exist, it is bound once created
+			r.Log.Info("service account does not exist", "name", name)
+
+			continue
+		}
+
+		sas[sa.Name] = sa
 	}
 
-	hs, err := r.getConfigData(ctx, hsc)
-	if err != nil {
-		return nil, nil, ctrl.Result{}, fmt.Errorf("get config data error: %w", err)
+	if psb.Spec.ServiceAccountSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(psb.Spec.ServiceAccountSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service account selector: %w", err)
+		}
+
+		list := &corev1.ServiceAccountList{}
+		if err := r.Client.List(ctx, list, client.InNamespace(psb.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			sas[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+
+	result := make([]*corev1.ServiceAccount, 0, len(sas))
+	for _, sa := range sas {
+		result = append(result, sa)
 	}
 
-	return hs, sa, ctrl.Result{}, nil
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
 }
 
-func (r *Reconciler) getHarborServerConfig(ctx context.Context, name string) (*goharborv1.HarborServerConfiguration, error) {
-	hsc := &goharborv1.HarborServerConfiguration{}
-	// HarborServerConfiguration is cluster scoped resource
-	namespacedName := types.NamespacedName{
-		Name: name,
+// bindServiceAccounts adds the pull secret to the service accounts and returns their names.
+func (r *Reconciler) bindServiceAccounts(ctx context.Context, sas []*corev1.ServiceAccount, secretName string) ([]string, error) {
+	bound := make([]string, 0, len(sas))
+
+	for _, sa := range sas {
+		bound = append(bound, sa.Name)
+
+		if hasImagePullSecret(sa, secretName) {
+			continue
+		}
+
+		sa.ImagePullSecrets = append(sa.ImagePullSecrets, corev1.LocalObjectReference{
+			Name: secretName,
+		})
+
+		if err := r.Client.Update(ctx, sa, &client.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("update service account %s error: %w", sa.Name, err)
+		}
 	}
-	if err := r.Client.Get(ctx, namespacedName, hsc); err != nil {
-		// Explicitly check not found error
-		if apierr.IsNotFound(err) {
-			return nil, nil
+
+	return bound, nil
+}
+
+// unbindServiceAccounts removes the pull secret from the service accounts bound to it.
+func (r *Reconciler) unbindServiceAccounts(ctx context.Context, psb *goharborv1.PullSecretBinding) error {
+	secretName, ok := psb.Annotations[consts.AnnotationRobotSecretRef]
+	if !ok {
+		return nil
+	}
+
+	for _, name := range psb.Status.ServiceAccounts {
+		sa, err := r.getServiceAccount(ctx, psb.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("get service account error: %w", err)
 		}
 
-		return nil, err
+		if sa == nil || !hasImagePullSecret(sa, secretName) {
+			continue
+		}
+
+		secrets := make([]corev1.LocalObjectReference, 0, len(sa.ImagePullSecrets))
+
+		for _, ref := range sa.ImagePullSecrets {
+			if ref.Name != secretName {
+				secrets = append(secrets, ref)
+			}
+		}
+
+		sa.ImagePullSecrets = secrets
+
+		if err := r.Client.Update(ctx, sa, &client.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update service account %s error: %w", sa.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func hasImagePullSecret(sa *corev1.ServiceAccount, secretName string) bool {
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == secretName {
+			return true
+		}
 	}
 
-	return hsc, nil
+	return false
 }
 
 func (r *Reconciler) getServiceAccount(ctx context.Context, ns, name string) (*corev1.ServiceAccount, error) {
@@ -310,18 +444,124 @@ func (r *Reconciler) getServiceAccount(ctx context.Context, ns, name string) (*c
 	return sc, nil
 }
 
-func (r *Reconciler) createRegSec(ctx context.Context, namespace string, registry string, robot *models.Robot, psb *goharborv1.PullSecretBinding) (*corev1.Secret, error) {
+// ensureRegSec returns the registry secret of the binding with the credentials of the robot accounts of all the registries.
+// The secrets of the robot accounts are generated when they are missing from the registry secret or about to expire,
+// then the registry secret is updated in place.
+func (r *Reconciler) ensureRegSec(ctx context.Context, psb *goharborv1.PullSecretBinding, registries []*bindingRegistry) (*corev1.Secret, *rotation, error) {
+	var regSec *corev1.Secret
+
+	current := &secret.Object{
+		Auths: map[string]*secret.Auth{},
+	}
+
+	if name, ok := psb.Annotations[consts.AnnotationRobotSecretRef]; ok {
+		sec := &corev1.Secret{}
+
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: psb.Namespace, Name: name}, sec)
+		if err != nil && !apierr.IsNotFound(err) {
+			return nil, nil, err
+		}
+
+		if err == nil {
+			regSec = sec
+
+			if decoded, err := secret.Decode(sec.Data[datakey]); err != nil {
+				r.Log.Error(err, "invalid registry secret, generating the robot account secrets again", "secret", name)
+			} else {
+				current = decoded
+			}
+		}
+	}
+
+	authKeys, err := getAuthKeys(registries)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	auths := &secret.Object{
 		Auths: map[string]*secret.Auth{},
 	}
-	auths.Auths[registry] = &secret.Auth{
-		Username: robot.Name,
-		Password: robot.Secret,
-		Email:    fmt.Sprintf("%s@goharbor.io", robot.Name),
+
+	rotation := &rotation{}
+
+	for i, registry := range registries {
+		auth := r.rotateRobot(ctx, psb, registry, current.Auths[authKeys[i]], rotation)
+		if auth != nil {
+			auths.Auths[authKeys[i]] = auth
+		}
 	}
 
 	encoded := auths.Encode()
 
+	if regSec == nil {
+		regSec, err = r.createRegSec(ctx, psb.Namespace, encoded, psb)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Update binding
+		setAnnotation(psb, consts.AnnotationRobotSecretRef, regSec.Name)
+		if err := r.update(ctx, psb); err != nil {
+			return nil, nil, fmt.Errorf("update error: %w", err)
+		}
+
+		return regSec, rotation, nil
+	}
+
+	if !bytes.Equal(regSec.Data[datakey], encoded) {
+		// The pulls use the new credentials as soon as the secret is updated.
+		// The rotated secrets are lost if the update fails, so it is retried on conflict.
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			regSec.Data = map[string][]byte{
+				datakey: encoded,
+			}
+
+			err := r.Client.Update(ctx, regSec, &client.UpdateOptions{})
+			if apierr.IsConflict(err) {
+				if err := r.Client.Get(ctx, client.ObjectKeyFromObject(regSec), regSec); err != nil {
+					return err
+				}
+			}
+
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return regSec, rotation, nil
+}
+
+// getAuthKeys returns the keys of the credentials of the registries in the docker config,
+// the URL of the Harbor server or the project path when several projects share a server.
+func getAuthKeys(registries []*bindingRegistry) ([]string, error) {
+	servers := map[string]int{}
+	for _, registry := range registries {
+		servers[registry.Server.ServerURL]++
+	}
+
+	keys := make([]string, 0, len(registries))
+
+	for _, registry := range registries {
+		authKey := registry.Server.ServerURL
+
+		if servers[authKey] > 1 {
+			project, err := registry.Harbor.GetProjectByID(int32(parseIntID(registry.ProjectID)))
+			if err != nil {
+				return nil, fmt.Errorf("get project error: %w", err)
+			}
+
+			authKey = fmt.Sprintf("%s/%s", gostrings.TrimSuffix(authKey, "/"), project.Name)
+		}
+
+		keys = append(keys, authKey)
+	}
+
+	return keys, nil
+}
+
+func (r *Reconciler) createRegSec(ctx context.Context, namespace string, encoded []byte, psb *goharborv1.PullSecretBinding) (*corev1.Secret, error) {
 	regSec := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      strings.RandomName("regsecret"),
@@ -0,0 +1,453 @@
+package pullsecretbinding_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-openapi/strfmt"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/pullsecretbinding"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/registry/secret"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/goharbor/harbor-operator/pkg/utils/consts"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetAuthKeys(t *testing.T) {
+	shared, _ := newRegistry(t, "shared", "1", "1", map[string]interface{}{
+		"GET /api/v2.0/projects/1": &models.Project{ProjectID: 1, Name: "library"},
+		"GET /api/v2.0/projects/2": &models.Project{ProjectID: 2, Name: "team"},
+	})
+
+	team := *shared
+	team.ProjectID = "2"
+	team.RobotID = "2"
+
+	other, _ := newRegistry(t, "other", "1", "1", nil)
+
+	keys, err := pullsecretbinding.GetAuthKeys([]*pullsecretbinding.BindingRegistry{shared, &team, other})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		shared.Server.ServerURL + "/library",
+		shared.Server.ServerURL + "/team",
+		other.Server.ServerURL,
+	}, keys, "the projects sharing a server are keyed by their path")
+
+	keys, err = pullsecretbinding.GetAuthKeys([]*pullsecretbinding.BindingRegistry{shared})
+	require.NoError(t, err)
+	require.Equal(t, []string{shared.Server.ServerURL}, keys)
+
+	missing := team
+	missing.ProjectID = "3"
+
+	_, err = pullsecretbinding.GetAuthKeys([]*pullsecretbinding.BindingRegistry{shared, &missing})
+	require.Error(t, err)
+}
+
+func TestNeedsRotation(t *testing.T) {
+	psb := &goharborv1.PullSecretBinding{}
+	robot := &models.Robot{ID: 1, Name: "robot$ci", ExpiresAt: -1}
+	current := &secret.Auth{Username: "robot$ci", Password: "secret"}
+
+	for name, tc := range map[string]struct {
+		expiresAt    time.Time
+		rotateBefore time.Duration
+		current      *secret.Auth
+		rotate       bool
+	}{
+		"up to date": {
+			expiresAt: time.Now().Add(30 * 24 * time.Hour),
+			current:   current,
+		},
+		"never expires": {
+			current: current,
+		},
+		"about to expire": {
+			expiresAt: time.Now().Add(24 * time.Hour),
+			current:   current,
+			rotate:    true,
+		},
+		"rotated later": {
+			expiresAt:    time.Now().Add(24 * time.Hour),
+			rotateBefore: time.Hour,
+			current:      current,
+		},
+		"missing from the secret": {
+			rotate: true,
+		},
+		"other robot account": {
+			current: &secret.Auth{Username: "robot$other", Password: "secret"},
+			rotate:  true,
+		},
+		"no password": {
+			current: &secret.Auth{Username: "robot$ci"},
+			rotate:  true,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			psb := psb.DeepCopy()
+			if tc.rotateBefore != 0 {
+				psb.Spec.RotateBefore = &metav1.Duration{Duration: tc.rotateBefore}
+			}
+
+			robot := *robot
+			if !tc.expiresAt.IsZero() {
+				robot.ExpiresAt = tc.expiresAt.Unix()
+			}
+
+			require.Equal(t, tc.rotate, pullsecretbinding.NeedsRotation(psb, &robot, tc.current))
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	created := time.Now().Add(-29*24*time.Hour - time.Hour)
+
+	robot := &models.Robot{
+		ID:           1,
+		Name:         "robot$ci",
+		Duration:     30,
+		CreationTime: strfmt.DateTime(created),
+		ExpiresAt:    created.Add(30 * 24 * time.Hour).Unix(),
+	}
+
+	renewed := *robot
+	renewed.Duration = 60
+	renewed.ExpiresAt = created.Add(60 * 24 * time.Hour).Unix()
+
+	registry, requests := newRegistry(t, "harbor", "1", "1", map[string]interface{}{
+		"PUT /api/v2.0/robots/1":   nil,
+		"PATCH /api/v2.0/robots/1": &models.RobotSec{Secret: "new-secret"},
+		"GET /api/v2.0/robots/1":   &renewed,
+	})
+
+	robot, password, err := pullsecretbinding.Rotate(&goharborv1.PullSecretBinding{}, registry, robot)
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", password)
+	require.Equal(t, renewed.ExpiresAt, robot.ExpiresAt)
+
+	var update models.Robot
+	require.NoError(t, json.Unmarshal(requests["PUT /api/v2.0/robots/1"], &update))
+	require.Equal(t, int64(60), update.Duration, "renewed for the default duration from now")
+	require.Empty(t, update.Secret)
+}
+
+func TestRotateNeverExpires(t *testing.T) {
+	robot := &models.Robot{ID: 1, Name: "robot$ci", Duration: -1, ExpiresAt: -1}
+
+	registry, requests := newRegistry(t, "harbor", "1", "1", map[string]interface{}{
+		"PATCH /api/v2.0/robots/1": &models.RobotSec{Secret: "new-secret"},
+		"GET /api/v2.0/robots/1":   robot,
+	})
+
+	_, password, err := pullsecretbinding.Rotate(&goharborv1.PullSecretBinding{}, registry, robot)
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", password)
+
+	require.NotContains(t, requests, "PUT /api/v2.0/robots/1", "not renewed")
+}
+
+func TestRequeueAfter(t *testing.T) {
+	psb := &goharborv1.PullSecretBinding{}
+	require.Equal(t, 5*time.Minute, pullsecretbinding.RequeueAfter(psb), "never expires")
+
+	psb.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(30 * 24 * time.Hour)}
+	require.Equal(t, 5*time.Minute, pullsecretbinding.RequeueAfter(psb), "rotation later")
+
+	psb.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(72*time.Hour + 2*time.Minute)}
+	require.InDelta(t, 2*time.Minute, pullsecretbinding.RequeueAfter(psb), float64(time.Second), "rotation before the default 72h")
+
+	psb.Spec.RotateBefore = &metav1.Duration{Duration: time.Hour}
+	psb.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour + 3*time.Minute)}
+	require.InDelta(t, 3*time.Minute, pullsecretbinding.RequeueAfter(psb), float64(time.Second), "rotation before the configured delay")
+
+	psb.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	require.Equal(t, time.Minute, pullsecretbinding.RequeueAfter(psb), "expired")
+}
+
+func TestEnsureRegSec(t *testing.T) {
+	ctx := context.TODO()
+
+	expiresAt := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second)
+
+	library, _ := newRegistry(t, "library", "1", "1", map[string]interface{}{
+		"GET /api/v2.0/robots/1": &models.Robot{ID: 1, Name: "robot$library+pull", ExpiresAt: expiresAt.Unix()},
+	})
+
+	team, _ := newRegistry(t, "team", "2", "2", map[string]interface{}{
+		"GET /api/v2.0/robots/2":   &models.Robot{ID: 2, Name: "robot$team+pull", ExpiresAt: -1},
+		"PATCH /api/v2.0/robots/2": &models.RobotSec{Secret: "team-secret"},
+	})
+
+	regSec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "regsecret-abcde"},
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: (&secret.Object{Auths: map[string]*secret.Auth{
+				library.Server.ServerURL:      {Username: "robot$library+pull", Password: "library-secret"},
+				"https://removed.example.com": {Username: "robot$removed+pull", Password: "removed-secret"},
+			}}).Encode(),
+		},
+	}
+
+	psb := &goharborv1.PullSecretBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "web",
+			Name:      "binding",
+			Annotations: map[string]string{
+				consts.AnnotationRobotSecretRef: regSec.Name,
+			},
+		},
+	}
+
+	r := newReconciler(t, regSec, psb)
+
+	_, rotation, err := pullsecretbinding.EnsureRegSec(r, ctx, psb, []*pullsecretbinding.BindingRegistry{library, team})
+	require.NoError(t, err)
+
+	updated := &corev1.Secret{}
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "web", Name: regSec.Name}, updated))
+
+	auths, err := secret.Decode(updated.Data[corev1.DockerConfigJsonKey])
+	require.NoError(t, err)
+	require.Len(t, auths.Auths, 2, "the credentials of the removed registries are dropped")
+	require.Equal(t, "library-secret", auths.Auths[library.Server.ServerURL].Password, "kept until about to expire")
+	require.Equal(t, "robot$team+pull", auths.Auths[team.Server.ServerURL].Username)
+	require.Equal(t, "team-secret", auths.Auths[team.Server.ServerURL].Password, "generated when missing")
+
+	pullsecretbinding.SetRotationStatus(rotation, &psb.Status)
+
+	require.Len(t, psb.Status.Robots, 2)
+	require.NotNil(t, psb.Status.LastRotationTime)
+	require.Equal(t, expiresAt, psb.Status.ExpiresAt.Time.Local())
+	require.True(t, meta.IsStatusConditionTrue(psb.Status.Conditions, goharborv1.PullSecretBindingConditionSecretRotated))
+}
+
+func TestEnsureRegSecRotationFailed(t *testing.T) {
+	ctx := context.TODO()
+
+	registry, _ := newRegistry(t, "library", "1", "1", nil)
+
+	psb := &goharborv1.PullSecretBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "binding"},
+		Status: goharborv1.PullSecretBindingStatus{
+			Robots: []goharborv1.PullSecretBindingRobot{{
+				HarborServerConfig: "library",
+				RobotID:            "1",
+				RobotName:          "robot$library+pull",
+			}},
+		},
+	}
+
+	r := newReconciler(t, psb)
+
+	regSec, rotation, err := pullsecretbinding.EnsureRegSec(r, ctx, psb, []*pullsecretbinding.BindingRegistry{registry})
+	require.NoError(t, err)
+	require.Equal(t, regSec.Name, psb.Annotations[consts.AnnotationRobotSecretRef], "created anyway")
+
+	pullsecretbinding.SetRotationStatus(rotation, &psb.Status)
+
+	require.Len(t, psb.Status.Robots, 1, "the known robot accounts are kept")
+	require.Nil(t, psb.Status.LastRotationTime)
+
+	condition := meta.FindStatusCondition(psb.Status.Conditions, goharborv1.PullSecretBindingConditionSecretRotated)
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, "RotationFailed", condition.Reason)
+}
+
+func TestEnsureRegSecUpdateFailed(t *testing.T) {
+	ctx := context.TODO()
+
+	registry, requests := newRegistry(t, "team", "2", "2", map[string]interface{}{
+		"GET /api/v2.0/robots/2":   &models.Robot{ID: 2, Name: "robot$team+pull", ExpiresAt: -1},
+		"PATCH /api/v2.0/robots/2": &models.RobotSec{Secret: "new-secret"},
+	})
+
+	regSec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "regsecret-abcde"},
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: (&secret.Object{Auths: map[string]*secret.Auth{}}).Encode(),
+		},
+	}
+
+	psb := &goharborv1.PullSecretBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "web",
+			Name:      "binding",
+			Annotations: map[string]string{
+				consts.AnnotationRobotSecretRef: regSec.Name,
+			},
+		},
+	}
+
+	r := newReconciler(t, regSec, psb)
+	c := r.Client
+	r.Client = &failingClient{Client: c, failures: -1}
+
+	_, _, err := pullsecretbinding.EnsureRegSec(r, ctx, psb, []*pullsecretbinding.BindingRegistry{registry})
+	require.Error(t, err)
+	require.Contains(t, requests, "PATCH /api/v2.0/robots/2")
+
+	stored := &goharborv1.PullSecretBinding{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "web", Name: "binding"}, stored))
+	require.Equal(t, []string{"team/2"}, stored.Status.PendingRotations, "recorded before the secret is generated")
+
+	// The pull secret holds a credential, but not the last generated secret
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "web", Name: regSec.Name}, regSec))
+	regSec.Data[corev1.DockerConfigJsonKey] = (&secret.Object{Auths: map[string]*secret.Auth{
+		registry.Server.ServerURL: {Username: "robot$team+pull", Password: "lost-secret"},
+	}}).Encode()
+	require.NoError(t, c.Update(ctx, regSec))
+
+	delete(requests, "PATCH /api/v2.0/robots/2")
+
+	r.Client = c
+
+	_, rotation, err := pullsecretbinding.EnsureRegSec(r, ctx, stored, []*pullsecretbinding.BindingRegistry{registry})
+	require.NoError(t, err)
+	require.Contains(t, requests, "PATCH /api/v2.0/robots/2", "rotated again")
+
+	updated := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "web", Name: regSec.Name}, updated))
+
+	auths, err := secret.Decode(updated.Data[corev1.DockerConfigJsonKey])
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", auths.Auths[registry.Server.ServerURL].Password)
+
+	pullsecretbinding.SetRotationStatus(rotation, &stored.Status)
+	require.Empty(t, stored.Status.PendingRotations)
+}
+
+func TestEnsureRegSecUpdateConflict(t *testing.T) {
+	ctx := context.TODO()
+
+	registry, _ := newRegistry(t, "team", "2", "2", map[string]interface{}{
+		"GET /api/v2.0/robots/2":   &models.Robot{ID: 2, Name: "robot$team+pull", ExpiresAt: -1},
+		"PATCH /api/v2.0/robots/2": &models.RobotSec{Secret: "new-secret"},
+	})
+
+	regSec := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "regsecret-abcde"}}
+
+	psb := &goharborv1.PullSecretBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "web",
+			Name:      "binding",
+			Annotations: map[string]string{
+				consts.AnnotationRobotSecretRef: regSec.Name,
+			},
+		},
+	}
+
+	r := newReconciler(t, regSec, psb)
+	r.Client = &failingClient{Client: r.Client, failures: 1, err: apierrors.NewConflict(corev1.Resource("secrets"), regSec.Name, nil)}
+
+	_, _, err := pullsecretbinding.EnsureRegSec(r, ctx, psb, []*pullsecretbinding.BindingRegistry{registry})
+	require.NoError(t, err)
+
+	updated := &corev1.Secret{}
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "web", Name: regSec.Name}, updated))
+
+	auths, err := secret.Decode(updated.Data[corev1.DockerConfigJsonKey])
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", auths.Auths[registry.Server.ServerURL].Password)
+}
+
+// failingClient fails the first updates of the secrets, all of them if failures is negative.
+type failingClient struct {
+	client.Client
+	failures int
+	err      error
+}
+
+func (c *failingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if _, ok := obj.(*corev1.Secret); ok && c.failures != 0 {
+		c.failures--
+
+		if c.err != nil {
+			return c.err
+		}
+
+		return apierrors.NewInternalError(errors.New("update failure"))
+	}
+
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func newReconciler(t *testing.T, objects ...client.Object) *pullsecretbinding.Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	return &pullsecretbinding.Reconciler{
+		Controller: &commonCtrl.Controller{Log: logr.Discard()},
+		Client:     fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:     scheme,
+	}
+}
+
+// newRegistry returns a registry of the binding served by a Harbor API serving the given payloads by method and path,
+// other requests are not found. The bodies of the requests are returned by method and path.
+func newRegistry(t *testing.T, hsc, projectID, robotID string, payloads map[string]interface{}) (*pullsecretbinding.BindingRegistry, map[string][]byte) {
+	requests := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		requests[key] = body
+
+		payload, ok := payloads[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if payload == nil {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(payload))
+	}))
+
+	t.Cleanup(server.Close)
+
+	harborServer := model.NewHarborServer(server.URL, "admin", "Harbor12345", false)
+
+	harbor, err := v2.NewWithServer(harborServer)
+	require.NoError(t, err)
+
+	return &pullsecretbinding.BindingRegistry{
+		PullSecretBindingRegistry: goharborv1.PullSecretBindingRegistry{
+			HarborServerConfig: hsc,
+			ProjectID:          projectID,
+			RobotID:            robotID,
+		},
+		Server: harborServer,
+		Harbor: harbor,
+	}, requests
+}
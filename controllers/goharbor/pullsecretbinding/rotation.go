@@ -0,0 +1,269 @@
+package pullsecretbinding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/registry/secret"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	defaultRotateBefore = 72 * time.Hour
+	// defaultRenewDuration is the validity in days the expiring robot accounts are renewed for.
+	defaultRenewDuration = 30
+	// robotDurationUnit is the unit of the duration of the robot accounts.
+	robotDurationUnit = 24 * time.Hour
+	// neverExpires is the duration and the expiration of the robot accounts which never expire.
+	neverExpires = -1
+	// minRequeueAfter avoids hammering Harbor when the rotation is overdue.
+	minRequeueAfter = time.Minute
+)
+
+// rotation collects the robot accounts of the pull secret and the failures of their rotation.
+type rotation struct {
+	robots  []goharborv1.PullSecretBindingRobot
+	rotated bool
+	errs    []error
+	err     error
+}
+
+// rotateRobot returns the credential of the robot account of the registry.
+// The secret of the robot account is generated again when it is missing from the current credential or about to expire.
+// The secret is generated again as well when a previous rotation may have been lost before the pull secret was updated.
+// The current credential is returned when the rotation fails.
+func (r *Reconciler) rotateRobot(ctx context.Context, psb *goharborv1.PullSecretBinding, registry *bindingRegistry, current *secret.Auth, rot *rotation) *secret.Auth {
+	robotID := parseIntID(registry.RobotID)
+
+	robot, err := registry.Harbor.GetRobot(robotID)
+	if err == nil && robot == nil {
+		err = errors.Errorf("robot account %d not found", robotID)
+	}
+
+	if err != nil {
+		rot.fail(registry, err)
+
+		return current
+	}
+
+	pending := strings.ContainsString(psb.Status.PendingRotations, pendingRotationKey(registry.HarborServerConfig, registry.RobotID))
+
+	if !pending && !needsRotation(psb, robot, current) {
+		rot.add(registry, robot)
+
+		return current
+	}
+
+	r.Log.Info("Rotating robot account secret", "binding", psb.Name, "robot", robot.Name, "pending", pending)
+
+	if err := r.markPendingRotation(ctx, psb, registry); err != nil {
+		rot.fail(registry, errors.Wrap(err, "cannot record the pending rotation"))
+
+		return current
+	}
+
+	robot, password, err := rotate(psb, registry, robot)
+	if err != nil {
+		rot.fail(registry, err)
+
+		return current
+	}
+
+	rot.add(registry, robot)
+	rot.rotated = true
+
+	return &secret.Auth{
+		Username: robot.Name,
+		Password: password,
+		Email:    fmt.Sprintf("%s@goharbor.io", robot.Name),
+	}
+}
+
+// markPendingRotation records the rotation of the robot account in the status before its secret is generated again:
+// the previous secret is invalidated at once, and the new one is lost if the pull secret cannot be updated with it.
+func (r *Reconciler) markPendingRotation(ctx context.Context, psb *goharborv1.PullSecretBinding, registry *bindingRegistry) error {
+	key := pendingRotationKey(registry.HarborServerConfig, registry.RobotID)
+	if strings.ContainsString(psb.Status.PendingRotations, key) {
+		return nil
+	}
+
+	psb.Status.PendingRotations = append(psb.Status.PendingRotations, key)
+
+	return r.Status().Update(ctx, psb)
+}
+
+func pendingRotationKey(harborServerConfig, robotID string) string {
+	return fmt.Sprintf("%s/%s", harborServerConfig, robotID)
+}
+
+// needsRotation checks whether the secret of the robot account has to be generated again,
+// because the robot account is about to expire or the pull secret does not hold it.
+func needsRotation(psb *goharborv1.PullSecretBinding, robot *models.Robot, current *secret.Auth) bool {
+	if current == nil || current.Username != robot.Name || current.Password == "" {
+		return true
+	}
+
+	return robot.ExpiresAt > 0 && time.Until(time.Unix(robot.ExpiresAt, 0)) < rotateBefore(psb)
+}
+
+// rotate renews the robot account if it expires, then generates a new secret.
+func rotate(psb *goharborv1.PullSecretBinding, registry *bindingRegistry, robot *models.Robot) (*models.Robot, string, error) {
+	if robot.ExpiresAt > 0 && time.Until(time.Unix(robot.ExpiresAt, 0)) < rotateBefore(psb) {
+		update := *robot
+		update.Secret = ""
+		update.Duration = neverExpires
+
+		if duration := renewDuration(psb); duration > 0 {
+			// The expiration is computed from the creation time of the robot account
+			age := time.Since(time.Time(robot.CreationTime))
+			update.Duration = int64(math.Ceil(age.Hours()/robotDurationUnit.Hours())) + duration
+		}
+
+		if err := registry.Harbor.UpdateRobot(robot.ID, &update); err != nil {
+			return nil, "", errors.Wrap(err, "cannot renew robot account")
+		}
+	}
+
+	password, err := registry.Harbor.RefreshRobotSecret(robot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	renewed, err := registry.Harbor.GetRobot(robot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if renewed == nil {
+		return nil, "", errors.Errorf("robot account %d not found after rotation", robot.ID)
+	}
+
+	return renewed, password, nil
+}
+
+func (rot *rotation) add(registry *bindingRegistry, robot *models.Robot) {
+	status := goharborv1.PullSecretBindingRobot{
+		HarborServerConfig: registry.HarborServerConfig,
+		RobotID:            registry.RobotID,
+		RobotName:          robot.Name,
+	}
+
+	if robot.ExpiresAt > 0 {
+		status.ExpiresAt = &metav1.Time{Time: time.Unix(robot.ExpiresAt, 0)}
+	}
+
+	rot.robots = append(rot.robots, status)
+}
+
+func (rot *rotation) fail(registry *bindingRegistry, err error) {
+	rot.errs = append(rot.errs, fmt.Errorf("robot account %s of %s: %w", registry.RobotID, registry.HarborServerConfig, err))
+	rot.err = kerrors.NewAggregate(rot.errs)
+}
+
+// setStatus records the robot accounts, their earliest expiration and the result of the rotation in the status.
+// It is called once the pull secret is updated: the pending rotations of the robot accounts it holds are complete.
+func (rot *rotation) setStatus(status *goharborv1.PullSecretBindingStatus) {
+	var pending []string
+
+	for _, key := range status.PendingRotations {
+		if !rot.hasKey(key) {
+			pending = append(pending, key)
+		}
+	}
+
+	status.PendingRotations = pending
+
+	if rot.err != nil {
+		// Keep the known robot accounts of the failed rotations
+		for _, robot := range status.Robots {
+			if !rot.has(robot) {
+				rot.robots = append(rot.robots, robot)
+			}
+		}
+	}
+
+	status.Robots = rot.robots
+	status.ExpiresAt = nil
+
+	for _, robot := range rot.robots {
+		if robot.ExpiresAt != nil && (status.ExpiresAt == nil || robot.ExpiresAt.Before(status.ExpiresAt)) {
+			status.ExpiresAt = robot.ExpiresAt.DeepCopy()
+		}
+	}
+
+	if rot.rotated {
+		now := metav1.Now()
+		status.LastRotationTime = &now
+	}
+
+	condition := metav1.Condition{
+		Type:    goharborv1.PullSecretBindingConditionSecretRotated,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SecretUpToDate",
+		Message: "The secrets of the robot accounts are up to date",
+	}
+
+	if rot.err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RotationFailed"
+		condition.Message = rot.err.Error()
+	}
+
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+func (rot *rotation) has(robot goharborv1.PullSecretBindingRobot) bool {
+	return rot.hasKey(pendingRotationKey(robot.HarborServerConfig, robot.RobotID))
+}
+
+func (rot *rotation) hasKey(key string) bool {
+	for _, r := range rot.robots {
+		if pendingRotationKey(r.HarborServerConfig, r.RobotID) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requeueAfter returns the time until the rotation of the first expiring robot account, at most the default cycle.
+func requeueAfter(psb *goharborv1.PullSecretBinding) time.Duration {
+	if psb.Status.ExpiresAt == nil {
+		return defaultCycle
+	}
+
+	untilRotation := time.Until(psb.Status.ExpiresAt.Add(-rotateBefore(psb)))
+	if untilRotation < minRequeueAfter {
+		return minRequeueAfter
+	}
+
+	if untilRotation > defaultCycle {
+		return defaultCycle
+	}
+
+	return untilRotation
+}
+
+func rotateBefore(psb *goharborv1.PullSecretBinding) time.Duration {
+	if psb.Spec.RotateBefore == nil {
+		return defaultRotateBefore
+	}
+
+	return psb.Spec.RotateBefore.Duration
+}
+
+func renewDuration(psb *goharborv1.PullSecretBinding) int64 {
+	if psb.Spec.RenewDuration == 0 {
+		return defaultRenewDuration
+	}
+
+	return psb.Spec.RenewDuration
+}
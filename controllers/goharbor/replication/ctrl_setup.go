@@ -0,0 +1,156 @@
+package replication
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/builder"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	policyFinalizerID            string = "harborreplicationpolicy.goharbor.io/finalizer"
+	endpointFinalizerID          string = "harborregistryendpoint.goharbor.io/finalizer"
+	defaultRequeueAfterMinutes   int    = 5
+	requeueAfterMinutesConfigKey string = "requeue-after-minutes"
+)
+
+// New HarborReplicationPolicy reconciler.
+func New(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &Reconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborReplicationPolicy, nil, configStore)
+
+	return r, nil
+}
+
+// Reconciler reconciles a replication policy cr.
+type Reconciler struct {
+	*commonCtrl.Controller
+	Scheme              *runtime.Scheme
+	RequeueAfterMinutes int
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborreplicationpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborreplicationpolicies/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborreplicationpolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborserverconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	requeueAfterMinutes, err := config.GetInt(r.ConfigStore, requeueAfterMinutesConfigKey, defaultRequeueAfterMinutes)
+	if err != nil {
+		return errors.Wrap(err, "cannot get requeue after config value")
+	}
+
+	r.RequeueAfterMinutes = requeueAfterMinutes
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1.HarborReplicationPolicy{}, ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Policies waiting for their registry endpoints are reconciled as soon as the endpoints are applied
+		Watches(&source.Kind{Type: &goharborv1.HarborRegistryEndpoint{}}, handler.EnqueueRequestsFromMapFunc(r.policiesOfEndpoint)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		Complete(r)
+}
+
+// policiesOfEndpoint returns the requests of the replication policies referring the registry endpoint.
+func (r *Reconciler) policiesOfEndpoint(obj client.Object) []reconcile.Request {
+	policies := &goharborv1.HarborReplicationPolicyList{}
+	if err := r.Client.List(context.Background(), policies, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "cannot list replication policies", "namespace", obj.GetNamespace())
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, policy := range policies.Items {
+		if policy.Spec.SourceRegistryRef == obj.GetName() || policy.Spec.DestinationRegistryRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: policy.GetNamespace(), Name: policy.GetName()},
+			})
+		}
+	}
+
+	return requests
+}
+
+func (r *Reconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborReplicationPolicy"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
+
+// NewRegistryEndpoint HarborRegistryEndpoint reconciler.
+func NewRegistryEndpoint(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &RegistryEndpointReconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborRegistryEndpoint, nil, configStore)
+
+	return r, nil
+}
+
+// RegistryEndpointReconciler reconciles a registry endpoint cr.
+type RegistryEndpointReconciler struct {
+	*commonCtrl.Controller
+	Scheme              *runtime.Scheme
+	RequeueAfterMinutes int
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborregistryendpoints/finalizers,verbs=update
+
+func (r *RegistryEndpointReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	requeueAfterMinutes, err := config.GetInt(r.ConfigStore, requeueAfterMinutesConfigKey, defaultRequeueAfterMinutes)
+	if err != nil {
+		return errors.Wrap(err, "cannot get requeue after config value")
+	}
+
+	r.RequeueAfterMinutes = requeueAfterMinutes
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	return builder.ControllerManagedBy(mgr).
+		For(&goharborv1.HarborRegistryEndpoint{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
+
+func (r *RegistryEndpointReconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborRegistryEndpoint"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
@@ -0,0 +1,6 @@
+package replication
+
+var (
+	FindReplicationPolicy = findReplicationPolicy
+	FindRegistry          = findRegistry
+)
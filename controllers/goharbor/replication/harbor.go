@@ -0,0 +1,41 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harborClient "github.com/goharbor/harbor-operator/pkg/rest"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ErrHarborCfgNotFound         = errors.New("harbor server configuration not found")
+	ErrUnexpectedHarborCfgStatus = errors.New("status of Harbor server referred in configuration %s is unexpected")
+)
+
+// getHarborClient returns a client of the harbor instance described by the named Harbor server configuration of the namespace.
+func getHarborClient(ctx context.Context, c client.Client, namespace, harborServerConfigName string) (*v2.Client, error) {
+	harborCfg, err := harborClient.GetHarborServerConfiguration(ctx, c, namespace, harborServerConfigName)
+	if err != nil {
+		return nil, fmt.Errorf("error finding harborCfg: %w", err)
+	}
+
+	if harborCfg == nil {
+		return nil, fmt.Errorf("%w: %s", ErrHarborCfgNotFound, harborServerConfigName)
+	}
+
+	if harborCfg.Status.Status == goharborv1.HarborServerConfigurationStatusUnknown || harborCfg.Status.Status == goharborv1.HarborServerConfigurationStatusFail {
+		return nil, fmt.Errorf("%w harborCfg %s with %s", ErrUnexpectedHarborCfgStatus, harborCfg.Name, harborCfg.Status.Status)
+	}
+
+	// Create harbor client
+	harborv2, err := harborClient.CreateHarborV2Client(ctx, c, harborCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return harborv2.WithContext(ctx), nil
+}
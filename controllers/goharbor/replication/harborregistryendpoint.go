@@ -0,0 +1,224 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var ErrRegistryConflict = errors.New("registry endpoint already exists in Harbor and is not managed by the resource")
+
+// Reconcile does registry endpoint reconcile.
+func (r *RegistryEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) { //nolint:funlen
+	log := r.Log.WithValues("resource", req.NamespacedName)
+	log.Info("Start reconciling")
+
+	hre := &goharborv1.HarborRegistryEndpoint{}
+	if err = r.Client.Get(ctx, req.NamespacedName, hre); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor registry endpoint %v", req)
+	}
+
+	hre.Status.Status = goharborv1.HarborReplicationStatusUnknown
+
+	defer func() {
+		if err != nil {
+			hre.Status.Status = goharborv1.HarborReplicationStatusFail
+			hre.Status.Message = err.Error()
+		} else {
+			hre.Status.Status = goharborv1.HarborReplicationStatusReady
+			hre.Status.Reason = ""
+			hre.Status.Message = ""
+			now := metav1.Now()
+			hre.Status.LastApplyTime = &now
+		}
+
+		log.Info("Reconcile end", "result", res, "error", err, "updateStatusError", r.Client.Status().Update(ctx, hre))
+	}()
+
+	harbor, err := getHarborClient(ctx, r.Client, hre.GetNamespace(), hre.Spec.HarborServerConfig)
+	if err != nil {
+		err = errors.Wrapf(err, "error get harbor client")
+		hre.Status.Reason = "HarborClientError"
+
+		return ctrl.Result{}, err
+	}
+
+	if !hre.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(hre, endpointFinalizerID) {
+			if hre.Status.RegistryID > 0 {
+				if err = harbor.DeleteRegistry(hre.Status.RegistryID); err != nil {
+					hre.Status.Reason = "DeleteRegistryError"
+					// Harbor refuses to delete the endpoints still used by replication policies,
+					// return with error so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(hre, endpointFinalizerID)
+
+			if err = r.Update(ctx, hre); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(hre, endpointFinalizerID) {
+		controllerutil.AddFinalizer(hre, endpointFinalizerID)
+
+		if err = r.Update(ctx, hre); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	credential, err := r.getCredential(ctx, hre)
+	if err != nil {
+		err = errors.Wrapf(err, "error get registry credential")
+		hre.Status.Reason = "CredentialError"
+
+		return ctrl.Result{}, err
+	}
+
+	existing, err := findRegistry(harbor, hre)
+	if err != nil {
+		hre.Status.Reason = "FindRegistryError"
+		if errors.Is(err, ErrRegistryConflict) {
+			hre.Status.Reason = "RegistryConflict"
+		}
+
+		err = errors.Wrapf(err, "error finding existing harbor registry")
+
+		return ctrl.Result{}, err
+	}
+
+	if existing == nil {
+		id, err := harbor.CreateRegistry(&models.Registry{
+			Name:        hre.GetRegistryName(),
+			Description: hre.Spec.Description,
+			Type:        hre.Spec.Type,
+			URL:         hre.Spec.URL,
+			Insecure:    hre.Spec.Insecure,
+			Credential:  credential,
+		})
+		if err != nil {
+			hre.Status.Reason = "CreateRegistryError"
+
+			return ctrl.Result{}, err
+		}
+
+		hre.Status.RegistryID = id
+	} else {
+		hre.Status.RegistryID = existing.ID
+
+		if err = harbor.UpdateRegistry(existing.ID, registryUpdate(hre, credential)); err != nil {
+			hre.Status.Reason = "UpdateRegistryError"
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	applied, err := harbor.GetRegistry(hre.Status.RegistryID)
+	if err != nil {
+		hre.Status.Reason = "GetRegistryError"
+
+		return ctrl.Result{}, err
+	}
+
+	if applied != nil {
+		hre.Status.Health = applied.Status
+	}
+
+	log.Info("Reconcile is completed")
+
+	return ctrl.Result{RequeueAfter: time.Minute * time.Duration(r.RequeueAfterMinutes)}, nil
+}
+
+// findRegistry returns the registry endpoint previously created for the resource, tracked by its ID.
+// A registry endpoint with the same name not created by the resource is never adopted.
+func findRegistry(harbor *v2.Client, hre *goharborv1.HarborRegistryEndpoint) (*models.Registry, error) {
+	if hre.Status.RegistryID > 0 {
+		reg, err := harbor.GetRegistry(hre.Status.RegistryID)
+		if err != nil || reg != nil {
+			return reg, err
+		}
+	}
+
+	reg, err := harbor.GetRegistryByName(hre.GetRegistryName())
+	if err != nil {
+		return nil, err
+	}
+
+	if reg != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRegistryConflict, hre.GetRegistryName())
+	}
+
+	return nil, nil
+}
+
+// getCredential reads the credential of the registry from the referred secret.
+// A nil credential is returned for anonymous access.
+func (r *RegistryEndpointReconciler) getCredential(ctx context.Context, hre *goharborv1.HarborRegistryEndpoint) (*models.RegistryCredential, error) {
+	if hre.Spec.CredentialRef == "" {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: hre.GetNamespace(), Name: hre.Spec.CredentialRef}, secret); err != nil {
+		return nil, err
+	}
+
+	accessKey, accessSecret, err := model.GetCredential(secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "secret %s", hre.Spec.CredentialRef)
+	}
+
+	return &models.RegistryCredential{
+		Type:         hre.Spec.CredentialType,
+		AccessKey:    accessKey,
+		AccessSecret: accessSecret,
+	}, nil
+}
+
+func registryUpdate(hre *goharborv1.HarborRegistryEndpoint, credential *models.RegistryCredential) *models.RegistryUpdate {
+	name := hre.GetRegistryName()
+	update := &models.RegistryUpdate{
+		Name:        &name,
+		Description: &hre.Spec.Description,
+		URL:         &hre.Spec.URL,
+		Insecure:    &hre.Spec.Insecure,
+	}
+
+	// Empty keys switch the registry endpoint to anonymous access
+	var accessKey, accessSecret string
+
+	credentialType := hre.Spec.CredentialType
+	if credential != nil {
+		accessKey, accessSecret, credentialType = credential.AccessKey, credential.AccessSecret, credential.Type
+	}
+
+	update.AccessKey = &accessKey
+	update.AccessSecret = &accessSecret
+	update.CredentialType = &credentialType
+
+	return update
+}
@@ -0,0 +1,288 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var (
+	ErrRegistryEndpointNotReady  = errors.New("registry endpoint is not ready")
+	ErrReplicationPolicyConflict = errors.New("replication policy already exists in Harbor and is not managed by the resource")
+)
+
+// Reconcile does replication policy reconcile.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) { //nolint:funlen
+	log := r.Log.WithValues("resource", req.NamespacedName)
+	log.Info("Start reconciling")
+
+	hrp := &goharborv1.HarborReplicationPolicy{}
+	if err = r.Client.Get(ctx, req.NamespacedName, hrp); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor replication policy %v", req)
+	}
+
+	hrp.Status.Status = goharborv1.HarborReplicationStatusUnknown
+
+	defer func() {
+		if err != nil {
+			hrp.Status.Status = goharborv1.HarborReplicationStatusFail
+			hrp.Status.Message = err.Error()
+		} else {
+			hrp.Status.Status = goharborv1.HarborReplicationStatusReady
+			hrp.Status.Reason = ""
+			hrp.Status.Message = ""
+			now := metav1.Now()
+			hrp.Status.LastApplyTime = &now
+		}
+
+		log.Info("Reconcile end", "result", res, "error", err, "updateStatusError", r.Client.Status().Update(ctx, hrp))
+	}()
+
+	harbor, err := getHarborClient(ctx, r.Client, hrp.GetNamespace(), hrp.Spec.HarborServerConfig)
+	if err != nil {
+		err = errors.Wrapf(err, "error get harbor client")
+		hrp.Status.Reason = "HarborClientError"
+
+		return ctrl.Result{}, err
+	}
+
+	if !hrp.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is being deleted
+		if controllerutil.ContainsFinalizer(hrp, policyFinalizerID) {
+			if hrp.Status.PolicyID > 0 {
+				if err = harbor.DeleteReplicationPolicy(hrp.Status.PolicyID); err != nil {
+					hrp.Status.Reason = "DeleteReplicationPolicyError"
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(hrp, policyFinalizerID)
+
+			if err = r.Update(ctx, hrp); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(hrp, policyFinalizerID) {
+		controllerutil.AddFinalizer(hrp, policyFinalizerID)
+
+		if err = r.Update(ctx, hrp); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	policy, err := r.toReplicationPolicy(ctx, hrp)
+	if err != nil {
+		hrp.Status.Reason = "RegistryEndpointError"
+
+		return ctrl.Result{}, err
+	}
+
+	existing, err := findReplicationPolicy(harbor, hrp)
+	if err != nil {
+		hrp.Status.Reason = "FindReplicationPolicyError"
+		if errors.Is(err, ErrReplicationPolicyConflict) {
+			hrp.Status.Reason = "ReplicationPolicyConflict"
+		}
+
+		err = errors.Wrapf(err, "error finding existing harbor replication policy")
+
+		return ctrl.Result{}, err
+	}
+
+	if existing == nil {
+		id, err := harbor.CreateReplicationPolicy(policy)
+		if err != nil {
+			hrp.Status.Reason = "CreateReplicationPolicyError"
+
+			return ctrl.Result{}, err
+		}
+
+		hrp.Status.PolicyID = id
+	} else {
+		hrp.Status.PolicyID = existing.ID
+		policy.ID = existing.ID
+
+		if err = harbor.UpdateReplicationPolicy(existing.ID, policy); err != nil {
+			hrp.Status.Reason = "UpdateReplicationPolicyError"
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	execution, err := harbor.GetLastReplicationExecution(hrp.Status.PolicyID)
+	if err != nil {
+		hrp.Status.Reason = "GetReplicationExecutionError"
+
+		return ctrl.Result{}, err
+	}
+
+	hrp.Status.LastExecution = toReplicationExecution(execution)
+
+	log.Info("Reconcile is completed")
+
+	return ctrl.Result{RequeueAfter: time.Minute * time.Duration(r.RequeueAfterMinutes)}, nil
+}
+
+// findReplicationPolicy returns the replication policy previously created for the resource, tracked by its ID.
+// A policy with the same name not created by the resource is never adopted, it may be managed by another resource.
+func findReplicationPolicy(harbor *v2.Client, hrp *goharborv1.HarborReplicationPolicy) (*models.ReplicationPolicy, error) {
+	if hrp.Status.PolicyID > 0 {
+		policy, err := harbor.GetReplicationPolicy(hrp.Status.PolicyID)
+		if err != nil || policy != nil {
+			return policy, err
+		}
+	}
+
+	policy, err := harbor.GetReplicationPolicyByName(hrp.GetPolicyName())
+	if err != nil {
+		return nil, err
+	}
+
+	if policy != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReplicationPolicyConflict, hrp.GetPolicyName())
+	}
+
+	return nil, nil
+}
+
+// getRegistry returns the registry of the named HarborRegistryEndpoint, or the local Harbor if the name is empty.
+func (r *Reconciler) getRegistry(ctx context.Context, hrp *goharborv1.HarborReplicationPolicy, name string) (*models.Registry, error) {
+	if name == "" {
+		// ID 0 is the local Harbor
+		return &models.Registry{}, nil
+	}
+
+	hre := &goharborv1.HarborRegistryEndpoint{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: hrp.GetNamespace(), Name: name}, hre); err != nil {
+		return nil, errors.Wrapf(err, "error get harbor registry endpoint %s", name)
+	}
+
+	if hre.Spec.HarborServerConfig != hrp.Spec.HarborServerConfig {
+		return nil, errors.Errorf("registry endpoint %s is managed by the harbor server configuration %s", name, hre.Spec.HarborServerConfig)
+	}
+
+	if hre.Status.Status != goharborv1.HarborReplicationStatusReady || hre.Status.RegistryID <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRegistryEndpointNotReady, name)
+	}
+
+	return &models.Registry{ID: hre.Status.RegistryID}, nil
+}
+
+// toReplicationPolicy converts the resource to a Harbor replication policy.
+func (r *Reconciler) toReplicationPolicy(ctx context.Context, hrp *goharborv1.HarborReplicationPolicy) (*models.ReplicationPolicy, error) {
+	src, err := r.getRegistry(ctx, hrp, hrp.Spec.SourceRegistryRef)
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := r.getRegistry(ctx, hrp, hrp.Spec.DestinationRegistryRef)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*models.ReplicationFilter, 0, len(hrp.Spec.Filters))
+
+	for _, filter := range hrp.Spec.Filters {
+		f := &models.ReplicationFilter{
+			Type:       filter.Type,
+			Value:      filter.Value,
+			Decoration: filter.Decoration,
+		}
+
+		if filter.Type == goharborv1.ReplicationFilterLabel {
+			var labels []string
+
+			for _, label := range strings.Split(filter.Value, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					labels = append(labels, label)
+				}
+			}
+
+			f.Value = labels
+		}
+
+		filters = append(filters, f)
+	}
+
+	trigger := &models.ReplicationTrigger{
+		Type: hrp.Spec.Trigger.Type,
+	}
+
+	if trigger.Type == "" {
+		trigger.Type = goharborv1.ReplicationTriggerManual
+	}
+
+	if trigger.Type == goharborv1.ReplicationTriggerScheduled {
+		trigger.TriggerSettings = &models.ReplicationTriggerSettings{
+			Cron: hrp.Spec.Trigger.Cron,
+		}
+	}
+
+	return &models.ReplicationPolicy{
+		Name:                      hrp.GetPolicyName(),
+		Description:               hrp.Spec.Description,
+		SrcRegistry:               src,
+		DestRegistry:              dest,
+		DestNamespace:             hrp.Spec.DestinationNamespace,
+		DestNamespaceReplaceCount: hrp.Spec.DestinationNamespaceReplaceCount,
+		Filters:                   filters,
+		Trigger:                   trigger,
+		Override:                  hrp.Spec.Override,
+		ReplicateDeletion:         hrp.Spec.ReplicateDeletion,
+		Enabled:                   hrp.Spec.Enabled == nil || *hrp.Spec.Enabled,
+		Speed:                     hrp.Spec.Speed,
+		CopyByChunk:               hrp.Spec.CopyByChunk,
+	}, nil
+}
+
+// toReplicationExecution converts the Harbor replication execution to its status representation.
+func toReplicationExecution(execution *models.ReplicationExecution) *goharborv1.HarborReplicationExecution {
+	if execution == nil {
+		return nil
+	}
+
+	status := &goharborv1.HarborReplicationExecution{
+		ID:         execution.ID,
+		Status:     execution.Status,
+		StatusText: execution.StatusText,
+		Trigger:    execution.Trigger,
+		Total:      execution.Total,
+		Succeed:    execution.Succeed,
+		Failed:     execution.Failed,
+		InProgress: execution.InProgress,
+		Stopped:    execution.Stopped,
+	}
+
+	if startTime := time.Time(execution.StartTime); !startTime.IsZero() {
+		status.StartTime = &metav1.Time{Time: startTime}
+	}
+
+	if endTime := time.Time(execution.EndTime); !endTime.IsZero() {
+		status.EndTime = &metav1.Time{Time: endTime}
+	}
+
+	return status
+}
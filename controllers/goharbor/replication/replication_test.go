@@ -0,0 +1,101 @@
+package replication_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/replication"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindReplicationPolicy(t *testing.T) {
+	harbor := newHarborClient(t, map[string]interface{}{
+		"/api/v2.0/replication/policies/1": &models.ReplicationPolicy{ID: 1, Name: "renamed"},
+		"/api/v2.0/replication/policies": []*models.ReplicationPolicy{
+			{ID: 2, Name: "nightly-backup"},
+			{ID: 3, Name: "nightly"},
+		},
+	})
+
+	hrp := &goharborv1.HarborReplicationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "nightly"},
+	}
+
+	hrp.Status.PolicyID = 1
+
+	policy, err := replication.FindReplicationPolicy(harbor, hrp)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), policy.ID, "tracked by its ID")
+
+	hrp.Status.PolicyID = 4
+
+	_, err = replication.FindReplicationPolicy(harbor, hrp)
+	require.ErrorIs(t, err, replication.ErrReplicationPolicyConflict, "policies of the same name are not adopted")
+
+	hrp.Status.PolicyID = 0
+	hrp.Spec.Name = "weekly"
+
+	policy, err = replication.FindReplicationPolicy(harbor, hrp)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+}
+
+func TestFindRegistry(t *testing.T) {
+	harbor := newHarborClient(t, map[string]interface{}{
+		"/api/v2.0/registries/1": &models.Registry{ID: 1, Name: "renamed"},
+		"/api/v2.0/registries": []*models.Registry{
+			{ID: 2, Name: "docker-hub-mirror"},
+			{ID: 3, Name: "docker-hub"},
+		},
+	})
+
+	hre := &goharborv1.HarborRegistryEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "harbor", Name: "docker-hub"},
+	}
+
+	hre.Status.RegistryID = 1
+
+	reg, err := replication.FindRegistry(harbor, hre)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), reg.ID, "tracked by its ID")
+
+	hre.Status.RegistryID = 0
+
+	_, err = replication.FindRegistry(harbor, hre)
+	require.ErrorIs(t, err, replication.ErrRegistryConflict, "registries of the same name are not adopted")
+
+	hre.Spec.Name = "quay"
+
+	reg, err = replication.FindRegistry(harbor, hre)
+	require.NoError(t, err)
+	require.Nil(t, reg)
+}
+
+// newHarborClient returns a client of a Harbor API serving the given payloads by path, other paths are not found.
+func newHarborClient(t *testing.T, payloads map[string]interface{}) *v2.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := payloads[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(payload))
+	}))
+
+	t.Cleanup(server.Close)
+
+	harbor, err := v2.NewWithServer(model.NewHarborServer(server.URL, "admin", "Harbor12345", false))
+	require.NoError(t, err)
+
+	return harbor
+}
@@ -0,0 +1,78 @@
+package robotaccount
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/utils/strings"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	finalizerID                  string = "harborrobotaccount.goharbor.io/finalizer"
+	defaultRequeueAfterMinutes   int    = 5
+	requeueAfterMinutesConfigKey string = "requeue-after-minutes"
+)
+
+// New HarborRobotAccount reconciler.
+func New(ctx context.Context, configStore *configstore.Store) (commonCtrl.Reconciler, error) {
+	r := &Reconciler{}
+	r.Controller = commonCtrl.NewController(ctx, controllers.HarborRobotAccount, nil, configStore)
+
+	return r, nil
+}
+
+// Reconciler reconciles a robot account cr.
+type Reconciler struct {
+	*commonCtrl.Controller
+	Scheme              *runtime.Scheme
+	RequeueAfterMinutes int
+}
+
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrobotaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrobotaccounts/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborrobotaccounts/finalizers,verbs=update
+// +kubebuilder:rbac:groups=goharbor.io,resources=harborserverconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=goharbor.io,resources=namespacedharborserverconfigurations,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	concurrentReconcile, err := config.GetInt(r.ConfigStore, config.ReconciliationKey, config.DefaultConcurrentReconcile)
+	if err != nil {
+		return errors.Wrap(err, "cannot get concurrent reconcile")
+	}
+
+	requeueAfterMinutes, err := config.GetInt(r.ConfigStore, requeueAfterMinutesConfigKey, defaultRequeueAfterMinutes)
+	if err != nil {
+		return errors.Wrap(err, "cannot get requeue after config value")
+	}
+
+	r.RequeueAfterMinutes = requeueAfterMinutes
+	r.Client = mgr.GetClient()
+	r.Scheme = mgr.GetScheme()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&goharborv1.HarborRobotAccount{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Deleted secrets are recreated with a new credential
+		Owns(&corev1.Secret{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: concurrentReconcile,
+		}).
+		Complete(r)
+}
+
+func (r *Reconciler) NormalizeName(ctx context.Context, name string, suffixes ...string) string {
+	suffixes = append([]string{"HarborRobotAccount"}, suffixes...)
+
+	return strings.NormalizeName(name, suffixes...)
+}
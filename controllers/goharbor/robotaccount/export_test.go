@@ -0,0 +1,8 @@
+package robotaccount
+
+var (
+	FindRobot     = findRobot
+	NeedsRotation = (*Reconciler).needsRotation
+	Rotate        = (*Reconciler).rotate
+	RequeueAfter  = (*Reconciler).requeueAfter
+)
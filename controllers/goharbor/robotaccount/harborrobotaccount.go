@@ -0,0 +1,390 @@
+package robotaccount
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harborClient "github.com/goharbor/harbor-operator/pkg/rest"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	robotLevelSystem = "system"
+	// neverExpires is the duration and the expiration of the robot accounts which never expire.
+	neverExpires      = -1
+	robotDurationUnit = 24 * time.Hour
+	// defaultRotateBefore is used when the rotation delay is not defaulted by the API server.
+	defaultRotateBefore = 72 * time.Hour
+)
+
+var (
+	ErrHarborCfgNotFound         = errors.New("harbor server configuration not found")
+	ErrUnexpectedHarborCfgStatus = errors.New("status of Harbor server referred in configuration %s is unexpected")
+	ErrSecretNotManaged          = errors.New("secret exists and is not managed by the robot account")
+	ErrRobotConflict             = errors.New("robot account already exists in Harbor and is not managed by the resource")
+)
+
+// Reconcile does robot account reconcile.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) { //nolint:funlen
+	log := r.Log.WithValues("resource", req.NamespacedName)
+	log.Info("Start reconciling")
+
+	hra := &goharborv1.HarborRobotAccount{}
+	if err = r.Client.Get(ctx, req.NamespacedName, hra); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The resource may have be deleted after reconcile request coming in
+			// Reconcile is done
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, errors.Wrapf(err, "error get harbor robot account %v", req)
+	}
+
+	hra.Status.Status = goharborv1.HarborRobotAccountStatusUnknown
+
+	defer func() {
+		if err != nil {
+			hra.Status.Status = goharborv1.HarborRobotAccountStatusFail
+			hra.Status.Message = err.Error()
+		} else {
+			hra.Status.Status = goharborv1.HarborRobotAccountStatusReady
+			hra.Status.Reason = ""
+			hra.Status.Message = ""
+			now := metav1.Now()
+			hra.Status.LastApplyTime = &now
+		}
+
+		log.Info("Reconcile end", "result", res, "error", err, "updateStatusError", r.Client.Status().Update(ctx, hra))
+	}()
+
+	hsc, err := r.getHarborServerConfig(ctx, hra.GetNamespace(), hra.Spec.HarborServerConfig)
+	if err != nil {
+		hra.Status.Reason = "HarborClientError"
+
+		return ctrl.Result{}, err
+	}
+
+	harborv2, err := harborClient.CreateHarborV2Client(ctx, r.Client, hsc)
+	if err != nil {
+		err = errors.Wrapf(err, "error get harbor client")
+		hra.Status.Reason = "HarborClientError"
+
+		return ctrl.Result{}, err
+	}
+
+	harbor := harborv2.WithContext(ctx)
+
+	if !hra.ObjectMeta.DeletionTimestamp.IsZero() {
+		// The object is being deleted, the secret is garbage collected
+		if controllerutil.ContainsFinalizer(hra, finalizerID) {
+			if hra.Status.RobotID > 0 {
+				if err = harbor.DeleteRobot(hra.Status.RobotID); err != nil {
+					hra.Status.Reason = "DeleteRobotError"
+					// if fail to delete the external dependency here, return with error
+					// so that it can be retried
+					return ctrl.Result{}, err
+				}
+			}
+
+			controllerutil.RemoveFinalizer(hra, finalizerID)
+
+			if err = r.Update(ctx, hra); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(hra, finalizerID) {
+		controllerutil.AddFinalizer(hra, finalizerID)
+
+		if err = r.Update(ctx, hra); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	robot, password, err := r.applyRobot(harbor, hra)
+	if err != nil {
+		hra.Status.Reason = "ApplyRobotError"
+		if errors.Is(err, ErrRobotConflict) {
+			hra.Status.Reason = "RobotConflict"
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	if password == "" {
+		// The secret of a robot account is only returned when it is generated
+		rotate, err := r.needsRotation(ctx, hra, robot)
+		if err != nil {
+			hra.Status.Reason = "GetSecretError"
+
+			return ctrl.Result{}, err
+		}
+
+		if rotate {
+			log.Info("Rotating robot account secret", "robot", robot.Name)
+
+			if robot, password, err = r.rotate(harbor, hra, robot); err != nil {
+				hra.Status.Reason = "RotateSecretError"
+
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	hra.Status.RobotID = robot.ID
+	hra.Status.RobotName = robot.Name
+	hra.Status.ExpiresAt = nil
+
+	if robot.ExpiresAt > 0 {
+		hra.Status.ExpiresAt = &metav1.Time{Time: time.Unix(robot.ExpiresAt, 0)}
+	}
+
+	if password != "" {
+		if err = r.applySecret(ctx, hra, hsc, robot.Name, password); err != nil {
+			hra.Status.Reason = "ApplySecretError"
+
+			return ctrl.Result{}, err
+		}
+
+		now := metav1.Now()
+		hra.Status.LastRotationTime = &now
+	}
+
+	log.Info("Reconcile is completed")
+
+	return ctrl.Result{RequeueAfter: r.requeueAfter(hra)}, nil
+}
+
+// requeueAfter returns the delay of the next reconciliation, it happens sooner when the robot account has to be rotated.
+func (r *Reconciler) requeueAfter(hra *goharborv1.HarborRobotAccount) time.Duration {
+	requeueAfter := time.Minute * time.Duration(r.RequeueAfterMinutes)
+
+	if hra.Status.ExpiresAt != nil {
+		untilRotation := time.Until(hra.Status.ExpiresAt.Add(-rotateBefore(hra)))
+		if untilRotation < time.Minute {
+			untilRotation = time.Minute
+		}
+
+		if untilRotation < requeueAfter {
+			requeueAfter = untilRotation
+		}
+	}
+
+	return requeueAfter
+}
+
+// applyRobot creates or updates the robot account.
+// The secret of the robot account is returned when it is created.
+func (r *Reconciler) applyRobot(harbor *v2.Client, hra *goharborv1.HarborRobotAccount) (*models.Robot, string, error) {
+	robot, err := findRobot(harbor, hra)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error finding existing harbor robot account")
+	}
+
+	if robot == nil {
+		created, err := harbor.CreateRobot(&models.RobotCreate{
+			Name:        hra.GetRobotName(),
+			Description: hra.Spec.Description,
+			Disable:     hra.Spec.Disabled,
+			Duration:    hra.Spec.Duration,
+			Level:       robotLevelSystem,
+			Permissions: toRobotPermissions(hra.Spec.Permissions),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		robot, err = harbor.GetRobot(created.ID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if robot == nil {
+			return nil, "", errors.Errorf("robot account %d not found after creation", created.ID)
+		}
+
+		return robot, created.Secret, nil
+	}
+
+	update := *robot
+	update.Description = hra.Spec.Description
+	update.Disable = hra.Spec.Disabled
+	update.Duration = desiredDuration(hra, robot)
+	update.Permissions = toRobotPermissions(hra.Spec.Permissions)
+	update.Secret = ""
+
+	if err := harbor.UpdateRobot(robot.ID, &update); err != nil {
+		return nil, "", err
+	}
+
+	robot, err = harbor.GetRobot(robot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if robot == nil {
+		return nil, "", errors.Errorf("robot account %d not found after update", update.ID)
+	}
+
+	return robot, "", nil
+}
+
+// findRobot returns the robot account previously created for the resource, tracked by its ID.
+// A robot account with the same name not created by the resource is never adopted,
+// its permissions could be changed and its secret would be unknown.
+func findRobot(harbor *v2.Client, hra *goharborv1.HarborRobotAccount) (*models.Robot, error) {
+	if hra.Status.RobotID > 0 {
+		robot, err := harbor.GetRobot(hra.Status.RobotID)
+		if err != nil || robot != nil {
+			return robot, err
+		}
+	}
+
+	robot, err := harbor.GetRobotByName(hra.GetRobotName())
+	if err != nil {
+		return nil, err
+	}
+
+	if robot != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRobotConflict, robot.Name)
+	}
+
+	return nil, nil
+}
+
+// desiredDuration returns the duration of the robot account, keeping the renewals of the previous rotations.
+func desiredDuration(hra *goharborv1.HarborRobotAccount, robot *models.Robot) int64 {
+	if hra.Spec.Duration == neverExpires || hra.Spec.Duration == 0 {
+		return neverExpires
+	}
+
+	if robot.Duration == neverExpires || robot.Duration < hra.Spec.Duration {
+		return hra.Spec.Duration
+	}
+
+	return robot.Duration
+}
+
+// needsRotation checks whether the secret of the robot account has to be generated again,
+// because the robot account is about to expire or the secret does not hold it anymore.
+func (r *Reconciler) needsRotation(ctx context.Context, hra *goharborv1.HarborRobotAccount, robot *models.Robot) (bool, error) {
+	if robot.ExpiresAt > 0 && time.Until(time.Unix(robot.ExpiresAt, 0)) < rotateBefore(hra) {
+		return true, nil
+	}
+
+	secret := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: hra.GetNamespace(), Name: hra.GetSecretName()}, secret)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !metav1.IsControlledBy(secret, hra) {
+		return false, fmt.Errorf("%w: %s", ErrSecretNotManaged, secret.GetName())
+	}
+
+	// The type of secrets is immutable, a new format requires a new secret.
+	return secret.Type != secretType(hra.Spec.Secret.Format), nil
+}
+
+// rotate renews the robot account for another duration if it expires, then generates a new secret.
+func (r *Reconciler) rotate(harbor *v2.Client, hra *goharborv1.HarborRobotAccount, robot *models.Robot) (*models.Robot, string, error) {
+	if robot.ExpiresAt > 0 && hra.Spec.Duration > 0 {
+		// The expiration is computed from the creation time of the robot account
+		age := time.Since(time.Time(robot.CreationTime))
+
+		update := *robot
+		update.Duration = int64(math.Ceil(age.Hours()/robotDurationUnit.Hours())) + hra.Spec.Duration
+		update.Secret = ""
+
+		if err := harbor.UpdateRobot(robot.ID, &update); err != nil {
+			return nil, "", errors.Wrap(err, "cannot renew robot account")
+		}
+	}
+
+	password, err := harbor.RefreshRobotSecret(robot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	robot, err = harbor.GetRobot(robot.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if robot == nil {
+		return nil, "", errors.Errorf("robot account %d not found after rotation", hra.Status.RobotID)
+	}
+
+	return robot, password, nil
+}
+
+func rotateBefore(hra *goharborv1.HarborRobotAccount) time.Duration {
+	if hra.Spec.RotateBefore == nil {
+		return defaultRotateBefore
+	}
+
+	return hra.Spec.RotateBefore.Duration
+}
+
+func toRobotPermissions(permissions []goharborv1.HarborRobotPermission) []*models.RobotPermission {
+	result := make([]*models.RobotPermission, 0, len(permissions))
+
+	for _, permission := range permissions {
+		access := make([]*models.Access, 0, len(permission.Access))
+
+		for _, a := range permission.Access {
+			access = append(access, &models.Access{
+				Resource: a.Resource,
+				Action:   a.Action,
+				Effect:   a.Effect,
+			})
+		}
+
+		kind := permission.Kind
+		if kind == "" {
+			kind = "project"
+		}
+
+		result = append(result, &models.RobotPermission{
+			Kind:      kind,
+			Namespace: permission.Namespace,
+			Access:    access,
+		})
+	}
+
+	return result
+}
+
+func (r *Reconciler) getHarborServerConfig(ctx context.Context, namespace, name string) (*goharborv1.HarborServerConfiguration, error) {
+	hsc, err := harborClient.GetHarborServerConfiguration(ctx, r.Client, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("error finding harborCfg: %w", err)
+	}
+
+	if hsc == nil {
+		return nil, fmt.Errorf("%w: %s", ErrHarborCfgNotFound, name)
+	}
+
+	if hsc.Status.Status == goharborv1.HarborServerConfigurationStatusUnknown || hsc.Status.Status == goharborv1.HarborServerConfigurationStatusFail {
+		return nil, fmt.Errorf("%w harborCfg %s with %s", ErrUnexpectedHarborCfgStatus, hsc.Name, hsc.Status.Status)
+	}
+
+	return hsc, nil
+}
@@ -0,0 +1,272 @@
+package robotaccount_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/robotaccount"
+	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFindRobot(t *testing.T) {
+	harbor, _ := newHarborClient(t, map[string]interface{}{
+		"GET /api/v2.0/robots/1": &models.Robot{ID: 1, Name: "robot$renamed"},
+		"GET /api/v2.0/robots": []*models.Robot{
+			{ID: 2, Name: "robot$ci-pull"},
+			{ID: 3, Name: "robot$ci"},
+		},
+	})
+
+	hra := newRobotAccount()
+	hra.Status.RobotID = 1
+
+	robot, err := robotaccount.FindRobot(harbor, hra)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), robot.ID, "tracked by its ID")
+
+	hra.Status.RobotID = 0
+
+	_, err = robotaccount.FindRobot(harbor, hra)
+	require.ErrorIs(t, err, robotaccount.ErrRobotConflict, "robot accounts of the same name are not adopted")
+
+	hra.Spec.Name = "deploy"
+
+	robot, err = robotaccount.FindRobot(harbor, hra)
+	require.NoError(t, err)
+	require.Nil(t, robot)
+}
+
+func TestNeedsRotation(t *testing.T) {
+	ctx := context.TODO()
+
+	hra := newRobotAccount()
+
+	for name, tc := range map[string]struct {
+		expiresAt time.Time
+		secret    *corev1.Secret
+		rotate    bool
+		err       error
+	}{
+		"up to date": {
+			expiresAt: time.Now().Add(30 * 24 * time.Hour),
+			secret:    newRobotSecret(hra, corev1.SecretTypeDockerConfigJson),
+		},
+		"never expires": {
+			secret: newRobotSecret(hra, corev1.SecretTypeDockerConfigJson),
+		},
+		"about to expire": {
+			expiresAt: time.Now().Add(24 * time.Hour),
+			secret:    newRobotSecret(hra, corev1.SecretTypeDockerConfigJson),
+			rotate:    true,
+		},
+		"secret deleted": {
+			rotate: true,
+		},
+		"format changed": {
+			secret: newRobotSecret(hra, corev1.SecretTypeBasicAuth),
+			rotate: true,
+		},
+		"secret not managed": {
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: hra.GetNamespace(), Name: hra.GetSecretName()},
+				Type:       corev1.SecretTypeDockerConfigJson,
+			},
+			err: robotaccount.ErrSecretNotManaged,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			var objects []client.Object
+			if tc.secret != nil {
+				objects = append(objects, tc.secret)
+			}
+
+			robot := &models.Robot{ID: 1, Name: "robot$ci", ExpiresAt: -1}
+			if !tc.expiresAt.IsZero() {
+				robot.ExpiresAt = tc.expiresAt.Unix()
+			}
+
+			rotate, err := robotaccount.NeedsRotation(newReconciler(t, objects...), ctx, hra, robot)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.rotate, rotate)
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	created := time.Now().Add(-29*24*time.Hour - time.Hour)
+
+	robot := &models.Robot{
+		ID:           1,
+		Name:         "robot$ci",
+		Duration:     30,
+		CreationTime: strfmt.DateTime(created),
+		ExpiresAt:    created.Add(30 * 24 * time.Hour).Unix(),
+	}
+
+	renewed := *robot
+	renewed.Duration = 60
+	renewed.ExpiresAt = created.Add(60 * 24 * time.Hour).Unix()
+
+	harbor, requests := newHarborClient(t, map[string]interface{}{
+		"PUT /api/v2.0/robots/1":   nil,
+		"PATCH /api/v2.0/robots/1": &models.RobotSec{Secret: "new-secret"},
+		"GET /api/v2.0/robots/1":   &renewed,
+	})
+
+	hra := newRobotAccount()
+	hra.Spec.Duration = 30
+	hra.Status.RobotID = 1
+
+	robot, password, err := robotaccount.Rotate(newReconciler(t), harbor, hra, robot)
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", password)
+	require.Equal(t, renewed.ExpiresAt, robot.ExpiresAt)
+
+	var update models.Robot
+	require.NoError(t, json.Unmarshal(requests["PUT /api/v2.0/robots/1"], &update))
+	require.Equal(t, int64(60), update.Duration, "renewed for another duration from now")
+	require.Empty(t, update.Secret)
+}
+
+func TestRotateNeverExpires(t *testing.T) {
+	harbor, requests := newHarborClient(t, map[string]interface{}{
+		"PATCH /api/v2.0/robots/1": &models.RobotSec{Secret: "new-secret"},
+		"GET /api/v2.0/robots/1":   &models.Robot{ID: 1, Name: "robot$ci", Duration: -1, ExpiresAt: -1},
+	})
+
+	hra := newRobotAccount()
+	hra.Status.RobotID = 1
+
+	_, password, err := robotaccount.Rotate(newReconciler(t), harbor, hra, &models.Robot{ID: 1, Name: "robot$ci", Duration: -1, ExpiresAt: -1})
+	require.NoError(t, err)
+	require.Equal(t, "new-secret", password)
+
+	require.NotContains(t, requests, "PUT /api/v2.0/robots/1", "not renewed")
+}
+
+func TestRequeueAfter(t *testing.T) {
+	r := newReconciler(t)
+	r.RequeueAfterMinutes = 5
+
+	hra := newRobotAccount()
+	require.Equal(t, 5*time.Minute, robotaccount.RequeueAfter(r, hra), "never expires")
+
+	hra.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(30 * 24 * time.Hour)}
+	require.Equal(t, 5*time.Minute, robotaccount.RequeueAfter(r, hra), "rotation later")
+
+	hra.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(72*time.Hour + 2*time.Minute)}
+	require.InDelta(t, 2*time.Minute, robotaccount.RequeueAfter(r, hra), float64(time.Second), "rotation before the default 72h")
+
+	hra.Spec.RotateBefore = &metav1.Duration{Duration: time.Hour}
+	hra.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour + 3*time.Minute)}
+	require.InDelta(t, 3*time.Minute, robotaccount.RequeueAfter(r, hra), float64(time.Second), "rotation before the configured delay")
+
+	hra.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	require.Equal(t, time.Minute, robotaccount.RequeueAfter(r, hra), "expired")
+}
+
+func newRobotAccount() *goharborv1.HarborRobotAccount {
+	return &goharborv1.HarborRobotAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "harbor",
+			Name:      "ci",
+			UID:       "robot-uid",
+		},
+		Spec: goharborv1.HarborRobotAccountSpec{
+			HarborServerConfig: "harbor",
+			Duration:           -1,
+		},
+	}
+}
+
+func newRobotSecret(hra *goharborv1.HarborRobotAccount, secretType corev1.SecretType) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: hra.GetNamespace(),
+			Name:      hra.GetSecretName(),
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: goharborv1.GroupVersion.String(),
+				Kind:       "HarborRobotAccount",
+				Name:       hra.GetName(),
+				UID:        hra.GetUID(),
+				Controller: pointer.Bool(true),
+			}},
+		},
+		Type: secretType,
+	}
+}
+
+func newReconciler(t *testing.T, objects ...client.Object) *robotaccount.Reconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	return &robotaccount.Reconciler{
+		Controller: &commonCtrl.Controller{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		},
+		Scheme: scheme,
+	}
+}
+
+// newHarborClient returns a client of a Harbor API serving the given payloads by method and path,
+// other requests are not found. The bodies of the requests are returned by method and path.
+func newHarborClient(t *testing.T, payloads map[string]interface{}) (*v2.Client, map[string][]byte) {
+	requests := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		requests[key] = body
+
+		payload, ok := payloads[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if payload == nil {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(payload))
+	}))
+
+	t.Cleanup(server.Close)
+
+	harbor, err := v2.NewWithServer(model.NewHarborServer(server.URL, "admin", "Harbor12345", false))
+	require.NoError(t, err)
+
+	return harbor, requests
+}
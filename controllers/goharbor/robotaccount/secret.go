@@ -0,0 +1,110 @@
+package robotaccount
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/registry/secret"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	accessKey    = "accessKey"
+	accessSecret = "accessSecret"
+)
+
+func secretType(format goharborv1.RobotAccountSecretFormat) corev1.SecretType {
+	switch format {
+	case goharborv1.RobotAccountSecretBasicAuth:
+		return corev1.SecretTypeBasicAuth
+	case goharborv1.RobotAccountSecretPlain:
+		return corev1.SecretTypeOpaque
+	case goharborv1.RobotAccountSecretDockerConfigJSON:
+		return corev1.SecretTypeDockerConfigJson
+	default:
+		return corev1.SecretTypeDockerConfigJson
+	}
+}
+
+// secretData returns the content of the secret holding the credential in the given format.
+func secretData(format goharborv1.RobotAccountSecretFormat, registry, username, password string) map[string][]byte {
+	switch format {
+	case goharborv1.RobotAccountSecretBasicAuth:
+		return map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte(username),
+			corev1.BasicAuthPasswordKey: []byte(password),
+		}
+	case goharborv1.RobotAccountSecretPlain:
+		return map[string][]byte{
+			accessKey:    []byte(username),
+			accessSecret: []byte(password),
+		}
+	case goharborv1.RobotAccountSecretDockerConfigJSON:
+		fallthrough
+	default:
+		auths := &secret.Object{
+			Auths: map[string]*secret.Auth{
+				registry: {
+					Username: username,
+					Password: password,
+					Email:    fmt.Sprintf("%s@goharbor.io", username),
+				},
+			},
+		}
+
+		return map[string][]byte{
+			corev1.DockerConfigJsonKey: auths.Encode(),
+		}
+	}
+}
+
+// registryHost returns the host of the harbor server, used as key of the docker configurations.
+func registryHost(hsc *goharborv1.HarborServerConfiguration) string {
+	u, err := url.Parse(hsc.Spec.ServerURL)
+	if err != nil || u.Host == "" {
+		return hsc.Spec.ServerURL
+	}
+
+	return u.Host
+}
+
+// applySecret writes the credential of the robot account into the secret owned by the resource.
+func (r *Reconciler) applySecret(ctx context.Context, hra *goharborv1.HarborRobotAccount, hsc *goharborv1.HarborServerConfiguration, username, password string) error {
+	sec := &corev1.Secret{}
+
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: hra.GetNamespace(), Name: hra.GetSecretName()}, sec)
+	if err == nil {
+		if !metav1.IsControlledBy(sec, hra) {
+			return fmt.Errorf("%w: %s", ErrSecretNotManaged, sec.GetName())
+		}
+
+		// The type of secrets is immutable, a secret of another format is replaced
+		if sec.Type != secretType(hra.Spec.Secret.Format) {
+			if err := r.Client.Delete(ctx, sec); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+
+			sec = &corev1.Secret{}
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	sec.SetName(hra.GetSecretName())
+	sec.SetNamespace(hra.GetNamespace())
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, sec, func() error {
+		sec.Type = secretType(hra.Spec.Secret.Format)
+		sec.Data = secretData(hra.Spec.Secret.Format, registryHost(hsc), username, password)
+
+		return controllerutil.SetControllerReference(hra, sec, r.Scheme)
+	})
+
+	return err
+}
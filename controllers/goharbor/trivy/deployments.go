@@ -52,12 +52,33 @@ func (r *Reconciler) AddDeployment(ctx context.Context, trivy *goharborv1.Trivy,
 		return errors.Wrap(err, "cannot get deployment")
 	}
 
+	horizontalPodAutoscaler := trivy.Spec.ComponentSpec.GetHorizontalPodAutoscaler(deploy)
+	if horizontalPodAutoscaler != nil {
+		// The replicas are left to the horizontal pod autoscaler
+		deploy.Spec.Replicas = nil
+	}
+
 	// Add deploy to reconciler controller
 	_, err = r.Controller.AddDeploymentToManage(ctx, deploy, dependencies...)
 	if err != nil {
 		return errors.Wrapf(err, "cannot manage deploy %s", deploy.GetName())
 	}
 
+	podDisruptionBudget, err := trivy.Spec.ComponentSpec.GetPodDisruptionBudget(deploy)
+	if err != nil {
+		return errors.Wrap(err, "cannot get pod disruption budget")
+	}
+
+	_, err = r.Controller.AddPodDisruptionBudgetToManage(ctx, podDisruptionBudget)
+	if err != nil {
+		return errors.Wrap(err, "cannot add pod disruption budget")
+	}
+
+	_, err = r.Controller.AddHorizontalPodAutoscalerToManage(ctx, horizontalPodAutoscaler)
+	if err != nil {
+		return errors.Wrap(err, "cannot add horizontal pod autoscaler")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,53 @@
+// +kubebuilder:skip
+package api
+
+// RedisReplication CRD definition of the OT-Container-Kit redis operator,
+// only the fields managed by the harbor operator are declared.
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisReplication defines a primary and its replicas deployed by the redis operator.
+type RedisReplication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisReplicationSpec   `json:"spec"`
+	Status RedisReplicationStatus `json:"status,omitempty"`
+}
+
+// RedisReplicationSpec defines the specification of the RedisReplication.
+type RedisReplicationSpec struct {
+	Size               int32            `json:"clusterSize"`
+	KubernetesConfig   KubernetesConfig `json:"kubernetesConfig"`
+	Storage            *Storage         `json:"storage,omitempty"`
+	ServiceAccountName string           `json:"serviceAccountName,omitempty"`
+}
+
+// KubernetesConfig defines the containers of the redis servers.
+type KubernetesConfig struct {
+	Image                  string                        `json:"image"`
+	ImagePullPolicy        corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	ImagePullSecrets       []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	Resources              *corev1.ResourceRequirements  `json:"resources,omitempty"`
+	ExistingPasswordSecret *ExistingPasswordSecret       `json:"redisSecret,omitempty"`
+}
+
+// ExistingPasswordSecret references the secret holding the password of the redis servers.
+type ExistingPasswordSecret struct {
+	Name string `json:"name,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// Storage defines the volume of each redis server.
+type Storage struct {
+	KeepAfterDelete     bool                         `json:"keepAfterDelete,omitempty"`
+	VolumeClaimTemplate corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+}
+
+// RedisReplicationStatus defines the observed state of the RedisReplication.
+type RedisReplicationStatus struct {
+	MasterNode string `json:"masterNode,omitempty"`
+}
@@ -7,4 +7,13 @@ const (
 	ErrorCreateRedisSecret            = "Create redis secret error" //nolint:gosec
 	ErrorCreateRedisCr                = "Create redis cr error"
 	ErrorDefaultUnstructuredConverter = "Default unstructured converter error"
+	ErrorDeleteRedis                  = "Delete redis error"
+	ErrorUnsupportedCacheKind         = "Unsupported cache kind error"
+	ErrorCheckRedisReadiness          = "Check redis readiness error"
+)
+
+const (
+	RedisSnapshotting = "Redis snapshotting before deletion"
+	RedisDeleting     = "Redis deleting"
+	RedisNotReady     = "Redis is not ready"
 )
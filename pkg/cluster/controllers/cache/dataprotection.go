@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ lcm.DataProtector = &RedisController{}
+
+// The keys are dumped one by one with DUMP and restored with RESTORE,
+// the index file holds the number of the dump file, the TTL in milliseconds and the key of each entry.
+// The master is found through the sentinels if any, else the redis host is the master.
+const redisMasterScript = `
+set -e
+host="$REDIS_HOST"
+port="$REDIS_PORT"
+if [ -n "$SENTINEL_MASTER_SET" ]; then
+  master=$(redis-cli -h "$REDIS_HOST" -p "$REDIS_PORT" SENTINEL get-master-addr-by-name "$SENTINEL_MASTER_SET")
+  host=$(echo "$master" | sed -n 1p)
+  port=$(echo "$master" | sed -n 2p)
+fi
+if [ -z "$host" ]; then
+  echo "No master found for $SENTINEL_MASTER_SET"
+  exit 1
+fi
+cli() {
+  redis-cli -h "$host" -p "$port" -a "$REDIS_PASSWORD" --no-auth-warning "$@"
+}
+`
+
+const redisBackupScript = redisMasterScript + `
+mkdir -p "$BACKUP_DIR"
+: > "$BACKUP_DIR/index"
+cli --scan > /tmp/keys
+i=0
+while IFS= read -r key; do
+  ttl=$(cli PTTL "$key")
+  if [ "$ttl" = "-2" ]; then
+    continue
+  elif [ "$ttl" = "-1" ]; then
+    ttl=0
+  fi
+  i=$((i+1))
+  cli --raw DUMP "$key" > /tmp/dump
+  # Strip the new line appended by redis-cli.
+  size=$(wc -c < /tmp/dump)
+  head -c $((size-1)) /tmp/dump > "$BACKUP_DIR/$i.dump"
+  printf '%s %s %s\n' "$i" "$ttl" "$key" >> "$BACKUP_DIR/index"
+done < /tmp/keys
+echo "$i keys dumped"
+`
+
+const redisRestoreScript = redisMasterScript + `
+i=0
+while read -r n ttl key; do
+  cli -x RESTORE "$key" "$ttl" REPLACE < "$BACKUP_DIR/$n.dump" > /dev/null
+  i=$((i+1))
+done < "$BACKUP_DIR/index"
+echo "$i keys restored"
+`
+
+// BackupJob returns the container dumping the keys of the in-cluster redis.
+func (rc *RedisController) BackupJob(ctx context.Context, cluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return rc.dataJob(ctx, cluster, "redis-dump", redisBackupScript)
+}
+
+// RestoreJob returns the container restoring the dumped keys into the in-cluster redis.
+func (rc *RedisController) RestoreJob(ctx context.Context, cluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return rc.dataJob(ctx, cluster, "redis-restore", redisRestoreScript)
+}
+
+func (rc *RedisController) dataJob(ctx context.Context, cluster *goharborv1.HarborCluster, name, script string) (*lcm.DataJob, error) {
+	p, err := rc.provider(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := p.GetImage(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := p.GetRedisSpec()
+
+	return &lcm.DataJob{
+		ImagePullSecrets: p.GetImagePullSecrets(ctx, cluster),
+		Container: corev1.Container{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: p.GetImagePullPolicy(ctx, cluster),
+			Command:         []string{"sh", "-c", script},
+			Env: []corev1.EnvVar{{
+				Name:  "REDIS_HOST",
+				Value: fmt.Sprintf("%s.%s.svc", spec.Host, cluster.Namespace),
+			}, {
+				Name:  "REDIS_PORT",
+				Value: fmt.Sprintf("%d", spec.Port),
+			}, {
+				Name:  "SENTINEL_MASTER_SET",
+				Value: spec.SentinelMasterSet,
+			}, {
+				Name: "REDIS_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: p.GetSecretName(),
+						},
+						Key: "password",
+					},
+				},
+			}},
+		},
+	}, nil
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Delete tears down the in-cluster redis of a deleted harbor cluster according to its deletion policy.
+// It returns a nil status once the teardown is complete.
+func (rc *RedisController) Delete(ctx context.Context, cluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	p, err := rc.provider(cluster)
+	if err != nil {
+		return cacheNotReadyStatus(ErrorUnsupportedCacheKind, err.Error()), err
+	}
+
+	switch cluster.Spec.Cache.GetDeletionPolicy() {
+	case goharborv1.DeletionPolicyRetain:
+		if err := rc.retain(ctx, cluster, p); err != nil {
+			return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+		}
+
+		return nil, nil
+	case goharborv1.DeletionPolicySnapshotThenDelete:
+		pvcs, err := rc.listVolumes(ctx, cluster, p)
+		if err != nil {
+			return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+		}
+
+		done, err := common.SnapshotVolumesBeforeDeletion(ctx, rc.DClient, cluster, pvcs.Items)
+		if err != nil {
+			return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+		}
+
+		if !done {
+			return cacheNotReadyStatus(RedisSnapshotting, "waiting for the volume snapshots to be ready"), nil
+		}
+	case goharborv1.DeletionPolicyDelete:
+	}
+
+	return rc.teardown(ctx, cluster, p)
+}
+
+// retain releases the ownership of the harbor cluster on the redis resources.
+// The volumes are kept after the deletion of the cache CR.
+func (rc *RedisController) retain(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider) error {
+	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(p.GetCacheGVR()), k8s.WithNamespace(cluster.Namespace))
+
+	cr, err := crdClient.Get(p.GetCacheCRName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil && common.ReleaseOwnership(cr, cluster) {
+		rc.Log.Info("Releasing Redis CR", "namespace", cr.GetNamespace(), "name", cr.GetName())
+
+		if _, err := crdClient.Update(cr, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	secret := &corev1.Secret{}
+
+	err = rc.Client.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: p.GetSecretName()}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !common.ReleaseOwnership(secret, cluster) {
+		return nil
+	}
+
+	return rc.Client.Update(ctx, secret)
+}
+
+// teardown deletes the redis CR, its volumes and its secret.
+// It returns a not ready status until all of them are gone.
+func (rc *RedisController) teardown(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider) (*lcm.CRStatus, error) {
+	name := p.GetCacheCRName()
+	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(p.GetCacheGVR()), k8s.WithNamespace(cluster.Namespace))
+
+	_, err := crdClient.Get(name, metav1.GetOptions{})
+	if err == nil {
+		rc.Log.Info("Deleting Redis CR", "namespace", cluster.Namespace, "name", name)
+
+		if err := crdClient.Delete(name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+		}
+
+		return cacheNotReadyStatus(RedisDeleting, fmt.Sprintf("waiting for %s to be deleted", name)), nil
+	} else if !apierrors.IsNotFound(err) {
+		return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+	}
+
+	pvcs, err := rc.listVolumes(ctx, cluster, p)
+	if err != nil {
+		return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+	}
+
+	for i := range pvcs.Items {
+		if pvcs.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		rc.Log.Info("Deleting Redis volume", "namespace", cluster.Namespace, "name", pvcs.Items[i].GetName())
+
+		if err := rc.Client.Delete(ctx, &pvcs.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.GetSecretName(),
+			Namespace: cluster.Namespace,
+		},
+	}
+
+	if err := rc.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return cacheNotReadyStatus(ErrorDeleteRedis, err.Error()), err
+	}
+
+	if len(pvcs.Items) > 0 {
+		return cacheNotReadyStatus(RedisDeleting, fmt.Sprintf("waiting for the volumes of %s to be deleted", name)), nil
+	}
+
+	return nil, nil
+}
+
+// listVolumes returns the PVCs of the redis statefulset.
+func (rc *RedisController) listVolumes(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider) (*corev1.PersistentVolumeClaimList, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+
+	err := rc.Client.List(ctx, pvcs, client.InNamespace(cluster.Namespace), client.MatchingLabels(p.GetVolumeLabels()))
+
+	return pvcs, err
+}
@@ -0,0 +1,177 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/cache"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/ovh/configstore"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const redisName = "cluster-redis"
+
+var redisReplicationGVR = cache.RedisReplicationGVK.GroupVersion().WithResource(cache.RedisReplicationPlural)
+
+func TestDeleteDefaultsToRetain(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newReplicationHarborCluster(nil)
+	harborcluster.UID = "harborcluster-uid"
+
+	require.Equal(t, goharborv1.DeletionPolicyRetain, harborcluster.Spec.Cache.GetDeletionPolicy())
+
+	secret := newRedisSecret(harborcluster)
+
+	rc, dClient := newRedisController(t, harborcluster, newRedisReplication(harborcluster), secret, newRedisVolume())
+
+	crs, err := rc.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Nil(t, crs)
+
+	cr, err := dClient.Resource(redisReplicationGVR).Namespace(harborcluster.Namespace).Get(ctx, redisName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, cr.GetOwnerReferences())
+
+	require.NoError(t, rc.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: redisName}, secret))
+	require.Empty(t, secret.GetOwnerReferences())
+
+	require.NoError(t, rc.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: "redis-" + redisName + "-0"}, &corev1.PersistentVolumeClaim{}))
+}
+
+func TestDeleteRetainWithoutResources(t *testing.T) {
+	harborcluster := newReplicationHarborCluster(nil)
+	harborcluster.Spec.Cache.DeletionPolicy = goharborv1.DeletionPolicyRetain
+
+	rc, _ := newRedisController(t, harborcluster, nil)
+
+	crs, err := rc.Delete(context.TODO(), harborcluster)
+	require.NoError(t, err)
+	require.Nil(t, crs)
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newReplicationHarborCluster(nil)
+	harborcluster.Spec.Cache.DeletionPolicy = goharborv1.DeletionPolicyDelete
+
+	rc, dClient := newRedisController(t, harborcluster, newRedisReplication(harborcluster), newRedisSecret(harborcluster), newRedisVolume())
+
+	// The CR is deleted first, the redis operator removes the statefulset
+	crs, err := rc.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, cache.RedisDeleting, crs.Condition.Reason)
+
+	_, err = dClient.Resource(redisReplicationGVR).Namespace(harborcluster.Namespace).Get(ctx, redisName, metav1.GetOptions{})
+	require.True(t, kerr.IsNotFound(err))
+
+	require.NoError(t, rc.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: redisName}, &corev1.Secret{}))
+
+	// Then the volumes and the secret
+	crs, err = rc.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, cache.RedisDeleting, crs.Condition.Reason)
+
+	err = rc.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: "redis-" + redisName + "-0"}, &corev1.PersistentVolumeClaim{})
+	require.True(t, kerr.IsNotFound(err))
+
+	err = rc.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: redisName}, &corev1.Secret{})
+	require.True(t, kerr.IsNotFound(err))
+
+	crs, err = rc.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Nil(t, crs)
+}
+
+func TestDeleteUnsupportedKind(t *testing.T) {
+	harborcluster := newReplicationHarborCluster(nil)
+	harborcluster.Spec.Cache.Kind = "Unknown"
+
+	rc, _ := newRedisController(t, harborcluster, nil)
+
+	crs, err := rc.Delete(context.TODO(), harborcluster)
+	require.Error(t, err)
+	require.Equal(t, cache.ErrorUnsupportedCacheKind, crs.Condition.Reason)
+}
+
+func newRedisController(t *testing.T, harborcluster *goharborv1.HarborCluster, cr *unstructured.Unstructured, objects ...client.Object) (*cache.RedisController, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	crs := []runtime.Object{}
+	if cr != nil {
+		crs = append(crs, cr)
+	}
+
+	dClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		redisReplicationGVR: cache.RedisReplicationKind + "List",
+	}, crs...)
+
+	return &cache.RedisController{
+		Log:     logr.Discard(),
+		DClient: k8s.NewDynamicClientWrapper(dClient),
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:  scheme,
+		Providers: map[string]cache.Provider{
+			goharborv1.KindCacheRedisReplication: cache.NewReplicationResourceManager(configstore.NewStore(), logr.Discard(), scheme),
+		},
+		ConfigStore: configstore.NewStore(),
+	}, dClient
+}
+
+func redisOwnerReference(harborcluster *goharborv1.HarborCluster) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: goharborv1.GroupVersion.String(),
+		Kind:       "HarborCluster",
+		Name:       harborcluster.Name,
+		UID:        harborcluster.UID,
+	}
+}
+
+func newRedisReplication(harborcluster *goharborv1.HarborCluster) *unstructured.Unstructured {
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(cache.RedisReplicationGVK)
+	cr.SetNamespace(harborcluster.Namespace)
+	cr.SetName(redisName)
+	cr.SetOwnerReferences([]metav1.OwnerReference{redisOwnerReference(harborcluster)})
+
+	return cr
+}
+
+func newRedisSecret(harborcluster *goharborv1.HarborCluster) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       harborcluster.Namespace,
+			Name:            redisName,
+			OwnerReferences: []metav1.OwnerReference{redisOwnerReference(harborcluster)},
+		},
+	}
+}
+
+func newRedisVolume() *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "harbor",
+			Name:      "redis-" + redisName + "-0",
+			Labels: map[string]string{
+				"app": redisName,
+			},
+		},
+	}
+}
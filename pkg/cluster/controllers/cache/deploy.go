@@ -15,19 +15,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// Deploy will deploy Redis sentinel cluster if that does not exist.
+// Deploy will deploy the in-cluster Redis of the provider if that does not exist.
 // It does:
 // - check redis does exist
-// - create any new RedisFailovers CRs
+// - create the cache CR of the provider
 // - create redis password secret
 // It does not:
-// - perform any RedisFailovers downscale (left for downscale phase)
-// - perform any RedisFailovers upscale (left for upscale phase)
+// - perform any cache CR downscale (left for downscale phase)
+// - perform any cache CR upscale (left for upscale phase)
 // - perform any pod upgrade (left for rolling upgrade phase).
-func (rc *RedisController) Deploy(ctx context.Context, cluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
-	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(redisFailoversGVR), k8s.WithNamespace(cluster.Namespace))
+func (rc *RedisController) Deploy(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider) (*lcm.CRStatus, error) {
+	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(p.GetCacheGVR()), k8s.WithNamespace(cluster.Namespace))
 
-	expectCR, err := rc.ResourceManager.GetCacheCR(ctx, cluster)
+	expectCR, err := p.GetCacheCR(ctx, cluster)
 	if err != nil {
 		return cacheNotReadyStatus(ErrorGenerateRedisCr, err.Error()), err
 	}
@@ -36,7 +36,7 @@ func (rc *RedisController) Deploy(ctx context.Context, cluster *goharborv1.Harbo
 		return cacheNotReadyStatus(ErrorSetOwnerReference, err.Error()), err
 	}
 
-	if err := rc.DeploySecret(ctx, cluster); err != nil {
+	if err := rc.DeploySecret(ctx, cluster, p); err != nil {
 		return cacheNotReadyStatus(ErrorCreateRedisSecret, err.Error()), err
 	}
 
@@ -56,10 +56,10 @@ func (rc *RedisController) Deploy(ctx context.Context, cluster *goharborv1.Harbo
 	return cacheUnknownStatus(), nil
 }
 
-func (rc *RedisController) DeploySecret(ctx context.Context, cluster *goharborv1.HarborCluster) error {
+func (rc *RedisController) DeploySecret(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider) error {
 	secret := &corev1.Secret{}
 
-	sec := rc.ResourceManager.GetSecret()
+	sec := p.GetSecret()
 	if err := controllerutil.SetControllerReference(cluster, sec, rc.Scheme); err != nil {
 		return err
 	}
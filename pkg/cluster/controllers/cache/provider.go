@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider deploys the in-cluster cache with a redis operator.
+// The redis controller creates, updates and deletes the cache CR generated by the provider,
+// and the provider tells when and how the harbor components can connect to the cache.
+type Provider interface {
+	ResourceManager
+
+	// GetCacheGVR returns the resource of the cache CR.
+	GetCacheGVR() schema.GroupVersionResource
+
+	// GetVolumeLabels returns the labels of the volumes of the redis servers.
+	GetVolumeLabels() map[string]string
+
+	// GetRedisSpec returns the connection of the harbor components to the cache.
+	GetRedisSpec() *goharborv1.ExternalRedisSpec
+
+	// IsReady checks the cache CR deployed by the redis operator, the reason is returned when it is not ready.
+	IsReady(ctx context.Context, c client.Client, actual *unstructured.Unstructured) (bool, string, error)
+}
+
+// provider returns the provider of the cache kind of the harbor cluster.
+func (rc *RedisController) provider(cluster *goharborv1.HarborCluster) (Provider, error) {
+	p, ok := rc.Providers[cluster.Spec.Cache.Kind]
+	if !ok {
+		return nil, errors.Errorf("unsupported cache kind %s", cluster.Spec.Cache.Kind)
+	}
+
+	p.WithCluster(cluster)
+
+	return p, nil
+}
@@ -7,44 +7,39 @@ import (
 	"math/big"
 
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
-	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	labels1 "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// Readiness reconcile will check Redis sentinel cluster if that has available.
+// Readiness reconcile will check Redis cluster if that has available.
 // It does:
-// - create redis connection pool
-// - ping redis server
+// - check the cache CR with the provider
 // - return redis properties if redis has available.
-func (rc *RedisController) Readiness(_ context.Context, cluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+func (rc *RedisController) Readiness(ctx context.Context, cluster *goharborv1.HarborCluster, p Provider, actual *unstructured.Unstructured) (*lcm.CRStatus, error) {
+	ready, reason, err := p.IsReady(ctx, rc.Client, actual)
+	if err != nil {
+		return cacheNotReadyStatus(ErrorCheckRedisReadiness, err.Error()), err
+	}
+
+	if !ready {
+		return cacheNotReadyStatus(RedisNotReady, reason), nil
+	}
+
 	rc.Log.Info("Redis already ready.",
 		"namespace", cluster.Namespace, "name", cluster.Name)
 
 	properties := lcm.Properties{}
-	properties.Add(lcm.CachePropertyName, rc.generateRedisSpec())
+	properties.Add(lcm.CachePropertyName, p.GetRedisSpec())
 
 	return cacheReadyStatus(&properties), nil
 }
 
-func (rc *RedisController) generateRedisSpec() *goharborv1.ExternalRedisSpec {
-	return &goharborv1.ExternalRedisSpec{
-		RedisHostSpec: harbormetav1.RedisHostSpec{
-			Host:              fmt.Sprintf("rfs-%s", rc.ResourceManager.GetCacheCRName()),
-			Port:              RedisSentinelConnPort,
-			SentinelMasterSet: RedisSentinelConnGroup,
-		},
-		RedisCredentials: harbormetav1.RedisCredentials{
-			PasswordRef: rc.ResourceManager.GetSecretName(),
-		},
-	}
-}
-
 // GetRedisPassword is get redis password.
 func (rc *RedisController) GetRedisPassword(ctx context.Context, secretName, namespace string) (string, error) {
 	var redisPassWord string
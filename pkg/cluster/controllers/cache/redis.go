@@ -9,7 +9,6 @@ import (
 	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
 	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
-	redisOp "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,8 +18,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-var redisFailoversGVR = redisOp.SchemeGroupVersion.WithResource(redisOp.RFNamePlural)
-
 // NewRedisController is constructor for redis controller.
 func NewRedisController(opts ...k8s.Option) lcm.Controller {
 	ctrlOpts := &k8s.CtrlOptions{}
@@ -30,40 +27,48 @@ func NewRedisController(opts ...k8s.Option) lcm.Controller {
 	}
 
 	return &RedisController{
-		DClient:         ctrlOpts.DClient,
-		Client:          ctrlOpts.Client,
-		Log:             ctrlOpts.Log,
-		Scheme:          ctrlOpts.Scheme,
-		ResourceManager: NewResourceManager(ctrlOpts.ConfigStore, ctrlOpts.Log, ctrlOpts.Scheme),
-		ConfigStore:     ctrlOpts.ConfigStore,
+		DClient: ctrlOpts.DClient,
+		Client:  ctrlOpts.Client,
+		Log:     ctrlOpts.Log,
+		Scheme:  ctrlOpts.Scheme,
+		Providers: map[string]Provider{
+			goharborv1.KindCacheRedisFailover:    NewResourceManager(ctrlOpts.ConfigStore, ctrlOpts.Log, ctrlOpts.Scheme),
+			goharborv1.KindCacheRedisReplication: NewReplicationResourceManager(ctrlOpts.ConfigStore, ctrlOpts.Log, ctrlOpts.Scheme),
+		},
+		ConfigStore: ctrlOpts.ConfigStore,
 	}
 }
 
 // RedisController implements lcm.Controller interface.
 type RedisController struct {
-	DClient         *k8s.DynamicClientWrapper
-	Client          client.Client
-	Recorder        record.EventRecorder
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	RedisConnect    *RedisConnect
-	ResourceManager ResourceManager
-	ConfigStore     *configstore.Store
+	DClient      *k8s.DynamicClientWrapper
+	Client       client.Client
+	Recorder     record.EventRecorder
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	RedisConnect *RedisConnect
+	// Providers of the in-cluster cache by cache kind.
+	Providers   map[string]Provider
+	ConfigStore *configstore.Store
 }
 
 // Apply creates/updates/scales the resources, like kubernetes apply operation.
 func (rc *RedisController) Apply(ctx context.Context, cluster *goharborv1.HarborCluster, _ ...lcm.Option) (*lcm.CRStatus, error) {
-	rc.ResourceManager.WithCluster(cluster)
-	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(redisFailoversGVR), k8s.WithNamespace(cluster.Namespace))
+	p, err := rc.provider(cluster)
+	if err != nil {
+		return cacheNotReadyStatus(ErrorUnsupportedCacheKind, err.Error()), err
+	}
+
+	crdClient := rc.DClient.DynamicClient(ctx, k8s.WithResource(p.GetCacheGVR()), k8s.WithNamespace(cluster.Namespace))
 
-	actualCR, err := crdClient.Get(rc.ResourceManager.GetCacheCRName(), metav1.GetOptions{})
+	actualCR, err := crdClient.Get(p.GetCacheCRName(), metav1.GetOptions{})
 	if apierrors.IsNotFound(err) {
-		return rc.Deploy(ctx, cluster)
+		return rc.Deploy(ctx, cluster, p)
 	} else if err != nil {
 		return cacheNotReadyStatus(ErrorGetRedisClient, err.Error()), err
 	}
 
-	expectCR, err := rc.ResourceManager.GetCacheCR(ctx, cluster)
+	expectCR, err := p.GetCacheCR(ctx, cluster)
 	if err != nil {
 		return cacheNotReadyStatus(ErrorGenerateRedisCr, err.Error()), err
 	}
@@ -72,17 +77,12 @@ func (rc *RedisController) Apply(ctx context.Context, cluster *goharborv1.Harbor
 		return cacheNotReadyStatus(ErrorSetOwnerReference, err.Error()), err
 	}
 
-	crStatus, err := rc.Update(ctx, cluster, actualCR, expectCR)
+	crStatus, err := rc.Update(ctx, cluster, p, actualCR, expectCR)
 	if err != nil {
 		return crStatus, err
 	}
 
-	return rc.Readiness(ctx, cluster)
-}
-
-// Delete...
-func (rc *RedisController) Delete(_ context.Context, _ *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
-	return nil, errors.Errorf("not implemented")
+	return rc.Readiness(ctx, cluster, p, actualCR)
 }
 
 func (rc *RedisController) Upgrade(_ context.Context, _ *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
@@ -0,0 +1,271 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/cache/api"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	"github.com/goharbor/harbor-operator/pkg/image"
+	"github.com/goharbor/harbor-operator/pkg/resources/checksum"
+	"github.com/ovh/configstore"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ReplicationComponentName = "cluster-redis-replication"
+
+	RedisReplicationGroupName = "redis.redis.opstreelabs.in"
+	RedisReplicationVersion   = "v1beta2"
+	RedisReplicationKind      = "RedisReplication"
+	RedisReplicationPlural    = "redisreplications"
+
+	RedisConnPort = 6379
+)
+
+var (
+	RedisReplicationGVK = schema.GroupVersionKind{Group: RedisReplicationGroupName, Version: RedisReplicationVersion, Kind: RedisReplicationKind}
+	redisReplicationGVR = RedisReplicationGVK.GroupVersion().WithResource(RedisReplicationPlural)
+)
+
+var _ Provider = &redisReplicationResourceManager{}
+
+// redisReplicationResourceManager deploys a primary and its replicas with the OT-Container-Kit redis operator.
+// The name, the labels and the password secret are the ones of the RedisFailover.
+type redisReplicationResourceManager struct {
+	*redisResourceManager
+}
+
+// NewReplicationResourceManager constructs a new cache resource manager deploying a RedisReplication of the OT-Container-Kit redis operator.
+func NewReplicationResourceManager(store *configstore.Store, logger logr.Logger, scheme *runtime.Scheme) Provider {
+	return &redisReplicationResourceManager{
+		redisResourceManager: &redisResourceManager{
+			configStore: store,
+			logger:      logger,
+			scheme:      scheme,
+		},
+	}
+}
+
+// WithCluster get resources based on the specified cluster spec.
+func (rm *redisReplicationResourceManager) WithCluster(cluster *goharborv1.HarborCluster) ResourceManager {
+	rm.cluster = cluster
+
+	return rm
+}
+
+// GetCacheGVR gets the resource of the RedisReplication.
+func (rm *redisReplicationResourceManager) GetCacheGVR() schema.GroupVersionResource {
+	return redisReplicationGVR
+}
+
+// GetCacheCR gets cache cr instance.
+func (rm *redisReplicationResourceManager) GetCacheCR(ctx context.Context, harborcluster *goharborv1.HarborCluster) (runtime.Object, error) {
+	image, err := rm.GetImage(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := rm.GetResources()
+
+	pvc, err := GenerateStoragePVC(rm.GetStorageClass(), rm.cluster.Name, rm.GetStorageSize(), rm.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &api.RedisReplication{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       RedisReplicationKind,
+			APIVersion: RedisReplicationGVK.GroupVersion().String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rm.GetCacheCRName(),
+			Namespace: rm.cluster.Namespace,
+			Labels:    rm.GetLabels(),
+		},
+		Spec: api.RedisReplicationSpec{
+			Size: int32(rm.GetServerReplica()),
+			KubernetesConfig: api.KubernetesConfig{
+				Image:            image,
+				ImagePullPolicy:  rm.GetImagePullPolicy(ctx, harborcluster),
+				ImagePullSecrets: rm.GetImagePullSecrets(ctx, harborcluster),
+				Resources:        &resources,
+				ExistingPasswordSecret: &api.ExistingPasswordSecret{
+					Name: rm.GetSecretName(),
+					Key:  "password",
+				},
+			},
+			Storage: &api.Storage{
+				KeepAfterDelete: true,
+				VolumeClaimTemplate: corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: pvc.Labels,
+					},
+					Spec: pvc.Spec,
+				},
+			},
+			ServiceAccountName: rm.getServiceAccountName(ctx, harborcluster),
+		},
+	}
+
+	dependencies := checksum.New(rm.scheme)
+	dependencies.Add(ctx, harborcluster, true)
+	dependencies.AddAnnotations(rr)
+
+	return rr, nil
+}
+
+// GetVolumeLabels gets the labels of the redis server volumes,
+// the statefulset adds its selector to the labels of the volumes.
+func (rm *redisReplicationResourceManager) GetVolumeLabels() map[string]string {
+	return map[string]string{
+		"app": rm.GetCacheCRName(),
+	}
+}
+
+// GetRedisSpec connects to the service of the primary.
+func (rm *redisReplicationResourceManager) GetRedisSpec() *goharborv1.ExternalRedisSpec {
+	return &goharborv1.ExternalRedisSpec{
+		RedisHostSpec: harbormetav1.RedisHostSpec{
+			Host: fmt.Sprintf("%s-master", rm.GetCacheCRName()),
+			Port: RedisConnPort,
+		},
+		RedisCredentials: harbormetav1.RedisCredentials{
+			PasswordRef: rm.GetSecretName(),
+		},
+	}
+}
+
+// IsReady checks all the redis servers are ready and the primary is elected.
+func (rm *redisReplicationResourceManager) IsReady(ctx context.Context, c client.Client, actual *unstructured.Unstructured) (bool, string, error) {
+	var rr api.RedisReplication
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(actual.UnstructuredContent(), &rr); err != nil {
+		return false, "", err
+	}
+
+	sts := &appsv1.StatefulSet{}
+
+	err := c.Get(ctx, types.NamespacedName{Namespace: rr.GetNamespace(), Name: rr.GetName()}, sts)
+	if apierrors.IsNotFound(err) {
+		return false, fmt.Sprintf("statefulset %s not found", rr.GetName()), nil
+	} else if err != nil {
+		return false, "", err
+	}
+
+	if sts.Status.ReadyReplicas < rr.Spec.Size {
+		return false, fmt.Sprintf("%d/%d redis servers are ready", sts.Status.ReadyReplicas, rr.Spec.Size), nil
+	}
+
+	if rr.Status.MasterNode == "" {
+		return false, "no primary elected", nil
+	}
+
+	return true, "", nil
+}
+
+// GetResources gets redis resources.
+func (rm *redisReplicationResourceManager) GetResources() corev1.ResourceRequirements {
+	if rm.server() == nil {
+		resourcesList, _ := GenerateResourceList(defaultResourceCPU, defaultResourceMemory)
+
+		return corev1.ResourceRequirements{
+			Limits:   resourcesList,
+			Requests: resourcesList,
+		}
+	}
+
+	return rm.server().Resources
+}
+
+// GetServerReplica gets the number of redis servers, including the primary.
+func (rm *redisReplicationResourceManager) GetServerReplica() int {
+	if rm.server() == nil || rm.server().Replicas == 0 {
+		return defaultResourceReplica
+	}
+
+	return rm.server().Replicas
+}
+
+// GetClusterServerReplica returns 0, there is no sentinel.
+func (rm *redisReplicationResourceManager) GetClusterServerReplica() int {
+	return 0
+}
+
+// GetStorageSize gets storage size.
+func (rm *redisReplicationResourceManager) GetStorageSize() string {
+	if rm.server() == nil || rm.server().Storage == "" {
+		return defaultStorageSize
+	}
+
+	return rm.server().Storage
+}
+
+// GetStorageClass gets the storage class name.
+func (rm *redisReplicationResourceManager) GetStorageClass() string {
+	if rm.server() != nil {
+		return rm.server().StorageClassName
+	}
+
+	return ""
+}
+
+// GetImage returns the configured image via configstore or default one.
+func (rm *redisReplicationResourceManager) GetImage(ctx context.Context, harborcluster *goharborv1.HarborCluster) (string, error) {
+	options := harborcluster.Spec.ImageSource.AddRepositoryAndTagSuffixOptions(
+		image.WithImageFromSpec(harborcluster.Spec.Cache.Spec.RedisReplication.Image),
+		image.WithHarborVersion(harborcluster.Spec.Version),
+	)
+
+	return image.GetImage(ctx, ReplicationComponentName, options...)
+}
+
+func (rm *redisReplicationResourceManager) GetImagePullPolicy(_ context.Context, harborcluster *goharborv1.HarborCluster) corev1.PullPolicy {
+	if harborcluster.Spec.Cache.Spec.RedisReplication.ImagePullPolicy != nil {
+		return *harborcluster.Spec.Cache.Spec.RedisReplication.ImagePullPolicy
+	}
+
+	if harborcluster.Spec.ImageSource != nil && harborcluster.Spec.ImageSource.ImagePullPolicy != nil {
+		return *harborcluster.Spec.ImageSource.ImagePullPolicy
+	}
+
+	return config.DefaultImagePullPolicy
+}
+
+func (rm *redisReplicationResourceManager) GetImagePullSecrets(_ context.Context, harborcluster *goharborv1.HarborCluster) []corev1.LocalObjectReference {
+	if len(harborcluster.Spec.Cache.Spec.RedisReplication.ImagePullSecrets) > 0 {
+		return harborcluster.Spec.Cache.Spec.RedisReplication.ImagePullSecrets
+	}
+
+	if harborcluster.Spec.ImageSource != nil {
+		return harborcluster.Spec.ImageSource.ImagePullSecrets
+	}
+
+	return nil
+}
+
+func (rm *redisReplicationResourceManager) getServiceAccountName(_ context.Context, _ *goharborv1.HarborCluster) string {
+	if rm.server() != nil {
+		return rm.server().ServiceAccountName
+	}
+
+	return ""
+}
+
+func (rm *redisReplicationResourceManager) server() *goharborv1.RedisServer {
+	if rm.cluster.Spec.Cache.Spec.RedisReplication == nil {
+		return nil
+	}
+
+	return rm.cluster.Spec.Cache.Spec.RedisReplication.Server
+}
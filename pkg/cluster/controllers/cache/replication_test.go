@@ -0,0 +1,189 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/cache"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/cache/api"
+	"github.com/ovh/configstore"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReplicationGetCacheCR(t *testing.T) {
+	harborcluster := newReplicationHarborCluster(nil)
+
+	rm := newReplicationResourceManager(t, harborcluster)
+
+	obj, err := rm.GetCacheCR(context.TODO(), harborcluster)
+	require.NoError(t, err)
+
+	rr, ok := obj.(*api.RedisReplication)
+	require.True(t, ok)
+
+	require.Equal(t, "cluster-redis", rr.GetName())
+	require.Equal(t, "harbor", rr.GetNamespace())
+	require.Equal(t, int32(3), rr.Spec.Size)
+	require.Equal(t, "redis:6.0", rr.Spec.KubernetesConfig.Image)
+	require.Equal(t, "cluster-redis", rr.Spec.KubernetesConfig.ExistingPasswordSecret.Name)
+	require.Equal(t, "password", rr.Spec.KubernetesConfig.ExistingPasswordSecret.Key)
+	require.True(t, rr.Spec.Storage.KeepAfterDelete)
+	require.Equal(t, "1Gi", rr.Spec.Storage.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String())
+	require.Nil(t, rr.Spec.Storage.VolumeClaimTemplate.Spec.StorageClassName)
+}
+
+func TestReplicationGetCacheCRWithServer(t *testing.T) {
+	harborcluster := newReplicationHarborCluster(&goharborv1.RedisServer{
+		Replicas:           2,
+		Storage:            "5Gi",
+		StorageClassName:   "fast",
+		ServiceAccountName: "redis",
+	})
+
+	rm := newReplicationResourceManager(t, harborcluster)
+
+	obj, err := rm.GetCacheCR(context.TODO(), harborcluster)
+	require.NoError(t, err)
+
+	rr, ok := obj.(*api.RedisReplication)
+	require.True(t, ok)
+
+	require.Equal(t, int32(2), rr.Spec.Size)
+	require.Equal(t, "redis", rr.Spec.ServiceAccountName)
+	require.Equal(t, "5Gi", rr.Spec.Storage.VolumeClaimTemplate.Spec.Resources.Requests.Storage().String())
+	require.Equal(t, "fast", *rr.Spec.Storage.VolumeClaimTemplate.Spec.StorageClassName)
+}
+
+func TestReplicationGetRedisSpec(t *testing.T) {
+	rm := newReplicationResourceManager(t, newReplicationHarborCluster(nil))
+
+	spec := rm.GetRedisSpec()
+
+	require.Equal(t, "cluster-redis-master", spec.Host)
+	require.Equal(t, int64(cache.RedisConnPort), int64(spec.Port))
+	require.Equal(t, "cluster-redis", spec.PasswordRef)
+	require.Empty(t, spec.SentinelMasterSet)
+}
+
+func TestReplicationIsReady(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newReplicationHarborCluster(nil)
+
+	rm := newReplicationResourceManager(t, harborcluster)
+
+	statefulSet := func(ready int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "harbor",
+				Name:      "cluster-redis",
+			},
+			Status: appsv1.StatefulSetStatus{
+				ReadyReplicas: ready,
+			},
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		statefulSet *appsv1.StatefulSet
+		master      string
+		ready       bool
+		reason      string
+	}{
+		"no statefulset": {
+			master: "cluster-redis-0",
+			reason: "statefulset cluster-redis not found",
+		},
+		"servers not ready": {
+			statefulSet: statefulSet(2),
+			master:      "cluster-redis-0",
+			reason:      "2/3 redis servers are ready",
+		},
+		"no primary": {
+			statefulSet: statefulSet(3),
+			reason:      "no primary elected",
+		},
+		"ready": {
+			statefulSet: statefulSet(3),
+			master:      "cluster-redis-0",
+			ready:       true,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+			objects := []client.Object{}
+			if tc.statefulSet != nil {
+				objects = append(objects, tc.statefulSet)
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			rr := &api.RedisReplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "harbor",
+					Name:      "cluster-redis",
+				},
+				Spec: api.RedisReplicationSpec{
+					Size: 3,
+				},
+				Status: api.RedisReplicationStatus{
+					MasterNode: tc.master,
+				},
+			}
+
+			data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rr)
+			require.NoError(t, err)
+
+			ready, reason, err := rm.IsReady(ctx, c, &unstructured.Unstructured{Object: data})
+			require.NoError(t, err)
+			require.Equal(t, tc.ready, ready)
+			require.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func newReplicationHarborCluster(server *goharborv1.RedisServer) *goharborv1.HarborCluster {
+	return &goharborv1.HarborCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "harbor",
+			Name:      "cluster",
+		},
+		Spec: goharborv1.HarborClusterSpec{
+			Cache: goharborv1.Cache{
+				Kind: goharborv1.KindCacheRedisReplication,
+				Spec: &goharborv1.CacheSpec{
+					RedisReplication: &goharborv1.RedisReplicationSpec{
+						ImageSpec: harbormetav1.ImageSpec{
+							Image: "redis:6.0",
+						},
+						Server: server,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newReplicationResourceManager(t *testing.T, harborcluster *goharborv1.HarborCluster) cache.Provider {
+	scheme := runtime.NewScheme()
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	rm := cache.NewReplicationResourceManager(configstore.NewStore(), logr.Discard(), scheme)
+	rm.WithCluster(harborcluster)
+
+	return rm
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-logr/logr"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
 	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
 	"github.com/goharbor/harbor-operator/pkg/config"
 	"github.com/goharbor/harbor-operator/pkg/resources/checksum"
@@ -13,7 +14,10 @@ import (
 	redisOp "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // ResourceManager defines the common interface of resources.
@@ -33,9 +37,14 @@ type ResourceGetter interface {
 	GetServerReplica() int
 	GetClusterServerReplica() int
 	GetStorageSize() string
+	GetImage(ctx context.Context, harborcluster *goharborv1.HarborCluster) (string, error)
+	GetImagePullPolicy(ctx context.Context, harborcluster *goharborv1.HarborCluster) corev1.PullPolicy
+	GetImagePullSecrets(ctx context.Context, harborcluster *goharborv1.HarborCluster) []corev1.LocalObjectReference
 }
 
-var _ ResourceManager = &redisResourceManager{}
+var _ Provider = &redisResourceManager{}
+
+var redisFailoversGVR = redisOp.SchemeGroupVersion.WithResource(redisOp.RFNamePlural)
 
 type redisResourceManager struct {
 	cluster     *goharborv1.HarborCluster
@@ -55,8 +64,8 @@ const (
 	labelApp = "goharbor.io/harbor-cluster"
 )
 
-// NewResourceManager constructs a new cache resource manager.
-func NewResourceManager(store *configstore.Store, logger logr.Logger, scheme *runtime.Scheme) ResourceManager {
+// NewResourceManager constructs a new cache resource manager deploying a RedisFailover of the spotahome redis operator.
+func NewResourceManager(store *configstore.Store, logger logr.Logger, scheme *runtime.Scheme) Provider {
 	return &redisResourceManager{
 		configStore: store,
 		logger:      logger,
@@ -108,16 +117,16 @@ func (rm *redisResourceManager) GetCacheCR(ctx context.Context, harborcluster *g
 					},
 				},
 				Image:              image,
-				ImagePullPolicy:    rm.getImagePullPolicy(ctx, harborcluster),
-				ImagePullSecrets:   rm.getImagePullSecrets(ctx, harborcluster),
+				ImagePullPolicy:    rm.GetImagePullPolicy(ctx, harborcluster),
+				ImagePullSecrets:   rm.GetImagePullSecrets(ctx, harborcluster),
 				ServiceAccountName: rm.getServiceAccountName(ctx, harborcluster),
 			},
 			Sentinel: redisOp.SentinelSettings{
 				Replicas:         int32(rm.GetClusterServerReplica()),
 				Resources:        resources,
 				Image:            image,
-				ImagePullPolicy:  rm.getImagePullPolicy(ctx, harborcluster),
-				ImagePullSecrets: rm.getImagePullSecrets(ctx, harborcluster),
+				ImagePullPolicy:  rm.GetImagePullPolicy(ctx, harborcluster),
+				ImagePullSecrets: rm.GetImagePullSecrets(ctx, harborcluster),
 			},
 			Auth: redisOp.AuthSettings{SecretPath: rm.GetSecretName()},
 		},
@@ -130,6 +139,38 @@ func (rm *redisResourceManager) GetCacheCR(ctx context.Context, harborcluster *g
 	return rf, nil
 }
 
+// GetCacheGVR gets the resource of the RedisFailover.
+func (rm *redisResourceManager) GetCacheGVR() schema.GroupVersionResource {
+	return redisFailoversGVR
+}
+
+// GetVolumeLabels gets the labels of the redis server volumes.
+func (rm *redisResourceManager) GetVolumeLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name": "cache",
+		labelApp:                 rm.cluster.Name,
+	}
+}
+
+// GetRedisSpec connects through the sentinels.
+func (rm *redisResourceManager) GetRedisSpec() *goharborv1.ExternalRedisSpec {
+	return &goharborv1.ExternalRedisSpec{
+		RedisHostSpec: harbormetav1.RedisHostSpec{
+			Host:              fmt.Sprintf("rfs-%s", rm.GetCacheCRName()),
+			Port:              RedisSentinelConnPort,
+			SentinelMasterSet: RedisSentinelConnGroup,
+		},
+		RedisCredentials: harbormetav1.RedisCredentials{
+			PasswordRef: rm.GetSecretName(),
+		},
+	}
+}
+
+// IsReady returns true, the sentinels are reachable as soon as the RedisFailover is created.
+func (rm *redisResourceManager) IsReady(_ context.Context, _ client.Client, _ *unstructured.Unstructured) (bool, string, error) {
+	return true, "", nil
+}
+
 // GetCacheCRName gets cache cr name.
 func (rm *redisResourceManager) GetCacheCRName() string {
 	return fmt.Sprintf("%s-%s", rm.cluster.Name, "redis")
@@ -220,7 +261,7 @@ func (rm *redisResourceManager) GetStorageClass() string {
 	return ""
 }
 
-func (rm *redisResourceManager) getImagePullPolicy(_ context.Context, harborcluster *goharborv1.HarborCluster) corev1.PullPolicy {
+func (rm *redisResourceManager) GetImagePullPolicy(_ context.Context, harborcluster *goharborv1.HarborCluster) corev1.PullPolicy {
 	if harborcluster.Spec.Cache.Spec.RedisFailover.ImagePullPolicy != nil {
 		return *harborcluster.Spec.Cache.Spec.RedisFailover.ImagePullPolicy
 	}
@@ -232,7 +273,7 @@ func (rm *redisResourceManager) getImagePullPolicy(_ context.Context, harborclus
 	return config.DefaultImagePullPolicy
 }
 
-func (rm *redisResourceManager) getImagePullSecrets(_ context.Context, harborcluster *goharborv1.HarborCluster) []corev1.LocalObjectReference {
+func (rm *redisResourceManager) GetImagePullSecrets(_ context.Context, harborcluster *goharborv1.HarborCluster) []corev1.LocalObjectReference {
 	if len(harborcluster.Spec.Cache.Spec.RedisFailover.ImagePullSecrets) > 0 {
 		return harborcluster.Spec.Cache.Spec.RedisFailover.ImagePullSecrets
 	}
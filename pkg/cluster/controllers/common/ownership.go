@@ -0,0 +1,27 @@
+package common
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReleaseOwnership removes the references to the owner from the object,
+// so the object is not garbage collected when the owner is deleted.
+// It returns true if the object has been changed.
+func ReleaseOwnership(obj, owner metav1.Object) bool {
+	refs := obj.GetOwnerReferences()
+	kept := make([]metav1.OwnerReference, 0, len(refs))
+
+	for _, ref := range refs {
+		if ref.UID != owner.GetUID() {
+			kept = append(kept, ref)
+		}
+	}
+
+	if len(kept) == len(refs) {
+		return false
+	}
+
+	obj.SetOwnerReferences(kept)
+
+	return true
+}
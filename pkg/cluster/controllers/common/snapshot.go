@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var VolumeSnapshotGVR = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+// NewVolumeSnapshot returns a VolumeSnapshot of the given PVC.
+// The default snapshot class is used if className is empty.
+func NewVolumeSnapshot(namespace, name, pvcName, className string, labels map[string]string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvcName,
+		},
+	}
+
+	if className != "" {
+		spec["volumeSnapshotClassName"] = className
+	}
+
+	snapshot := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	snapshot.SetAPIVersion(VolumeSnapshotGVR.GroupVersion().String())
+	snapshot.SetKind("VolumeSnapshot")
+	snapshot.SetName(name)
+	snapshot.SetNamespace(namespace)
+	snapshot.SetLabels(labels)
+
+	return snapshot
+}
+
+// VolumeSnapshotReady returns true when the snapshot is ready to use.
+// An error is returned if the snapshot failed.
+func VolumeSnapshotReady(snapshot *unstructured.Unstructured) (bool, error) {
+	if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+		return false, fmt.Errorf("volume snapshot %s failed: %s, delete it to retry", snapshot.GetName(), message)
+	}
+
+	ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+
+	return ready, nil
+}
+
+// SnapshotVolumesBeforeDeletion takes a VolumeSnapshot of each PVC of a harbor cluster being deleted.
+// Snapshots are named after the PVC and the deletion timestamp so each deletion gets its own snapshots.
+// It returns true once all the snapshots are ready to use.
+func SnapshotVolumesBeforeDeletion(ctx context.Context, dClient *k8s.DynamicClientWrapper, harborcluster *goharborv1.HarborCluster, pvcs []corev1.PersistentVolumeClaim) (bool, error) {
+	crdClient := dClient.DynamicClient(ctx, k8s.WithResource(VolumeSnapshotGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	var timestamp int64
+	if harborcluster.DeletionTimestamp != nil {
+		timestamp = harborcluster.DeletionTimestamp.Unix()
+	}
+
+	done := true
+
+	for _, pvc := range pvcs {
+		name := fmt.Sprintf("%s-%d", pvc.GetName(), timestamp)
+
+		snapshot, err := crdClient.Get(name, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			snapshot = NewVolumeSnapshot(harborcluster.Namespace, name, pvc.GetName(), "", map[string]string{
+				k8s.HarborClusterNameLabel: harborcluster.Name,
+			})
+
+			if _, err := crdClient.Create(snapshot, metav1.CreateOptions{}); err != nil {
+				return false, err
+			}
+
+			done = false
+
+			continue
+		} else if err != nil {
+			return false, err
+		}
+
+		ready, err := VolumeSnapshotReady(snapshot)
+		if err != nil {
+			return false, err
+		}
+
+		done = done && ready
+	}
+
+	return done, nil
+}
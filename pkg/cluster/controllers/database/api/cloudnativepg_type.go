@@ -0,0 +1,69 @@
+// +kubebuilder:skip
+package api
+
+// CloudNativePG Cluster CRD definition, only the fields managed by the harbor operator are declared.
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloudNativePGCluster defines the Cluster Custom Resource of the CloudNativePG operator.
+type CloudNativePGCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudNativePGClusterSpec   `json:"spec"`
+	Status CloudNativePGClusterStatus `json:"status,omitempty"`
+}
+
+// CloudNativePGClusterSpec defines the specification of the Cluster.
+type CloudNativePGClusterSpec struct {
+	Instances        int                                 `json:"instances"`
+	ImageName        string                              `json:"imageName,omitempty"`
+	ImagePullPolicy  corev1.PullPolicy                   `json:"imagePullPolicy,omitempty"`
+	ImagePullSecrets []CloudNativePGLocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	PostgresConfiguration CloudNativePGPostgresConfiguration `json:"postgresql,omitempty"`
+	Bootstrap             *CloudNativePGBootstrap            `json:"bootstrap,omitempty"`
+	StorageConfiguration  CloudNativePGStorageConfiguration  `json:"storage,omitempty"`
+	Resources             corev1.ResourceRequirements        `json:"resources,omitempty"`
+}
+
+// CloudNativePGLocalObjectReference references an object of the namespace of the Cluster.
+type CloudNativePGLocalObjectReference struct {
+	Name string `json:"name"`
+}
+
+// CloudNativePGPostgresConfiguration defines the PostgreSQL configuration.
+type CloudNativePGPostgresConfiguration struct {
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// CloudNativePGBootstrap defines how the Cluster is initialized.
+type CloudNativePGBootstrap struct {
+	InitDB *CloudNativePGBootstrapInitDB `json:"initdb,omitempty"`
+}
+
+// CloudNativePGBootstrapInitDB creates a new empty Cluster with an application database.
+type CloudNativePGBootstrapInitDB struct {
+	Database    string   `json:"database,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	PostInitSQL []string `json:"postInitSQL,omitempty"`
+}
+
+// CloudNativePGStorageConfiguration defines the volume of each instance.
+type CloudNativePGStorageConfiguration struct {
+	StorageClass *string `json:"storageClass,omitempty"`
+	Size         string  `json:"size,omitempty"`
+}
+
+// CloudNativePGClusterStatus defines the observed state of the Cluster.
+type CloudNativePGClusterStatus struct {
+	Instances      int                `json:"instances,omitempty"`
+	ReadyInstances int                `json:"readyInstances,omitempty"`
+	Phase          string             `json:"phase,omitempty"`
+	CurrentPrimary string             `json:"currentPrimary,omitempty"`
+	WriteService   string             `json:"writeService,omitempty"`
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -29,6 +29,7 @@ package api
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -54,6 +55,188 @@ func (in *AdditionalVolume) DeepCopy() *AdditionalVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGBootstrap) DeepCopyInto(out *CloudNativePGBootstrap) {
+	*out = *in
+	if in.InitDB != nil {
+		in, out := &in.InitDB, &out.InitDB
+		*out = new(CloudNativePGBootstrapInitDB)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGBootstrap.
+func (in *CloudNativePGBootstrap) DeepCopy() *CloudNativePGBootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGBootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGBootstrapInitDB) DeepCopyInto(out *CloudNativePGBootstrapInitDB) {
+	*out = *in
+	if in.PostInitSQL != nil {
+		in, out := &in.PostInitSQL, &out.PostInitSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGBootstrapInitDB.
+func (in *CloudNativePGBootstrapInitDB) DeepCopy() *CloudNativePGBootstrapInitDB {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGBootstrapInitDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGCluster) DeepCopyInto(out *CloudNativePGCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGCluster.
+func (in *CloudNativePGCluster) DeepCopy() *CloudNativePGCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudNativePGCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGClusterSpec) DeepCopyInto(out *CloudNativePGClusterSpec) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]CloudNativePGLocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(CloudNativePGBootstrap)
+		(*in).DeepCopyInto(*out)
+	}
+	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
+	in.Resources.DeepCopyInto(&out.Resources)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGClusterSpec.
+func (in *CloudNativePGClusterSpec) DeepCopy() *CloudNativePGClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGClusterStatus) DeepCopyInto(out *CloudNativePGClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGClusterStatus.
+func (in *CloudNativePGClusterStatus) DeepCopy() *CloudNativePGClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGLocalObjectReference) DeepCopyInto(out *CloudNativePGLocalObjectReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGLocalObjectReference.
+func (in *CloudNativePGLocalObjectReference) DeepCopy() *CloudNativePGLocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGLocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGPostgresConfiguration) DeepCopyInto(out *CloudNativePGPostgresConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGPostgresConfiguration.
+func (in *CloudNativePGPostgresConfiguration) DeepCopy() *CloudNativePGPostgresConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGPostgresConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudNativePGStorageConfiguration) DeepCopyInto(out *CloudNativePGStorageConfiguration) {
+	*out = *in
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudNativePGStorageConfiguration.
+func (in *CloudNativePGStorageConfiguration) DeepCopy() *CloudNativePGStorageConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudNativePGStorageConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionPooler) DeepCopyInto(out *ConnectionPooler) {
 	*out = *in
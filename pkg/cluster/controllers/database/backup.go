@@ -0,0 +1,315 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	backupMountPath     = "/backup"
+	backupContainerName = "pg-dump"
+	backupJobType       = "postgresql-upgrade-backup"
+	superUser           = "postgres"
+	standbyUser         = "standby"
+)
+
+var nonAlphanumeric = regexp.MustCompile("[^a-z0-9]+")
+
+const dumpScript = `
+set -e
+file="$BACKUP_DIR/$PGHOST-pg$PG_VERSION-$(date +%Y%m%d%H%M%S).sql.gz"
+until pg_isready -h "$PGHOST" -p "$PGPORT"; do
+  echo "Waiting for $PGHOST:$PGPORT"
+  sleep 2
+done
+pg_dumpall -h "$PGHOST" -p "$PGPORT" -U "$PGUSER" --clean --if-exists | gzip > "$file"
+echo "Database dumped to $file"
+`
+
+// backupBeforeUpgrade takes the configured backup of the running cluster.
+// It returns true once the backup is complete.
+func (p *PostgreSQLController) backupBeforeUpgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql, current upgradeTarget) (*lcm.CRStatus, bool, error) {
+	switch harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.GetPreUpgradeBackup() {
+	case goharborv1.PostgreSQLBackupNone:
+		return nil, true, nil
+	case goharborv1.PostgreSQLBackupSnapshot:
+		return p.applyUpgradeSnapshot(ctx, harborcluster, current)
+	default:
+		return p.applyUpgradeDump(ctx, harborcluster, actualCR, current)
+	}
+}
+
+// upgradeBackupName returns the name of the backup taken before upgrading from the current target.
+// The generation of the harbor cluster makes it unique per upgrade: a later upgrade from the same
+// versions does not reuse the job, the volume or the snapshot of a previous one.
+func (p *PostgreSQLController) upgradeBackupName(harborcluster *goharborv1.HarborCluster, current upgradeTarget) string {
+	name := fmt.Sprintf("%s-pg%s", p.resourceName(harborcluster.Namespace, harborcluster.Name), current.Version)
+
+	if current.OperatorVersion != "" {
+		name = fmt.Sprintf("%s-op%s", name, nonAlphanumeric.ReplaceAllString(strings.ToLower(current.OperatorVersion), "-"))
+	}
+
+	return fmt.Sprintf("%s-g%d-backup", name, harborcluster.GetGeneration())
+}
+
+// applyUpgradeDump runs a job dumping all the databases into a dedicated volume.
+func (p *PostgreSQLController) applyUpgradeDump(ctx context.Context, harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql, current upgradeTarget) (*lcm.CRStatus, bool, error) {
+	name := p.upgradeBackupName(harborcluster, current)
+
+	if err := p.applyUpgradeDumpPVC(ctx, harborcluster, name); err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+	}
+
+	job := &batchv1.Job{}
+
+	err := p.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: name}, job)
+	if kerr.IsNotFound(err) {
+		job, err = p.generateUpgradeDumpJob(harborcluster, actualCR, name, current)
+		if err != nil {
+			return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+		}
+
+		p.Log.Info("Creating Database backup job", "namespace", harborcluster.Namespace, "name", name)
+
+		if err := p.Client.Create(ctx, job); err != nil {
+			return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+		}
+
+		return databaseNotReadyStatus(DatabaseUpgradeBackingUp, fmt.Sprintf("dumping %s before upgrading", current)), false, nil
+	} else if err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type { //nolint:exhaustive
+		case batchv1.JobComplete:
+			return nil, true, nil
+		case batchv1.JobFailed:
+			return databaseNotReadyStatus(
+				DatabaseUpgradeFailed,
+				fmt.Sprintf("backup job %s failed: %s, delete it to retry", name, condition.Message),
+			), false, nil
+		}
+	}
+
+	return databaseNotReadyStatus(DatabaseUpgradeBackingUp, fmt.Sprintf("dumping %s before upgrading", current)), false, nil
+}
+
+func (p *PostgreSQLController) applyUpgradeDumpPVC(ctx context.Context, harborcluster *goharborv1.HarborCluster, name string) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+
+	err := p.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: name}, pvc)
+	if !kerr.IsNotFound(err) {
+		return err
+	}
+
+	spec := harborcluster.Spec.Database.Spec.ZlandoPostgreSQL
+
+	size := p.GetPostgreStorageSize(harborcluster)
+	storageClass := p.GetStorageClass(harborcluster)
+
+	if spec.Upgrade != nil {
+		if spec.Upgrade.Storage != "" {
+			size = spec.Upgrade.Storage
+		}
+
+		if spec.Upgrade.StorageClassName != "" {
+			storageClass = spec.Upgrade.StorageClassName
+		}
+	}
+
+	storage, err := resource.ParseQuantity(size)
+	if err != nil {
+		return err
+	}
+
+	pvc = &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: harborcluster.Namespace,
+			Labels: map[string]string{
+				"job-type":                 backupJobType,
+				k8s.HarborClusterNameLabel: harborcluster.Name,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: storage},
+			},
+		},
+	}
+
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	if err := controllerutil.SetControllerReference(harborcluster, pvc, p.Scheme); err != nil {
+		return err
+	}
+
+	p.Log.Info("Creating Database backup volume", "namespace", harborcluster.Namespace, "name", name)
+
+	return p.Client.Create(ctx, pvc)
+}
+
+func (p *PostgreSQLController) generateUpgradeDumpJob(harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql, name string, current upgradeTarget) (*batchv1.Job, error) { //nolint:funlen
+	resName := p.resourceName(harborcluster.Namespace, harborcluster.Name)
+	spec := harborcluster.Spec.Database.Spec.ZlandoPostgreSQL
+
+	labels := map[string]string{
+		"job-type":                 backupJobType,
+		k8s.HarborClusterNameLabel: harborcluster.Name,
+	}
+
+	var backoffLimit int32 = 3
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: harborcluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: spec.ImagePullSecrets,
+					RestartPolicy:    corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{{
+						Name:            backupContainerName,
+						Image:           actualCR.Spec.DockerImage,
+						ImagePullPolicy: p.getImagePullPolicy(harborcluster),
+						Command:         []string{"bash", "-c", dumpScript},
+						Env: []corev1.EnvVar{{
+							Name:  "PGHOST",
+							Value: fmt.Sprintf("%s.%s.svc", resName, harborcluster.Namespace),
+						}, {
+							Name:  "PGPORT",
+							Value: InClusterDatabasePort,
+						}, {
+							Name:  "PGUSER",
+							Value: superUser,
+						}, {
+							Name:  "PG_VERSION",
+							Value: current.Version,
+						}, {
+							Name:  "BACKUP_DIR",
+							Value: backupMountPath,
+						}, {
+							Name: "PGPASSWORD",
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: GenInClusterPasswordSecretName(superUser, resName),
+									},
+									Key: InClusterDatabasePasswordKey,
+								},
+							},
+						}},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "backup",
+							MountPath: backupMountPath,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "backup",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+								ClaimName: name,
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(harborcluster, job, p.Scheme); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// applyUpgradeSnapshot takes a VolumeSnapshot of the master volume.
+func (p *PostgreSQLController) applyUpgradeSnapshot(ctx context.Context, harborcluster *goharborv1.HarborCluster, current upgradeTarget) (*lcm.CRStatus, bool, error) {
+	name := p.upgradeBackupName(harborcluster, current)
+	crdClient := p.DClient.DynamicClient(ctx, k8s.WithResource(common.VolumeSnapshotGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	snapshot, err := crdClient.Get(name, metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
+		snapshot, err = p.generateUpgradeSnapshot(ctx, harborcluster, name)
+		if err != nil {
+			return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+		}
+
+		p.Log.Info("Creating Database volume snapshot", "namespace", harborcluster.Namespace, "name", name)
+
+		if _, err := crdClient.Create(snapshot, metav1.CreateOptions{}); err != nil {
+			return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+		}
+
+		return databaseNotReadyStatus(DatabaseUpgradeBackingUp, fmt.Sprintf("snapshotting %s before upgrading", current)), false, nil
+	} else if err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseBackupError, err.Error()), false, err
+	}
+
+	ready, err := common.VolumeSnapshotReady(snapshot)
+	if err != nil {
+		return databaseNotReadyStatus(DatabaseUpgradeFailed, err.Error()), false, nil
+	}
+
+	if ready {
+		return nil, true, nil
+	}
+
+	return databaseNotReadyStatus(DatabaseUpgradeBackingUp, fmt.Sprintf("snapshotting %s before upgrading", current)), false, nil
+}
+
+func (p *PostgreSQLController) generateUpgradeSnapshot(ctx context.Context, harborcluster *goharborv1.HarborCluster, name string) (*unstructured.Unstructured, error) {
+	pods, err := p.GetStatefulSetPods(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no master found for %s", p.resourceName(harborcluster.Namespace, harborcluster.Name))
+	}
+
+	var className string
+	if upgrade := harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.Upgrade; upgrade != nil {
+		className = upgrade.VolumeSnapshotClassName
+	}
+
+	// PVCs of the spilo statefulset are named pgdata-<pod>.
+	snapshot := common.NewVolumeSnapshot(harborcluster.Namespace, name, fmt.Sprintf("pgdata-%s", pods.Items[0].GetName()), className, map[string]string{
+		k8s.HarborClusterNameLabel: harborcluster.Name,
+	})
+
+	// The snapshot is kept when the cluster is deleted, it is the last known good state of the database.
+	return snapshot, nil
+}
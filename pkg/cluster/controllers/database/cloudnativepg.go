@@ -0,0 +1,376 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	"github.com/goharbor/harbor-operator/pkg/image"
+	"github.com/goharbor/harbor-operator/pkg/resources/checksum"
+	"github.com/ovh/configstore"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	CloudNativePGComponentName = "cluster-cloudnativepg"
+	cloudNativePGSuffix        = "postgresql"
+)
+
+var (
+	CloudNativePGClusterGVK = schema.GroupVersionKind{Group: CloudNativePGGroupName, Version: APIVersion, Kind: CloudNativePGClusterKind}
+	cloudNativePGClusterGVR = CloudNativePGClusterGVK.GroupVersion().WithResource(CloudNativePGClusterResourcePlural)
+)
+
+var (
+	_ lcm.Controller    = &CloudNativePGController{}
+	_ lcm.DataProtector = &CloudNativePGController{}
+)
+
+// CloudNativePGController manages the in-cluster PostgreSQL deployed by the CloudNativePG operator.
+type CloudNativePGController struct {
+	Log         logr.Logger
+	DClient     *k8s.DynamicClientWrapper
+	Client      client.Client
+	Scheme      *runtime.Scheme
+	ConfigStore *configstore.Store
+}
+
+// Apply creates the CloudNativePG cluster, updates it when the harbor cluster changes and checks its readiness.
+func (c *CloudNativePGController) Apply(ctx context.Context, harborcluster *goharborv1.HarborCluster, _ ...lcm.Option) (*lcm.CRStatus, error) {
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	actual, err := crdClient.Get(c.resourceName(harborcluster.Name), metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
+		return c.Deploy(ctx, harborcluster)
+	} else if err != nil {
+		return databaseNotReadyStatus(GetDatabaseCrError, err.Error()), err
+	}
+
+	image, err := c.GetImage(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(GenerateDatabaseCrError, err.Error()), err
+	}
+
+	if imageName, _, _ := unstructured.NestedString(actual.Object, "spec", "imageName"); imageName != image {
+		return c.Upgrade(ctx, harborcluster)
+	}
+
+	if err := c.Update(ctx, harborcluster, actual); err != nil {
+		return databaseNotReadyStatus(UpdateDatabaseCrError, err.Error()), err
+	}
+
+	return c.Readiness(ctx, harborcluster, actual)
+}
+
+// Upgrade rolls out a new image of the same major version of PostgreSQL,
+// the CloudNativePG operator restarts the standbys then switches over the primary.
+// The major version cannot be changed in place, the upgrade is reported as failed and the cluster is left as is.
+func (c *CloudNativePGController) Upgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	actual, err := crdClient.Get(c.resourceName(harborcluster.Name), metav1.GetOptions{})
+	if err != nil {
+		return databaseNotReadyStatus(GetDatabaseCrError, err.Error()), err
+	}
+
+	image, err := c.GetImage(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(GenerateDatabaseCrError, err.Error()), err
+	}
+
+	current, _, err := unstructured.NestedString(actual.Object, "spec", "imageName")
+	if err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+	}
+
+	if current == image {
+		return c.Readiness(ctx, harborcluster, actual)
+	}
+
+	// Images without a version tag are left to the validation of the CloudNativePG operator.
+	if from, to := postgresMajorVersion(current), postgresMajorVersion(image); from != "" && to != "" && from != to {
+		c.Log.Info("Cannot upgrade the major version of PostgreSQL in place.", "namespace", harborcluster.Namespace, "name", actual.GetName(), "from", current, "to", image)
+
+		return databaseNotReadyStatus(
+			DatabaseUpgradeFailed,
+			fmt.Sprintf("cannot upgrade %s from PostgreSQL %s to %s in place, backup and restore it into a new harbor cluster", actual.GetName(), from, to),
+		), nil
+	}
+
+	if err := unstructured.SetNestedField(actual.Object, image, "spec", "imageName"); err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+	}
+
+	c.Log.Info("Upgrading Database.", "namespace", harborcluster.Namespace, "name", actual.GetName(), "from", current, "to", image)
+
+	if _, err := crdClient.Update(actual, metav1.UpdateOptions{}); err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+	}
+
+	return databaseNotReadyStatus(DatabaseUpgrading, fmt.Sprintf("rolling out %s", image)), nil
+}
+
+// postgresMajorVersion returns the major version of PostgreSQL from the tag of the image, eg 15 for 15.3-1,
+// or an empty string when the tag does not start with a version.
+func postgresMajorVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+
+	tag := image[i+1:]
+
+	end := strings.IndexFunc(tag, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		return tag
+	}
+
+	return tag[:end]
+}
+
+// Deploy creates the CloudNativePG cluster.
+func (c *CloudNativePGController) Deploy(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	expectCR, err := c.GetCloudNativePGCR(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(GenerateDatabaseCrError, err.Error()), err
+	}
+
+	if err := controllerutil.SetControllerReference(harborcluster, expectCR, c.Scheme); err != nil {
+		return databaseNotReadyStatus(SetOwnerReferenceError, err.Error()), err
+	}
+
+	c.Log.Info("Creating Database.", "namespace", harborcluster.Namespace, "name", expectCR.GetName())
+
+	if _, err := crdClient.Create(expectCR, metav1.CreateOptions{}); err != nil {
+		return databaseNotReadyStatus(CreateDatabaseCrError, err.Error()), err
+	}
+
+	return databaseUnknownStatus(), nil
+}
+
+// Update applies the changes of the harbor cluster to the CloudNativePG cluster.
+// Only the fields managed by the operator are updated, the bootstrap and the storage class are immutable.
+func (c *CloudNativePGController) Update(ctx context.Context, harborcluster *goharborv1.HarborCluster, actual *unstructured.Unstructured) error {
+	if common.Equals(ctx, c.Scheme, harborcluster, actual) {
+		return nil
+	}
+
+	expected, err := c.GetCloudNativePGCR(ctx, harborcluster)
+	if err != nil {
+		return err
+	}
+
+	for _, fields := range [][]string{
+		{"spec", "instances"},
+		{"spec", "imageName"},
+		{"spec", "imagePullPolicy"},
+		{"spec", "imagePullSecrets"},
+		{"spec", "postgresql", "parameters"},
+		{"spec", "storage", "size"},
+		{"spec", "resources"},
+	} {
+		value, found, err := unstructured.NestedFieldNoCopy(expected.Object, fields...)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			unstructured.RemoveNestedField(actual.Object, fields...)
+
+			continue
+		}
+
+		if err := unstructured.SetNestedField(actual.Object, value, fields...); err != nil {
+			return err
+		}
+	}
+
+	annotations := actual.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for key, value := range expected.GetAnnotations() {
+		annotations[key] = value
+	}
+
+	actual.SetAnnotations(annotations)
+
+	c.Log.Info("Update Database resource", "namespace", harborcluster.Namespace, "name", actual.GetName())
+
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	_, err = crdClient.Update(actual, metav1.UpdateOptions{})
+
+	return err
+}
+
+// GetCloudNativePGCR returns the CloudNativePG cluster of the harbor cluster.
+func (c *CloudNativePGController) GetCloudNativePGCR(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*unstructured.Unstructured, error) {
+	spec := harborcluster.Spec.Database.Spec.CloudNativePG
+
+	image, err := c.GetImage(ctx, harborcluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot get image")
+	}
+
+	instances := spec.Instances
+	if instances == 0 {
+		instances = DefaultDatabaseReplica
+	}
+
+	size := spec.Storage
+	if size == "" {
+		size = DefaultDatabaseMemory
+	}
+
+	var storageClass *string
+	if spec.StorageClassName != "" {
+		storageClass = &spec.StorageClassName
+	}
+
+	parameters := map[string]string{
+		"max_connections": getMaxConnections(c.Log, c.ConfigStore),
+	}
+
+	for key, value := range spec.Parameters {
+		parameters[key] = value
+	}
+
+	pullSecrets := make([]api.CloudNativePGLocalObjectReference, 0, len(spec.ImagePullSecrets))
+	for _, secret := range spec.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, api.CloudNativePGLocalObjectReference{Name: secret.Name})
+	}
+
+	conf := &api.CloudNativePGCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       CloudNativePGClusterKind,
+			APIVersion: CloudNativePGClusterGVK.GroupVersion().String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.resourceName(harborcluster.Name),
+			Namespace: harborcluster.Namespace,
+			Labels: map[string]string{
+				k8s.HarborClusterNameLabel: harborcluster.Name,
+			},
+		},
+		Spec: api.CloudNativePGClusterSpec{
+			Instances:        instances,
+			ImageName:        image,
+			ImagePullPolicy:  c.getImagePullPolicy(harborcluster),
+			ImagePullSecrets: pullSecrets,
+			PostgresConfiguration: api.CloudNativePGPostgresConfiguration{
+				Parameters: parameters,
+			},
+			Bootstrap: &api.CloudNativePGBootstrap{
+				InitDB: &api.CloudNativePGBootstrapInitDB{
+					Database:    CoreDatabase,
+					Owner:       DefaultDatabaseUser,
+					PostInitSQL: getPostInitSQL(harborcluster),
+				},
+			},
+			StorageConfiguration: api.CloudNativePGStorageConfiguration{
+				StorageClass: storageClass,
+				Size:         size,
+			},
+			Resources: spec.Resources,
+		},
+	}
+
+	dependencies := checksum.New(c.Scheme)
+	dependencies.Add(ctx, harborcluster, true)
+	dependencies.AddAnnotations(conf)
+
+	mapResult, err := runtime.DefaultUnstructuredConverter.ToUnstructured(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// The status is owned by the CloudNativePG operator.
+	delete(mapResult, "status")
+
+	return &unstructured.Unstructured{Object: mapResult}, nil
+}
+
+// getPostInitSQL returns the statements creating the databases of the harbor components other than core.
+// The harbor user can create databases so it can restore dumps.
+func getPostInitSQL(harborcluster *goharborv1.HarborCluster) []string {
+	statements := []string{
+		fmt.Sprintf(`ALTER ROLE "%s" CREATEDB`, DefaultDatabaseUser),
+	}
+
+	databases := getDatabases(harborcluster)
+
+	names := make([]string, 0, len(databases))
+	for name := range databases {
+		if name != CoreDatabase {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		statements = append(statements, fmt.Sprintf(`CREATE DATABASE "%s" OWNER "%s"`, name, databases[name]))
+	}
+
+	return statements
+}
+
+// GetImage returns the configured image via configstore or default one.
+// The tag of the default image is the major version of PostgreSQL if specified.
+func (c *CloudNativePGController) GetImage(ctx context.Context, harborcluster *goharborv1.HarborCluster) (string, error) {
+	spec := harborcluster.Spec.Database.Spec.CloudNativePG
+
+	options := harborcluster.Spec.ImageSource.AddRepositoryAndTagSuffixOptions(
+		image.WithImageFromSpec(spec.Image),
+		image.WithTag(spec.Version),
+		image.WithHarborVersion(harborcluster.Spec.Version),
+	)
+
+	return image.GetImage(ctx, CloudNativePGComponentName, options...)
+}
+
+func (c *CloudNativePGController) getImagePullPolicy(harborcluster *goharborv1.HarborCluster) corev1.PullPolicy {
+	if harborcluster.Spec.Database.Spec.CloudNativePG.ImagePullPolicy != nil {
+		return *harborcluster.Spec.Database.Spec.CloudNativePG.ImagePullPolicy
+	}
+
+	if harborcluster.Spec.ImageSource != nil && harborcluster.Spec.ImageSource.ImagePullPolicy != nil {
+		return *harborcluster.Spec.ImageSource.ImagePullPolicy
+	}
+
+	return config.DefaultImagePullPolicy
+}
+
+// resourceName returns the name of the CloudNativePG cluster.
+func (c *CloudNativePGController) resourceName(name string) string {
+	return fmt.Sprintf("%s-%s", name, cloudNativePGSuffix)
+}
+
+// appSecretName returns the name of the secret generated by the CloudNativePG operator for the application user.
+func (c *CloudNativePGController) appSecretName(name string) string {
+	return fmt.Sprintf("%s-app", c.resourceName(name))
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Delete tears down the CloudNativePG cluster of a deleted harbor cluster according to its deletion policy.
+// It returns a nil status once the teardown is complete.
+func (c *CloudNativePGController) Delete(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	switch harborcluster.Spec.Database.GetDeletionPolicy() {
+	case goharborv1.DeletionPolicyRetain:
+		if err := c.retain(ctx, harborcluster); err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		return nil, nil
+	case goharborv1.DeletionPolicySnapshotThenDelete:
+		pvcs, err := c.listDataVolumes(ctx, harborcluster)
+		if err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		done, err := common.SnapshotVolumesBeforeDeletion(ctx, c.DClient, harborcluster, pvcs.Items)
+		if err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		if !done {
+			return databaseNotReadyStatus(DatabaseSnapshotting, "waiting for the volume snapshots to be ready"), nil
+		}
+	case goharborv1.DeletionPolicyDelete:
+	}
+
+	return c.teardown(ctx, harborcluster)
+}
+
+// retain releases the ownership of the harbor cluster on the CloudNativePG cluster and the component secret.
+// The volumes and the generated secrets are owned by the CloudNativePG cluster and are kept with it.
+func (c *CloudNativePGController) retain(ctx context.Context, harborcluster *goharborv1.HarborCluster) error {
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	cr, err := crdClient.Get(c.resourceName(harborcluster.Name), metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil && common.ReleaseOwnership(cr, harborcluster) {
+		c.Log.Info("Releasing Database CR", "namespace", cr.GetNamespace(), "name", cr.GetName())
+
+		if _, err := crdClient.Update(cr, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	secret := &corev1.Secret{}
+
+	err = c.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: getDatabasePasswordRefName(harborcluster.Name)}, secret)
+	if kerr.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !common.ReleaseOwnership(secret, harborcluster) {
+		return nil
+	}
+
+	return c.Client.Update(ctx, secret)
+}
+
+// teardown deletes the CloudNativePG cluster, its volumes and the component secret.
+// It returns a not ready status until all of them are gone.
+func (c *CloudNativePGController) teardown(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	name := c.resourceName(harborcluster.Name)
+	crdClient := c.DClient.DynamicClient(ctx, k8s.WithResource(cloudNativePGClusterGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	_, err := crdClient.Get(name, metav1.GetOptions{})
+	if err == nil {
+		c.Log.Info("Deleting Database CR", "namespace", harborcluster.Namespace, "name", name)
+
+		if err := crdClient.Delete(name, metav1.DeleteOptions{}); err != nil && !kerr.IsNotFound(err) {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		return databaseNotReadyStatus(DatabaseDeleting, fmt.Sprintf("waiting for %s to be deleted", name)), nil
+	} else if !kerr.IsNotFound(err) {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	pvcs, err := c.listDataVolumes(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	for i := range pvcs.Items {
+		if pvcs.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		c.Log.Info("Deleting Database volume", "namespace", harborcluster.Namespace, "name", pvcs.Items[i].GetName())
+
+		if err := c.Client.Delete(ctx, &pvcs.Items[i]); err != nil && !kerr.IsNotFound(err) {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+	}
+
+	secret := &corev1.Secret{}
+	secret.SetName(getDatabasePasswordRefName(harborcluster.Name))
+	secret.SetNamespace(harborcluster.Namespace)
+
+	if err := c.Client.Delete(ctx, secret); err != nil && !kerr.IsNotFound(err) {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	if len(pvcs.Items) > 0 {
+		return databaseNotReadyStatus(DatabaseDeleting, fmt.Sprintf("waiting for the volumes of %s to be deleted", name)), nil
+	}
+
+	return nil, nil
+}
+
+// listDataVolumes returns the PVCs of the instances of the CloudNativePG cluster.
+func (c *CloudNativePGController) listDataVolumes(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*corev1.PersistentVolumeClaimList, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+
+	err := c.Client.List(ctx, pvcs, client.InNamespace(harborcluster.Namespace), client.MatchingLabels{
+		CloudNativePGClusterLabel: c.resourceName(harborcluster.Name),
+	})
+
+	return pvcs, err
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	cloudNativePGReadyCondition = "Ready"
+	cloudNativePGHealthyPhase   = "Cluster in healthy state"
+	cloudNativePGPrimaryRole    = "primary"
+	cloudNativePGRoleLabel      = "cnpg.io/instanceRole"
+)
+
+// Readiness checks the CloudNativePG cluster is healthy, then deploys the harbor component database secret
+// from the application user secret generated by the CloudNativePG operator.
+func (c *CloudNativePGController) Readiness(ctx context.Context, harborcluster *goharborv1.HarborCluster, curUnstructured *unstructured.Unstructured) (*lcm.CRStatus, error) {
+	name := harborcluster.Name
+
+	var cluster api.CloudNativePGCluster
+	if err := runtime.DefaultUnstructuredConverter.
+		FromUnstructured(curUnstructured.UnstructuredContent(), &cluster); err != nil {
+		return nil, err
+	}
+
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, cloudNativePGReadyCondition) && cluster.Status.Phase != cloudNativePGHealthyPhase {
+		return databaseNotReadyStatus(
+			"Database is not ready",
+			fmt.Sprintf("cloudnativepg cluster is %s", cluster.Status.Phase),
+		), nil
+	}
+
+	conn, err := c.GetInClusterDatabaseInfo(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.DeployComponentSecret(ctx, conn, getDatabasePasswordRefName(name), harborcluster); err != nil {
+		return nil, err
+	}
+
+	c.Log.Info("Database is ready.", "namespace", harborcluster.Namespace, "name", name)
+
+	properties := &lcm.Properties{}
+	properties.Add(lcm.DatabasePropertyName, &goharborv1.HarborDatabaseSpec{
+		PostgresCredentials: harbormetav1.PostgresCredentials{
+			Username:    conn.Username,
+			PasswordRef: getDatabasePasswordRefName(name),
+		},
+		Hosts: []harbormetav1.PostgresHostSpec{
+			{
+				Host: conn.Host,
+				Port: InClusterDatabasePortInt32,
+			},
+		},
+		// The CloudNativePG operator always enables TLS, with a self-signed CA by default.
+		SSLMode: harbormetav1.PostgresSSLModeRequire,
+	})
+
+	return databaseReadyStatus(
+		"Database is ready",
+		"Harbor component database secrets are already create",
+		*properties,
+	), nil
+}
+
+// DeployComponentSecret creates or updates the harbor component database secret.
+// The password follows the rotations of the application user secret.
+func (c *CloudNativePGController) DeployComponentSecret(ctx context.Context, conn *Connect, secretName string, harborcluster *goharborv1.HarborCluster) error {
+	secret := &corev1.Secret{}
+	secret.SetName(secretName)
+	secret.SetNamespace(harborcluster.Namespace)
+
+	result, err := controllerutil.CreateOrUpdate(ctx, c.Client, secret, func() error {
+		secret.Data = map[string][]byte{
+			harbormetav1.PostgresqlPasswordKey: []byte(conn.Password),
+		}
+
+		return controllerutil.SetControllerReference(harborcluster, secret, c.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	if result != controllerutil.OperationResultNone {
+		c.Log.Info("Harbor Component Secret deployed", "namespace", harborcluster.Namespace, "name", secretName, "operation", result)
+	}
+
+	return nil
+}
+
+// GetInClusterDatabaseInfo returns the connection of the application user to the primary.
+func (c *CloudNativePGController) GetInClusterDatabaseInfo(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*Connect, error) {
+	secret := &corev1.Secret{}
+	if err := c.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: c.appSecretName(harborcluster.Name)}, secret); err != nil {
+		return nil, err
+	}
+
+	host, err := c.GetInClusterHost(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	username := string(secret.Data["username"])
+	if username == "" {
+		username = DefaultDatabaseUser
+	}
+
+	return &Connect{
+		Host:     host,
+		Port:     InClusterDatabasePort,
+		Password: string(secret.Data[InClusterDatabasePasswordKey]),
+		Username: username,
+		Database: CoreDatabase,
+	}, nil
+}
+
+// GetInClusterHost returns the read-write service of the CloudNativePG cluster,
+// or the primary pod ip when the operator runs out of the cluster.
+func (c *CloudNativePGController) GetInClusterHost(ctx context.Context, harborcluster *goharborv1.HarborCluster) (string, error) {
+	if _, err := rest.InClusterConfig(); err == nil {
+		return fmt.Sprintf("%s-rw.%s.svc", c.resourceName(harborcluster.Name), harborcluster.Namespace), nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.Client.List(ctx, pods, client.InNamespace(harborcluster.Namespace), client.MatchingLabels{
+		CloudNativePGClusterLabel: c.resourceName(harborcluster.Name),
+		cloudNativePGRoleLabel:    cloudNativePGPrimaryRole,
+	}); err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.PodIP != "" {
+			return pod.Status.PodIP, nil
+		}
+	}
+
+	return "", errors.Errorf("primary pod of %s not found", c.resourceName(harborcluster.Name))
+}
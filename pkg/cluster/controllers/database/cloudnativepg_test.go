@@ -0,0 +1,128 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/ovh/configstore"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testCloudNativePGName = testCluster + "-postgresql"
+
+var cloudNativePGGVR = database.CloudNativePGClusterGVK.GroupVersion().WithResource(database.CloudNativePGClusterResourcePlural)
+
+func TestPostgresMajorVersion(t *testing.T) {
+	for image, version := range map[string]string{
+		"ghcr.io/cloudnative-pg/postgresql:15":           "15",
+		"ghcr.io/cloudnative-pg/postgresql:15.3-1":       "15",
+		"registry:5000/postgresql:14.8@sha256:0123abcd":  "14",
+		"registry:5000/postgresql":                       "",
+		"ghcr.io/cloudnative-pg/postgresql:latest":       "",
+		"ghcr.io/cloudnative-pg/postgresql@sha256:abcde": "",
+	} {
+		require.Equal(t, version, database.PostgresMajorVersion(image), image)
+	}
+}
+
+func TestCloudNativePGUpgradeMinor(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newCloudNativePGHarborCluster("postgresql:15.4")
+
+	ctrl, dClient := newCloudNativePGController(t, harborcluster, newCloudNativePGCluster("postgresql:15.3"))
+
+	crs, err := ctrl.Apply(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgrading, crs.Condition.Reason)
+
+	require.Equal(t, "postgresql:15.4", getCloudNativePGImage(ctx, t, dClient))
+}
+
+func TestCloudNativePGUpgradeMajor(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newCloudNativePGHarborCluster("postgresql:16.0")
+
+	ctrl, dClient := newCloudNativePGController(t, harborcluster, newCloudNativePGCluster("postgresql:15.3"))
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgradeFailed, crs.Condition.Reason)
+
+	require.Equal(t, "postgresql:15.3", getCloudNativePGImage(ctx, t, dClient))
+}
+
+func newCloudNativePGHarborCluster(image string) *goharborv1.HarborCluster {
+	return &goharborv1.HarborCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testCluster,
+		},
+		Spec: goharborv1.HarborClusterSpec{
+			Database: goharborv1.Database{
+				Kind: goharborv1.KindDatabaseCloudNativePG,
+				Spec: goharborv1.DatabaseSpec{
+					CloudNativePG: &goharborv1.CloudNativePGSpec{
+						ImageSpec: harbormetav1.ImageSpec{
+							Image: image,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newCloudNativePGCluster(image string) *unstructured.Unstructured {
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(database.CloudNativePGClusterGVK)
+	cluster.SetNamespace(testNamespace)
+	cluster.SetName(testCloudNativePGName)
+
+	if err := unstructured.SetNestedField(cluster.Object, image, "spec", "imageName"); err != nil {
+		panic(err)
+	}
+
+	return cluster
+}
+
+func newCloudNativePGController(t *testing.T, harborcluster *goharborv1.HarborCluster, cluster *unstructured.Unstructured) (*database.CloudNativePGController, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	dClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		cloudNativePGGVR: "ClusterList",
+	}, cluster)
+
+	return &database.CloudNativePGController{
+		Log:         logr.Discard(),
+		DClient:     k8s.NewDynamicClientWrapper(dClient),
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(harborcluster).Build(),
+		Scheme:      scheme,
+		ConfigStore: configstore.NewStore(),
+	}, dClient
+}
+
+func getCloudNativePGImage(ctx context.Context, t *testing.T, dClient *dynamicfake.FakeDynamicClient) string {
+	cluster, err := dClient.Resource(cloudNativePGGVR).Namespace(testNamespace).Get(ctx, testCloudNativePGName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	image, _, err := unstructured.NestedString(cluster.Object, "spec", "imageName")
+	require.NoError(t, err)
+
+	return image
+}
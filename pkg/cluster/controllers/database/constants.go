@@ -8,6 +8,20 @@ const (
 	GetDatabaseCrError                = "Get database CR error"
 	SetOwnerReferenceError            = "Set owner reference error"
 	DefaultUnstructuredConverterError = "Default unstructured converter error"
+	UpgradeDatabaseCrError            = "Upgrade database CR error"
+	UpgradeDatabaseBackupError        = "Upgrade database backup error"
+	DeleteDatabaseError               = "Delete database error"
+	UnsupportedDatabaseKindError      = "Unsupported database kind"
+)
+
+const (
+	DatabaseUpgradeBackingUp = "Database upgrade backing up"
+	DatabaseUpgrading        = "Database upgrading"
+	DatabaseUpgraded         = "Database upgraded"
+	DatabaseUpgradeFailed    = "Database upgrade failed"
+	DatabaseUpgradeCanceled  = "Database upgrade canceled"
+	DatabaseSnapshotting     = "Database snapshotting before deletion"
+	DatabaseDeleting         = "Database deleting"
 )
 
 const (
@@ -16,6 +30,14 @@ const (
 	InClusterDatabasePasswordKey       = "password"
 )
 
+const (
+	CloudNativePGGroupName             = "postgresql.cnpg.io"
+	CloudNativePGClusterResourcePlural = "clusters"
+	CloudNativePGClusterKind           = "Cluster"
+	// CloudNativePGClusterLabel is set by the CloudNativePG operator on the pods and volumes of a cluster.
+	CloudNativePGClusterLabel = "cnpg.io/cluster"
+)
+
 const (
 	PostgresCRDResourcePlural = "postgresqls"
 	// GroupName is the group name for the operator CRDs.
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ lcm.Controller    = &Controller{}
+	_ lcm.DataProtector = &Controller{}
+)
+
+type provider interface {
+	lcm.Controller
+	lcm.DataProtector
+}
+
+// Controller manages the in-cluster database with the provider of the database kind.
+type Controller struct {
+	ZalandoPostgreSQL *PostgreSQLController
+	CloudNativePG     *CloudNativePGController
+}
+
+func (c *Controller) provider(harborcluster *goharborv1.HarborCluster) (provider, error) {
+	switch kind := harborcluster.Spec.Database.Kind; kind {
+	case goharborv1.KindDatabaseZlandoPostgreSQL:
+		return c.ZalandoPostgreSQL, nil
+	case goharborv1.KindDatabaseCloudNativePG:
+		return c.CloudNativePG, nil
+	default:
+		return nil, errors.Errorf("unsupported database kind %s", kind)
+	}
+}
+
+func (c *Controller) Apply(ctx context.Context, harborcluster *goharborv1.HarborCluster, options ...lcm.Option) (*lcm.CRStatus, error) {
+	p, err := c.provider(harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(UnsupportedDatabaseKindError, err.Error()), err
+	}
+
+	return p.Apply(ctx, harborcluster, options...)
+}
+
+func (c *Controller) Delete(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	p, err := c.provider(harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(UnsupportedDatabaseKindError, err.Error()), err
+	}
+
+	return p.Delete(ctx, harborcluster)
+}
+
+func (c *Controller) Upgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	p, err := c.provider(harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(UnsupportedDatabaseKindError, err.Error()), err
+	}
+
+	return p.Upgrade(ctx, harborcluster)
+}
+
+func (c *Controller) BackupJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	p, err := c.provider(harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.BackupJob(ctx, harborcluster)
+}
+
+func (c *Controller) RestoreJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	p, err := c.provider(harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.RestoreJob(ctx, harborcluster)
+}
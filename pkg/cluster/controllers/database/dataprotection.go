@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ lcm.DataProtector = &PostgreSQLController{}
+
+// The databases are dumped one by one, the roles are managed by the postgres operator of each cluster.
+const databaseBackupScript = `
+set -e
+until pg_isready -h "$PGHOST" -p "$PGPORT"; do
+  echo "Waiting for $PGHOST:$PGPORT"
+  sleep 2
+done
+mkdir -p "$BACKUP_DIR"
+for db in $(psql -d postgres -Atc "SELECT datname FROM pg_database WHERE NOT datistemplate AND datname <> 'postgres'"); do
+  echo "Dumping database $db"
+  pg_dump -d "$db" --clean --if-exists --create | gzip > "$BACKUP_DIR/$db.sql.gz"
+done
+`
+
+const databaseRestoreScript = `
+set -e
+until pg_isready -h "$PGHOST" -p "$PGPORT"; do
+  echo "Waiting for $PGHOST:$PGPORT"
+  sleep 2
+done
+for file in "$BACKUP_DIR"/*.sql.gz; do
+  db=$(basename "$file" .sql.gz)
+  echo "Restoring database $db"
+  # Harbor components reconnect as soon as their connections are terminated.
+  psql -d postgres -c "ALTER DATABASE \"$db\" ALLOW_CONNECTIONS false" || true
+  psql -d postgres -c "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = '$db' AND pid <> pg_backend_pid()"
+  gunzip -c "$file" | psql -d postgres -v ON_ERROR_STOP=1
+done
+`
+
+// BackupJob returns the container dumping the databases of the in-cluster PostgreSQL.
+func (p *PostgreSQLController) BackupJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return p.dataJob(ctx, harborcluster, "pg-dump", databaseBackupScript)
+}
+
+// RestoreJob returns the container replacing the databases of the in-cluster PostgreSQL with the dumps.
+func (p *PostgreSQLController) RestoreJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return p.dataJob(ctx, harborcluster, "pg-restore", databaseRestoreScript)
+}
+
+func (p *PostgreSQLController) dataJob(ctx context.Context, harborcluster *goharborv1.HarborCluster, name, script string) (*lcm.DataJob, error) {
+	image, err := p.GetImage(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := p.GetInClusterDatabaseConn(ctx, harborcluster, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resName := p.resourceName(harborcluster.Namespace, harborcluster.Name)
+
+	return newDatabaseDataJob(name, script, image, p.getImagePullPolicy(harborcluster), harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.ImagePullSecrets, conn, &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: GenInClusterPasswordSecretName(conn.Username, resName),
+		},
+		Key: InClusterDatabasePasswordKey,
+	}), nil
+}
+
+// BackupJob returns the container dumping the databases of the CloudNativePG cluster.
+func (c *CloudNativePGController) BackupJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return c.dataJob(ctx, harborcluster, "pg-dump", databaseBackupScript)
+}
+
+// RestoreJob returns the container replacing the databases of the CloudNativePG cluster with the dumps.
+func (c *CloudNativePGController) RestoreJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return c.dataJob(ctx, harborcluster, "pg-restore", databaseRestoreScript)
+}
+
+func (c *CloudNativePGController) dataJob(ctx context.Context, harborcluster *goharborv1.HarborCluster, name, script string) (*lcm.DataJob, error) {
+	image, err := c.GetImage(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.GetInClusterDatabaseInfo(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDatabaseDataJob(name, script, image, c.getImagePullPolicy(harborcluster), harborcluster.Spec.Database.Spec.CloudNativePG.ImagePullSecrets, conn, &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: c.appSecretName(harborcluster.Name),
+		},
+		Key: InClusterDatabasePasswordKey,
+	}), nil
+}
+
+func newDatabaseDataJob(name, script, image string, pullPolicy corev1.PullPolicy, pullSecrets []corev1.LocalObjectReference, conn *Connect, password *corev1.SecretKeySelector) *lcm.DataJob {
+	return &lcm.DataJob{
+		ImagePullSecrets: pullSecrets,
+		Container: corev1.Container{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: pullPolicy,
+			Command:         []string{"bash", "-c", script},
+			Env: []corev1.EnvVar{{
+				Name:  "PGHOST",
+				Value: conn.Host,
+			}, {
+				Name:  "PGPORT",
+				Value: conn.Port,
+			}, {
+				Name:  "PGUSER",
+				Value: conn.Username,
+			}, {
+				Name: "PGPASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: password,
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/common"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Delete tears down the in-cluster database of a deleted harbor cluster according to its deletion policy.
+// It returns a nil status once the teardown is complete.
+func (p *PostgreSQLController) Delete(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	switch harborcluster.Spec.Database.GetDeletionPolicy() {
+	case goharborv1.DeletionPolicyRetain:
+		if err := p.retain(ctx, harborcluster); err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		return nil, nil
+	case goharborv1.DeletionPolicySnapshotThenDelete:
+		pvcs, err := p.listDataVolumes(ctx, harborcluster)
+		if err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		done, err := common.SnapshotVolumesBeforeDeletion(ctx, p.DClient, harborcluster, pvcs.Items)
+		if err != nil {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		if !done {
+			return databaseNotReadyStatus(DatabaseSnapshotting, "waiting for the volume snapshots to be ready"), nil
+		}
+	case goharborv1.DeletionPolicyDelete:
+	}
+
+	return p.teardown(ctx, harborcluster)
+}
+
+// retain releases the ownership of the harbor cluster on the database resources.
+// The volumes of the spilo statefulset are not owned by the harbor cluster and are kept by the postgres operator.
+func (p *PostgreSQLController) retain(ctx context.Context, harborcluster *goharborv1.HarborCluster) error {
+	crdClient := p.DClient.DynamicClient(ctx, k8s.WithResource(databaseGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	cr, err := crdClient.Get(p.resourceName(harborcluster.Namespace, harborcluster.Name), metav1.GetOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil && common.ReleaseOwnership(cr, harborcluster) {
+		p.Log.Info("Releasing Database CR", "namespace", cr.GetNamespace(), "name", cr.GetName())
+
+		if _, err := crdClient.Update(cr, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	secrets, err := p.listSecrets(ctx, harborcluster)
+	if err != nil {
+		return err
+	}
+
+	for i := range secrets {
+		if !common.ReleaseOwnership(&secrets[i], harborcluster) {
+			continue
+		}
+
+		if err := p.Client.Update(ctx, &secrets[i]); err != nil {
+			return err
+		}
+	}
+
+	backups := &corev1.PersistentVolumeClaimList{}
+	if err := p.Client.List(ctx, backups, client.InNamespace(harborcluster.Namespace), client.MatchingLabels{
+		"job-type":                 backupJobType,
+		k8s.HarborClusterNameLabel: harborcluster.Name,
+	}); err != nil {
+		return err
+	}
+
+	for i := range backups.Items {
+		if !common.ReleaseOwnership(&backups.Items[i], harborcluster) {
+			continue
+		}
+
+		if err := p.Client.Update(ctx, &backups.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// teardown deletes the database CR, its volumes and its secrets.
+// It returns a not ready status until all of them are gone.
+func (p *PostgreSQLController) teardown(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	name := p.resourceName(harborcluster.Namespace, harborcluster.Name)
+	crdClient := p.DClient.DynamicClient(ctx, k8s.WithResource(databaseGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	_, err := crdClient.Get(name, metav1.GetOptions{})
+	if err == nil {
+		p.Log.Info("Deleting Database CR", "namespace", harborcluster.Namespace, "name", name)
+
+		if err := crdClient.Delete(name, metav1.DeleteOptions{}); err != nil && !kerr.IsNotFound(err) {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+
+		return databaseNotReadyStatus(DatabaseDeleting, fmt.Sprintf("waiting for %s to be deleted", name)), nil
+	} else if !kerr.IsNotFound(err) {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	pvcs, err := p.listDataVolumes(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	for i := range pvcs.Items {
+		if pvcs.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+
+		p.Log.Info("Deleting Database volume", "namespace", harborcluster.Namespace, "name", pvcs.Items[i].GetName())
+
+		if err := p.Client.Delete(ctx, &pvcs.Items[i]); err != nil && !kerr.IsNotFound(err) {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+	}
+
+	secrets, err := p.listSecrets(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+	}
+
+	for i := range secrets {
+		if err := p.Client.Delete(ctx, &secrets[i]); err != nil && !kerr.IsNotFound(err) {
+			return databaseNotReadyStatus(DeleteDatabaseError, err.Error()), err
+		}
+	}
+
+	if len(pvcs.Items) > 0 {
+		return databaseNotReadyStatus(DatabaseDeleting, fmt.Sprintf("waiting for the volumes of %s to be deleted", name)), nil
+	}
+
+	return nil, nil
+}
+
+// listDataVolumes returns the PVCs of the spilo statefulset.
+func (p *PostgreSQLController) listDataVolumes(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*corev1.PersistentVolumeClaimList, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+
+	err := p.Client.List(ctx, pvcs, client.InNamespace(harborcluster.Namespace), client.MatchingLabels{
+		"application":  "spilo",
+		"cluster-name": p.resourceName(harborcluster.Namespace, harborcluster.Name),
+	})
+
+	return pvcs, err
+}
+
+// listSecrets returns the secrets generated by the postgres operator and the harbor component database secret.
+func (p *PostgreSQLController) listSecrets(ctx context.Context, harborcluster *goharborv1.HarborCluster) ([]corev1.Secret, error) {
+	resName := p.resourceName(harborcluster.Namespace, harborcluster.Name)
+
+	names := []string{getDatabasePasswordRefName(harborcluster.Name)}
+	for _, user := range []string{DefaultDatabaseUser, superUser, standbyUser} {
+		names = append(names, GenInClusterPasswordSecretName(user, resName))
+	}
+
+	secrets := make([]corev1.Secret, 0, len(names))
+
+	for _, name := range names {
+		secret := corev1.Secret{}
+
+		err := p.Client.Get(ctx, types.NamespacedName{Namespace: harborcluster.Namespace, Name: name}, &secret)
+		if kerr.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
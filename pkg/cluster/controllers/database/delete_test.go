@@ -0,0 +1,106 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeleteRetain(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	harborcluster.UID = "harborcluster-uid"
+	harborcluster.Spec.Database.DeletionPolicy = goharborv1.DeletionPolicyRetain
+
+	pg := newPostgresql("13", "spilo:13", nil, "")
+	pg.SetOwnerReferences([]metav1.OwnerReference{ownerReference(harborcluster)})
+
+	secret := newDatabaseSecret(harborcluster)
+
+	ctrl, dClient := newController(t, harborcluster, pg, secret, newDataVolume())
+
+	crs, err := ctrl.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Nil(t, crs)
+
+	require.Empty(t, getPostgresql(ctx, t, dClient).GetOwnerReferences())
+
+	require.NoError(t, ctrl.Client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secret.Name}, secret))
+	require.Empty(t, secret.GetOwnerReferences())
+
+	require.NoError(t, ctrl.Client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pgdata-" + testCRName + "-0"}, &corev1.PersistentVolumeClaim{}))
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	harborcluster.Spec.Database.DeletionPolicy = goharborv1.DeletionPolicyDelete
+
+	secret := newDatabaseSecret(harborcluster)
+
+	ctrl, dClient := newController(t, harborcluster, newPostgresql("13", "spilo:13", nil, ""), secret, newDataVolume())
+
+	// The CR is deleted first, the postgres operator removes the statefulset
+	crs, err := ctrl.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseDeleting, crs.Condition.Reason)
+
+	_, err = dClient.Resource(databaseGVR).Namespace(testNamespace).Get(ctx, testCRName, metav1.GetOptions{})
+	require.True(t, kerr.IsNotFound(err))
+
+	// Then the volumes and the secrets
+	crs, err = ctrl.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseDeleting, crs.Condition.Reason)
+
+	err = ctrl.Client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "pgdata-" + testCRName + "-0"}, &corev1.PersistentVolumeClaim{})
+	require.True(t, kerr.IsNotFound(err))
+
+	err = ctrl.Client.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secret.Name}, &corev1.Secret{})
+	require.True(t, kerr.IsNotFound(err))
+
+	crs, err = ctrl.Delete(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Nil(t, crs)
+}
+
+func ownerReference(harborcluster *goharborv1.HarborCluster) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: goharborv1.GroupVersion.String(),
+		Kind:       "HarborCluster",
+		Name:       harborcluster.Name,
+		UID:        harborcluster.UID,
+	}
+}
+
+func newDatabaseSecret(harborcluster *goharborv1.HarborCluster) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            harborcluster.Name + "-database-password",
+			OwnerReferences: []metav1.OwnerReference{ownerReference(harborcluster)},
+		},
+	}
+}
+
+func newDataVolume() *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      "pgdata-" + testCRName + "-0",
+			Labels: map[string]string{
+				"application":  "spilo",
+				"cluster-name": testCRName,
+			},
+		},
+	}
+}
@@ -0,0 +1,4 @@
+package database
+
+var NeedsUpgrade = needsUpgrade
+var PostgresMajorVersion = postgresMajorVersion
@@ -50,6 +50,9 @@ func (p *PostgreSQLController) GetPostgresCR(ctx context.Context, harborcluster
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      p.resourceName(harborcluster.Namespace, harborcluster.Name),
 			Namespace: harborcluster.Namespace,
+			Annotations: map[string]string{
+				OperatorVersionAnnotation: p.GetOperatorVersion(harborcluster),
+			},
 		},
 		Spec: api.PostgresSpec{
 			Volume: api.Volume{
@@ -18,7 +18,9 @@ import (
 	"context"
 
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/config"
 	"github.com/goharbor/harbor-operator/pkg/image"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -34,3 +36,15 @@ func (p *PostgreSQLController) GetImage(ctx context.Context, harborcluster *goha
 
 	return image.GetImage(ctx, ComponentName, options...)
 }
+
+func (p *PostgreSQLController) getImagePullPolicy(harborcluster *goharborv1.HarborCluster) corev1.PullPolicy {
+	if harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.ImagePullPolicy != nil {
+		return *harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.ImagePullPolicy
+	}
+
+	if harborcluster.Spec.ImageSource != nil && harborcluster.Spec.ImageSource.ImagePullPolicy != nil {
+		return *harborcluster.Spec.ImageSource.ImagePullPolicy
+	}
+
+	return config.DefaultImagePullPolicy
+}
@@ -40,6 +40,15 @@ func (p *PostgreSQLController) Apply(ctx context.Context, harborcluster *goharbo
 		return databaseNotReadyStatus(GetDatabaseCrError, err.Error()), err
 	}
 
+	upgrade, err := p.upgradeRequired(ctx, harborcluster, actualUnstructured)
+	if err != nil {
+		return databaseNotReadyStatus(GenerateDatabaseCrError, err.Error()), err
+	}
+
+	if upgrade {
+		return p.Upgrade(ctx, harborcluster)
+	}
+
 	if _, err := p.Update(ctx, harborcluster, actualUnstructured); err != nil {
 		return databaseNotReadyStatus(CheckDatabaseHealthError, err.Error()), err
 	}
@@ -47,14 +56,8 @@ func (p *PostgreSQLController) Apply(ctx context.Context, harborcluster *goharbo
 	return p.Readiness(ctx, harborcluster, actualUnstructured)
 }
 
-func (p *PostgreSQLController) Delete(_ context.Context, _ *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
-	panic("implement me")
-}
-
-func (p *PostgreSQLController) Upgrade(_ context.Context, _ *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
-	panic("implement me")
-}
-
+// NewDatabaseController returns the controller of the in-cluster database,
+// it dispatches to the provider of the database kind of each harbor cluster.
 func NewDatabaseController(options ...k8s.Option) lcm.Controller {
 	o := &k8s.CtrlOptions{}
 
@@ -62,11 +65,20 @@ func NewDatabaseController(options ...k8s.Option) lcm.Controller {
 		option(o)
 	}
 
-	return &PostgreSQLController{
-		Log:         o.Log,
-		DClient:     o.DClient,
-		Client:      o.Client,
-		Scheme:      o.Scheme,
-		ConfigStore: o.ConfigStore,
+	return &Controller{
+		ZalandoPostgreSQL: &PostgreSQLController{
+			Log:         o.Log,
+			DClient:     o.DClient,
+			Client:      o.Client,
+			Scheme:      o.Scheme,
+			ConfigStore: o.ConfigStore,
+		},
+		CloudNativePG: &CloudNativePGController{
+			Log:         o.Log,
+			DClient:     o.DClient,
+			Client:      o.Client,
+			Scheme:      o.Scheme,
+			ConfigStore: o.ConfigStore,
+		},
 	}
 }
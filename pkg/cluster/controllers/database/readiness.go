@@ -67,11 +67,11 @@ func (p *PostgreSQLController) Readiness(ctx context.Context, harborcluster *goh
 		return nil, err
 	}
 
-	if err := p.SetRefSecretOwner(ctx, "postgres", harborcluster); err != nil {
+	if err := p.SetRefSecretOwner(ctx, superUser, harborcluster); err != nil {
 		return nil, err
 	}
 
-	if err := p.SetRefSecretOwner(ctx, "standby", harborcluster); err != nil {
+	if err := p.SetRefSecretOwner(ctx, standbyUser, harborcluster); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,375 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	acidzalandov1 "github.com/zalando/postgres-operator/pkg/apis/acid.zalan.do/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OperatorVersionAnnotation records the zalando operator version the postgresql CR has been applied with.
+	OperatorVersionAnnotation = "goharbor.io/postgresql-operator-version"
+	// UpgradeFromVersionAnnotation records the postgresql version an in-flight upgrade started from.
+	UpgradeFromVersionAnnotation = "goharbor.io/postgresql-upgrade-from-version"
+	// UpgradeFromImageAnnotation records the spilo image an in-flight upgrade started from.
+	UpgradeFromImageAnnotation = "goharbor.io/postgresql-upgrade-from-image"
+	// UpgradeFromOperatorVersionAnnotation records the operator version an in-flight upgrade started from.
+	UpgradeFromOperatorVersionAnnotation = "goharbor.io/postgresql-upgrade-from-operator-version"
+	// UpgradeFromBackupAnnotation records the name of the backup taken before an in-flight upgrade.
+	UpgradeFromBackupAnnotation = "goharbor.io/postgresql-upgrade-from-backup"
+)
+
+var upgradeFromAnnotations = []string{
+	UpgradeFromVersionAnnotation,
+	UpgradeFromImageAnnotation,
+	UpgradeFromOperatorVersionAnnotation,
+	UpgradeFromBackupAnnotation,
+}
+
+// upgradeTarget describes the version a postgresql cluster runs or should run.
+type upgradeTarget struct {
+	Version         string
+	Image           string
+	OperatorVersion string
+}
+
+func (t upgradeTarget) String() string {
+	if t.OperatorVersion == "" {
+		return fmt.Sprintf("postgresql %s", t.Version)
+	}
+
+	return fmt.Sprintf("postgresql %s (operator %s)", t.Version, t.OperatorVersion)
+}
+
+func currentTarget(pg *api.Postgresql) upgradeTarget {
+	return upgradeTarget{
+		Version:         pg.Spec.PgVersion,
+		Image:           pg.Spec.DockerImage,
+		OperatorVersion: pg.GetAnnotations()[OperatorVersionAnnotation],
+	}
+}
+
+func previousTarget(pg *api.Postgresql) upgradeTarget {
+	annotations := pg.GetAnnotations()
+
+	return upgradeTarget{
+		Version:         annotations[UpgradeFromVersionAnnotation],
+		Image:           annotations[UpgradeFromImageAnnotation],
+		OperatorVersion: annotations[UpgradeFromOperatorVersionAnnotation],
+	}
+}
+
+func isUpgrading(pg *api.Postgresql) bool {
+	_, ok := pg.GetAnnotations()[UpgradeFromVersionAnnotation]
+
+	return ok
+}
+
+// upgradeRequired checks whether the running postgresql cluster has to go through the upgrade flow.
+func (p *PostgreSQLController) upgradeRequired(ctx context.Context, harborcluster *goharborv1.HarborCluster, curUnstructured *unstructured.Unstructured) (bool, error) {
+	expectUnstructured, err := p.GetPostgresCR(ctx, harborcluster)
+	if err != nil {
+		return false, err
+	}
+
+	var actualCR, expectCR api.Postgresql
+
+	if err := runtime.DefaultUnstructuredConverter.
+		FromUnstructured(curUnstructured.UnstructuredContent(), &actualCR); err != nil {
+		return false, err
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.
+		FromUnstructured(expectUnstructured.UnstructuredContent(), &expectCR); err != nil {
+		return false, err
+	}
+
+	return needsUpgrade(&actualCR, &expectCR), nil
+}
+
+// needsUpgrade returns true if the major version or the operator version of the running cluster
+// differ from the expected ones. Image only changes are left to the regular update.
+func needsUpgrade(actual, expected *api.Postgresql) bool {
+	if isUpgrading(actual) {
+		return true
+	}
+
+	current, desired := currentTarget(actual), currentTarget(expected)
+
+	if current.Version != desired.Version {
+		return true
+	}
+
+	// Clusters created before the operator version was tracked are not upgraded.
+	return current.OperatorVersion != "" && current.OperatorVersion != desired.OperatorVersion
+}
+
+// Upgrade reconcile will upgrade the postgresql cluster to the expected version.
+// It does:
+// - backup the database (dump or volume snapshot) before any change
+// - patch the postgresqls.acid.zalan.do CR with the new version, the zalando operator rolls the pods
+// - gate the upgrade completion on the cluster readiness
+// - report the failure if the zalando operator reports one, the data directory may already be upgraded
+// so the cluster is left as is for a manual restore from the backup
+// - apply the spec changed during an in-flight upgrade, eg an image able to run the targeted version,
+// and cancel the upgrade if the spec returns to the version it started from.
+func (p *PostgreSQLController) Upgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.CRStatus, error) {
+	crdClient := p.DClient.DynamicClient(ctx, k8s.WithResource(databaseGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	actualUnstructured, err := crdClient.Get(p.resourceName(harborcluster.Namespace, harborcluster.Name), metav1.GetOptions{})
+	if err != nil {
+		return databaseNotReadyStatus(GetDatabaseCrError, err.Error()), err
+	}
+
+	expectUnstructured, err := p.GetPostgresCR(ctx, harborcluster)
+	if err != nil {
+		return databaseNotReadyStatus(GenerateDatabaseCrError, err.Error()), err
+	}
+
+	var actualCR, expectCR api.Postgresql
+
+	if err := runtime.DefaultUnstructuredConverter.
+		FromUnstructured(actualUnstructured.UnstructuredContent(), &actualCR); err != nil {
+		return databaseNotReadyStatus(DefaultUnstructuredConverterError, err.Error()), err
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.
+		FromUnstructured(expectUnstructured.UnstructuredContent(), &expectCR); err != nil {
+		return databaseNotReadyStatus(DefaultUnstructuredConverterError, err.Error()), err
+	}
+
+	if isUpgrading(&actualCR) {
+		if currentTarget(&expectCR) != currentTarget(&actualCR) {
+			return p.retargetUpgrade(ctx, harborcluster, &actualCR, &expectCR)
+		}
+
+		return p.checkUpgrade(ctx, harborcluster, &actualCR)
+	}
+
+	current, desired := currentTarget(&actualCR), currentTarget(&expectCR)
+
+	crs, done, err := p.backupBeforeUpgrade(ctx, harborcluster, &actualCR, current)
+	if err != nil || !done {
+		return crs, err
+	}
+
+	p.Log.Info(
+		"Upgrading Database",
+		"namespace", harborcluster.Namespace, "name", actualCR.GetName(),
+		"from", current.String(), "to", desired.String(),
+	)
+
+	expectCR.SetOwnerReferences(actualCR.GetOwnerReferences())
+	expectCR.SetResourceVersion(actualCR.GetResourceVersion())
+
+	annotations := expectCR.GetAnnotations()
+	annotations[UpgradeFromVersionAnnotation] = current.Version
+	annotations[UpgradeFromImageAnnotation] = current.Image
+	annotations[UpgradeFromOperatorVersionAnnotation] = current.OperatorVersion
+	annotations[UpgradeFromBackupAnnotation] = p.upgradeBackupName(harborcluster, current)
+	expectCR.SetAnnotations(annotations)
+
+	if err := p.updatePostgresCR(ctx, harborcluster, &expectCR); err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+	}
+
+	return databaseNotReadyStatus(
+		DatabaseUpgrading,
+		fmt.Sprintf("upgrading from %s to %s", current, desired),
+	), nil
+}
+
+// checkUpgrade gates the completion of an in-flight upgrade on the zalando cluster status.
+func (p *PostgreSQLController) checkUpgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql) (*lcm.CRStatus, error) {
+	previous, current := previousTarget(actualCR), currentTarget(actualCR)
+
+	switch actualCR.Status.PostgresClusterStatus {
+	case acidzalandov1.ClusterStatusRunning:
+		rolled, err := p.clusterPodsRolled(ctx, harborcluster, actualCR)
+		if err != nil {
+			return databaseNotReadyStatus(CheckDatabaseHealthError, err.Error()), err
+		}
+
+		if !rolled {
+			return databaseNotReadyStatus(
+				DatabaseUpgrading,
+				fmt.Sprintf("upgrading from %s to %s: waiting for the pods to be rolled", previous, current),
+			), nil
+		}
+
+		p.Log.Info(
+			"Database upgrade complete",
+			"namespace", harborcluster.Namespace, "name", actualCR.GetName(),
+			"from", previous.String(), "to", current.String(),
+		)
+
+		annotations := actualCR.GetAnnotations()
+		for _, key := range upgradeFromAnnotations {
+			delete(annotations, key)
+		}
+
+		actualCR.SetAnnotations(annotations)
+
+		if err := p.updatePostgresCR(ctx, harborcluster, actualCR); err != nil {
+			return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+		}
+
+		return databaseUnknownStatus().
+			WithReason(DatabaseUpgraded).
+			WithMessage(fmt.Sprintf("upgraded from %s to %s", previous, current)), nil
+	case acidzalandov1.ClusterStatusUpdateFailed, acidzalandov1.ClusterStatusSyncFailed, acidzalandov1.ClusterStatusInvalid:
+		// pg_upgrade may already have run on the data directory, restoring the previous version
+		// of the CR would start the previous postgresql on upgraded data: the cluster is left as is
+		// until the spec is changed, see retargetUpgrade.
+		p.Log.Info(
+			"Database upgrade failed",
+			"namespace", harborcluster.Namespace, "name", actualCR.GetName(),
+			"from", previous.String(), "to", current.String(), "status", actualCR.Status.PostgresClusterStatus,
+		)
+
+		return databaseNotReadyStatus(
+			DatabaseUpgradeFailed,
+			fmt.Sprintf(
+				"upgrade from %s to %s failed: psql is %s, %s",
+				previous, current, actualCR.Status.PostgresClusterStatus, p.upgradeRecoveryHint(harborcluster, actualCR),
+			),
+		), nil
+	default:
+		return databaseNotReadyStatus(
+			DatabaseUpgrading,
+			fmt.Sprintf("upgrading from %s to %s: psql is %s", previous, current, actualCR.Status.PostgresClusterStatus),
+		), nil
+	}
+}
+
+// retargetUpgrade applies the spec changed during an in-flight upgrade.
+// The upgrade goes on to the new target, eg a failed upgrade is retried with an image able to run the targeted version,
+// unless the spec returns to the version the upgrade started from: the upgrade is then canceled.
+func (p *PostgreSQLController) retargetUpgrade(ctx context.Context, harborcluster *goharborv1.HarborCluster, actualCR, expectCR *api.Postgresql) (*lcm.CRStatus, error) {
+	previous, current, desired := previousTarget(actualCR), currentTarget(actualCR), currentTarget(expectCR)
+
+	expectCR.SetOwnerReferences(actualCR.GetOwnerReferences())
+	expectCR.SetResourceVersion(actualCR.GetResourceVersion())
+
+	canceled := desired.Version == previous.Version && desired.OperatorVersion == previous.OperatorVersion
+
+	annotations := expectCR.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	for _, key := range upgradeFromAnnotations {
+		if value, ok := actualCR.GetAnnotations()[key]; ok && !canceled {
+			annotations[key] = value
+		} else {
+			delete(annotations, key)
+		}
+	}
+
+	expectCR.SetAnnotations(annotations)
+
+	p.Log.Info(
+		"Database upgrade target changed",
+		"namespace", harborcluster.Namespace, "name", actualCR.GetName(),
+		"from", previous.String(), "to", current.String(), "target", desired.String(), "canceled", canceled,
+	)
+
+	if err := p.updatePostgresCR(ctx, harborcluster, expectCR); err != nil {
+		return databaseNotReadyStatus(UpgradeDatabaseCrError, err.Error()), err
+	}
+
+	if canceled {
+		return databaseNotReadyStatus(
+			DatabaseUpgradeCanceled,
+			fmt.Sprintf("upgrade from %s to %s canceled, back to %s", previous, current, desired),
+		), nil
+	}
+
+	return databaseNotReadyStatus(
+		DatabaseUpgrading,
+		fmt.Sprintf("upgrading from %s to %s", previous, desired),
+	), nil
+}
+
+// upgradeRecoveryHint tells how to recover the data of the cluster when the upgrade failed.
+func (p *PostgreSQLController) upgradeRecoveryHint(harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql) string {
+	name, ok := actualCR.GetAnnotations()[UpgradeFromBackupAnnotation]
+	if !ok {
+		// Upgrades started before the name of the backup was recorded.
+		name = fmt.Sprintf("%s-pg%s-backup", p.resourceName(harborcluster.Namespace, harborcluster.Name), previousTarget(actualCR).Version)
+	}
+
+	switch harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.GetPreUpgradeBackup() {
+	case goharborv1.PostgreSQLBackupNone:
+		return "fix the cluster manually, no backup was taken before upgrading"
+	case goharborv1.PostgreSQLBackupSnapshot:
+		return fmt.Sprintf("restore the volume snapshot %s to recover the data", name)
+	default:
+		return fmt.Sprintf("restore the dump of the volume %s to recover the data", name)
+	}
+}
+
+// clusterPodsRolled returns true once all the spilo pods run the expected image and are ready.
+func (p *PostgreSQLController) clusterPodsRolled(ctx context.Context, harborcluster *goharborv1.HarborCluster, actualCR *api.Postgresql) (bool, error) {
+	pods := &corev1.PodList{}
+
+	if err := p.Client.List(ctx, pods, client.InNamespace(harborcluster.Namespace), client.MatchingLabels{
+		"application":  "spilo",
+		"cluster-name": actualCR.GetName(),
+	}); err != nil {
+		return false, err
+	}
+
+	if int32(len(pods.Items)) != actualCR.Spec.NumberOfInstances {
+		return false, nil
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			return false, nil
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if container.Name == "postgres" && container.Image != actualCR.Spec.DockerImage {
+				return false, nil
+			}
+		}
+
+		ready := false
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+
+		if !ready {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (p *PostgreSQLController) updatePostgresCR(ctx context.Context, harborcluster *goharborv1.HarborCluster, cr *api.Postgresql) error {
+	crdClient := p.DClient.DynamicClient(ctx, k8s.WithResource(databaseGVR), k8s.WithNamespace(harborcluster.Namespace))
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr)
+	if err != nil {
+		return err
+	}
+
+	_, err = crdClient.Update(&unstructured.Unstructured{Object: data}, metav1.UpdateOptions{})
+
+	return err
+}
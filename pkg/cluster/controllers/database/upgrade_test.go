@@ -0,0 +1,450 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	harbormetav1 "github.com/goharbor/harbor-operator/apis/meta/v1alpha1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database"
+	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
+	"github.com/goharbor/harbor-operator/pkg/cluster/k8s"
+	"github.com/ovh/configstore"
+	"github.com/stretchr/testify/require"
+	acidzalandov1 "github.com/zalando/postgres-operator/pkg/apis/acid.zalan.do/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "harbor"
+	testCluster   = "cluster"
+	testCRName    = "postgresql-harbor-cluster"
+	// testBackupName is the backup taken before upgrading from postgresql 12 with the operator 1.6.1
+	// at the generation 1 of the harbor cluster.
+	testBackupName = testCRName + "-pg12-op1-6-1-g1-backup"
+)
+
+var databaseGVR = database.SchemeGroupVersion.WithResource(database.PostgresCRDResourcePlural)
+
+func TestNeedsUpgrade(t *testing.T) {
+	postgresql := func(version, operatorVersion string, annotations map[string]string) *api.Postgresql {
+		pg := &api.Postgresql{}
+		pg.Spec.PgVersion = version
+		pg.SetAnnotations(map[string]string{})
+
+		if operatorVersion != "" {
+			pg.GetAnnotations()[database.OperatorVersionAnnotation] = operatorVersion
+		}
+
+		for key, value := range annotations {
+			pg.GetAnnotations()[key] = value
+		}
+
+		return pg
+	}
+
+	tests := []struct {
+		description string
+		actual      *api.Postgresql
+		expected    *api.Postgresql
+		upgrade     bool
+	}{
+		{
+			description: "same versions",
+			actual:      postgresql("12", "1.6.1", nil),
+			expected:    postgresql("12", "1.6.1", nil),
+			upgrade:     false,
+		},
+		{
+			description: "major version bump",
+			actual:      postgresql("12", "1.6.1", nil),
+			expected:    postgresql("13", "1.6.1", nil),
+			upgrade:     true,
+		},
+		{
+			description: "operator version bump",
+			actual:      postgresql("12", "1.6.1", nil),
+			expected:    postgresql("12", "1.7.0", nil),
+			upgrade:     true,
+		},
+		{
+			description: "untracked operator version",
+			actual:      postgresql("12", "", nil),
+			expected:    postgresql("12", "1.7.0", nil),
+			upgrade:     false,
+		},
+		{
+			description: "in-flight upgrade",
+			actual:      postgresql("13", "1.6.1", map[string]string{database.UpgradeFromVersionAnnotation: "12"}),
+			expected:    postgresql("13", "1.6.1", nil),
+			upgrade:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.upgrade, database.NeedsUpgrade(tt.actual, tt.expected), tt.description)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	pg := newPostgresql("12", "spilo:12", nil, "")
+
+	ctrl, dClient := newController(t, harborcluster, pg)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionFalse, crs.Condition.Status)
+	require.Equal(t, database.DatabaseUpgrading, crs.Condition.Reason)
+
+	actual := getPostgresql(ctx, t, dClient)
+	require.Equal(t, "13", actual.Spec.PgVersion)
+	require.Equal(t, "spilo:13", actual.Spec.DockerImage)
+	require.Equal(t, "12", actual.GetAnnotations()[database.UpgradeFromVersionAnnotation])
+	require.Equal(t, "spilo:12", actual.GetAnnotations()[database.UpgradeFromImageAnnotation])
+}
+
+func TestUpgradeDumpsFirst(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupDump)
+	pg := newPostgresql("12", "spilo:12", nil, "")
+
+	ctrl, dClient := newController(t, harborcluster, pg)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgradeBackingUp, crs.Condition.Reason)
+
+	backup := types.NamespacedName{Namespace: testNamespace, Name: testBackupName}
+
+	require.NoError(t, ctrl.Client.Get(ctx, backup, &corev1.PersistentVolumeClaim{}))
+
+	job := &batchv1.Job{}
+	require.NoError(t, ctrl.Client.Get(ctx, backup, job))
+
+	// The postgresql CR is not touched until the dump is complete
+	require.Equal(t, "12", getPostgresql(ctx, t, dClient).Spec.PgVersion)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	require.NoError(t, ctrl.Client.Status().Update(ctx, job))
+
+	crs, err = ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgradeFailed, crs.Condition.Reason)
+	require.Equal(t, "12", getPostgresql(ctx, t, dClient).Spec.PgVersion)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, ctrl.Client.Status().Update(ctx, job))
+
+	crs, err = ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgrading, crs.Condition.Reason)
+
+	actual := getPostgresql(ctx, t, dClient)
+	require.Equal(t, "13", actual.Spec.PgVersion)
+	require.Equal(t, testBackupName, actual.GetAnnotations()[database.UpgradeFromBackupAnnotation])
+}
+
+func TestUpgradeIgnoresPreviousBackup(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupDump)
+	harborcluster.SetGeneration(3)
+
+	pg := newPostgresql("12", "spilo:12", nil, "")
+
+	// Backups of previous upgrades from the same postgresql version
+	var previous []client.Object
+
+	for _, name := range []string{testCRName + "-pg12-backup", testBackupName} {
+		previous = append(previous,
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name}},
+			&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+				},
+			},
+		)
+	}
+
+	ctrl, dClient := newController(t, harborcluster, pg, previous...)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgradeBackingUp, crs.Condition.Reason)
+
+	backup := types.NamespacedName{Namespace: testNamespace, Name: testCRName + "-pg12-op1-6-1-g3-backup"}
+	require.NoError(t, ctrl.Client.Get(ctx, backup, &corev1.PersistentVolumeClaim{}))
+	require.NoError(t, ctrl.Client.Get(ctx, backup, &batchv1.Job{}))
+
+	// The postgresql CR is not touched until the new dump is complete
+	actual := getPostgresql(ctx, t, dClient)
+	require.Equal(t, "12", actual.Spec.PgVersion)
+	require.NotContains(t, actual.GetAnnotations(), database.UpgradeFromVersionAnnotation)
+}
+
+func TestUpgradeWaitsForRolledPods(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	pg := newPostgresql("13", "spilo:13", upgradeAnnotations(), acidzalandov1.ClusterStatusRunning)
+
+	ctrl, dClient := newController(t, harborcluster, pg, newSpiloPod("0", "spilo:12"))
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgrading, crs.Condition.Reason)
+	require.Contains(t, getPostgresql(ctx, t, dClient).GetAnnotations(), database.UpgradeFromVersionAnnotation)
+}
+
+func TestUpgradeComplete(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	pg := newPostgresql("13", "spilo:13", upgradeAnnotations(), acidzalandov1.ClusterStatusRunning)
+
+	ctrl, dClient := newController(t, harborcluster, pg, newSpiloPod("0", "spilo:13"))
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionUnknown, crs.Condition.Status)
+	require.Equal(t, database.DatabaseUpgraded, crs.Condition.Reason)
+
+	annotations := getPostgresql(ctx, t, dClient).GetAnnotations()
+	require.NotContains(t, annotations, database.UpgradeFromVersionAnnotation)
+	require.NotContains(t, annotations, database.UpgradeFromImageAnnotation)
+	require.NotContains(t, annotations, database.UpgradeFromOperatorVersionAnnotation)
+	require.NotContains(t, annotations, database.UpgradeFromBackupAnnotation)
+}
+
+func TestUpgradeFailed(t *testing.T) {
+	ctx := context.TODO()
+
+	for _, status := range []string{
+		acidzalandov1.ClusterStatusUpdateFailed,
+		acidzalandov1.ClusterStatusSyncFailed,
+		acidzalandov1.ClusterStatusInvalid,
+	} {
+		harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupSnapshot)
+		pg := newPostgresql("13", "spilo:13", upgradeAnnotations(), status)
+
+		ctrl, dClient := newController(t, harborcluster, pg)
+
+		crs, err := ctrl.Upgrade(ctx, harborcluster)
+		require.NoError(t, err, status)
+		require.Equal(t, corev1.ConditionFalse, crs.Condition.Status, status)
+		require.Equal(t, database.DatabaseUpgradeFailed, crs.Condition.Reason, status)
+		require.Contains(t, crs.Condition.Message, testBackupName, status)
+
+		// The data may already be upgraded, the previous version is not restored
+		actual := getPostgresql(ctx, t, dClient)
+		require.Equal(t, "13", actual.Spec.PgVersion, status)
+		require.Equal(t, "spilo:13", actual.Spec.DockerImage, status)
+		require.Equal(t, "12", actual.GetAnnotations()[database.UpgradeFromVersionAnnotation], status)
+	}
+}
+
+func TestUpgradeRetried(t *testing.T) {
+	ctx := context.TODO()
+
+	// The image of the failed upgrade cannot run postgresql 13
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupNone)
+	pg := newPostgresql("13", "spilo:12", upgradeAnnotations(), acidzalandov1.ClusterStatusUpdateFailed)
+
+	ctrl, dClient := newController(t, harborcluster, pg)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgrading, crs.Condition.Reason)
+
+	actual := getPostgresql(ctx, t, dClient)
+	require.Equal(t, "13", actual.Spec.PgVersion)
+	require.Equal(t, "spilo:13", actual.Spec.DockerImage)
+	require.Equal(t, "12", actual.GetAnnotations()[database.UpgradeFromVersionAnnotation])
+	require.Equal(t, testBackupName, actual.GetAnnotations()[database.UpgradeFromBackupAnnotation])
+}
+
+func TestUpgradeCanceled(t *testing.T) {
+	ctx := context.TODO()
+
+	harborcluster := newHarborCluster("12", goharborv1.PostgreSQLBackupNone)
+	pg := newPostgresql("13", "spilo:13", upgradeAnnotations(), acidzalandov1.ClusterStatusUpdateFailed)
+
+	ctrl, dClient := newController(t, harborcluster, pg)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionFalse, crs.Condition.Status)
+	require.Equal(t, database.DatabaseUpgradeCanceled, crs.Condition.Reason)
+
+	actual := getPostgresql(ctx, t, dClient)
+	require.Equal(t, "12", actual.Spec.PgVersion)
+	require.Equal(t, "spilo:12", actual.Spec.DockerImage)
+
+	for key := range upgradeAnnotations() {
+		require.NotContains(t, actual.GetAnnotations(), key)
+	}
+
+	require.False(t, database.NeedsUpgrade(actual, actual))
+}
+
+func TestUpgradeFailedBeforeBackupNameRecorded(t *testing.T) {
+	ctx := context.TODO()
+
+	annotations := upgradeAnnotations()
+	delete(annotations, database.UpgradeFromBackupAnnotation)
+
+	harborcluster := newHarborCluster("13", goharborv1.PostgreSQLBackupDump)
+	pg := newPostgresql("13", "spilo:13", annotations, acidzalandov1.ClusterStatusUpdateFailed)
+
+	ctrl, _ := newController(t, harborcluster, pg)
+
+	crs, err := ctrl.Upgrade(ctx, harborcluster)
+	require.NoError(t, err)
+	require.Equal(t, database.DatabaseUpgradeFailed, crs.Condition.Reason)
+	require.Contains(t, crs.Condition.Message, testCRName+"-pg12-backup")
+}
+
+func newHarborCluster(version, backup string) *goharborv1.HarborCluster {
+	return &goharborv1.HarborCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  testNamespace,
+			Name:       testCluster,
+			Generation: 1,
+		},
+		Spec: goharborv1.HarborClusterSpec{
+			Database: goharborv1.Database{
+				Kind: goharborv1.KindDatabaseZlandoPostgreSQL,
+				Spec: goharborv1.DatabaseSpec{
+					ZlandoPostgreSQL: &goharborv1.ZlandoPostgreSQLSpec{
+						ImageSpec: harbormetav1.ImageSpec{
+							Image: "spilo:" + version,
+						},
+						OperatorVersion: "1.6.1",
+						Replicas:        1,
+						Version:         version,
+						Upgrade: &goharborv1.PostgreSQLUpgradeSpec{
+							PreUpgradeBackup: backup,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func upgradeAnnotations() map[string]string {
+	return map[string]string{
+		database.UpgradeFromVersionAnnotation:         "12",
+		database.UpgradeFromImageAnnotation:           "spilo:12",
+		database.UpgradeFromOperatorVersionAnnotation: "1.6.1",
+		database.UpgradeFromBackupAnnotation:          testBackupName,
+	}
+}
+
+func newPostgresql(version, image string, annotations map[string]string, status string) *unstructured.Unstructured {
+	pg := &api.Postgresql{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "postgresql",
+			APIVersion: database.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testCRName,
+			Annotations: map[string]string{
+				database.OperatorVersionAnnotation: "1.6.1",
+			},
+		},
+		Spec: api.PostgresSpec{
+			NumberOfInstances: 1,
+			DockerImage:       image,
+			PostgresqlParam: api.PostgresqlParam{
+				PgVersion: version,
+			},
+		},
+		Status: api.PostgresStatus{
+			PostgresClusterStatus: status,
+		},
+	}
+
+	for key, value := range annotations {
+		pg.Annotations[key] = value
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pg)
+	if err != nil {
+		panic(err)
+	}
+
+	return &unstructured.Unstructured{Object: data}
+}
+
+func newSpiloPod(index, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testCRName + "-" + index,
+			Labels: map[string]string{
+				"application":  "spilo",
+				"cluster-name": testCRName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "postgres",
+				Image: image,
+			}},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+}
+
+func newController(t *testing.T, harborcluster *goharborv1.HarborCluster, pg *unstructured.Unstructured, objects ...client.Object) (*database.PostgreSQLController, *dynamicfake.FakeDynamicClient) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	dClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		databaseGVR: "postgresqlList",
+	}, pg)
+
+	objects = append(objects, harborcluster)
+
+	return &database.PostgreSQLController{
+		Log:         logr.Discard(),
+		DClient:     k8s.NewDynamicClientWrapper(dClient),
+		Client:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:      scheme,
+		ConfigStore: configstore.NewStore(),
+	}, dClient
+}
+
+func getPostgresql(ctx context.Context, t *testing.T, dClient *dynamicfake.FakeDynamicClient) *api.Postgresql {
+	data, err := dClient.Resource(databaseGVR).Namespace(testNamespace).Get(ctx, testCRName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	pg := &api.Postgresql{}
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(data.UnstructuredContent(), pg))
+
+	return pg
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/go-logr/logr"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/pkg/cluster/controllers/database/api"
 	"github.com/goharbor/harbor-operator/pkg/config"
+	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -27,6 +29,11 @@ var postgresqlVersions = map[string]string{
 }
 
 func (p *PostgreSQLController) GetDatabases(harborcluster *goharborv1.HarborCluster) map[string]string {
+	return getDatabases(harborcluster)
+}
+
+// getDatabases returns the databases of the harbor components and their owner.
+func getDatabases(harborcluster *goharborv1.HarborCluster) map[string]string {
 	databases := map[string]string{
 		CoreDatabase: DefaultDatabaseUser,
 	}
@@ -123,6 +130,10 @@ func (p *PostgreSQLController) GetPostgreStorageSize(harborcluster *goharborv1.H
 }
 
 func (p *PostgreSQLController) GetPostgreVersion(harborcluster *goharborv1.HarborCluster) (string, error) {
+	if spec := harborcluster.Spec.Database.Spec.ZlandoPostgreSQL; spec != nil && spec.Version != "" {
+		return spec.Version, nil
+	}
+
 	for _, harborVersion := range []string{harborcluster.Spec.Version, "*"} {
 		if version, ok := postgresqlVersions[harborVersion]; ok {
 			return version, nil
@@ -132,6 +143,15 @@ func (p *PostgreSQLController) GetPostgreVersion(harborcluster *goharborv1.Harbo
 	return "", errors.Errorf("postgresql version not found for harbor %s", harborcluster.Spec.Version)
 }
 
+// GetOperatorVersion returns the zalando operator version the database is managed with.
+func (p *PostgreSQLController) GetOperatorVersion(harborcluster *goharborv1.HarborCluster) string {
+	if harborcluster.Spec.Database.Spec.ZlandoPostgreSQL == nil {
+		return ""
+	}
+
+	return harborcluster.Spec.Database.Spec.ZlandoPostgreSQL.OperatorVersion
+}
+
 func (p *PostgreSQLController) GetPostgreParameters() map[string]string {
 	return map[string]string{
 		"max_connections": p.GetPosgresMaxConnections(),
@@ -139,18 +159,22 @@ func (p *PostgreSQLController) GetPostgreParameters() map[string]string {
 }
 
 func (p *PostgreSQLController) GetPosgresMaxConnections() string {
-	maxConnections, err := p.ConfigStore.GetItemValue(ConfigMaxConnectionsKey)
+	return getMaxConnections(p.Log, p.ConfigStore)
+}
+
+func getMaxConnections(log logr.Logger, configStore *configstore.Store) string {
+	maxConnections, err := configStore.GetItemValue(ConfigMaxConnectionsKey)
 	if err != nil {
 		if !config.IsNotFound(err, ConfigMaxConnectionsKey) {
 			// Just logged
-			p.Log.Error(err, "failed to get database max connections")
+			log.Error(err, "failed to get database max connections")
 		}
 
 		maxConnections = DefaultDatabaseMaxConnections
 	}
 
 	if _, err := strconv.ParseInt(maxConnections, baseInt10, basebBitSize); err != nil {
-		p.Log.Error(err, "%s is not a valid number for postgres max connections", maxConnections)
+		log.Error(err, "%s is not a valid number for postgres max connections", maxConnections)
 
 		maxConnections = DefaultDatabaseMaxConnections
 	}
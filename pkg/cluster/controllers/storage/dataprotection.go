@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/cluster/lcm"
+	"github.com/goharbor/harbor-operator/pkg/config"
+	"github.com/goharbor/harbor-operator/pkg/image"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ lcm.DataProtector = &MinIOController{}
+
+// The bucket is mirrored directly from or to bucket targets, it may be too large for a scratch volume.
+const storageTargetScript = `
+set -e
+insecure=${TARGET_INSECURE:+--insecure}
+mc alias set minio "$MINIO_ENDPOINT" "$MINIO_ACCESS_KEY" "$MINIO_SECRET_KEY"
+if [ -n "$TARGET_ENDPOINT" ]; then
+  mc $insecure alias set target "$TARGET_ENDPOINT" "$TARGET_ACCESS_KEY" "$TARGET_SECRET_KEY"
+  location="target/$TARGET_URL"
+else
+  mkdir -p "$BACKUP_DIR"
+  location="$BACKUP_DIR"
+fi
+`
+
+const storageBackupScript = storageTargetScript + `
+mc $insecure mirror --overwrite "minio/$MINIO_BUCKET" "$location"
+`
+
+const storageRestoreScript = storageTargetScript + `
+mc $insecure mirror --overwrite "$location" "minio/$MINIO_BUCKET"
+`
+
+const transferScript = `
+set -e
+insecure=${TARGET_INSECURE:+--insecure}
+mc $insecure alias set target "$TARGET_ENDPOINT" "$TARGET_ACCESS_KEY" "$TARGET_SECRET_KEY"
+`
+
+const uploadScript = transferScript + `
+mc $insecure mirror --overwrite "$BACKUP_DIR" "target/$TARGET_URL"
+`
+
+const downloadScript = transferScript + `
+mkdir -p "$BACKUP_DIR"
+mc $insecure mirror --overwrite "target/$TARGET_URL" "$BACKUP_DIR"
+`
+
+// TransferJob returns the container uploading the BACKUP_DIR directory to a bucket target,
+// or downloading the bucket target into it.
+// The MinIO client image is used, the in-cluster MinIO is not required.
+func TransferJob(ctx context.Context, harborcluster *goharborv1.HarborCluster, upload bool) (*lcm.DataJob, error) {
+	name, script := "download", downloadScript
+	if upload {
+		name, script = "upload", uploadScript
+	}
+
+	var (
+		imageFromSpec    string
+		pullPolicy       = config.DefaultImagePullPolicy
+		imagePullSecrets []corev1.LocalObjectReference
+	)
+
+	if harborcluster.Spec.ImageSource != nil {
+		if harborcluster.Spec.ImageSource.ImagePullPolicy != nil {
+			pullPolicy = *harborcluster.Spec.ImageSource.ImagePullPolicy
+		}
+
+		imagePullSecrets = harborcluster.Spec.ImageSource.ImagePullSecrets
+	}
+
+	if minio := harborcluster.Spec.Storage.Spec.MinIO; minio != nil && minio.MinIOClientSpec != nil {
+		imageFromSpec = minio.MinIOClientSpec.Image
+
+		if minio.MinIOClientSpec.ImagePullPolicy != nil {
+			pullPolicy = *minio.MinIOClientSpec.ImagePullPolicy
+		}
+
+		if len(minio.MinIOClientSpec.ImagePullSecrets) > 0 {
+			imagePullSecrets = minio.MinIOClientSpec.ImagePullSecrets
+		}
+	}
+
+	options := harborcluster.Spec.ImageSource.AddRepositoryAndTagSuffixOptions(
+		image.WithImageFromSpec(imageFromSpec),
+		image.WithHarborVersion(harborcluster.Spec.Version),
+	)
+
+	clientImage, err := image.GetImage(ctx, MinIOClientComponentName, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lcm.DataJob{
+		ImagePullSecrets: imagePullSecrets,
+		RemoteTarget:     true,
+		Container: corev1.Container{
+			Name:            name,
+			Image:           clientImage,
+			ImagePullPolicy: pullPolicy,
+			Command:         []string{"bash", "-c", script},
+		},
+	}, nil
+}
+
+// BackupJob returns the container mirroring the harbor bucket of the in-cluster MinIO.
+func (m *MinIOController) BackupJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return m.dataJob(ctx, harborcluster, "minio-mirror", storageBackupScript)
+}
+
+// RestoreJob returns the container mirroring the backup into the harbor bucket of the in-cluster MinIO.
+func (m *MinIOController) RestoreJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*lcm.DataJob, error) {
+	return m.dataJob(ctx, harborcluster, "minio-restore", storageRestoreScript)
+}
+
+func (m *MinIOController) dataJob(ctx context.Context, harborcluster *goharborv1.HarborCluster, name, script string) (*lcm.DataJob, error) {
+	image, err := m.getMinIOClientImage(ctx, harborcluster)
+	if err != nil {
+		return nil, err
+	}
+
+	secretName := m.getMinIOSecretNamespacedName(harborcluster).Name
+
+	return &lcm.DataJob{
+		ImagePullSecrets: m.getMinIOClientImagePullSecrets(ctx, harborcluster),
+		RemoteTarget:     true,
+		Container: corev1.Container{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: m.getMinIOClientImagePullPolicy(ctx, harborcluster),
+			Command:         []string{"bash", "-c", script},
+			Env: []corev1.EnvVar{{
+				Name:  "MINIO_BUCKET",
+				Value: DefaultBucket,
+			}, {
+				Name:  "MINIO_ENDPOINT",
+				Value: fmt.Sprintf("http://%s.%s.svc:%d", m.getTenantsServiceName(harborcluster), harborcluster.Namespace, m.getServicePort()),
+			}, {
+				Name: "MINIO_ACCESS_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+						Key:                  "accesskey",
+					},
+				},
+			}, {
+				Name: "MINIO_SECRET_KEY",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+						Key:                  "secretkey",
+					},
+				},
+			}},
+		},
+	}, nil
+}
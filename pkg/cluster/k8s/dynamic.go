@@ -62,6 +62,13 @@ func (d *DynamicClientWrapper) DynamicClient(ctx context.Context, options ...Dyn
 	}
 }
 
+// NewDynamicClientWrapper wraps the dynamic interface.
+func NewDynamicClientWrapper(dClient dynamic.Interface) *DynamicClientWrapper {
+	return &DynamicClientWrapper{
+		dClient: dClient,
+	}
+}
+
 // RawClient returns the used dynamic.Interface.
 func (d *DynamicClientWrapper) RawClient() dynamic.Interface {
 	return d.dClient
@@ -74,9 +81,7 @@ func DynamicClient() (*DynamicClientWrapper, error) {
 		return nil, err
 	}
 
-	return &DynamicClientWrapper{
-		dClient: client,
-	}, nil
+	return NewDynamicClientWrapper(client), nil
 }
 
 // DClient wraps a client-go dynamic.
@@ -0,0 +1,39 @@
+package lcm
+
+import (
+	"context"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// BackupDirEnv is the environment variable holding the local directory of the backup in data jobs.
+	BackupDirEnv = "BACKUP_DIR"
+
+	// The environment variables describing the bucket target, set if the data job handles remote targets.
+	BackupTargetEndpointEnv  = "TARGET_ENDPOINT"
+	BackupTargetURLEnv       = "TARGET_URL"
+	BackupTargetAccessKeyEnv = "TARGET_ACCESS_KEY"
+	BackupTargetSecretKeyEnv = "TARGET_SECRET_KEY"
+	BackupTargetInsecureEnv  = "TARGET_INSECURE"
+)
+
+// DataJob describes the container dumping or restoring the data of an in-cluster service.
+type DataJob struct {
+	Container        corev1.Container
+	ImagePullSecrets []corev1.LocalObjectReference
+
+	// RemoteTarget is true if the container transfers the data from or to a bucket target by itself.
+	// Otherwise, the data is read from or written to the BACKUP_DIR directory.
+	RemoteTarget bool
+}
+
+// DataProtector is implemented by the controllers able to backup and restore the data of their in-cluster service.
+type DataProtector interface {
+	// BackupJob returns the container writing the data of the service.
+	BackupJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*DataJob, error)
+
+	// RestoreJob returns the container replacing the data of the service with the backup.
+	RestoreJob(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*DataJob, error)
+}
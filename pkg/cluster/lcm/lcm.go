@@ -22,6 +22,8 @@ type Controller interface {
 	// As we support connecting to the external or incluster provisioned dependent services,
 	// the dependent service may switch from incluster to external mode and then the incluster
 	// services may need to be unloaded.
+	// It is also called when the harbor cluster is deleted, a nil status is returned once the
+	// resources are released and the deletion can proceed.
 	Delete(ctx context.Context, harborcluster *goharborv1.HarborCluster) (*CRStatus, error)
 
 	// Upgrade the specified resource to the given version.
@@ -14,16 +14,19 @@ const (
 	NetworkPoliciesEnabledKey = "network-policies"
 	CtrlConfigDirectoryKey    = "controllers-config-directory"
 	TemplateDirectoryKey      = "template-directory"
+	// HarborServerPermissionCheckKey enables the live check of the credentials of the Harbor server configurations at admission.
+	HarborServerPermissionCheckKey = "hsc-permission-check"
 )
 
 const (
 	DefaultPriority = 5
 
-	DefaultConcurrentReconcile    = 1
-	DefaultHarborClass            = ""
-	DefaultNetworkPoliciesEnabled = false
-	DefaultConfigDirectory        = "/etc/harbor-operator"
-	DefaultTemplateDirectory      = DefaultConfigDirectory + "/templates"
+	DefaultConcurrentReconcile         = 1
+	DefaultHarborClass                 = ""
+	DefaultNetworkPoliciesEnabled      = false
+	DefaultHarborServerPermissionCheck = false
+	DefaultConfigDirectory             = "/etc/harbor-operator"
+	DefaultTemplateDirectory           = DefaultConfigDirectory + "/templates"
 
 	// DefaultImagePullPolicy specifies the policy to image pulls.
 	DefaultImagePullPolicy = corev1.PullIfNotPresent
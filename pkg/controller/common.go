@@ -17,6 +17,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -103,6 +104,26 @@ func (c *Controller) GetAndFilter(ctx context.Context, key client.ObjectKey, obj
 	return true, nil
 }
 
+// IsHorizontalPodAutoscalerSupported returns whether the autoscaling/v2 API, served since Kubernetes 1.23, is available.
+func (c *Controller) IsHorizontalPodAutoscalerSupported() (bool, error) {
+	resources, err := c.DiscoveryClient.ServerResourcesForGroupVersion(autoscalingv2.SchemeGroupVersion.String())
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "cannot discover autoscaling resources")
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Kind == "HorizontalPodAutoscaler" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (c *Controller) AreNetworkPoliciesEnabled(ctx context.Context, resource resources.Resource) (bool, error) {
 	for name, value := range resource.GetAnnotations() {
 		if name == harbormetav1.NetworkPoliciesAnnotationName {
@@ -0,0 +1,77 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/goharbor/harbor-operator/pkg/controller"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestIsHorizontalPodAutoscalerSupported(t *testing.T) {
+	cases := map[string]struct {
+		status    int
+		resources []metav1.APIResource
+		supported bool
+		err       bool
+	}{
+		"served": {
+			status: http.StatusOK,
+			resources: []metav1.APIResource{
+				{Name: "horizontalpodautoscalers", Namespaced: true, Kind: "HorizontalPodAutoscaler"},
+				{Name: "horizontalpodautoscalers/status", Namespaced: true, Kind: "HorizontalPodAutoscaler"},
+			},
+			supported: true,
+		},
+		"not served": {
+			status: http.StatusNotFound,
+		},
+		"without horizontal pod autoscalers": {
+			status: http.StatusOK,
+		},
+		"discovery failure": {
+			status: http.StatusInternalServerError,
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/apis/autoscaling/v2", r.URL.Path)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+
+				if tc.status == http.StatusOK {
+					require.NoError(t, json.NewEncoder(w).Encode(&metav1.APIResourceList{
+						GroupVersion: "autoscaling/v2",
+						APIResources: tc.resources,
+					}))
+				}
+			}))
+			defer server.Close()
+
+			c := &Controller{
+				DiscoveryClient: discovery.NewDiscoveryClientForConfigOrDie(&rest.Config{Host: server.URL}),
+			}
+
+			supported, err := c.IsHorizontalPodAutoscalerSupported()
+			if tc.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.supported, supported)
+		})
+	}
+}
@@ -56,7 +56,9 @@ func (c *Controller) Mark(ctx context.Context, owner client.Object) error { //no
 		Name:       owner.GetName(),
 	}
 
-	willDeletableResources := map[namespacedNameWithGVK]client.Object{}
+	// Resources are matched on their group and kind, the deletable resources being listed in their preferred version
+	// which may differ from the version of the resources in the graph.
+	willDeletableResources := map[namespacedNameWithGK]client.Object{}
 
 	for deletableGVK := range c.deletableResources {
 		unstructuredList := &unstructured.UnstructuredList{}
@@ -76,12 +78,12 @@ func (c *Controller) Mark(ctx context.Context, owner client.Object) error { //no
 			}
 			unstructured.GetObjectKind().SetGroupVersionKind(unstructuredGVK)
 			if containsOwnerReferences(unstructured, reference) {
-				willDeletableResources[namespacedNameWithGVK{
+				willDeletableResources[namespacedNameWithGK{
 					NamespacedName: types.NamespacedName{
 						Namespace: unstructured.GetNamespace(),
 						Name:      unstructured.GetName(),
 					},
-					GroupVersionKind: unstructured.GetObjectKind().GroupVersionKind(),
+					GroupKind: unstructuredGVK.GroupKind(),
 				}] = unstructured
 			}
 
@@ -98,12 +100,12 @@ func (c *Controller) Mark(ctx context.Context, owner client.Object) error { //no
 		}
 
 		obj := resource.resource.DeepCopyObject().(client.Object)
-		key := namespacedNameWithGVK{
+		key := namespacedNameWithGK{
 			NamespacedName: types.NamespacedName{
 				Namespace: obj.GetNamespace(),
 				Name:      obj.GetName(),
 			},
-			GroupVersionKind: c.AddGVKToSpan(ctx, span, obj),
+			GroupKind: c.AddGVKToSpan(ctx, span, obj).GroupKind(),
 		}
 		delete(willDeletableResources, key)
 	}
@@ -117,9 +119,9 @@ func (c *Controller) Mark(ctx context.Context, owner client.Object) error { //no
 	return nil
 }
 
-type namespacedNameWithGVK struct {
+type namespacedNameWithGK struct {
 	types.NamespacedName
-	schema.GroupVersionKind
+	schema.GroupKind
 }
 
 func containsOwnerReferences(o client.Object, reference metav1.OwnerReference) bool {
@@ -0,0 +1,99 @@
+package controller_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/controllers"
+	. "github.com/goharbor/harbor-operator/pkg/controller"
+	sgraph "github.com/goharbor/harbor-operator/pkg/controller/internal/graph"
+	"github.com/goharbor/harbor-operator/pkg/factories/application"
+	"github.com/goharbor/harbor-operator/pkg/factories/owner"
+	"github.com/goharbor/harbor-operator/pkg/scheme"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMark(t *testing.T) {
+	setupCtx := context.TODO()
+
+	application.SetName(&setupCtx, "test-app")
+	application.SetVersion(&setupCtx, "test")
+	application.SetGitCommit(&setupCtx, "test")
+	application.SetDeletableResources(&setupCtx, map[schema.GroupVersionKind]struct{}{
+		policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):          {},
+		autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"): {},
+	})
+
+	s, err := scheme.New(setupCtx)
+	require.NoError(t, err)
+
+	harbor := &goharborv1.Harbor{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "namespace",
+			Name:      "harbor",
+		},
+	}
+	ownerReference := metav1.OwnerReference{
+		APIVersion: goharborv1.GroupVersion.String(),
+		Kind:       "Harbor",
+		Name:       "harbor",
+	}
+	otherReference := metav1.OwnerReference{
+		APIVersion: goharborv1.GroupVersion.String(),
+		Kind:       "Harbor",
+		Name:       "other",
+	}
+
+	objectMeta := func(name string, reference metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:       "namespace",
+			Name:            name,
+			OwnerReferences: []metav1.OwnerReference{reference},
+		}
+	}
+
+	managed := &policyv1.PodDisruptionBudget{ObjectMeta: objectMeta("harbor-registry", ownerReference)}
+
+	c := NewController(setupCtx, controllers.Controller(0), nil, nil)
+	c.Scheme = s
+	c.Client = fake.NewClientBuilder().WithScheme(s).WithObjects(
+		managed.DeepCopy(),
+		&policyv1.PodDisruptionBudget{ObjectMeta: objectMeta("harbor-core", ownerReference)},
+		&policyv1.PodDisruptionBudget{ObjectMeta: objectMeta("other-core", otherReference)},
+		&autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: objectMeta("harbor-core", ownerReference)},
+	).Build()
+
+	ctx := c.PopulateContext(context.TODO(), controllerruntime.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: "namespace",
+			Name:      "harbor",
+		},
+	})
+	owner.Set(&ctx, harbor)
+
+	_, err = c.AddPodDisruptionBudgetToManage(ctx, managed)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Mark(ctx, harbor))
+
+	var swept []string
+
+	for _, res := range sgraph.Get(ctx).GetAllResources(ctx) {
+		if obj, ok := res.(client.Object); ok {
+			swept = append(swept, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+		}
+	}
+
+	sort.Strings(swept)
+	require.Equal(t, []string{"HorizontalPodAutoscaler/harbor-core", "PodDisruptionBudget/harbor-core"}, swept)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/goharbor/harbor-operator/pkg/graph"
 	"github.com/goharbor/harbor-operator/pkg/resources"
 	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -127,9 +129,38 @@ func (c *Controller) DeploymentMutateFn(ctx context.Context, dependencies ...gra
 		return nil
 	})
 
+	mutate.AppendMutation(c.keepReplicasMutation)
+
 	return mutate, nil
 }
 
+// keepReplicasMutation keeps the replicas of the existing deployment when the replicas are left to
+// a HorizontalPodAutoscaler, so that the updates do not reset them to the default value.
+func (c *Controller) keepReplicasMutation(ctx context.Context, obj runtime.Object) error {
+	deploy, ok := obj.(*appsv1.Deployment)
+	if !ok || deploy.Spec.Replicas != nil {
+		return nil
+	}
+
+	existing := &appsv1.Deployment{}
+
+	err := c.Client.Get(ctx, types.NamespacedName{
+		Name:      deploy.GetName(),
+		Namespace: deploy.GetNamespace(),
+	}, existing)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+
+		return errors.Wrap(err, "cannot get deployment replicas")
+	}
+
+	deploy.Spec.Replicas = existing.Spec.Replicas
+
+	return nil
+}
+
 func (c *Controller) SecretMutateFn(ctx context.Context, immutable *bool) (resources.Mutable, error) {
 	mutate, err := c.GlobalMutateFn(ctx)
 	if err != nil {
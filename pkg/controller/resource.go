@@ -14,8 +14,10 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -554,3 +556,51 @@ func (c *Controller) AddDeploymentToManage(ctx context.Context, resource *appsv1
 
 	return res, g.AddResource(ctx, res, dependencies, c.ProcessFunc(ctx, resource, dependencies...))
 }
+
+func (c *Controller) AddPodDisruptionBudgetToManage(ctx context.Context, resource *policyv1.PodDisruptionBudget, dependencies ...graph.Resource) (graph.Resource, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	mutate, err := c.GlobalMutateFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Resource{
+		mutable:   mutate,
+		checkable: statuscheck.True,
+		resource:  resource,
+	}
+
+	g := sgraph.Get(ctx)
+	if g == nil {
+		return nil, errors.Errorf("no graph in current context")
+	}
+
+	return res, g.AddResource(ctx, res, dependencies, c.ProcessFunc(ctx, resource, dependencies...))
+}
+
+func (c *Controller) AddHorizontalPodAutoscalerToManage(ctx context.Context, resource *autoscalingv2.HorizontalPodAutoscaler, dependencies ...graph.Resource) (graph.Resource, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	mutate, err := c.GlobalMutateFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Resource{
+		mutable:   mutate,
+		checkable: statuscheck.True,
+		resource:  resource,
+	}
+
+	g := sgraph.Get(ctx)
+	if g == nil {
+		return nil, errors.Errorf("no graph in current context")
+	}
+
+	return res, g.AddResource(ctx, res, dependencies, c.ProcessFunc(ctx, resource, dependencies...))
+}
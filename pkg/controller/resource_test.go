@@ -15,8 +15,10 @@ import (
 	"github.com/goharbor/harbor-operator/pkg/resources"
 	certv1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -45,6 +47,12 @@ var _ = Context("Adding", func() {
 		&appsv1.Deployment{}: func(c *Controller, ctx context.Context, res resources.Resource, dep ...graph.Resource) (graph.Resource, error) {
 			return c.AddDeploymentToManage(ctx, res.(*appsv1.Deployment), dep...)
 		},
+		&policyv1.PodDisruptionBudget{}: func(c *Controller, ctx context.Context, res resources.Resource, dep ...graph.Resource) (graph.Resource, error) {
+			return c.AddPodDisruptionBudgetToManage(ctx, res.(*policyv1.PodDisruptionBudget), dep...)
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{}: func(c *Controller, ctx context.Context, res resources.Resource, dep ...graph.Resource) (graph.Resource, error) {
+			return c.AddHorizontalPodAutoscalerToManage(ctx, res.(*autoscalingv2.HorizontalPodAutoscaler), dep...)
+		},
 		&unstructured.Unstructured{}: func(c *Controller, ctx context.Context, res resources.Resource, dep ...graph.Resource) (graph.Resource, error) {
 			return c.AddUnsctructuredToManage(ctx, res.(*unstructured.Unstructured), dep...)
 		},
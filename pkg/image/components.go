@@ -110,6 +110,7 @@ func init() { //nolint:gochecknoinits
 		"core":         "harbor-core",
 		"exporter":     "harbor-exporter",
 		"jobservice":   "harbor-jobservice",
+		"nginx":        "nginx-photon",
 		"notaryserver": "notary-server-photon",
 		"notarysigner": "notary-signer-photon",
 		"portal":       "harbor-portal",
@@ -127,10 +128,18 @@ func init() { //nolint:gochecknoinits
 	RegisterImageName("cluster-redis", "redis", "*")
 	RegisterTag("cluster-redis", "5.0-alpine", "~2.2.0", "~2.3.0", "~2.4.0", "~2.5.0", "~2.6.0")
 
+	RegisterRepository("cluster-redis-replication", "quay.io/opstree", "*")
+	RegisterImageName("cluster-redis-replication", "redis", "*")
+	RegisterTag("cluster-redis-replication", "v7.0.15", "~2.2.0", "~2.3.0", "~2.4.0", "~2.5.0", "~2.6.0")
+
 	RegisterRepository("cluster-postgresql", "registry.opensource.zalan.do/acid", "*")
 	RegisterImageName("cluster-postgresql", "spilo-13", "*")
 	RegisterTag("cluster-postgresql", "2.1-p1", "~2.2.0", "~2.3.0", "~2.4.0", "~2.5.0", "~2.6.0")
 
+	RegisterRepository("cluster-cloudnativepg", "ghcr.io/cloudnative-pg", "*")
+	RegisterImageName("cluster-cloudnativepg", "postgresql", "*")
+	RegisterTag("cluster-cloudnativepg", "15", "~2.2.0", "~2.3.0", "~2.4.0", "~2.5.0", "~2.6.0")
+
 	RegisterRepository("cluster-minio", "minio", "*") // the minio repository of dockerhub
 	RegisterImageName("cluster-minio", "minio", "*")
 	RegisterTag("cluster-minio", "RELEASE.2022-08-26T19-53-15Z", "~2.2.0", "~2.3.0", "~2.4.0", "~2.5.0", "~2.6.0")
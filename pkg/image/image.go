@@ -18,6 +18,7 @@ type Options struct {
 	imageFromSpec string
 	repository    string
 	tagSuffix     string
+	tag           string
 	harborVersion string
 }
 
@@ -41,6 +42,13 @@ func WithTagSuffix(tagSuffix string) Option {
 	}
 }
 
+// WithTag overrides the tag registered for the harbor version.
+func WithTag(tag string) Option {
+	return func(opts *Options) {
+		opts.tag = tag
+	}
+}
+
 func WithHarborVersion(version string) Option {
 	return func(opts *Options) {
 		opts.harborVersion = version
@@ -89,7 +97,10 @@ func GetImage(ctx context.Context, component string, options ...Option) (string,
 		repository += "/"
 	}
 
-	tag := knownComponents.Get(component, tagKind, opts.harborVersion, "v"+opts.harborVersion)
+	tag := opts.tag
+	if tag == "" {
+		tag = knownComponents.Get(component, tagKind, opts.harborVersion, "v"+opts.harborVersion)
+	}
 
 	return fmt.Sprintf("%s%s:%s%s", repository, imageName, tag, opts.tagSuffix), nil
 }
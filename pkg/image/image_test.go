@@ -145,4 +145,20 @@ var _ = Describe("Get image", func() {
 			Expect(image).To(Equal("ghcr.io/goharbor/spilo-13:2.1-p1"))
 		})
 	})
+
+	Describe("Get image for in cluster cloudnativepg", func() {
+		It("Should pass", func() {
+			image, err := getImage(ctx, "cluster-cloudnativepg")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(image).To(Equal("ghcr.io/cloudnative-pg/postgresql:15"))
+		})
+	})
+
+	Describe("Get image for in cluster cloudnativepg with tag", func() {
+		It("Should pass", func() {
+			image, err := getImage(ctx, "cluster-cloudnativepg", WithTag("16"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(image).To(Equal("ghcr.io/cloudnative-pg/postgresql:16"))
+		})
+	})
 })
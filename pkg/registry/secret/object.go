@@ -27,3 +27,17 @@ func (o *Object) Encode() []byte {
 
 	return bytes
 }
+
+// Decode returns the docker config object of the encoded data.
+func Decode(data []byte) (*Object, error) {
+	o := &Object{}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, err
+	}
+
+	if o.Auths == nil {
+		o.Auths = map[string]*Auth{}
+	}
+
+	return o, nil
+}
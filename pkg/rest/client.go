@@ -13,7 +13,7 @@ import (
 )
 
 func CreateHarborV2Client(ctx context.Context, client client.Client, hsc *goharborv1beta1.HarborServerConfiguration) (*v2.Client, error) {
-	server, err := createHarborServer(ctx, client, hsc)
+	server, err := CreateHarborServer(ctx, client, hsc)
 	if err != nil {
 		return nil, err
 	}
@@ -21,9 +21,9 @@ func CreateHarborV2Client(ctx context.Context, client client.Client, hsc *goharb
 	return v2.NewWithServer(server)
 }
 
-// Check if the server configuration is valid.
+// CreateHarborServer checks if the server configuration is valid and returns the connection data.
 // That is checking if the admin password secret object is valid.
-func createHarborServer(ctx context.Context, client client.Client, hsc *goharborv1beta1.HarborServerConfiguration) (*model.HarborServer, error) {
+func CreateHarborServer(ctx context.Context, client client.Client, hsc *goharborv1beta1.HarborServerConfiguration) (*model.HarborServer, error) {
 	// construct accessCreds from Secret
 	secretNSedName := types.NamespacedName{
 		Namespace: hsc.Spec.AccessCredential.Namespace,
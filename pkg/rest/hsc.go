@@ -0,0 +1,83 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	goharborv1beta1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetHarborServerConfiguration returns the named Harbor server configuration of the namespace.
+// The NamespacedHarborServerConfiguration of the namespace takes precedence over the cluster scoped HarborServerConfiguration.
+// Nil is returned if none of them exists.
+func GetHarborServerConfiguration(ctx context.Context, c client.Client, namespace, name string) (*goharborv1beta1.HarborServerConfiguration, error) {
+	if namespace != "" {
+		nhsc := &goharborv1beta1.NamespacedHarborServerConfiguration{}
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, nhsc)
+		if err == nil {
+			return nhsc.ToHarborServerConfiguration(), nil
+		}
+
+		// The namespaced configurations are optional, the CRD may not be installed
+		if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("get namespaced harbor server configuration error: %w", err)
+		}
+	}
+
+	hsc := &goharborv1beta1.HarborServerConfiguration{}
+	// HarborServerConfiguration is cluster scoped resource
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, hsc); err != nil {
+		// Explicitly check not found error
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get harbor server configuration error: %w", err)
+	}
+
+	return hsc, nil
+}
+
+// GetDefaultHarborServerConfiguration returns the default Harbor server configuration of the namespace.
+// The default NamespacedHarborServerConfiguration of the namespace takes precedence over the default HarborServerConfiguration.
+// Nil is returned if there is no default configuration.
+func GetDefaultHarborServerConfiguration(ctx context.Context, c client.Client, namespace string) (*goharborv1beta1.HarborServerConfiguration, error) {
+	if namespace != "" {
+		nhscs := &goharborv1beta1.NamespacedHarborServerConfigurationList{}
+
+		err := c.List(ctx, nhscs, client.InNamespace(namespace))
+		if err != nil && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("list namespaced harbor server configurations error: %w", err)
+		}
+
+		// the webhook rejects multiple defaults, sort them anyway to get a stable answer
+		sort.Slice(nhscs.Items, func(i, j int) bool {
+			return nhscs.Items[i].Name < nhscs.Items[j].Name
+		})
+
+		for i := range nhscs.Items {
+			if nhscs.Items[i].Spec.Default {
+				return nhscs.Items[i].ToHarborServerConfiguration(), nil
+			}
+		}
+	}
+
+	hscs := &goharborv1beta1.HarborServerConfigurationList{}
+	if err := c.List(ctx, hscs); err != nil {
+		return nil, fmt.Errorf("list harbor server configurations error: %w", err)
+	}
+
+	for i := range hscs.Items {
+		if hscs.Items[i].Spec.Default {
+			return &hscs.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,67 @@
+package rest_test
+
+import (
+	"context"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rest"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_GetHarborServerConfiguration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&goharborv1.HarborServerConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+			Spec: goharborv1.HarborServerConfigurationSpec{
+				ServerURL:        "https://cluster.example.com",
+				Default:          true,
+				AccessCredential: &goharborv1.AccessCredential{Namespace: "harbor", AccessSecretRef: "creds"},
+			},
+		},
+		&goharborv1.NamespacedHarborServerConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "harbor", Namespace: "tenant-a"},
+			Spec: goharborv1.HarborServerConfigurationSpec{
+				ServerURL:        "https://tenant-a.example.com",
+				AccessCredential: &goharborv1.AccessCredential{Namespace: "harbor", AccessSecretRef: "creds"},
+			},
+		},
+		&goharborv1.NamespacedHarborServerConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "own", Namespace: "tenant-b"},
+			Spec: goharborv1.HarborServerConfigurationSpec{
+				ServerURL:        "https://tenant-b.example.com",
+				Default:          true,
+				AccessCredential: &goharborv1.AccessCredential{Namespace: "tenant-b", AccessSecretRef: "creds"},
+			},
+		},
+	).Build()
+
+	ctx := context.Background()
+
+	hsc, err := rest.GetHarborServerConfiguration(ctx, c, "tenant-a", "harbor")
+	require.NoError(t, err)
+	require.Equal(t, "https://tenant-a.example.com", hsc.Spec.ServerURL, "namespaced configuration first")
+	require.Equal(t, "tenant-a", hsc.Spec.AccessCredential.Namespace, "access secret in the namespace of the configuration")
+
+	hsc, err = rest.GetHarborServerConfiguration(ctx, c, "tenant-b", "harbor")
+	require.NoError(t, err)
+	require.Equal(t, "https://cluster.example.com", hsc.Spec.ServerURL, "cluster configuration then")
+
+	hsc, err = rest.GetHarborServerConfiguration(ctx, c, "tenant-a", "own")
+	require.NoError(t, err)
+	require.Nil(t, hsc, "configurations of other namespaces are ignored")
+
+	hsc, err = rest.GetDefaultHarborServerConfiguration(ctx, c, "tenant-b")
+	require.NoError(t, err)
+	require.Equal(t, "own", hsc.Name, "default namespaced configuration first")
+
+	hsc, err = rest.GetDefaultHarborServerConfiguration(ctx, c, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, "https://cluster.example.com", hsc.Spec.ServerURL, "default cluster configuration then")
+}
@@ -21,7 +21,7 @@ func GetCredential(secret *corev1.Secret) (string, string, error) {
 	decodedAS, ok2 := secret.Data[accessSecret]
 
 	if !(ok1 && ok2) {
-		return "", "", errors.New("invalid access secret")
+		return "", "", errors.Errorf("invalid access secret, the %s and %s keys are required", accessKey, accessSecret)
 	}
 
 	if len(decodedAK) == 0 || len(decodedAS) == 0 {
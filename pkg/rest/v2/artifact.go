@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+)
+
+// acceptedVulnerabilityReports are the mime types of the vulnerability reports summarized in the scan overview.
+const acceptedVulnerabilityReports = "application/vnd.security.vulnerability.report; version=1.1, application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+
+// GetArtifact gets the artifact of the repository of the project by tag or digest, with its scan overview.
+// A nil artifact is returned if it does not exist.
+func (c *Client) GetArtifact(projectName, repository, reference string) (*models.Artifact, error) {
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	withScanOverview := true
+	accept := acceptedVulnerabilityReports
+
+	// repositories with slashes are encoded twice
+	params := artifact.NewGetArtifactParams().
+		WithTimeout(c.timeout).
+		WithProjectName(projectName).
+		WithRepositoryName(url.PathEscape(repository)).
+		WithReference(reference).
+		WithWithScanOverview(&withScanOverview).
+		WithXAcceptVulnerabilities(&accept)
+
+	res, err := c.harborClient.Client.Artifact.GetArtifact(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get artifact error: %w", err)
+	}
+
+	return res.Payload, nil
+}
@@ -0,0 +1,106 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/immutable"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+)
+
+// ListImmutableRules lists the tag immutability rules of the project.
+func (c *Client) ListImmutableRules(projectName string) ([]*models.ImmutableRule, error) {
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	var rules []*models.ImmutableRule
+
+	pageSize := paginationSize
+	page := int64(1)
+
+	for {
+		params := immutable.NewListImmuRulesParams().
+			WithTimeout(c.timeout).
+			WithProjectNameOrID(projectName).
+			WithPageSize(&pageSize).
+			WithPage(&page)
+
+		res, err := c.harborClient.Client.Immutable.ListImmuRules(c.context, params)
+		if err != nil {
+			return nil, fmt.Errorf("list immutable rules error: %w", err)
+		}
+
+		rules = append(rules, res.Payload...)
+
+		if len(res.Payload) == 0 || int64(len(rules)) >= res.XTotalCount {
+			return rules, nil
+		}
+
+		page++
+	}
+}
+
+// CreateImmutableRule creates the tag immutability rule in the project.
+func (c *Client) CreateImmutableRule(projectName string, rule *models.ImmutableRule) error {
+	if rule == nil {
+		return errors.New("nil immutable rule")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := immutable.NewCreateImmuRuleParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithImmutableRule(rule)
+
+	if _, err := c.harborClient.Client.Immutable.CreateImmuRule(c.context, params); err != nil {
+		return fmt.Errorf("create immutable rule error: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateImmutableRule updates the tag immutability rule with the given ID.
+func (c *Client) UpdateImmutableRule(projectName string, id int64, rule *models.ImmutableRule) error {
+	if rule == nil {
+		return errors.New("nil immutable rule")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := immutable.NewUpdateImmuRuleParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithImmutableRuleID(id).
+		WithImmutableRule(rule)
+
+	if _, err := c.harborClient.Client.Immutable.UpdateImmuRule(c.context, params); err != nil {
+		return fmt.Errorf("update immutable rule error: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteImmutableRule deletes the tag immutability rule with the given ID.
+// Deleting a rule which does not exist is not an error.
+func (c *Client) DeleteImmutableRule(projectName string, id int64) error {
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := immutable.NewDeleteImmuRuleParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithImmutableRuleID(id)
+
+	if _, err := c.harborClient.Client.Immutable.DeleteImmuRule(c.context, params); err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete immutable rule error: %w", err)
+	}
+
+	return nil
+}
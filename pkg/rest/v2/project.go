@@ -8,6 +8,7 @@ import (
 
 	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/member"
 	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
+	projectmetadata "github.com/goharbor/go-client/pkg/sdk/v2.0/client/project_metadata"
 	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/quota"
 	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
 	goharborv1beta1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
@@ -128,6 +129,11 @@ func (c *Client) CreateProject(hp *goharborv1beta1.HarborProject) (int32, error)
 		return -1, fmt.Errorf("create project error: %w", err)
 	}
 
+	// the upstream registry of proxy cache projects can only be set at creation
+	if hp.Status.ProxyCacheRegistryID > 0 {
+		projectRequest.RegistryID = &hp.Status.ProxyCacheRegistryID
+	}
+
 	params := project.NewCreateProjectParams().WithProject(projectRequest)
 
 	res, err := c.harborClient.Client.Project.CreateProject(c.context, params)
@@ -207,6 +213,69 @@ func (c *Client) DeleteProject(name string) error {
 	return nil
 }
 
+// GetProjectMetadata gets the value of a metadata of the project.
+// An empty value is returned if the metadata is not set.
+func (c *Client) GetProjectMetadata(projectName, key string) (string, error) {
+	if c.harborClient == nil {
+		return "", errors.New("nil harbor client")
+	}
+
+	params := projectmetadata.NewGetProjectMetadataParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithMetaName(key)
+
+	res, err := c.harborClient.Client.ProjectMetadata.GetProjectMetadata(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get project metadata error: %w", err)
+	}
+
+	return res.Payload[key], nil
+}
+
+// SetProjectMetadata sets a metadata of the project, metadata missing from the project request model included.
+func (c *Client) SetProjectMetadata(projectName, key, value string) error {
+	current, err := c.GetProjectMetadata(projectName, key)
+	if err != nil {
+		return err
+	}
+
+	if current == value {
+		return nil
+	}
+
+	metadata := map[string]string{key: value}
+
+	if current == "" {
+		params := projectmetadata.NewAddProjectMetadatasParams().
+			WithTimeout(c.timeout).
+			WithProjectNameOrID(projectName).
+			WithMetadata(metadata)
+
+		if _, err := c.harborClient.Client.ProjectMetadata.AddProjectMetadatas(c.context, params); err != nil {
+			return fmt.Errorf("add project metadata error: %w", err)
+		}
+
+		return nil
+	}
+
+	params := projectmetadata.NewUpdateProjectMetadataParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithMetaName(key).
+		WithMetadata(metadata)
+
+	if _, err := c.harborClient.Client.ProjectMetadata.UpdateProjectMetadata(c.context, params); err != nil {
+		return fmt.Errorf("update project metadata error: %w", err)
+	}
+
+	return nil
+}
+
 func (c *Client) GetQuotaByProjectID(projectID int32) (*models.Quota, error) {
 	id := strconv.Itoa(int(projectID))
 
@@ -0,0 +1,151 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	gruntime "github.com/go-openapi/runtime"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/registry"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	utilstring "github.com/goharbor/harbor-operator/pkg/utils/strings"
+)
+
+// isNotFound checks whether the error is a not found response of the Harbor API,
+// either declared by the operation or not.
+func isNotFound(err error) bool {
+	var coded interface{ IsCode(code int) bool }
+	if errors.As(err, &coded) {
+		return coded.IsCode(http.StatusNotFound)
+	}
+
+	var apiErr *gruntime.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusNotFound
+	}
+
+	return false
+}
+
+// CreateRegistry creates the registry endpoint and returns its ID.
+func (c *Client) CreateRegistry(reg *models.Registry) (int64, error) {
+	if reg == nil {
+		return 0, errors.New("nil registry")
+	}
+
+	if c.harborClient == nil {
+		return 0, errors.New("nil harbor client")
+	}
+
+	params := registry.NewCreateRegistryParams().
+		WithTimeout(c.timeout).
+		WithRegistry(reg)
+
+	res, err := c.harborClient.Client.Registry.CreateRegistry(c.context, params)
+	if err != nil {
+		return 0, fmt.Errorf("create registry error: %w", err)
+	}
+
+	return utilstring.ExtractID(res.Location)
+}
+
+// UpdateRegistry updates the registry endpoint with the given ID.
+func (c *Client) UpdateRegistry(id int64, reg *models.RegistryUpdate) error {
+	if id <= 0 {
+		return errors.New("invalid registry id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := registry.NewUpdateRegistryParams().
+		WithTimeout(c.timeout).
+		WithID(id).
+		WithRegistry(reg)
+
+	if _, err := c.harborClient.Client.Registry.UpdateRegistry(c.context, params); err != nil {
+		return fmt.Errorf("update registry error: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegistry gets the registry endpoint with the given ID.
+// A nil registry is returned if it does not exist.
+func (c *Client) GetRegistry(id int64) (*models.Registry, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid registry id")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := registry.NewGetRegistryParams().
+		WithTimeout(c.timeout).
+		WithID(id)
+
+	res, err := c.harborClient.Client.Registry.GetRegistry(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get registry error: %w", err)
+	}
+
+	return res.Payload, nil
+}
+
+// GetRegistryByName gets the registry endpoint with the given name.
+// A nil registry is returned if it does not exist.
+func (c *Client) GetRegistryByName(name string) (*models.Registry, error) {
+	if len(name) == 0 {
+		return nil, errors.New("registry name is empty")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := registry.NewListRegistriesParams().
+		WithTimeout(c.timeout).
+		WithName(&name)
+
+	res, err := c.harborClient.Client.Registry.ListRegistries(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("list registries error: %w", err)
+	}
+
+	// The name parameter is a fuzzy match
+	for _, reg := range res.Payload {
+		if reg.Name == name {
+			return reg, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteRegistry deletes the registry endpoint with the given ID.
+// It does not fail if the registry endpoint does not exist.
+func (c *Client) DeleteRegistry(id int64) error {
+	if id <= 0 {
+		return errors.New("invalid registry id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := registry.NewDeleteRegistryParams().
+		WithTimeout(c.timeout).
+		WithID(id)
+
+	if _, err := c.harborClient.Client.Registry.DeleteRegistry(c.context, params); err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete registry error: %w", err)
+	}
+
+	return nil
+}
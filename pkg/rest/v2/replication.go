@@ -0,0 +1,165 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/replication"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	utilstring "github.com/goharbor/harbor-operator/pkg/utils/strings"
+)
+
+// CreateReplicationPolicy creates the replication policy and returns its ID.
+func (c *Client) CreateReplicationPolicy(policy *models.ReplicationPolicy) (int64, error) {
+	if policy == nil {
+		return 0, errors.New("nil replication policy")
+	}
+
+	if c.harborClient == nil {
+		return 0, errors.New("nil harbor client")
+	}
+
+	params := replication.NewCreateReplicationPolicyParams().
+		WithTimeout(c.timeout).
+		WithPolicy(policy)
+
+	res, err := c.harborClient.Client.Replication.CreateReplicationPolicy(c.context, params)
+	if err != nil {
+		return 0, fmt.Errorf("create replication policy error: %w", err)
+	}
+
+	return utilstring.ExtractID(res.Location)
+}
+
+// UpdateReplicationPolicy updates the replication policy with the given ID.
+func (c *Client) UpdateReplicationPolicy(id int64, policy *models.ReplicationPolicy) error {
+	if id <= 0 {
+		return errors.New("invalid replication policy id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := replication.NewUpdateReplicationPolicyParams().
+		WithTimeout(c.timeout).
+		WithID(id).
+		WithPolicy(policy)
+
+	if _, err := c.harborClient.Client.Replication.UpdateReplicationPolicy(c.context, params); err != nil {
+		return fmt.Errorf("update replication policy error: %w", err)
+	}
+
+	return nil
+}
+
+// GetReplicationPolicy gets the replication policy with the given ID.
+// A nil policy is returned if it does not exist.
+func (c *Client) GetReplicationPolicy(id int64) (*models.ReplicationPolicy, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid replication policy id")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := replication.NewGetReplicationPolicyParams().
+		WithTimeout(c.timeout).
+		WithID(id)
+
+	res, err := c.harborClient.Client.Replication.GetReplicationPolicy(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get replication policy error: %w", err)
+	}
+
+	return res.Payload, nil
+}
+
+// GetReplicationPolicyByName gets the replication policy with the given name.
+// A nil policy is returned if it does not exist.
+func (c *Client) GetReplicationPolicyByName(name string) (*models.ReplicationPolicy, error) {
+	if len(name) == 0 {
+		return nil, errors.New("replication policy name is empty")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := replication.NewListReplicationPoliciesParams().
+		WithTimeout(c.timeout).
+		WithName(&name)
+
+	res, err := c.harborClient.Client.Replication.ListReplicationPolicies(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("list replication policies error: %w", err)
+	}
+
+	// The name parameter is a fuzzy match
+	for _, policy := range res.Payload {
+		if policy.Name == name {
+			return policy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteReplicationPolicy deletes the replication policy with the given ID.
+// It does not fail if the replication policy does not exist.
+func (c *Client) DeleteReplicationPolicy(id int64) error {
+	if id <= 0 {
+		return errors.New("invalid replication policy id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := replication.NewDeleteReplicationPolicyParams().
+		WithTimeout(c.timeout).
+		WithID(id)
+
+	if _, err := c.harborClient.Client.Replication.DeleteReplicationPolicy(c.context, params); err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete replication policy error: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastReplicationExecution gets the most recent execution of the replication policy with the given ID.
+// A nil execution is returned if the policy never ran.
+func (c *Client) GetLastReplicationExecution(policyID int64) (*models.ReplicationExecution, error) {
+	if policyID <= 0 {
+		return nil, errors.New("invalid replication policy id")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	sort := "-start_time"
+	pageSize := int64(1)
+
+	params := replication.NewListReplicationExecutionsParams().
+		WithTimeout(c.timeout).
+		WithPolicyID(&policyID).
+		WithSort(&sort).
+		WithPageSize(&pageSize)
+
+	res, err := c.harborClient.Client.Replication.ListReplicationExecutions(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("list replication executions error: %w", err)
+	}
+
+	if len(res.Payload) < 1 {
+		return nil, nil
+	}
+
+	return res.Payload[0], nil
+}
@@ -0,0 +1,81 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/retention"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	utilstring "github.com/goharbor/harbor-operator/pkg/utils/strings"
+)
+
+// CreateRetention creates the tag retention policy and returns its ID.
+func (c *Client) CreateRetention(policy *models.RetentionPolicy) (int64, error) {
+	if policy == nil {
+		return 0, errors.New("nil retention policy")
+	}
+
+	if c.harborClient == nil {
+		return 0, errors.New("nil harbor client")
+	}
+
+	params := retention.NewCreateRetentionParams().
+		WithTimeout(c.timeout).
+		WithPolicy(policy)
+
+	res, err := c.harborClient.Client.Retention.CreateRetention(c.context, params)
+	if err != nil {
+		return 0, fmt.Errorf("create retention policy error: %w", err)
+	}
+
+	return utilstring.ExtractID(res.Location)
+}
+
+// UpdateRetention updates the tag retention policy with the given ID.
+func (c *Client) UpdateRetention(id int64, policy *models.RetentionPolicy) error {
+	if policy == nil {
+		return errors.New("nil retention policy")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := retention.NewUpdateRetentionParams().
+		WithTimeout(c.timeout).
+		WithID(id).
+		WithPolicy(policy)
+
+	if _, err := c.harborClient.Client.Retention.UpdateRetention(c.context, params); err != nil {
+		return fmt.Errorf("update retention policy error: %w", err)
+	}
+
+	return nil
+}
+
+// GetRetention gets the tag retention policy with the given ID.
+// A nil policy is returned if it does not exist.
+func (c *Client) GetRetention(id int64) (*models.RetentionPolicy, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid retention policy id")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := retention.NewGetRetentionParams().
+		WithTimeout(c.timeout).
+		WithID(id)
+
+	res, err := c.harborClient.Client.Retention.GetRetention(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get retention policy error: %w", err)
+	}
+
+	return res.Payload, nil
+}
@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/robot"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+)
+
+// CreateRobot creates the robot account.
+// The returned secret is the only occasion to get the secret of the robot account.
+func (c *Client) CreateRobot(r *models.RobotCreate) (*models.RobotCreated, error) {
+	if r == nil {
+		return nil, errors.New("nil robot account")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := robot.NewCreateRobotParams().
+		WithTimeout(c.timeout).
+		WithRobot(r)
+
+	res, err := c.harborClient.Client.Robot.CreateRobot(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("create robot account error: %w", err)
+	}
+
+	return res.Payload, nil
+}
+
+// GetRobot gets the robot account with the given ID.
+// A nil robot account is returned if it does not exist.
+func (c *Client) GetRobot(id int64) (*models.Robot, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid robot id")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	params := robot.NewGetRobotByIDParams().
+		WithTimeout(c.timeout).
+		WithRobotID(id)
+
+	res, err := c.harborClient.Client.Robot.GetRobotByID(c.context, params)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get robot account error: %w", err)
+	}
+
+	return res.Payload, nil
+}
+
+// GetRobotByName gets the system level robot account with the given name, without the robot prefix.
+// A nil robot account is returned if it does not exist.
+func (c *Client) GetRobotByName(name string) (*models.Robot, error) {
+	if len(name) == 0 {
+		return nil, errors.New("robot name is empty")
+	}
+
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	query := fmt.Sprintf("Level=system,name=~%s", name)
+
+	params := robot.NewListRobotParams().
+		WithTimeout(c.timeout).
+		WithQ(&query)
+
+	res, err := c.harborClient.Client.Robot.ListRobot(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("list robot accounts error: %w", err)
+	}
+
+	// The returned names are prefixed by the configurable robot prefix, robot$ by default
+	for _, r := range res.Payload {
+		if r.Name == name || strings.HasSuffix(r.Name, "$"+name) {
+			return r, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// UpdateRobot updates the robot account with the given ID.
+func (c *Client) UpdateRobot(id int64, r *models.Robot) error {
+	if id <= 0 {
+		return errors.New("invalid robot id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := robot.NewUpdateRobotParams().
+		WithTimeout(c.timeout).
+		WithRobotID(id).
+		WithRobot(r)
+
+	if _, err := c.harborClient.Client.Robot.UpdateRobot(c.context, params); err != nil {
+		return fmt.Errorf("update robot account error: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshRobotSecret generates a new secret for the robot account with the given ID and returns it.
+func (c *Client) RefreshRobotSecret(id int64) (string, error) {
+	if id <= 0 {
+		return "", errors.New("invalid robot id")
+	}
+
+	if c.harborClient == nil {
+		return "", errors.New("nil harbor client")
+	}
+
+	// An empty secret lets Harbor generate a new one
+	params := robot.NewRefreshSecParams().
+		WithTimeout(c.timeout).
+		WithRobotID(id).
+		WithRobotSec(&models.RobotSec{})
+
+	res, err := c.harborClient.Client.Robot.RefreshSec(c.context, params)
+	if err != nil {
+		return "", fmt.Errorf("refresh robot account secret error: %w", err)
+	}
+
+	return res.Payload.Secret, nil
+}
+
+// DeleteRobot deletes the robot account with the given ID.
+// It does not fail if the robot account does not exist.
+func (c *Client) DeleteRobot(id int64) error {
+	if id <= 0 {
+		return errors.New("invalid robot id")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := robot.NewDeleteRobotParams().
+		WithTimeout(c.timeout).
+		WithRobotID(id)
+
+	if _, err := c.harborClient.Client.Robot.DeleteRobot(c.context, params); err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete robot account error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package v2
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/systeminfo"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidCredential is returned when Harbor rejects the credential of the client.
+var ErrInvalidCredential = errors.New("invalid credential")
+
+// GetHarborVersion returns the version of the Harbor server, e.g. v2.4.1-c4b06d79.
+// The version is only returned to the authenticated users.
+func (c *Client) GetHarborVersion() (string, error) {
+	if c.harborClient == nil {
+		return "", errors.New("nil harbor client")
+	}
+
+	params := systeminfo.NewGetSystemInfoParams().
+		WithTimeout(c.timeout)
+
+	res, err := c.harborClient.Client.Systeminfo.GetSystemInfo(c.context, params)
+	if err != nil {
+		return "", fmt.Errorf("get system info error: %w", err)
+	}
+
+	if res.Payload == nil || res.Payload.HarborVersion == nil {
+		return "", nil
+	}
+
+	return *res.Payload.HarborVersion, nil
+}
+
+// CheckCredential returns ErrInvalidCredential if Harbor rejects the credential of the client.
+// The registry API is used as it authenticates both the users and the robot accounts.
+func (c *Client) CheckCredential() error {
+	if c.server == nil {
+		return errors.New("nil harbor server")
+	}
+
+	req, err := http.NewRequestWithContext(c.context, http.MethodGet, strings.TrimSuffix(c.server.ServerURL, "/")+"/v2/", nil)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(c.server.Username, c.server.Password)
+
+	httpClient := &http.Client{
+		Timeout: c.timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: c.server.Insecure, //nolint:gosec
+			},
+		},
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("check credential error: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return ErrInvalidCredential
+	default:
+		return errors.Errorf("check credential error: unexpected status %s", res.Status)
+	}
+}
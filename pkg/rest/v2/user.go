@@ -0,0 +1,34 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/user"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/pkg/errors"
+)
+
+// systemScope is the scope of the system level permissions.
+const systemScope = "/system"
+
+// GetSystemPermissions returns the system level permissions of the user or the robot account of the client.
+func (c *Client) GetSystemPermissions() ([]*models.Permission, error) {
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	scope := systemScope
+	relative := true
+
+	params := user.NewGetCurrentUserPermissionsParams().
+		WithTimeout(c.timeout).
+		WithScope(&scope).
+		WithRelative(&relative)
+
+	res, err := c.harborClient.Client.User.GetCurrentUserPermissions(c.context, params)
+	if err != nil {
+		return nil, fmt.Errorf("get current user permissions error: %w", err)
+	}
+
+	return res.Payload, nil
+}
@@ -0,0 +1,108 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/webhook"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	utilstring "github.com/goharbor/harbor-operator/pkg/utils/strings"
+)
+
+// ListWebhookPolicies lists the webhook policies of the project.
+func (c *Client) ListWebhookPolicies(projectName string) ([]*models.WebhookPolicy, error) {
+	if c.harborClient == nil {
+		return nil, errors.New("nil harbor client")
+	}
+
+	var policies []*models.WebhookPolicy
+
+	pageSize := paginationSize
+	page := int64(1)
+
+	for {
+		params := webhook.NewListWebhookPoliciesOfProjectParams().
+			WithTimeout(c.timeout).
+			WithProjectNameOrID(projectName).
+			WithPageSize(&pageSize).
+			WithPage(&page)
+
+		res, err := c.harborClient.Client.Webhook.ListWebhookPoliciesOfProject(c.context, params)
+		if err != nil {
+			return nil, fmt.Errorf("list webhook policies error: %w", err)
+		}
+
+		policies = append(policies, res.Payload...)
+
+		if len(res.Payload) == 0 || int64(len(policies)) >= res.XTotalCount {
+			return policies, nil
+		}
+
+		page++
+	}
+}
+
+// CreateWebhookPolicy creates the webhook policy in the project and returns its ID.
+func (c *Client) CreateWebhookPolicy(projectName string, policy *models.WebhookPolicy) (int64, error) {
+	if policy == nil {
+		return 0, errors.New("nil webhook policy")
+	}
+
+	if c.harborClient == nil {
+		return 0, errors.New("nil harbor client")
+	}
+
+	params := webhook.NewCreateWebhookPolicyOfProjectParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithPolicy(policy)
+
+	res, err := c.harborClient.Client.Webhook.CreateWebhookPolicyOfProject(c.context, params)
+	if err != nil {
+		return 0, fmt.Errorf("create webhook policy error: %w", err)
+	}
+
+	return utilstring.ExtractID(res.Location)
+}
+
+// UpdateWebhookPolicy updates the webhook policy with the given ID.
+func (c *Client) UpdateWebhookPolicy(projectName string, id int64, policy *models.WebhookPolicy) error {
+	if policy == nil {
+		return errors.New("nil webhook policy")
+	}
+
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := webhook.NewUpdateWebhookPolicyOfProjectParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithWebhookPolicyID(id).
+		WithPolicy(policy)
+
+	if _, err := c.harborClient.Client.Webhook.UpdateWebhookPolicyOfProject(c.context, params); err != nil {
+		return fmt.Errorf("update webhook policy error: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebhookPolicy deletes the webhook policy with the given ID.
+// Deleting a policy which does not exist is not an error.
+func (c *Client) DeleteWebhookPolicy(projectName string, id int64) error {
+	if c.harborClient == nil {
+		return errors.New("nil harbor client")
+	}
+
+	params := webhook.NewDeleteWebhookPolicyOfProjectParams().
+		WithTimeout(c.timeout).
+		WithProjectNameOrID(projectName).
+		WithWebhookPolicyID(id)
+
+	if _, err := c.harborClient.Client.Webhook.DeleteWebhookPolicyOfProject(c.context, params); err != nil && !isNotFound(err) {
+		return fmt.Errorf("delete webhook policy error: %w", err)
+	}
+
+	return nil
+}
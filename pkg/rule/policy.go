@@ -0,0 +1,110 @@
+package rule
+
+import (
+	"net/url"
+	"path"
+	"sort"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/umisama/go-regexpcache"
+)
+
+// Policy is an image rewrite policy resolved against its Harbor server.
+type Policy struct {
+	// Name identifies the policy in logs, namespace/name for namespaced policies.
+	Name       string
+	Namespaced bool
+	Spec       goharborv1.ImageRewritePolicySpec
+	ServerURL  string
+	// HarborServerConfig is the name of the HSC the images are pulled through, the default HSC included.
+	HarborServerConfig string
+}
+
+// NewPolicy creates the policy pulling the matching images through the given Harbor server.
+func NewPolicy(name string, namespaced bool, spec goharborv1.ImageRewritePolicySpec, hsc *goharborv1.HarborServerConfiguration) (Policy, error) {
+	u, err := url.Parse(hsc.Spec.ServerURL)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{
+		Name:               name,
+		Namespaced:         namespaced,
+		Spec:               spec,
+		ServerURL:          u.Host,
+		HarborServerConfig: hsc.GetName(),
+	}, nil
+}
+
+// Source returns the kind/name of the policy.
+func (p *Policy) Source() string {
+	if p.Namespaced {
+		return "ImageRewritePolicy/" + p.Name
+	}
+
+	return "ClusterImageRewritePolicy/" + p.Name
+}
+
+// Matches returns whether the image with the given registry, repository and tag is rewritten by the policy.
+// The tag is empty for images referenced by digest only.
+func (p *Policy) Matches(registry, repository, tag string) (bool, error) {
+	match := p.Spec.Match
+
+	if ok, err := matchGlob(match.Registry, registry); !ok || err != nil {
+		return false, err
+	}
+
+	if match.Tag != "" {
+		if tag == "" {
+			return false, nil
+		}
+
+		if ok, err := path.Match(match.Tag, tag); !ok || err != nil {
+			return false, err
+		}
+	}
+
+	if match.Repository == nil {
+		return true, nil
+	}
+
+	if match.Repository.Regex != "" {
+		regex, err := regexpcache.Compile("^(?:" + match.Repository.Regex + ")$")
+		if err != nil {
+			return false, err
+		}
+
+		return regex.MatchString(repository), nil
+	}
+
+	return matchGlob(match.Repository.Glob, repository)
+}
+
+// Target returns the registry replacing the one of the matching images.
+func (p *Policy) Target() string {
+	return p.ServerURL + "/" + p.Spec.Target.Project
+}
+
+func matchGlob(glob, value string) (bool, error) {
+	if glob == "" {
+		return true, nil
+	}
+
+	return path.Match(glob, value)
+}
+
+// SortPolicies sorts the policies in evaluation order: by decreasing priority,
+// namespaced policies first at the same priority, then by name.
+func SortPolicies(policies []Policy) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Spec.Priority != policies[j].Spec.Priority {
+			return policies[i].Spec.Priority > policies[j].Spec.Priority
+		}
+
+		if policies[i].Namespaced != policies[j].Namespaced {
+			return policies[i].Namespaced
+		}
+
+		return policies[i].Name < policies[j].Name
+	})
+}
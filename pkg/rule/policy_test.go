@@ -0,0 +1,111 @@
+package rule_test
+
+import (
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rule"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PolicyMatches(t *testing.T) {
+	type testcase struct {
+		description string
+		match       goharborv1.ImageRewriteMatch
+		registry    string
+		repository  string
+		tag         string
+		expected    bool
+	}
+
+	tests := []testcase{
+		{
+			description: "empty match",
+			registry:    "docker.io",
+			repository:  "library/nginx",
+			tag:         "latest",
+			expected:    true,
+		},
+		{
+			description: "registry glob",
+			match:       goharborv1.ImageRewriteMatch{Registry: "*.gcr.io"},
+			registry:    "eu.gcr.io",
+			repository:  "project/app",
+			expected:    true,
+		},
+		{
+			description: "other registry",
+			match:       goharborv1.ImageRewriteMatch{Registry: "docker.io"},
+			registry:    "quay.io",
+			repository:  "library/nginx",
+			expected:    false,
+		},
+		{
+			description: "repository glob does not match nested repositories",
+			match:       goharborv1.ImageRewriteMatch{Repository: &goharborv1.ImageRewriteRepositoryMatch{Glob: "library/*"}},
+			registry:    "docker.io",
+			repository:  "library/team/nginx",
+			expected:    false,
+		},
+		{
+			description: "repository regex matches the whole repository",
+			match:       goharborv1.ImageRewriteMatch{Repository: &goharborv1.ImageRewriteRepositoryMatch{Regex: "(library|bitnami)/.+"}},
+			registry:    "docker.io",
+			repository:  "bitnami/redis",
+			expected:    true,
+		},
+		{
+			description: "repository regex is anchored",
+			match:       goharborv1.ImageRewriteMatch{Repository: &goharborv1.ImageRewriteRepositoryMatch{Regex: "library"}},
+			registry:    "docker.io",
+			repository:  "library/nginx",
+			expected:    false,
+		},
+		{
+			description: "tag glob",
+			match:       goharborv1.ImageRewriteMatch{Tag: "v1.*"},
+			registry:    "docker.io",
+			repository:  "library/nginx",
+			tag:         "v1.2",
+			expected:    true,
+		},
+		{
+			description: "tag glob does not match digests",
+			match:       goharborv1.ImageRewriteMatch{Tag: "*"},
+			registry:    "docker.io",
+			repository:  "library/nginx",
+			expected:    false,
+		},
+	}
+
+	for _, tc := range tests {
+		policy, err := rule.NewPolicy("test", true, goharborv1.ImageRewritePolicySpec{
+			Match:  tc.match,
+			Target: goharborv1.ImageRewriteTarget{Project: "proxy"},
+		}, &goharborv1.HarborServerConfiguration{Spec: goharborv1.HarborServerConfigurationSpec{ServerURL: rawURL}})
+		require.NoError(t, err)
+		require.Equal(t, testURL+"/proxy", policy.Target())
+
+		output, err := policy.Matches(tc.registry, tc.repository, tc.tag)
+		require.NoError(t, err, tc.description)
+		require.Equal(t, tc.expected, output, tc.description)
+	}
+}
+
+func Test_SortPolicies(t *testing.T) {
+	policies := []rule.Policy{
+		{Name: "cluster-low", Spec: goharborv1.ImageRewritePolicySpec{Priority: 1}},
+		{Name: "ns/b", Namespaced: true, Spec: goharborv1.ImageRewritePolicySpec{Priority: 10}},
+		{Name: "cluster-high", Spec: goharborv1.ImageRewritePolicySpec{Priority: 10}},
+		{Name: "ns/a", Namespaced: true, Spec: goharborv1.ImageRewritePolicySpec{Priority: 10}},
+	}
+
+	rule.SortPolicies(policies)
+
+	names := make([]string, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+
+	require.Equal(t, []string{"ns/a", "ns/b", "cluster-high", "cluster-low"}, names)
+}
@@ -11,6 +11,25 @@ type Rule struct {
 	RegistryRegex string
 	Project       string
 	ServerURL     string
+	// HarborServerConfig is the name of the HSC the images are pulled through.
+	HarborServerConfig string
+	// Source is the kind/name of the resource defining the rule.
+	Source string
+}
+
+// String returns the rule in the 'registry=>project' format.
+func (r Rule) String() string {
+	return r.RegistryRegex + "=>" + r.Project
+}
+
+// WithSource sets the HSC and the source of the rules.
+func WithSource(rules []Rule, harborServerConfig, source string) []Rule {
+	for i := range rules {
+		rules[i].HarborServerConfig = harborServerConfig
+		rules[i].Source = source
+	}
+
+	return rules
 }
 
 // StringToRules parse rule and create Rule object
@@ -7,6 +7,7 @@ import (
 	"path"
 
 	"github.com/goharbor/harbor-operator/controllers"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/backup"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/chartmuseum"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/configuration"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/core"
@@ -22,6 +23,8 @@ import (
 	"github.com/goharbor/harbor-operator/controllers/goharbor/project"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/pullsecretbinding"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/registry"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/replication"
+	"github.com/goharbor/harbor-operator/controllers/goharbor/robotaccount"
 	"github.com/goharbor/harbor-operator/controllers/goharbor/trivy"
 	"github.com/goharbor/harbor-operator/pkg/config"
 	commonCtrl "github.com/goharbor/harbor-operator/pkg/controller"
@@ -49,12 +52,19 @@ var controllersBuilder = map[controllers.Controller]func(context.Context, *confi
 	controllers.HarborCluster: harborcluster.New,
 	// old configmap controller is planned to be removed at v1.3,
 	// the controller converts the cm to configuration cr.
-	controllers.HarborConfigurationCm:     configuration.NewWithCm,
-	controllers.HarborConfiguration:       configuration.New,
-	controllers.HarborServerConfiguration: harborserverconfiguration.New,
-	controllers.PullSecretBinding:         pullsecretbinding.New,
-	controllers.Namespace:                 namespace.New,
-	controllers.HarborProject:             project.New,
+	controllers.HarborConfigurationCm:               configuration.NewWithCm,
+	controllers.HarborConfiguration:                 configuration.New,
+	controllers.HarborServerConfiguration:           harborserverconfiguration.New,
+	controllers.PullSecretBinding:                   pullsecretbinding.New,
+	controllers.Namespace:                           namespace.New,
+	controllers.HarborProject:                       project.New,
+	controllers.HarborBackup:                        backup.New,
+	controllers.HarborRestore:                       backup.NewRestore,
+	controllers.HarborBackupSchedule:                backup.NewSchedule,
+	controllers.HarborRegistryEndpoint:              replication.NewRegistryEndpoint,
+	controllers.HarborReplicationPolicy:             replication.New,
+	controllers.HarborRobotAccount:                  robotaccount.New,
+	controllers.NamespacedHarborServerConfiguration: harborserverconfiguration.NewNamespaced,
 }
 
 type ControllerFactory func(context.Context, string, string, *configstore.Store) (commonCtrl.Reconciler, error)
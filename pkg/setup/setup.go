@@ -3,10 +3,12 @@ package setup
 import (
 	"context"
 
+	"github.com/goharbor/harbor-operator/pkg/config"
 	"github.com/goharbor/harbor-operator/pkg/factories/application"
 	"github.com/goharbor/harbor-operator/pkg/factories/logger"
 	"github.com/goharbor/harbor-operator/webhooks/harborserverconfiguration"
 	"github.com/goharbor/harbor-operator/webhooks/pod"
+	"github.com/ovh/configstore"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	kauthn "k8s.io/api/authorization/v1"
@@ -152,23 +154,57 @@ func WebhooksWithManager(ctx context.Context, mgr manager.Manager) error {
 	}
 
 	// setup separate webhooks
-	setupCustomWebhooks(mgr)
-
-	return nil
+	return setupCustomWebhooks(mgr)
 }
 
-func setupCustomWebhooks(mgr manager.Manager) {
+func setupCustomWebhooks(mgr manager.Manager) error {
+	checkPermissions, err := config.GetBool(configstore.DefaultStore, config.HarborServerPermissionCheckKey, config.DefaultHarborServerPermissionCheck)
+	if err != nil {
+		return errors.Wrap(err, "cannot check if the permissions of the harbor server configurations are checked")
+	}
+
+	// the pod and workload webhooks share the cached credentials and answers of the image verifications
+	servers := pod.NewHarborServers(mgr.GetClient())
+	verifier := pod.NewImageVerifier(servers)
+	enforcer := pod.NewImageEnforcer(servers)
+
 	mgr.GetWebhookServer().Register("/mutate-image-path", &webhook.Admission{
 		Handler: &pod.ImagePathRewriter{
-			Client: mgr.GetClient(),
-			Log:    logf.Log.WithName("webhooks").WithName("MutatingImagePath"),
+			Client:   mgr.GetClient(),
+			Log:      logf.Log.WithName("webhooks").WithName("MutatingImagePath"),
+			Recorder: mgr.GetEventRecorderFor("harbor-operator-image-rewriter"),
+			Verifier: verifier,
+			Enforcer: enforcer,
+		},
+	})
+
+	mgr.GetWebhookServer().Register("/mutate-workload-image-path", &webhook.Admission{
+		Handler: &pod.WorkloadImagePathRewriter{
+			ImagePathRewriter: pod.ImagePathRewriter{
+				Client:   mgr.GetClient(),
+				Log:      logf.Log.WithName("webhooks").WithName("MutatingWorkloadImagePath"),
+				Recorder: mgr.GetEventRecorderFor("harbor-operator-image-rewriter"),
+				Verifier: verifier,
+				Enforcer: enforcer,
+			},
 		},
 	})
 
 	mgr.GetWebhookServer().Register("/validate-hsc", &webhook.Admission{
 		Handler: &harborserverconfiguration.Validator{
-			Client: mgr.GetClient(),
-			Log:    logf.Log.WithName("webhooks").WithName("HarborServerConfigurationValidator"),
+			Client:           mgr.GetClient(),
+			Log:              logf.Log.WithName("webhooks").WithName("HarborServerConfigurationValidator"),
+			CheckPermissions: checkPermissions,
 		},
 	})
+
+	mgr.GetWebhookServer().Register("/validate-nhsc", &webhook.Admission{
+		Handler: &harborserverconfiguration.NamespacedValidator{
+			Client:           mgr.GetClient(),
+			Log:              logf.Log.WithName("webhooks").WithName("NamespacedHarborServerConfigurationValidator"),
+			CheckPermissions: checkPermissions,
+		},
+	})
+
+	return nil
 }
@@ -16,19 +16,26 @@ const (
 )
 
 var webhooksBuilder = map[controllers.Controller][]WebHook{
-	controllers.ChartMuseum:        {&goharborv1.ChartMuseum{}},
-	controllers.Core:               {&goharborv1.Core{}},
-	controllers.Exporter:           {&goharborv1.Exporter{}},
-	controllers.Harbor:             {&goharborv1.Harbor{}},
-	controllers.JobService:         {&goharborv1.JobService{}},
-	controllers.Registry:           {&goharborv1.Registry{}},
-	controllers.Portal:             {&goharborv1.Portal{}},
-	controllers.RegistryController: {&goharborv1.RegistryController{}},
-	controllers.Trivy:              {&goharborv1.Trivy{}},
-	controllers.NotaryServer:       {&goharborv1.NotaryServer{}},
-	controllers.NotarySigner:       {&goharborv1.NotarySigner{}},
-	controllers.HarborCluster:      {&goharborv1.HarborCluster{}},
-	controllers.HarborProject:      {&goharborv1.HarborProject{}},
+	controllers.ChartMuseum:             {&goharborv1.ChartMuseum{}},
+	controllers.Core:                    {&goharborv1.Core{}},
+	controllers.Exporter:                {&goharborv1.Exporter{}},
+	controllers.Harbor:                  {&goharborv1.Harbor{}},
+	controllers.JobService:              {&goharborv1.JobService{}},
+	controllers.Registry:                {&goharborv1.Registry{}},
+	controllers.Portal:                  {&goharborv1.Portal{}},
+	controllers.RegistryController:      {&goharborv1.RegistryController{}},
+	controllers.Trivy:                   {&goharborv1.Trivy{}},
+	controllers.NotaryServer:            {&goharborv1.NotaryServer{}},
+	controllers.NotarySigner:            {&goharborv1.NotarySigner{}},
+	controllers.HarborCluster:           {&goharborv1.HarborCluster{}},
+	controllers.HarborProject:           {&goharborv1.HarborProject{}},
+	controllers.HarborBackup:            {&goharborv1.HarborBackup{}},
+	controllers.HarborRestore:           {&goharborv1.HarborRestore{}},
+	controllers.HarborBackupSchedule:    {&goharborv1.HarborBackupSchedule{}},
+	controllers.HarborRegistryEndpoint:  {&goharborv1.HarborRegistryEndpoint{}},
+	controllers.HarborReplicationPolicy: {&goharborv1.HarborReplicationPolicy{}},
+	controllers.HarborRobotAccount:      {&goharborv1.HarborRobotAccount{}},
+	controllers.ImageRewritePolicy:      {&goharborv1.ImageRewritePolicy{}, &goharborv1.ClusterImageRewritePolicy{}},
 }
 
 type WebHook interface {
@@ -5,6 +5,8 @@ const (
 	AnnotationHarborServer = "goharbor.io/harbor"
 	// AnnotationAccount is the annnotation for service account.
 	AnnotationAccount = "goharbor.io/service-account"
+	// AnnotationAccountSelector is the annotation for the label selector of the service accounts.
+	AnnotationAccountSelector = "goharbor.io/service-account-selector"
 	// AnnotationProject is the annnotation for harbor project name.
 	AnnotationProject = "goharbor.io/project"
 	// AnnotationRobot is the annotation for robot id.
@@ -15,6 +17,8 @@ const (
 	AnnotationSecOwner = "goharbor.io/owner"
 	// AnnotationImageRewriteRuleConfigMapRef is the annotation for reference to configmap that stores rules.
 	AnnotationImageRewriteRuleConfigMapRef = "goharbor.io/rewriting-rules"
+	// AnnotationImageRewrites is the annotation recording the image rewrites of a pod, for auditing.
+	AnnotationImageRewrites = "goharbor.io/image-rewrites"
 
 	// ConfigMapKeyHarborServer is the key in configmap that for HSC.
 	ConfigMapKeyHarborServer = "hsc"
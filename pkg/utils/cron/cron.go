@@ -0,0 +1,30 @@
+package cron
+
+import (
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule is a parsed cron expression.
+// Its Next method returns the first activation strictly after the given time,
+// or the zero time if the schedule never activates.
+type Schedule = cron.Schedule
+
+var (
+	parser = cron.NewParser(
+		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	)
+	secondsParser = cron.NewParser(
+		cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow,
+	)
+)
+
+// Parse parses a standard 5 fields cron expression: minute, hour, day of month, month and day of week,
+// optionally preceded by a seconds field. Predefined schedules like @daily are supported.
+func Parse(spec string) (Schedule, error) {
+	return parser.Parse(spec)
+}
+
+// ParseWithSeconds parses a 6 fields cron expression, seconds first, as used by Harbor.
+func ParseWithSeconds(spec string) (Schedule, error) {
+	return secondsParser.Parse(spec)
+}
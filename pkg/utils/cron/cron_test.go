@@ -0,0 +1,66 @@
+package cron_test
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor-operator/pkg/utils/cron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cron", func() {
+	from := time.Date(2021, time.March, 15, 10, 30, 0, 0, time.UTC) // Monday
+
+	DescribeTable("Next activation",
+		func(spec string, expected time.Time) {
+			schedule, err := cron.Parse(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schedule.Next(from)).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2021, time.March, 15, 10, 31, 0, 0, time.UTC)),
+		Entry("every 15 minutes", "*/15 * * * *", time.Date(2021, time.March, 15, 10, 45, 0, 0, time.UTC)),
+		Entry("daily", "@daily", time.Date(2021, time.March, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("hourly range", "0 9-17 * * *", time.Date(2021, time.March, 15, 11, 0, 0, 0, time.UTC)),
+		Entry("list of hours", "0 2,22 * * *", time.Date(2021, time.March, 15, 22, 0, 0, 0, time.UTC)),
+		Entry("sunday", "0 3 * * 0", time.Date(2021, time.March, 21, 3, 0, 0, 0, time.UTC)),
+		Entry("next month", "0 0 1 * *", time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 20 * 2", time.Date(2021, time.March, 16, 0, 0, 0, 0, time.UTC)),
+		Entry("leap day", "0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("with seconds", "30 */15 * * * *", time.Date(2021, time.March, 15, 10, 30, 30, 0, time.UTC)),
+		Entry("every interval", "@every 90s", time.Date(2021, time.March, 15, 10, 31, 30, 0, time.UTC)),
+	)
+
+	It("Should never match an impossible date", func() {
+		schedule, err := cron.Parse("0 0 30 2 *")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule.Next(from).IsZero()).To(BeTrue())
+	})
+
+	DescribeTable("Invalid expressions",
+		func(spec string) {
+			_, err := cron.Parse(spec)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("missing field", "* * * *"),
+		Entry("too many fields", "0 0 0 * * * *"),
+		Entry("out of range", "60 * * * *"),
+		Entry("invalid step", "*/0 * * * *"),
+		Entry("reversed range", "0 5-2 * * *"),
+		Entry("not a number", "a * * * *"),
+	)
+	DescribeTable("Harbor expressions",
+		func(spec string, valid bool) {
+			_, err := cron.ParseWithSeconds(spec)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("daily", "0 0 0 * * *", true),
+		Entry("seconds out of range", "60 0 0 * * *", false),
+		Entry("invalid seconds", "a 0 0 * * *", false),
+		Entry("missing seconds", "0 0 * * *", false),
+		Entry("descriptor", "@daily", false),
+	)
+})
@@ -0,0 +1,99 @@
+package harborserverconfiguration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rest"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// permissionCheckTimeout bounds the Harbor API calls of the permission check, so that the admission does not time out.
+const permissionCheckTimeout = 5 * time.Second
+
+// requiredPermissions are the system level permissions the operator needs to create the projects and their robot accounts.
+var requiredPermissions = []models.Permission{
+	{Resource: "project", Action: "create"},
+	{Resource: "robot", Action: "create"},
+}
+
+// validateCredential checks that the access secret exists with the expected keys.
+// When checkPermissions is set, it also checks with Harbor that the credential is valid
+// and has the system level permissions to create the projects and the robot accounts.
+func validateCredential(ctx context.Context, c client.Client, hsc *goharborv1.HarborServerConfiguration, checkPermissions bool) error {
+	if hsc.Spec.AccessCredential == nil {
+		return errors.New("the access credential is required")
+	}
+
+	server, err := rest.CreateHarborServer(ctx, c, hsc)
+	if err != nil {
+		return errors.Wrapf(err, "access secret %s/%s", hsc.Spec.AccessCredential.Namespace, hsc.Spec.AccessCredential.AccessSecretRef)
+	}
+
+	if !checkPermissions {
+		return nil
+	}
+
+	harbor, err := v2.NewWithServer(server)
+	if err != nil {
+		return errors.Wrapf(err, "invalid Harbor server %s", hsc.Spec.ServerURL)
+	}
+
+	harbor = harbor.WithContext(ctx).WithTimeout(permissionCheckTimeout)
+
+	if err := harbor.CheckCredential(); err != nil {
+		if errors.Is(err, v2.ErrInvalidCredential) {
+			return errors.Errorf("the Harbor server %s rejects the credential of the access secret", hsc.Spec.ServerURL)
+		}
+
+		return errors.Wrapf(err, "cannot reach the Harbor server %s", hsc.Spec.ServerURL)
+	}
+
+	permissions, err := harbor.GetSystemPermissions()
+	if err != nil {
+		return errors.Wrap(err, "cannot get the permissions of the credential")
+	}
+
+	if missing := missingPermissions(permissions); len(missing) > 0 {
+		return errors.Errorf("the credential lacks the system permissions to create the projects and the robot accounts: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func missingPermissions(permissions []*models.Permission) []string {
+	var missing []string
+
+	for _, required := range requiredPermissions {
+		found := false
+
+		for _, permission := range permissions {
+			if permission != nil && permission.Resource == required.Resource && permission.Action == required.Action {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, fmt.Sprintf("%s %s", required.Action, required.Resource))
+		}
+	}
+
+	return missing
+}
+
+// connectionChanged checks whether the update changes how the operator connects to the Harbor server.
+// The permissions are not checked again for the other updates, so that they are not blocked while Harbor is down.
+func connectionChanged(old, spec *goharborv1.HarborServerConfigurationSpec) bool {
+	return old.ServerURL != spec.ServerURL ||
+		old.Insecure != spec.Insecure ||
+		!reflect.DeepEqual(old.AccessCredential, spec.AccessCredential)
+}
@@ -0,0 +1,90 @@
+package harborserverconfiguration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-nhsc,mutating=false,failurePolicy=fail,groups="goharbor.io",resources=namespacedharborserverconfigurations,verbs=create;update,sideEffects=None,admissionReviewVersions=v1beta1,versions=v1beta1,name=nhsc.goharbor.io
+
+// NamespacedValidator validates the NamespacedHarborServerConfigurations.
+// The access secret must be in the namespace of the configuration, so that tenants cannot use the credentials of other namespaces.
+type NamespacedValidator struct {
+	Client client.Client
+	Log    logr.Logger
+	// CheckPermissions enables the live check of the credential and of its permissions with the Harbor server.
+	CheckPermissions bool
+	decoder          *admission.Decoder
+}
+
+var (
+	_ admission.Handler         = (*NamespacedValidator)(nil)
+	_ admission.DecoderInjector = (*NamespacedValidator)(nil)
+)
+
+func (h *NamespacedValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	nhsc := &goharborv1.NamespacedHarborServerConfiguration{}
+
+	err := h.decoder.Decode(req, nhsc)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	namespace := nhsc.Namespace
+	if namespace == "" {
+		namespace = req.Namespace
+	}
+
+	if nhsc.Spec.AccessCredential != nil && nhsc.Spec.AccessCredential.Namespace != namespace {
+		return admission.ValidationResponse(false, fmt.Sprintf("%s can not be validated, the access secret must be in the namespace %q", nhsc.Name, namespace))
+	}
+
+	// the image rewriting of the pod webhook is configured by the cluster administrators only
+	if len(nhsc.Spec.Rules) > 0 || nhsc.Spec.NamespaceSelector != nil || nhsc.Spec.ImageVerification != nil || nhsc.Spec.ImagePolicy != nil {
+		return admission.ValidationResponse(false, fmt.Sprintf("%s can not be validated, rules, namespaceSelector, imageVerification and imagePolicy are only supported by HarborServerConfiguration", nhsc.Name))
+	}
+
+	checkPermissions := h.CheckPermissions
+
+	if checkPermissions && req.Operation == admissionv1.Update {
+		old := &goharborv1.NamespacedHarborServerConfiguration{}
+		if err := h.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		checkPermissions = connectionChanged(&old.Spec, &nhsc.Spec)
+	}
+
+	if err := validateCredential(ctx, h.Client, nhsc.ToHarborServerConfiguration(), checkPermissions); err != nil {
+		return admission.ValidationResponse(false, fmt.Sprintf("%s can not be validated, %s", nhsc.Name, err.Error()))
+	}
+
+	// Check for duplicate default configurations in the namespace
+	if nhsc.Spec.Default {
+		nhscList := &goharborv1.NamespacedHarborServerConfigurationList{}
+		if err := h.Client.List(ctx, nhscList, client.InNamespace(namespace)); err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to list namespaced harbor server configurations: %w", err))
+		}
+
+		for _, harborConf := range nhscList.Items {
+			if harborConf.Name != nhsc.Name && harborConf.Spec.Default {
+				return admission.ValidationResponse(false, fmt.Sprintf("%q can not be set as default, %q is the default harbor server configuration of namespace %q", nhsc.Name, harborConf.Name, namespace))
+			}
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+func (h *NamespacedValidator) InjectDecoder(decoder *admission.Decoder) error {
+	h.decoder = decoder
+
+	return nil
+}
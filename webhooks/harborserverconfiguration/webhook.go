@@ -9,6 +9,7 @@ import (
 	"github.com/go-logr/logr"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/umisama/go-regexpcache"
+	admissionv1 "k8s.io/api/admission/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -17,9 +18,11 @@ import (
 // +kubebuilder:webhook:path=/validate-hsc,mutating=false,failurePolicy=fail,groups="goharbor.io",resources=harborserverconfigurations,verbs=create;update,sideEffects=None,admissionReviewVersions=v1beta1,versions=v1beta1,name=hsc.goharbor.io
 
 type Validator struct {
-	Client  client.Client
-	Log     logr.Logger
-	decoder *admission.Decoder
+	Client client.Client
+	Log    logr.Logger
+	// CheckPermissions enables the live check of the credential and of its permissions with the Harbor server.
+	CheckPermissions bool
+	decoder          *admission.Decoder
 }
 
 var (
@@ -42,6 +45,16 @@ func (h *Validator) Handle(ctx context.Context, req admission.Request) admission
 			return admission.ValidationResponse(false, fmt.Sprintf("%s can not be validated, %q is not a valid regular expression: %s", hsc.Name, registryRegex, err.Error()))
 		}
 	}
+
+	checkPermissions, err := h.shouldCheckPermissions(req, &hsc.Spec)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := validateCredential(ctx, h.Client, hsc, checkPermissions); err != nil {
+		return admission.ValidationResponse(false, fmt.Sprintf("%s can not be validated, %s", hsc.Name, err.Error()))
+	}
+
 	// Check for duplicate default configurations
 	if hsc.Spec.Default {
 		hscList := &goharborv1.HarborServerConfigurationList{}
@@ -59,6 +72,20 @@ func (h *Validator) Handle(ctx context.Context, req admission.Request) admission
 	return admission.Allowed("")
 }
 
+// shouldCheckPermissions checks the permissions on creation, and on the updates changing the connection to the Harbor server.
+func (h *Validator) shouldCheckPermissions(req admission.Request, spec *goharborv1.HarborServerConfigurationSpec) (bool, error) {
+	if !h.CheckPermissions || req.Operation != admissionv1.Update {
+		return h.CheckPermissions, nil
+	}
+
+	old := &goharborv1.HarborServerConfiguration{}
+	if err := h.decoder.DecodeRaw(req.OldObject, old); err != nil {
+		return false, err
+	}
+
+	return connectionChanged(&old.Spec, spec), nil
+}
+
 func (h *Validator) InjectDecoder(decoder *admission.Decoder) error {
 	h.decoder = decoder
 
@@ -0,0 +1,136 @@
+package harborserverconfiguration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/webhooks/harborserverconfiguration"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newHarborAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	passwords := map[string]string{
+		"admin":   "Harbor12345",
+		"limited": "Harbor12345",
+	}
+
+	permissions := map[string][]map[string]string{
+		"admin":   {{"resource": "project", "action": "create"}, {"resource": "robot", "action": "create"}},
+		"limited": {{"resource": "project", "action": "create"}},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || passwords[username] != password {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/api/v2.0/users/current/permissions":
+			require.Equal(t, "/system", r.URL.Query().Get("scope"))
+			require.NoError(t, json.NewEncoder(w).Encode(permissions[username]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func Test_Validator_Credential(t *testing.T) {
+	server := newHarborAPI(t)
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	secret := func(name, username, password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "harbor"},
+			Data: map[string][]byte{
+				"accessKey":    []byte(username),
+				"accessSecret": []byte(password),
+			},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		secret("admin", "admin", "Harbor12345"),
+		secret("limited", "limited", "Harbor12345"),
+		secret("wrong", "admin", "wrong"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "nokeys", Namespace: "harbor"},
+			Data:       map[string][]byte{"username": []byte("admin")},
+		},
+	).Build()
+
+	decoder, err := admission.NewDecoder(scheme)
+	require.NoError(t, err)
+
+	validate := func(checkPermissions bool, secretName string) admission.Response {
+		validator := &harborserverconfiguration.Validator{
+			Client:           c,
+			Log:              logr.Discard(),
+			CheckPermissions: checkPermissions,
+		}
+		require.NoError(t, validator.InjectDecoder(decoder))
+
+		raw, err := json.Marshal(&goharborv1.HarborServerConfiguration{
+			TypeMeta:   metav1.TypeMeta{APIVersion: goharborv1.GroupVersion.String(), Kind: "HarborServerConfiguration"},
+			ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+			Spec: goharborv1.HarborServerConfigurationSpec{
+				ServerURL:        server.URL,
+				Version:          "2.4.1",
+				AccessCredential: &goharborv1.AccessCredential{Namespace: "harbor", AccessSecretRef: secretName},
+			},
+		})
+		require.NoError(t, err)
+
+		return validator.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	res := validate(false, "missing")
+	require.False(t, res.Allowed, "missing access secret")
+	require.Contains(t, string(res.Result.Reason), "access secret harbor/missing")
+
+	res = validate(false, "nokeys")
+	require.False(t, res.Allowed, "access secret without the expected keys")
+	require.Contains(t, string(res.Result.Reason), "accessKey and accessSecret keys are required")
+
+	res = validate(false, "wrong")
+	require.True(t, res.Allowed, "the credential is not checked with Harbor by default")
+
+	res = validate(true, "admin")
+	require.True(t, res.Allowed, res.Result)
+
+	res = validate(true, "wrong")
+	require.False(t, res.Allowed, "rejected credential")
+	require.Contains(t, string(res.Result.Reason), "rejects the credential")
+
+	res = validate(true, "limited")
+	require.False(t, res.Allowed, "missing permissions")
+	require.Contains(t, string(res.Result.Reason), "create robot")
+	require.NotContains(t, string(res.Result.Reason), "create project")
+}
@@ -32,11 +32,64 @@ func ReplaceRegistryInImageRef(imageReference, replacementRegistry string) (imag
 	return strings.Replace(named.String(), reference.Domain(named), replacementRegistry, 1), nil
 }
 
-// rewriteContainer replaces any registries matching the image rules with the given serverURL.
-func rewriteContainer(imageReference string, rules []rule.Rule) (imageRef string, err error) {
-	registry, err := RegistryFromImageRef(imageReference)
+// ImageRewrite records the rewrite of the image of a container, for auditing.
+type ImageRewrite struct {
+	// Container is the name of the container.
+	Container string `json:"container"`
+	// Original is the image before the rewrite.
+	Original string `json:"original"`
+	// Rewritten is the image after the rewrite.
+	Rewritten string `json:"rewritten"`
+	// HarborServerConfig is the name of the HSC the image is pulled through.
+	HarborServerConfig string `json:"harborServerConfig,omitempty"`
+	// Source is the kind/name of the policy or of the resource defining the rule which rewrote the image.
+	Source string `json:"source"`
+	// Rule is the rule which rewrote the image, empty for policies.
+	Rule string `json:"rule,omitempty"`
+	// Digest is the digest the image is pinned by, if resolved by the image policy of the HSC.
+	Digest string `json:"digest,omitempty"`
+}
+
+// rewriteContainer replaces the registry of the image with the target of the first matching policy,
+// or with the given serverURL of the first matching image rule.
+// A nil rewrite is returned if the image does not match, or is already pulled through Harbor.
+func rewriteContainer(imageReference string, policies []rule.Policy, rules []rule.Rule) (*ImageRewrite, error) {
+	named, err := reference.ParseDockerRef(imageReference)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	registry := reference.Domain(named)
+
+	// the image is already pulled through Harbor, e.g. from a rewritten pod template
+	if isHarborRegistry(registry, policies, rules) {
+		return nil, nil
+	}
+
+	var tag string
+	if tagged, ok := named.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+
+	for i := range policies {
+		match, err := policies[i].Matches(registry, reference.Path(named), tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if match {
+			rewritten, err := ReplaceRegistryInImageRef(imageReference, policies[i].Target())
+			if err != nil {
+				return nil, err
+			}
+
+			return &ImageRewrite{
+				Original:           imageReference,
+				Rewritten:          rewritten,
+				HarborServerConfig: policies[i].HarborServerConfig,
+				Source:             policies[i].Source(),
+			}, nil
+		}
 	}
 
 	var starRule *rule.Rule
@@ -45,25 +98,53 @@ func rewriteContainer(imageReference string, rules []rule.Rule) (imageRef string
 		if r.RegistryRegex != "*" {
 			regex, err := regexpcache.Compile(r.RegistryRegex)
 			if err != nil {
-				return "", err
+				return nil, err
 			}
 
 			if regex.MatchString(registry) {
-				rewritten := fmt.Sprintf("%s/%s", r.ServerURL, r.Project)
-
-				return ReplaceRegistryInImageRef(imageReference, rewritten)
+				return ruleRewrite(imageReference, r)
 			}
-		} else {
+		} else if starRule == nil {
 			starRule = &rules[i]
 		}
 	}
 
-	// * has the lowerest priority in the rules, match this in the end.
+	// * has the lowerest priority in the rules, match the first one in the end.
 	if starRule != nil {
-		rewritten := fmt.Sprintf("%s/%s", starRule.ServerURL, starRule.Project)
+		return ruleRewrite(imageReference, *starRule)
+	}
 
-		return ReplaceRegistryInImageRef(imageReference, rewritten)
+	return nil, nil
+}
+
+// isHarborRegistry returns whether the registry is the Harbor server of a policy or a rule.
+func isHarborRegistry(registry string, policies []rule.Policy, rules []rule.Rule) bool {
+	for _, p := range policies {
+		if p.ServerURL == registry {
+			return true
+		}
+	}
+
+	for _, r := range rules {
+		if r.ServerURL == registry {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ruleRewrite(imageReference string, r rule.Rule) (*ImageRewrite, error) {
+	rewritten, err := ReplaceRegistryInImageRef(imageReference, fmt.Sprintf("%s/%s", r.ServerURL, r.Project))
+	if err != nil {
+		return nil, err
 	}
 
-	return "", nil
+	return &ImageRewrite{
+		Original:           imageReference,
+		Rewritten:          rewritten,
+		HarborServerConfig: r.HarborServerConfig,
+		Source:             r.Source,
+		Rule:               r.String(),
+	}, nil
 }
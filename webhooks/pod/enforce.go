@@ -0,0 +1,192 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	v2 "github.com/goharbor/harbor-operator/pkg/rest/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultImagePolicyTimeout = 5 * time.Second
+
+	scanStatusSuccess = "Success"
+)
+
+// severityCodes orders the severities of the vulnerabilities as Harbor does, unknown severities being the highest.
+var severityCodes = map[string]int{
+	"none":       0,
+	"unknown":    0,
+	"negligible": 1,
+	"low":        2,
+	"medium":     3,
+	"high":       4,
+	"critical":   5,
+}
+
+func severityCode(severity string) int {
+	if code, ok := severityCodes[strings.ToLower(severity)]; ok {
+		return code
+	}
+
+	return 99 //nolint:gomnd
+}
+
+// ImageDeniedError is returned when the image policy of the HSC denies an image.
+type ImageDeniedError struct {
+	Image  string
+	Reason string
+}
+
+func (e *ImageDeniedError) Error() string {
+	return fmt.Sprintf("image %s is denied: %s", e.Image, e.Reason)
+}
+
+// ImageEnforcer enforces the image policy of the HSCs through the Harbor API.
+type ImageEnforcer struct {
+	Servers *HarborServers
+}
+
+// NewImageEnforcer returns an enforcer connecting to the given Harbor servers.
+func NewImageEnforcer(servers *HarborServers) *ImageEnforcer {
+	return &ImageEnforcer{
+		Servers: servers,
+	}
+}
+
+// Enforce returns the image pinned by digest when digest pinning is enabled in the image policy of the HSC.
+// An ImageDeniedError is returned when the image policy denies the image.
+func (e *ImageEnforcer) Enforce(ctx context.Context, hsc *goharborv1.HarborServerConfiguration, image string) (string, error) {
+	policy := hsc.Spec.ImagePolicy
+	if policy == nil {
+		return image, nil
+	}
+
+	pinning := policy.DigestPinning != "" && policy.DigestPinning != goharborv1.DigestPinningDisabled
+	if !pinning && !policy.DenyVulnerable {
+		return image, nil
+	}
+
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return "", err
+	}
+
+	// the first component of the path is the Harbor project
+	projectName, repository, ok := strings.Cut(reference.Path(named), "/")
+	if !ok {
+		return "", &ImageDeniedError{Image: image, Reason: "the image is not in a Harbor project"}
+	}
+
+	// Harbor failures deny the image only when the policy is strict
+	strict := policy.DenyVulnerable || policy.DigestPinning == goharborv1.DigestPinningRequired
+	failure := func(reason string) (string, error) {
+		if strict {
+			return "", &ImageDeniedError{Image: image, Reason: reason}
+		}
+
+		return image, nil
+	}
+
+	timeout := defaultImagePolicyTimeout
+	if policy.Timeout != nil {
+		timeout = policy.Timeout.Duration
+	}
+
+	server, err := e.Servers.Get(ctx, hsc)
+	if err != nil {
+		return failure(err.Error())
+	}
+
+	harborClient, err := v2.NewWithServer(server)
+	if err != nil {
+		return failure(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	harborClient.WithContext(ctx).WithTimeout(timeout)
+
+	art, err := harborClient.GetArtifact(projectName, repository, imageReference(named))
+	if err != nil {
+		return failure(err.Error())
+	}
+
+	if art == nil {
+		return failure("the artifact does not exist in Harbor")
+	}
+
+	if policy.DenyVulnerable {
+		if err := checkVulnerabilities(harborClient, projectName, art); err != nil {
+			return "", &ImageDeniedError{Image: image, Reason: err.Error()}
+		}
+	}
+
+	if !pinning {
+		return image, nil
+	}
+
+	if _, ok := named.(reference.Digested); ok {
+		return image, nil
+	}
+
+	return fmt.Sprintf("%s@%s", named.Name(), art.Digest), nil
+}
+
+// checkVulnerabilities returns an error when the project prevents vulnerable images from running
+// and the artifact has no scan report or vulnerabilities of the severity of the project or above.
+func checkVulnerabilities(harborClient *v2.Client, projectName string, art *models.Artifact) error {
+	project, err := harborClient.GetProjectByName(projectName)
+	if err != nil {
+		return err
+	}
+
+	if project.Metadata == nil || project.Metadata.PreventVul == nil || *project.Metadata.PreventVul != "true" {
+		return nil
+	}
+
+	severity := "low"
+	if project.Metadata.Severity != nil && *project.Metadata.Severity != "" {
+		severity = *project.Metadata.Severity
+	}
+
+	scanned := false
+
+	for _, report := range art.ScanOverview {
+		if report.ScanStatus != scanStatusSuccess {
+			continue
+		}
+
+		scanned = true
+
+		if severityCode(report.Severity) >= severityCode(severity) {
+			return errors.Errorf("the artifact has %s vulnerabilities, project %s prevents %s vulnerabilities and above", report.Severity, projectName, severity)
+		}
+	}
+
+	if !scanned {
+		return errors.Errorf("the artifact has no scan report, project %s prevents vulnerable images", projectName)
+	}
+
+	return nil
+}
+
+// imageReference returns the digest or the tag of the image, latest by default.
+func imageReference(named reference.Named) string {
+	if digested, ok := named.(reference.Digested); ok {
+		return digested.Digest().String()
+	}
+
+	if tagged, ok := named.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+
+	return "latest"
+}
@@ -0,0 +1,133 @@
+package pod_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/webhooks/pod"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func newHarborAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	nginx := map[string]interface{}{
+		"digest": testDigest,
+		"scan_overview": map[string]interface{}{
+			"application/vnd.security.vulnerability.report; version=1.1": map[string]interface{}{
+				"scan_status": "Success",
+				"severity":    "Low",
+			},
+		},
+	}
+
+	artifacts := map[string]map[string]interface{}{
+		"/api/v2.0/projects/proxy/repositories/library%252Fnginx/artifacts/1.21":          nginx,
+		"/api/v2.0/projects/proxy/repositories/library%252Fnginx/artifacts/" + testDigest: nginx,
+		"/api/v2.0/projects/proxy/repositories/library%252Fredis/artifacts/6": {
+			"digest": testDigest,
+			"scan_overview": map[string]interface{}{
+				"application/vnd.security.vulnerability.report; version=1.1": map[string]interface{}{
+					"scan_status": "Success",
+					"severity":    "Critical",
+				},
+			},
+		},
+		"/api/v2.0/projects/proxy/repositories/library%252Fbusybox/artifacts/1.36": {
+			"digest": testDigest,
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/v2.0/projects" {
+			require.NoError(t, json.NewEncoder(w).Encode([]map[string]interface{}{{
+				"name": r.URL.Query().Get("name"),
+				"metadata": map[string]string{
+					"prevent_vul": "true",
+					"severity":    "high",
+				},
+			}}))
+
+			return
+		}
+
+		if artifact, ok := artifacts[r.URL.EscapedPath()]; ok {
+			require.NoError(t, json.NewEncoder(w).Encode(artifact))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"errors": []interface{}{}}))
+	}))
+}
+
+func Test_ImageEnforcer(t *testing.T) {
+	server := newHarborAPI(t)
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "harbor"},
+		Data: map[string][]byte{
+			"accessKey":    []byte("admin"),
+			"accessSecret": []byte("secret"),
+		},
+	}
+
+	hsc := &goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL: server.URL,
+			AccessCredential: &goharborv1.AccessCredential{
+				Namespace:       "harbor",
+				AccessSecretRef: "creds",
+			},
+			ImagePolicy: &goharborv1.HarborServerImagePolicy{
+				DigestPinning:  goharborv1.DigestPinningRequired,
+				DenyVulnerable: true,
+			},
+		},
+	}
+
+	enforcer := pod.NewImageEnforcer(pod.NewHarborServers(fake.NewClientBuilder().WithObjects(secret).Build()))
+	host := strings.TrimPrefix(server.URL, "http://")
+	ctx := context.Background()
+
+	pinned, err := enforcer.Enforce(ctx, hsc, host+"/proxy/library/nginx:1.21")
+	require.NoError(t, err)
+	require.Equal(t, host+"/proxy/library/nginx@"+testDigest, pinned)
+
+	pinned, err = enforcer.Enforce(ctx, hsc, host+"/proxy/library/nginx@"+testDigest)
+	require.NoError(t, err, "images pinned by digest are kept")
+	require.Equal(t, host+"/proxy/library/nginx@"+testDigest, pinned)
+
+	var denied *pod.ImageDeniedError
+
+	_, err = enforcer.Enforce(ctx, hsc, host+"/proxy/library/redis:6")
+	require.True(t, errors.As(err, &denied), "critical vulnerabilities are denied")
+
+	_, err = enforcer.Enforce(ctx, hsc, host+"/proxy/library/busybox:1.36")
+	require.True(t, errors.As(err, &denied), "images without scan report are denied")
+
+	_, err = enforcer.Enforce(ctx, hsc, host+"/proxy/library/missing:1.0")
+	require.True(t, errors.As(err, &denied), "missing images are denied when pinning is required")
+
+	hsc.Spec.ImagePolicy = &goharborv1.HarborServerImagePolicy{DigestPinning: goharborv1.DigestPinningPreferred}
+
+	pinned, err = enforcer.Enforce(ctx, hsc, host+"/proxy/library/missing:1.0")
+	require.NoError(t, err)
+	require.Equal(t, host+"/proxy/library/missing:1.0", pinned, "missing images are kept when pinning is preferred")
+}
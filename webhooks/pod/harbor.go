@@ -0,0 +1,75 @@
+package pod
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/rest"
+	"github.com/goharbor/harbor-operator/pkg/rest/model"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultCredentialCacheDuration = 5 * time.Minute
+
+type cachedServer struct {
+	server  *model.HarborServer
+	expires time.Time
+}
+
+// HarborServers caches the connection data of the HSCs, with the credentials read from their access secret.
+// Rotated credentials are read again once the cache duration is over.
+type HarborServers struct {
+	Client        client.Client
+	CacheDuration time.Duration
+
+	lock  sync.Mutex
+	cache map[string]cachedServer
+}
+
+// NewHarborServers returns the cache of the HSC connection data, reading the credentials with the client.
+func NewHarborServers(c client.Client) *HarborServers {
+	return &HarborServers{
+		Client:        c,
+		CacheDuration: defaultCredentialCacheDuration,
+		cache:         map[string]cachedServer{},
+	}
+}
+
+// Get returns the connection data of the Harbor server of the HSC.
+func (h *HarborServers) Get(ctx context.Context, hsc *goharborv1.HarborServerConfiguration) (*model.HarborServer, error) {
+	// the resource version invalidates the cached credential when the HSC changes
+	key := fmt.Sprintf("%s/%s", hsc.GetName(), hsc.GetResourceVersion())
+
+	h.lock.Lock()
+	cached, ok := h.cache[key]
+	h.lock.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.server, nil
+	}
+
+	server, err := rest.CreateHarborServer(ctx, h.Client, hsc)
+	if err != nil {
+		return nil, err
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	now := time.Now()
+	for k, c := range h.cache {
+		if now.After(c.expires) {
+			delete(h.cache, k)
+		}
+	}
+
+	h.cache[key] = cachedServer{
+		server:  server,
+		expires: now.Add(h.CacheDuration),
+	}
+
+	return server, nil
+}
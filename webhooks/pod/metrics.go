@@ -0,0 +1,26 @@
+package pod
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	decisionRewritten = "rewritten"
+	decisionSkipped   = "skipped"
+	decisionFallback  = "fallback"
+	decisionDenied    = "denied"
+)
+
+var imageRewriteDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "harbor_operator_image_rewrite_decisions_total",
+	Help: "Number of container images admitted by the image rewrite webhook by decision: rewritten, skipped when no rule matches, fallback to the original image when the verification fails, or denied by the image policy.",
+}, []string{"decision", "harbor_server_configuration"})
+
+func init() { //nolint:gochecknoinits
+	metrics.Registry.MustRegister(imageRewriteDecisions)
+}
+
+func recordDecision(decision, harborServerConfig string) {
+	imageRewriteDecisions.WithLabelValues(decision, harborServerConfig).Inc()
+}
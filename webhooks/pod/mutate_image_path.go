@@ -5,31 +5,47 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/go-logr/logr"
 	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
 	"github.com/goharbor/harbor-operator/pkg/rule"
 	"github.com/goharbor/harbor-operator/pkg/utils/consts"
 	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// +kubebuilder:webhook:path=/mutate-image-path,mutating=true,failurePolicy=fail,groups="",resources=pods,verbs=create;update,sideEffects=NoneOnDryRun,admissionReviewVersions=v1beta1,versions=v1,name=mimg.kb.io
+// +kubebuilder:rbac:groups=goharbor.io,resources=imagerewritepolicies;clusterimagerewritepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// +kubebuilder:webhook:path=/mutate-image-path,mutating=true,failurePolicy=fail,groups="",resources=pods;pods/ephemeralcontainers,verbs=create;update,sideEffects=NoneOnDryRun,admissionReviewVersions=v1beta1,versions=v1,name=mimg.kb.io
 
 // ImagePathRewriter implements webhook logic to mutate the image path of deploying pods.
 type ImagePathRewriter struct {
-	Client  client.Client
-	Log     logr.Logger
-	decoder *admission.Decoder
+	Client   client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Verifier verifies the rewritten images of the HSCs with image verification enabled.
+	Verifier *ImageVerifier
+	// Enforcer enforces the image policy of the HSCs.
+	Enforcer *ImageEnforcer
+	decoder  *admission.Decoder
 }
 
 // Handle the admission webhook for mutating the image path of deploying pods.
-func (ipr *ImagePathRewriter) Handle(ctx context.Context, req admission.Request) admission.Response { //nolint:funlen,gocognit
+func (ipr *ImagePathRewriter) Handle(ctx context.Context, req admission.Request) admission.Response {
 	pod := &corev1.Pod{}
 
 	err := ipr.decoder.Decode(req, pod)
@@ -37,20 +53,101 @@ func (ipr *ImagePathRewriter) Handle(ctx context.Context, req admission.Request)
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	// Get namespace of pod
-	podNS, err := ipr.getPodNamespace(ctx, req.Namespace)
+	ipr.Log.Info("receive pod request", "pod", pod.Name, "namespace", req.Namespace)
+
+	var previous map[string]string
+
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		old := &corev1.Pod{}
+		if err := ipr.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		previous = podImages(old)
+	}
+
+	rw, res := ipr.resolveRewriting(ctx, req.Namespace)
+	if rw == nil {
+		return res
+	}
+
+	rw.previous = previous
+
+	ipr.Log.Info("try rewrite the image path")
+
+	return ipr.rewriteContainers(ctx, req, rw, pod)
+}
+
+// rewriting holds the image rewrite rules and policies applying to a namespace.
+type rewriting struct {
+	// servers are all the HSCs by name, for the policies referring to them.
+	servers map[string]*goharborv1.HarborServerConfiguration
+	// scoped are the HSCs applying to the namespace, the assigned HSC first.
+	scoped   []*goharborv1.HarborServerConfiguration
+	policies []rule.Policy
+	rules    []rule.Rule
+	// previous are the images of the containers by name before an update.
+	previous map[string]string
+}
+
+// hasImagePolicy returns whether an HSC has an image policy.
+func hasImagePolicy(hscs []*goharborv1.HarborServerConfiguration) bool {
+	for _, hsc := range hscs {
+		if hsc.Spec.ImagePolicy != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// harborServerOf returns the HSC applying to the namespace of the Harbor server the image refers to, nil if none.
+// The first HSC in precedence order wins when several HSCs refer to the same server.
+func (rw *rewriting) harborServerOf(image string) *goharborv1.HarborServerConfiguration {
+	registry, err := RegistryFromImageRef(image)
 	if err != nil {
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("get pod namespace object error: %w", err))
+		return nil
 	}
 
-	ipr.Log.Info("receive pod request", "pod", pod.Name, "namespace", podNS.Name)
+	for _, hsc := range rw.scoped {
+		u, err := url.Parse(hsc.Spec.ServerURL)
+		if err == nil && u.Host == registry {
+			return hsc
+		}
+	}
 
-	// whether to rewrite image path is dependent on rules
-	// the rules could be in assigned hsc or default hsc
+	return nil
+}
+
+// unchanged returns whether the image of the container is unchanged by an update.
+func (rw *rewriting) unchanged(container, image string) bool {
+	previous, ok := rw.previous[container]
+
+	return ok && previous == image
+}
+
+// resolveRewriting returns the image rewrite rules and policies applying to the namespace.
+// The response is returned when there is nothing to rewrite, or on error.
+func (ipr *ImagePathRewriter) resolveRewriting(ctx context.Context, namespace string) (*rewriting, admission.Response) { //nolint:funlen,gocognit
+	podNS, err := ipr.getPodNamespace(ctx, namespace)
+	if err != nil {
+		return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("get pod namespace object error: %w", err))
+	}
+
+	// whether to rewrite image path is dependent on rules and policies
+	// the rules could be in the assigned hsc or in the hscs selecting the namespace
 	// assigned hsc has higher priority
-	ipr.Log.Info("try find image rewrite rules that will be applied to this pod")
+	ipr.Log.Info("try find image rewrite rules that will be applied to this namespace")
 
-	var allRules []rule.Rule
+	hscList := &goharborv1.HarborServerConfigurationList{}
+	if err := ipr.Client.List(ctx, hscList); err != nil {
+		return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("list hsc objects error: %w", err))
+	}
+
+	var (
+		allRules    []rule.Rule
+		assignedHSC *goharborv1.HarborServerConfiguration
+	)
 
 	// check if the configmap exist
 	if cmName, ok := podNS.Annotations[consts.AnnotationImageRewriteRuleConfigMapRef]; ok { //nolint:nestif
@@ -58,18 +155,18 @@ func (ipr *ImagePathRewriter) Handle(ctx context.Context, req admission.Request)
 		if err != nil {
 			// The resource may have been deleted after reconcile request coming in
 			if apierr.IsNotFound(err) {
-				return admission.Errored(http.StatusBadRequest, fmt.Errorf("the ConfigMap %s/%s is not found: %w", podNS.Name, cmName, err))
+				return nil, admission.Errored(http.StatusBadRequest, fmt.Errorf("the ConfigMap %s/%s is not found: %w", podNS.Name, cmName, err))
 			}
 
-			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to get ConfigMap %s/%s:,%w", podNS.Name, cmName, err))
+			return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to get ConfigMap %s/%s:,%w", podNS.Name, cmName, err))
 		}
 
 		// skip if rewriting is off
 		if enable, yes := cm.Data[consts.ConfigMapKeyRewriting]; yes {
 			if enable == consts.ConfigMapValueRewritingOff {
-				return admission.Allowed("no change")
+				return nil, admission.Allowed("no change")
 			} else if enable != consts.ConfigMapValueRewritingOn {
-				return admission.Errored(http.StatusBadRequest, errors.Errorf("the rewriting value in configmap %s/%s '%s' is unacceptable", podNS.Name, cmName, enable))
+				return nil, admission.Errored(http.StatusBadRequest, errors.Errorf("the rewriting value in configmap %s/%s '%s' is unacceptable", podNS.Name, cmName, enable))
 			}
 		}
 
@@ -77,73 +174,238 @@ func (ipr *ImagePathRewriter) Handle(ctx context.Context, req admission.Request)
 		if hscKey, yes := cm.Data[consts.ConfigMapKeyHarborServer]; yes {
 			hsc, err := ipr.getHarborServerConfig(ctx, hscKey)
 			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, err)
+				return nil, admission.Errored(http.StatusInternalServerError, err)
 			}
 
 			// check selector, error out if assigned HSC doesn't select current namespace
-			if hsc.Spec.NamespaceSelector != nil {
-				if match := checkNamespaceSelector(podNS.Labels, hsc.Spec.NamespaceSelector.MatchLabels); !match {
-					return admission.Errored(http.StatusBadRequest, errors.New("the selector specified in HSC doesn't match the current namespace"))
-				}
+			match, err := selectorMatches(hsc.Spec.NamespaceSelector, podNS.Labels)
+			if err != nil {
+				return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("invalid namespace selector of hsc %s: %w", hsc.Name, err))
+			}
+
+			if !match {
+				return nil, admission.Errored(http.StatusBadRequest, errors.New("the selector specified in HSC doesn't match the current namespace"))
 			}
 
 			// append rules of configMap to rules of hsc.
 			rulesFromHSC, err := rule.StringToRules(hsc.Spec.Rules, hsc.Spec.ServerURL)
 			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule, error: %w", err))
+				return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule, error: %w", err))
 			}
 
 			rulesFromConfigMap, err := rule.StringToRules(strings.Split(strings.TrimSpace(cm.Data[consts.ConfigMapKeyRules]), "\n"), hsc.Spec.ServerURL)
 			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule, error: %w", err))
+				return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule, error: %w", err))
 			}
 
-			allRules = rule.MergeRules(rulesFromConfigMap, rulesFromHSC)
+			allRules = rule.MergeRules(
+				rule.WithSource(rulesFromConfigMap, hsc.Name, fmt.Sprintf("ConfigMap/%s/%s", podNS.Name, cmName)),
+				rule.WithSource(rulesFromHSC, hsc.Name, "HarborServerConfiguration/"+hsc.Name),
+			)
+			assignedHSC = hsc
 		} else if _, yes := cm.Data[consts.ConfigMapKeyRules]; yes && strings.TrimSpace(cm.Data[consts.ConfigMapKeyRules]) != "" {
-			return admission.Errored(http.StatusBadRequest, errors.New("rule are defined in configMap but there is no hsc associated with it"))
+			return nil, admission.Errored(http.StatusBadRequest, errors.New("rule are defined in configMap but there is no hsc associated with it"))
 		}
 	}
 
-	defaultHSC, err := ipr.lookupDefaultHarborServerConfig(ctx)
+	// the rules of the hscs selecting the namespace have a lower priority
+	matchingHSCs, err := MatchingHarborServerConfigs(podNS, hscList.Items)
 	if err != nil {
-		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("get default hsc object error: %w", err))
+		return nil, admission.Errored(http.StatusInternalServerError, err)
 	}
 
-	if defaultHSC != nil && defaultHSC.Spec.NamespaceSelector != nil {
-		// check selector, if there is match, add the default rule to it. it has lowerest priority
-		if match := checkNamespaceSelector(podNS.Labels, defaultHSC.Spec.NamespaceSelector.MatchLabels); match {
-			ruleFromDefaultHSC, err := rule.StringToRules(defaultHSC.Spec.Rules, defaultHSC.Spec.ServerURL)
-			if err != nil {
-				return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule, error: %w", err))
-			}
+	var scoped []*goharborv1.HarborServerConfiguration
+	if assignedHSC != nil {
+		scoped = append(scoped, assignedHSC)
+	}
+
+	for _, hsc := range matchingHSCs {
+		if assignedHSC != nil && hsc.Name == assignedHSC.Name {
+			continue
+		}
+
+		scoped = append(scoped, hsc)
 
-			allRules = rule.MergeRules(allRules, ruleFromDefaultHSC)
-		} else {
-			// it's ok to not match the default hsc
-			ipr.Log.Info("default hsc ", defaultHSC.Namespace, "/", defaultHSC.Name, " doesn't match current namespace")
+		rulesFromHSC, err := rule.StringToRules(hsc.Spec.Rules, hsc.Spec.ServerURL)
+		if err != nil {
+			return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to parse rule of hsc %s, error: %w", hsc.Name, err))
 		}
+
+		allRules = rule.MergeRules(allRules, rule.WithSource(rulesFromHSC, hsc.Name, "HarborServerConfiguration/"+hsc.Name))
 	}
 
-	// there is no rule that will be applied to the current namespace, skip
-	if len(allRules) == 0 {
-		return admission.Allowed("no change")
+	servers := indexHarborServerConfigs(hscList.Items)
+
+	// image rewrite policies are evaluated before the rules
+	policies, err := ipr.lookupPolicies(ctx, podNS, servers)
+	if err != nil {
+		return nil, admission.Errored(http.StatusInternalServerError, fmt.Errorf("get image rewrite policies error: %w", err))
 	}
 
-	ipr.Log.Info("try rewrite the image path")
+	// there is no rule that will be applied to the current namespace and no image policy, skip
+	if len(allRules) == 0 && len(policies) == 0 && !hasImagePolicy(scoped) {
+		return nil, admission.Allowed("no change")
+	}
 
-	return ipr.rewriteContainers(req, allRules, pod)
+	return &rewriting{
+		servers:  servers,
+		scoped:   scoped,
+		policies: policies,
+		rules:    allRules,
+	}, admission.Response{}
 }
 
-func checkNamespaceSelector(nsLabels, hscLabelSelector map[string]string) bool {
-	if nsLabels != nil && hscLabelSelector != nil {
-		for k, v := range nsLabels {
-			if _, ok := hscLabelSelector[k]; ok && v == hscLabelSelector[k] {
-				return true
+// MatchingHarborServerConfigs returns the HSCs whose rules apply to the namespace, in precedence order:
+// the HSCs with a namespace selector matching the namespace by name, then the default HSC.
+// The default HSC applies to all the namespaces if it has no namespace selector,
+// the other HSCs only apply to the namespaces selected by their namespace selector.
+func MatchingHarborServerConfigs(ns *corev1.Namespace, hscs []goharborv1.HarborServerConfiguration) ([]*goharborv1.HarborServerConfiguration, error) {
+	var (
+		matching   []*goharborv1.HarborServerConfiguration
+		defaultHSC *goharborv1.HarborServerConfiguration
+	)
+
+	for _, hsc := range sortHarborServerConfigs(hscs) {
+		if !hsc.Spec.Default && hsc.Spec.NamespaceSelector == nil {
+			continue
+		}
+
+		match, err := selectorMatches(hsc.Spec.NamespaceSelector, ns.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector of hsc %s: %w", hsc.Name, err)
+		}
+
+		switch {
+		case !match:
+			continue
+		case hsc.Spec.Default:
+			if defaultHSC == nil {
+				defaultHSC = hsc
 			}
+		default:
+			matching = append(matching, hsc)
 		}
 	}
 
-	return false
+	if defaultHSC != nil {
+		matching = append(matching, defaultHSC)
+	}
+
+	return matching, nil
+}
+
+// sortHarborServerConfigs returns the HSCs sorted by name.
+func sortHarborServerConfigs(hscs []goharborv1.HarborServerConfiguration) []*goharborv1.HarborServerConfiguration {
+	sorted := make([]*goharborv1.HarborServerConfiguration, 0, len(hscs))
+	for i := range hscs {
+		sorted = append(sorted, &hscs[i])
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+// selectorMatches returns whether the selector matches the labels of the namespace, a nil selector matching everything.
+func selectorMatches(selector *metav1.LabelSelector, nsLabels map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+
+	return s.Matches(labels.Set(nsLabels)), nil
+}
+
+// indexHarborServerConfigs returns the HSCs by name, the default HSC being also indexed by the empty name.
+func indexHarborServerConfigs(hscs []goharborv1.HarborServerConfiguration) map[string]*goharborv1.HarborServerConfiguration {
+	servers := map[string]*goharborv1.HarborServerConfiguration{}
+
+	for _, hsc := range sortHarborServerConfigs(hscs) {
+		servers[hsc.Name] = hsc
+
+		if _, ok := servers[""]; !ok && hsc.Spec.Default {
+			servers[""] = hsc
+		}
+	}
+
+	return servers
+}
+
+// lookupPolicies returns the image rewrite policies applying to the namespace, in evaluation order.
+// Policies without hsc use the default one.
+func (ipr *ImagePathRewriter) lookupPolicies(ctx context.Context, ns *corev1.Namespace, servers map[string]*goharborv1.HarborServerConfiguration) ([]rule.Policy, error) {
+	var policies []rule.Policy
+
+	irpList := &goharborv1.ImageRewritePolicyList{}
+	if err := ipr.Client.List(ctx, irpList, client.InNamespace(ns.Name)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+
+	for _, irp := range irpList.Items {
+		policy, err := ipr.resolvePolicy(servers, fmt.Sprintf("%s/%s", irp.Namespace, irp.Name), true, irp.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if policy != nil {
+			policies = append(policies, *policy)
+		}
+	}
+
+	cirpList := &goharborv1.ClusterImageRewritePolicyList{}
+	if err := ipr.Client.List(ctx, cirpList); err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+
+	for _, cirp := range cirpList.Items {
+		match, err := selectorMatches(cirp.Spec.NamespaceSelector, ns.Labels)
+		if err != nil {
+			ipr.Log.Error(err, "invalid namespace selector, skip the cluster image rewrite policy", "policy", cirp.Name)
+
+			continue
+		}
+
+		if !match {
+			continue
+		}
+
+		policy, err := ipr.resolvePolicy(servers, cirp.Name, false, cirp.Spec.ImageRewritePolicySpec)
+		if err != nil {
+			return nil, err
+		}
+
+		if policy != nil {
+			policies = append(policies, *policy)
+		}
+	}
+
+	rule.SortPolicies(policies)
+
+	return policies, nil
+}
+
+// resolvePolicy resolves the Harbor server of the policy.
+// A nil policy is returned if the HSC does not exist.
+func (ipr *ImagePathRewriter) resolvePolicy(servers map[string]*goharborv1.HarborServerConfiguration, name string, namespaced bool, spec goharborv1.ImageRewritePolicySpec) (*rule.Policy, error) {
+	hsc, ok := servers[spec.HarborServerConfig]
+	if !ok {
+		ipr.Log.Info("hsc not found, skip the image rewrite policy", "policy", name, "hsc", spec.HarborServerConfig)
+
+		return nil, nil
+	}
+
+	policy, err := rule.NewPolicy(name, namespaced, spec, hsc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
 }
 
 func (ipr *ImagePathRewriter) getConfigMap(ctx context.Context, name, namespace string) (*corev1.ConfigMap, error) {
@@ -160,39 +422,52 @@ func (ipr *ImagePathRewriter) getConfigMap(ctx context.Context, name, namespace
 	return cm, nil
 }
 
-func (ipr *ImagePathRewriter) rewriteContainers(req admission.Request, rules []rule.Rule, pod *corev1.Pod) admission.Response {
-	for i, c := range pod.Spec.Containers {
-		rewrittenImage, err := rewriteContainer(c.Image, rules)
-		if err != nil {
-			ipr.Log.Error(err, "invalid container image format", "image", c.Image)
+func (ipr *ImagePathRewriter) rewriteContainers(ctx context.Context, req admission.Request, rw *rewriting, pod *corev1.Pod) admission.Response {
+	var rewrites []ImageRewrite
 
-			continue
-		}
+	ref := podEventReference(req, pod)
 
-		if rewrittenImage != "" {
-			rewrittenContainer := c.DeepCopy()
-			rewrittenContainer.Image = rewrittenImage
-			pod.Spec.Containers[i] = *rewrittenContainer
+	images := make([]*string, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers)+len(pod.Spec.EphemeralContainers))
+	names := make([]string, 0, cap(images))
 
-			ipr.Log.Info("rewrite container image", "original", c.Image, "rewrite", rewrittenImage)
-		}
+	for i := range pod.Spec.Containers {
+		images = append(images, &pod.Spec.Containers[i].Image)
+		names = append(names, pod.Spec.Containers[i].Name)
 	}
 
-	for i, c := range pod.Spec.InitContainers {
-		rewrittenImage, err := rewriteContainer(c.Image, rules)
+	for i := range pod.Spec.InitContainers {
+		images = append(images, &pod.Spec.InitContainers[i].Image)
+		names = append(names, pod.Spec.InitContainers[i].Name)
+	}
+
+	for i := range pod.Spec.EphemeralContainers {
+		images = append(images, &pod.Spec.EphemeralContainers[i].Image)
+		names = append(names, pod.Spec.EphemeralContainers[i].Name)
+	}
+
+	for i, image := range images {
+		rewrite, err := ipr.rewriteImage(ctx, req, rw, ref, names[i], *image)
 		if err != nil {
-			ipr.Log.Error(err, "invalid container image format", "image", c.Image)
+			return admission.Denied(err.Error())
+		}
 
-			continue
+		if rewrite != nil {
+			*image = rewrite.Rewritten
+			rewrites = append(rewrites, *rewrite)
 		}
+	}
 
-		if rewrittenImage != "" {
-			rewrittenContainer := c.DeepCopy()
-			rewrittenContainer.Image = rewrittenImage
-			pod.Spec.InitContainers[i] = *rewrittenContainer
+	if len(rewrites) > 0 {
+		audit, err := MergeImageRewrites(pod.Annotations[consts.AnnotationImageRewrites], rewrites)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
 
-			ipr.Log.Info("rewrite init image", "original", c.Image, "rewrite", rewrittenImage)
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
 		}
+
+		pod.Annotations[consts.AnnotationImageRewrites] = audit
 	}
 
 	marshaledPod, err := json.Marshal(pod)
@@ -203,19 +478,178 @@ func (ipr *ImagePathRewriter) rewriteContainers(req admission.Request, rules []r
 	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
 }
 
-func (ipr *ImagePathRewriter) lookupDefaultHarborServerConfig(ctx context.Context) (*goharborv1.HarborServerConfiguration, error) {
-	hscList := &goharborv1.HarborServerConfigurationList{}
-	if err := ipr.Client.List(ctx, hscList); err != nil {
-		return nil, err
+// podImages returns the images of the containers of the pod by container name.
+func podImages(pod *corev1.Pod) map[string]string {
+	images := map[string]string{}
+
+	for _, c := range pod.Spec.Containers {
+		images[c.Name] = c.Image
 	}
 
-	for _, hsc := range hscList.Items {
-		if hsc.Spec.Default {
-			return &hsc, nil
+	for _, c := range pod.Spec.InitContainers {
+		images[c.Name] = c.Image
+	}
+
+	for _, c := range pod.Spec.EphemeralContainers {
+		images[c.Name] = c.Image
+	}
+
+	return images
+}
+
+// MergeImageRewrites returns the audit annotation with the rewrites replacing the previous rewrites of the same containers.
+func MergeImageRewrites(annotation string, rewrites []ImageRewrite) (string, error) {
+	var merged []ImageRewrite
+
+	if annotation != "" {
+		// an invalid annotation is overwritten
+		if err := json.Unmarshal([]byte(annotation), &merged); err != nil {
+			merged = nil
 		}
 	}
 
-	return nil, nil
+	for _, rewrite := range rewrites {
+		replaced := false
+
+		for i := range merged {
+			if merged[i].Container == rewrite.Container {
+				merged[i] = rewrite
+				replaced = true
+
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, rewrite)
+		}
+	}
+
+	audit, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+
+	return string(audit), nil
+}
+
+// rewriteImage returns the rewrite of the image of the container, nil if the image is kept.
+// When the image verification is enabled on the HSC, the original image is kept if the rewritten one cannot be verified.
+// The image policy of the HSC applies to the rewritten images and to the images already referring to a Harbor server,
+// an ImageDeniedError is returned if it denies the image.
+// The image policy does not apply to the images unchanged by an update, they were enforced when created.
+// The events are recorded on the given object, if any.
+func (ipr *ImagePathRewriter) rewriteImage(ctx context.Context, req admission.Request, rw *rewriting, ref *corev1.ObjectReference, container, image string) (*ImageRewrite, error) { //nolint:funlen
+	enforce := !rw.unchanged(container, image)
+
+	rewrite, err := rewriteContainer(image, rw.policies, rw.rules)
+	if err != nil {
+		ipr.Log.Error(err, "invalid container image format", "image", image)
+		recordDecision(decisionSkipped, "")
+
+		return nil, nil
+	}
+
+	if rewrite == nil {
+		hsc := rw.harborServerOf(image)
+		if !enforce || hsc == nil || hsc.Spec.ImagePolicy == nil {
+			recordDecision(decisionSkipped, "")
+
+			return nil, nil
+		}
+
+		rewrite = &ImageRewrite{
+			Original:           image,
+			Rewritten:          image,
+			HarborServerConfig: hsc.Name,
+			Source:             "HarborServerConfiguration/" + hsc.Name,
+		}
+	}
+
+	rewrite.Container = container
+	hsc := rw.servers[rewrite.HarborServerConfig]
+
+	if hsc != nil && hsc.Spec.ImageVerification != nil && ipr.Verifier != nil && rewrite.Rewritten != image {
+		if err := ipr.Verifier.Verify(ctx, hsc, rewrite.Rewritten); err != nil {
+			ipr.Log.Info("image verification failed, keep the original image", "container", container, "original", image, "rewrite", rewrite.Rewritten, "error", err.Error())
+			recordDecision(decisionFallback, rewrite.HarborServerConfig)
+			ipr.recordEvent(req, ref, corev1.EventTypeWarning, "ImageRewriteFallback",
+				fmt.Sprintf("Keep image %s of container %s: %s cannot be verified in %s: %v", image, container, rewrite.Rewritten, rewrite.HarborServerConfig, err))
+
+			return nil, nil
+		}
+	}
+
+	if enforce && hsc != nil && hsc.Spec.ImagePolicy != nil && ipr.Enforcer != nil {
+		pinned, err := ipr.Enforcer.Enforce(ctx, hsc, rewrite.Rewritten)
+		if err != nil {
+			ipr.Log.Info("image denied by the image policy", "container", container, "image", rewrite.Rewritten, "hsc", hsc.Name, "error", err.Error())
+			recordDecision(decisionDenied, rewrite.HarborServerConfig)
+			ipr.recordEvent(req, ref, corev1.EventTypeWarning, "ImageDenied",
+				fmt.Sprintf("Deny image %s of container %s by the image policy of %s: %v", rewrite.Rewritten, container, hsc.Name, err))
+
+			return nil, err
+		}
+
+		if pinned != rewrite.Rewritten {
+			if named, err := reference.ParseDockerRef(pinned); err == nil {
+				if digested, ok := named.(reference.Digested); ok {
+					rewrite.Digest = digested.Digest().String()
+				}
+			}
+
+			rewrite.Rewritten = pinned
+		}
+	}
+
+	if rewrite.Rewritten == image {
+		recordDecision(decisionSkipped, rewrite.HarborServerConfig)
+
+		return nil, nil
+	}
+
+	ipr.Log.Info("rewrite container image", "container", container, "original", image, "rewrite", rewrite.Rewritten, "source", rewrite.Source)
+	recordDecision(decisionRewritten, rewrite.HarborServerConfig)
+	ipr.recordEvent(req, ref, corev1.EventTypeNormal, "ImageRewritten",
+		fmt.Sprintf("Rewrite image %s of container %s to %s by %s", image, container, rewrite.Rewritten, rewrite.Source))
+
+	return rewrite, nil
+}
+
+// podEventReference returns the pod, or its controller while the pod has no name yet.
+func podEventReference(req admission.Request, pod *corev1.Pod) *corev1.ObjectReference {
+	if pod.GetName() != "" {
+		return &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  req.Namespace,
+			Name:       pod.GetName(),
+			UID:        pod.GetUID(),
+		}
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil
+	}
+
+	return &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Namespace:  req.Namespace,
+		Name:       owner.Name,
+		UID:        owner.UID,
+	}
+}
+
+// recordEvent records an event on the referenced object.
+// No event is recorded for dry-run requests.
+func (ipr *ImagePathRewriter) recordEvent(req admission.Request, ref *corev1.ObjectReference, eventType, reason, message string) {
+	if ipr.Recorder == nil || ref == nil || (req.DryRun != nil && *req.DryRun) {
+		return
+	}
+
+	ipr.Recorder.Event(ref, eventType, reason, message)
 }
 
 // A decoder will be automatically injected.
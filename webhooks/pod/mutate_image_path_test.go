@@ -0,0 +1,215 @@
+package pod_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/webhooks/pod"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newHSC(name string, isDefault bool, selector *metav1.LabelSelector) goharborv1.HarborServerConfiguration {
+	return goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			Default:           isDefault,
+			NamespaceSelector: selector,
+		},
+	}
+}
+
+func Test_MatchingHarborServerConfigs(t *testing.T) {
+	type testcase struct {
+		description string
+		labels      map[string]string
+		hscs        []goharborv1.HarborServerConfiguration
+		expected    []string
+	}
+
+	teamWeb := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}}
+	notProd := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      "env",
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   []string{"prod"},
+		}},
+	}
+
+	tests := []testcase{
+		{
+			description: "default hsc without selector applies to all namespaces",
+			labels:      map[string]string{"team": "db"},
+			hscs:        []goharborv1.HarborServerConfiguration{newHSC("default", true, nil), newHSC("web", false, teamWeb)},
+			expected:    []string{"default"},
+		},
+		{
+			description: "hsc without selector does not apply",
+			labels:      map[string]string{"team": "web"},
+			hscs:        []goharborv1.HarborServerConfiguration{newHSC("other", false, nil)},
+			expected:    nil,
+		},
+		{
+			description: "matching hscs by name then the default one",
+			labels:      map[string]string{"team": "web", "env": "dev"},
+			hscs: []goharborv1.HarborServerConfiguration{
+				newHSC("default", true, nil),
+				newHSC("web", false, teamWeb),
+				newHSC("dev", false, notProd),
+			},
+			expected: []string{"dev", "web", "default"},
+		},
+		{
+			description: "match expressions are evaluated",
+			labels:      map[string]string{"team": "web", "env": "prod"},
+			hscs: []goharborv1.HarborServerConfiguration{
+				newHSC("default", true, notProd),
+				newHSC("web", false, teamWeb),
+				newHSC("dev", false, notProd),
+			},
+			expected: []string{"web"},
+		},
+	}
+
+	for _, tc := range tests {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: tc.labels}}
+
+		hscs, err := pod.MatchingHarborServerConfigs(ns, tc.hscs)
+		require.NoError(t, err, tc.description)
+
+		var names []string
+		for _, hsc := range hscs {
+			names = append(names, hsc.Name)
+		}
+
+		require.Equal(t, tc.expected, names, tc.description)
+	}
+}
+
+func Test_ImagePathRewriter_ImagePolicy(t *testing.T) {
+	server := newHarborAPI(t)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	newPod := func(image string) []byte {
+		raw, err := json.Marshal(&corev1.Pod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{Name: "redis", Namespace: "web"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "redis", Image: image}},
+			},
+		})
+		require.NoError(t, err)
+
+		return raw
+	}
+
+	vulnerable := host + "/proxy/library/redis:6"
+
+	for name, tc := range map[string]struct {
+		selector *metav1.LabelSelector
+		old      string
+		image    string
+		allowed  bool
+	}{
+		"created": {
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			image:    vulnerable,
+		},
+		"namespace not selected": {
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "db"}},
+			image:    vulnerable,
+			allowed:  true,
+		},
+		"image unchanged by an update": {
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			old:      vulnerable,
+			image:    vulnerable,
+			allowed:  true,
+		},
+		"image changed by an update": {
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+			old:      host + "/proxy/library/nginx:1.21",
+			image:    vulnerable,
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			ipr := newImagePolicyRewriter(t, server.URL, tc.selector)
+
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Namespace: "web",
+				Object:    runtime.RawExtension{Raw: newPod(tc.image)},
+			}}
+
+			if tc.old != "" {
+				req.Operation = admissionv1.Update
+				req.OldObject = runtime.RawExtension{Raw: newPod(tc.old)}
+			}
+
+			res := ipr.Handle(context.Background(), req)
+			require.Equal(t, tc.allowed, res.Allowed, res.Result)
+			require.Empty(t, res.Patches)
+		})
+	}
+}
+
+func newImagePolicyRewriter(t *testing.T, serverURL string, selector *metav1.LabelSelector) *pod.ImagePathRewriter {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "harbor"},
+		Data: map[string][]byte{
+			"accessKey":    []byte("admin"),
+			"accessSecret": []byte("secret"),
+		},
+	}
+
+	hsc := &goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL:         serverURL,
+			NamespaceSelector: selector,
+			AccessCredential: &goharborv1.AccessCredential{
+				Namespace:       "harbor",
+				AccessSecretRef: "creds",
+			},
+			ImagePolicy: &goharborv1.HarborServerImagePolicy{
+				DenyVulnerable: true,
+			},
+		},
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, hsc, ns).Build()
+
+	decoder, err := admission.NewDecoder(scheme)
+	require.NoError(t, err)
+
+	ipr := &pod.ImagePathRewriter{
+		Client:   c,
+		Log:      logr.Discard(),
+		Enforcer: pod.NewImageEnforcer(pod.NewHarborServers(c)),
+	}
+	require.NoError(t, ipr.InjectDecoder(decoder))
+
+	return ipr
+}
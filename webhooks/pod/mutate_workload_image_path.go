@@ -0,0 +1,200 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/goharbor/harbor-operator/pkg/utils/consts"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/mutate-workload-image-path,mutating=true,failurePolicy=fail,groups=apps;batch;argoproj.io,resources=deployments;statefulsets;daemonsets;jobs;cronjobs;rollouts,verbs=create;update,sideEffects=NoneOnDryRun,admissionReviewVersions=v1beta1,versions=v1;v1alpha1,name=mworkloadimg.kb.io
+
+// PodTemplatePaths are the paths of the pod template in the spec of the supported workload kinds.
+var PodTemplatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:     {"spec", "template"},
+	{Group: "apps", Kind: "StatefulSet"}:    {"spec", "template"},
+	{Group: "apps", Kind: "DaemonSet"}:      {"spec", "template"},
+	{Group: "batch", Kind: "Job"}:           {"spec", "template"},
+	{Group: "batch", Kind: "CronJob"}:       {"spec", "jobTemplate", "spec", "template"},
+	{Group: "argoproj.io", Kind: "Rollout"}: {"spec", "template"},
+}
+
+// podTemplateContainerFields are the fields of the containers in a pod template spec.
+var podTemplateContainerFields = []string{"containers", "initContainers"}
+
+// WorkloadImagePathRewriter implements webhook logic to mutate the image path in the pod template of workloads,
+// with the rules and policies of the pods of their namespace.
+type WorkloadImagePathRewriter struct {
+	ImagePathRewriter
+}
+
+// Handle the admission webhook for mutating the image path in the pod template of workloads.
+func (wipr *WorkloadImagePathRewriter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &unstructured.Unstructured{}
+
+	err := wipr.decoder.Decode(req, obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	templatePath, ok := PodTemplatePaths[obj.GroupVersionKind().GroupKind()]
+	if !ok {
+		return admission.Allowed("unsupported kind")
+	}
+
+	// the pod template of jobs is immutable
+	if obj.GetKind() == "Job" && req.Operation == admissionv1.Update {
+		return admission.Allowed("no change")
+	}
+
+	// rollouts may refer to the template of another workload
+	if _, ok, _ := unstructured.NestedMap(obj.Object, templatePath...); !ok {
+		return admission.Allowed("no pod template")
+	}
+
+	wipr.Log.Info("receive workload request", "kind", obj.GetKind(), "name", obj.GetName(), "namespace", req.Namespace)
+
+	var previous map[string]string
+
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		old := &unstructured.Unstructured{}
+		if err := wipr.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		previous = templateImages(old, templatePath)
+	}
+
+	rw, res := wipr.resolveRewriting(ctx, req.Namespace)
+	if rw == nil {
+		return res
+	}
+
+	rw.previous = previous
+
+	var ref *corev1.ObjectReference
+	if obj.GetName() != "" {
+		ref = &corev1.ObjectReference{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  req.Namespace,
+			Name:       obj.GetName(),
+			UID:        obj.GetUID(),
+		}
+	}
+
+	rewrites, err := wipr.rewriteTemplate(ctx, req, rw, ref, obj, templatePath)
+	if err != nil {
+		var denied *ImageDeniedError
+		if errors.As(err, &denied) {
+			return admission.Denied(err.Error())
+		}
+
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if len(rewrites) > 0 {
+		annotationsPath := append(append([]string{}, templatePath...), "metadata", "annotations")
+
+		annotations, _, err := unstructured.NestedStringMap(obj.Object, annotationsPath...)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		audit, err := MergeImageRewrites(annotations[consts.AnnotationImageRewrites], rewrites)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[consts.AnnotationImageRewrites] = audit
+
+		if err := unstructured.SetNestedStringMap(obj.Object, annotations, annotationsPath...); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	marshaledObj, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledObj)
+}
+
+// templateImages returns the images of the containers of the pod template by container name.
+func templateImages(obj *unstructured.Unstructured, templatePath []string) map[string]string {
+	images := map[string]string{}
+
+	for _, field := range podTemplateContainerFields {
+		containersPath := append(append([]string{}, templatePath...), "spec", field)
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, containersPath...)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _, _ := unstructured.NestedString(container, "name")
+			image, _, _ := unstructured.NestedString(container, "image")
+			images[name] = image
+		}
+	}
+
+	return images
+}
+
+// rewriteTemplate rewrites the images of the containers of the pod template, only the image fields are changed.
+func (wipr *WorkloadImagePathRewriter) rewriteTemplate(ctx context.Context, req admission.Request, rw *rewriting, ref *corev1.ObjectReference, obj *unstructured.Unstructured, templatePath []string) ([]ImageRewrite, error) {
+	var rewrites []ImageRewrite
+
+	for _, field := range podTemplateContainerFields {
+		containersPath := append(append([]string{}, templatePath...), "spec", field)
+
+		containers, ok, err := unstructured.NestedSlice(obj.Object, containersPath...)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		for i, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("invalid container %d in %s", i, field)
+			}
+
+			name, _, _ := unstructured.NestedString(container, "name")
+			image, _, _ := unstructured.NestedString(container, "image")
+
+			rewrite, err := wipr.rewriteImage(ctx, req, rw, ref, name, image)
+			if err != nil {
+				return nil, err
+			}
+
+			if rewrite != nil {
+				container["image"] = rewrite.Rewritten
+				rewrites = append(rewrites, *rewrite)
+			}
+		}
+
+		if err := unstructured.SetNestedSlice(obj.Object, containers, containersPath...); err != nil {
+			return nil, err
+		}
+	}
+
+	return rewrites, nil
+}
@@ -0,0 +1,156 @@
+package pod_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/go-logr/logr"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/pkg/utils/consts"
+	"github.com/goharbor/harbor-operator/webhooks/pod"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newImagePathRewriter(t *testing.T) pod.ImagePathRewriter {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, goharborv1.AddToScheme(scheme))
+
+	hsc := &goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL: "https://harbor.example.com",
+			Default:   true,
+			Rules:     []string{"docker.io=>dockerhub"},
+		},
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+
+	decoder, err := admission.NewDecoder(scheme)
+	require.NoError(t, err)
+
+	ipr := pod.ImagePathRewriter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(hsc, ns).Build(),
+		Log:    logr.Discard(),
+	}
+	require.NoError(t, ipr.InjectDecoder(decoder))
+
+	return ipr
+}
+
+func applyPatches(t *testing.T, res admission.Response, original []byte, obj interface{}) {
+	t.Helper()
+
+	require.True(t, res.Allowed, res.Result)
+
+	raw, err := json.Marshal(res.Patches)
+	require.NoError(t, err)
+
+	patch, err := jsonpatch.DecodePatch(raw)
+	require.NoError(t, err)
+
+	result, err := patch.Apply(original)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(result, obj))
+}
+
+func Test_WorkloadImagePathRewriter(t *testing.T) {
+	wipr := &pod.WorkloadImagePathRewriter{ImagePathRewriter: newImagePathRewriter(t)}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"team": "web"}},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+					Containers: []corev1.Container{
+						{Name: "nginx", Image: "nginx:1.21"},
+						{Name: "rewritten", Image: "harbor.example.com/dockerhub/library/redis:6"},
+						{Name: "quay", Image: "quay.io/org/app:v1"},
+					},
+				},
+			},
+		},
+	}
+
+	raw, err := json.Marshal(deployment)
+	require.NoError(t, err)
+
+	res := wipr.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Namespace: "web",
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Object:    runtime.RawExtension{Raw: raw},
+	}})
+
+	rewritten := &appsv1.Deployment{}
+	applyPatches(t, res, raw, rewritten)
+
+	spec := rewritten.Spec.Template.Spec
+	require.Equal(t, "harbor.example.com/dockerhub/library/busybox:latest", spec.InitContainers[0].Image)
+	require.Equal(t, "harbor.example.com/dockerhub/library/nginx:1.21", spec.Containers[0].Image)
+	require.Equal(t, "harbor.example.com/dockerhub/library/redis:6", spec.Containers[1].Image, "images already pulled through Harbor are kept")
+	require.Equal(t, "quay.io/org/app:v1", spec.Containers[2].Image)
+
+	var rewrites []pod.ImageRewrite
+	require.NoError(t, json.Unmarshal([]byte(rewritten.Spec.Template.Annotations[consts.AnnotationImageRewrites]), &rewrites))
+	require.Len(t, rewrites, 2)
+	require.Equal(t, "web", rewritten.Spec.Template.Annotations["team"])
+}
+
+func Test_ImagePathRewriter_EphemeralContainers(t *testing.T) {
+	ipr := newImagePathRewriter(t)
+
+	p := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nginx",
+			Namespace: "web",
+			Annotations: map[string]string{
+				consts.AnnotationImageRewrites: `[{"container":"nginx","original":"nginx:1.21","rewritten":"harbor.example.com/dockerhub/library/nginx:1.21","source":"HarborServerConfiguration/harbor"}]`,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "harbor.example.com/dockerhub/library/nginx:1.21"}},
+			EphemeralContainers: []corev1.EphemeralContainer{{
+				EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox:1.36"},
+			}},
+		},
+	}
+
+	raw, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	res := ipr.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation:   admissionv1.Update,
+		Namespace:   "web",
+		SubResource: "ephemeralcontainers",
+		Object:      runtime.RawExtension{Raw: raw},
+	}})
+
+	rewritten := &corev1.Pod{}
+	applyPatches(t, res, raw, rewritten)
+
+	require.Equal(t, "harbor.example.com/dockerhub/library/nginx:1.21", rewritten.Spec.Containers[0].Image)
+	require.Equal(t, "harbor.example.com/dockerhub/library/busybox:1.36", rewritten.Spec.EphemeralContainers[0].Image)
+
+	var rewrites []pod.ImageRewrite
+	require.NoError(t, json.Unmarshal([]byte(rewritten.Annotations[consts.AnnotationImageRewrites]), &rewrites))
+	require.Len(t, rewrites, 2, "previous rewrites are kept")
+	require.Equal(t, "debugger", rewrites[1].Container)
+}
@@ -0,0 +1,168 @@
+package pod
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultVerificationTimeout       = 2 * time.Second
+	defaultVerificationCacheDuration = 5 * time.Minute
+)
+
+// manifestMediaTypes are the media types of the manifests accepted when verifying an image.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ErrImageNotFound is returned when the manifest of an image does not exist in Harbor.
+var ErrImageNotFound = errors.New("image not found")
+
+type verification struct {
+	err     error
+	expires time.Time
+}
+
+// ImageVerifier checks that the images exist in Harbor through the registry API.
+// The answers, including the failures, are cached for the cache duration of the HSC.
+type ImageVerifier struct {
+	Servers *HarborServers
+
+	lock     sync.Mutex
+	cache    map[string]verification
+	secure   *http.Client
+	insecure *http.Client
+}
+
+// NewImageVerifier returns a verifier connecting to the given Harbor servers.
+func NewImageVerifier(servers *HarborServers) *ImageVerifier {
+	return &ImageVerifier{
+		Servers: servers,
+		cache:   map[string]verification{},
+		secure: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		},
+		insecure: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true, //nolint:gosec
+				},
+			},
+		},
+	}
+}
+
+// Verify returns an error if the image cannot be pulled from the Harbor server of the HSC.
+func (v *ImageVerifier) Verify(ctx context.Context, hsc *goharborv1.HarborServerConfiguration, image string) error {
+	timeout, cacheDuration := defaultVerificationTimeout, defaultVerificationCacheDuration
+
+	if verif := hsc.Spec.ImageVerification; verif != nil {
+		if verif.Timeout != nil {
+			timeout = verif.Timeout.Duration
+		}
+
+		if verif.CacheDuration != nil {
+			cacheDuration = verif.CacheDuration.Duration
+		}
+	}
+
+	// the resource version invalidates the cached answers when the HSC changes
+	key := fmt.Sprintf("%s/%s/%s", hsc.GetName(), hsc.GetResourceVersion(), image)
+
+	v.lock.Lock()
+	cached, ok := v.cache[key]
+	v.lock.Unlock()
+
+	if ok && time.Now().Before(cached.expires) {
+		return cached.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := v.verify(ctx, hsc, image)
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	now := time.Now()
+	for k, c := range v.cache {
+		if now.After(c.expires) {
+			delete(v.cache, k)
+		}
+	}
+
+	v.cache[key] = verification{
+		err:     err,
+		expires: now.Add(cacheDuration),
+	}
+
+	return err
+}
+
+func (v *ImageVerifier) verify(ctx context.Context, hsc *goharborv1.HarborServerConfiguration, image string) error {
+	named, err := reference.ParseDockerRef(image)
+	if err != nil {
+		return err
+	}
+
+	server, err := v.Servers.Get(ctx, hsc)
+	if err != nil {
+		return err
+	}
+
+	scheme := "https"
+	if strings.HasPrefix(hsc.Spec.ServerURL, "http://") {
+		scheme = "http"
+	}
+
+	manifestURL := url.URL{
+		Scheme: scheme,
+		Host:   reference.Domain(named),
+		Path:   fmt.Sprintf("/v2/%s/manifests/%s", reference.Path(named), imageReference(named)),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(server.Username, server.Password)
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+
+	httpClient := v.secure
+	if hsc.Spec.Insecure {
+		httpClient = v.insecure
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get manifest error: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrImageNotFound
+	default:
+		return errors.Errorf("get manifest error: unexpected status %s", res.Status)
+	}
+}
@@ -0,0 +1,73 @@
+package pod_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-operator/apis/goharbor.io/v1beta1"
+	"github.com/goharbor/harbor-operator/webhooks/pod"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ImageVerifier(t *testing.T) {
+	requests := map[string]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.Method == http.MethodHead && r.URL.Path == "/v2/proxy/library/nginx/manifests/1.21" {
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "harbor"},
+		Data: map[string][]byte{
+			"accessKey":    []byte("admin"),
+			"accessSecret": []byte("secret"),
+		},
+	}
+
+	hsc := &goharborv1.HarborServerConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor"},
+		Spec: goharborv1.HarborServerConfigurationSpec{
+			ServerURL: server.URL,
+			AccessCredential: &goharborv1.AccessCredential{
+				Namespace:       "harbor",
+				AccessSecretRef: "creds",
+			},
+			ImageVerification: &goharborv1.HarborServerImageVerification{},
+		},
+	}
+
+	verifier := pod.NewImageVerifier(pod.NewHarborServers(fake.NewClientBuilder().WithObjects(secret).Build()))
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ctx := context.Background()
+
+	require.NoError(t, verifier.Verify(ctx, hsc, host+"/proxy/library/nginx:1.21"), "existing image")
+	require.ErrorIs(t, verifier.Verify(ctx, hsc, host+"/proxy/library/nginx:1.22"), pod.ErrImageNotFound, "missing image")
+
+	// answers are cached
+	require.NoError(t, verifier.Verify(ctx, hsc, host+"/proxy/library/nginx:1.21"), "cached existing image")
+	require.ErrorIs(t, verifier.Verify(ctx, hsc, host+"/proxy/library/nginx:1.22"), pod.ErrImageNotFound, "cached missing image")
+	require.Equal(t, 1, requests["/v2/proxy/library/nginx/manifests/1.21"])
+	require.Equal(t, 1, requests["/v2/proxy/library/nginx/manifests/1.22"])
+}